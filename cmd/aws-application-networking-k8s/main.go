@@ -27,11 +27,13 @@ import (
 
 	"github.com/aws/aws-application-networking-k8s/pkg/webhook"
 	"github.com/go-logr/zapr"
-	"go.uber.org/zap/zapcore"
 	k8swebhook "sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	"github.com/aws/aws-application-networking-k8s/pkg/aws"
+	"github.com/aws/aws-application-networking-k8s/pkg/aws/services"
+	"github.com/aws/aws-application-networking-k8s/pkg/shard"
 	"github.com/aws/aws-application-networking-k8s/pkg/utils/gwlog"
+	"github.com/aws/aws-application-networking-k8s/pkg/utils/tracing"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -39,9 +41,13 @@ import (
 
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/uuid"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 
 	"github.com/aws/aws-application-networking-k8s/pkg/controllers"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -53,7 +59,10 @@ import (
 	anv1alpha1 "github.com/aws/aws-application-networking-k8s/pkg/apis/applicationnetworking/v1alpha1"
 	"github.com/aws/aws-application-networking-k8s/pkg/config"
 	"github.com/aws/aws-application-networking-k8s/pkg/k8s"
+	corev1 "k8s.io/api/core/v1"
 	discoveryv1 "k8s.io/api/discovery/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
@@ -91,7 +100,9 @@ func addOptionalCRDs(scheme *runtime.Scheme) {
 		&anv1alpha1.TargetGroupPolicy{}, &anv1alpha1.TargetGroupPolicyList{},
 		&anv1alpha1.AccessLogPolicy{}, &anv1alpha1.AccessLogPolicyList{},
 		&anv1alpha1.VpcAssociationPolicy{}, &anv1alpha1.VpcAssociationPolicyList{},
-		&anv1alpha1.IAMAuthPolicy{}, &anv1alpha1.IAMAuthPolicyList{})
+		&anv1alpha1.IAMAuthPolicy{}, &anv1alpha1.IAMAuthPolicyList{},
+		&anv1alpha1.LatticeServiceStatus{}, &anv1alpha1.LatticeServiceStatusList{},
+		&anv1alpha1.ServiceImportPolicy{}, &anv1alpha1.ServiceImportPolicyList{})
 
 	metav1.AddToGroupVersion(scheme, groupVersion)
 }
@@ -141,47 +152,173 @@ func checkRequiredCRDs(mgr ctrl.Manager) error {
 	return nil
 }
 
+// cacheOptions limits the cache, and so every List call of the controller, to the watched namespaces.
+// Gateways, routes, ServiceExports and ServiceImports are also limited to the watch label selector,
+// policies are not since they only take effect through their targetRef.
+func cacheOptions() cache.Options {
+	opts := cache.Options{}
+	if len(config.WatchNamespaces) > 0 {
+		opts.DefaultNamespaces = map[string]cache.Config{}
+		for _, ns := range config.WatchNamespaces {
+			opts.DefaultNamespaces[ns] = cache.Config{}
+		}
+	}
+	if !config.WatchLabelSelector.Empty() {
+		opts.ByObject = map[client.Object]cache.ByObject{}
+		for _, obj := range []client.Object{
+			&gwv1.Gateway{}, &gwv1.HTTPRoute{}, &gwv1.GRPCRoute{}, &gwv1alpha2.TLSRoute{},
+			&anv1alpha1.ServiceExport{}, &anv1alpha1.ServiceImport{},
+		} {
+			opts.ByObject[obj] = cache.ByObject{Label: config.WatchLabelSelector}
+		}
+	}
+	return opts
+}
+
+// controllerNamespace returns the namespace the controller runs in
+func controllerNamespace() (string, error) {
+	namespace := os.Getenv("POD_NAMESPACE")
+	if namespace == "" {
+		data, err := os.ReadFile("/var/run/secrets/kubernetes.io/serviceaccount/namespace")
+		if err != nil {
+			return "", fmt.Errorf("POD_NAMESPACE is not set and the namespace of the controller is unknown: %w", err)
+		}
+		namespace = strings.TrimSpace(string(data))
+	}
+	return namespace, nil
+}
+
+// loadConfigMap overrides the environment with the config ConfigMap, when it exists, and returns
+// the environment before the overrides
+func loadConfigMap(ctx context.Context, log gwlog.Logger, name types.NamespacedName) (map[string]string, error) {
+	// the manager does not exist yet, the config is needed to create it
+	c, err := client.New(ctrl.GetConfigOrDie(), client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, err
+	}
+	cm := &corev1.ConfigMap{}
+	if err := c.Get(ctx, name, cm); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Infof(ctx, "config ConfigMap %s not found, using the environment", name)
+			return config.Environment(), nil
+		}
+		return nil, err
+	}
+	env, unknown := config.OverrideEnvironment(cm.Data)
+	if len(unknown) > 0 {
+		log.Warnf(ctx, "Ignoring unknown settings of config ConfigMap %s: %s", name, strings.Join(unknown, ", "))
+	}
+	return env, nil
+}
+
+// newShardManager splits the routes across the replicas, using leases in the namespace of the controller
+func newShardManager(log gwlog.Logger, mgr ctrl.Manager) (*shard.Manager, error) {
+	namespace, err := controllerNamespace()
+	if err != nil {
+		return nil, err
+	}
+	identity := os.Getenv("POD_NAME")
+	if identity == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return nil, err
+		}
+		identity = hostname
+	}
+	// a restarted pod keeps its name, but must not reuse the leases of its previous run
+	identity = strings.ToLower(identity) + "-" + string(uuid.NewUUID())[:8]
+
+	// leases are read directly, they are neither in the cache nor in the watched namespaces
+	leaseClient, err := client.New(mgr.GetConfig(), client.Options{Scheme: mgr.GetScheme()})
+	if err != nil {
+		return nil, err
+	}
+	return shard.NewManager(log, leaseClient, shard.Config{
+		Shards:    config.RouteShards,
+		Namespace: namespace,
+		Identity:  identity,
+	}), nil
+}
+
 func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
+	var watchNamespaces string
+	var watchLabelSelector string
+	var configMapName string
 
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.StringVar(&watchNamespaces, "watch-namespaces", "",
+		"Comma separated list of namespaces the controller watches. All namespaces are watched when empty.")
+	flag.StringVar(&watchLabelSelector, "watch-label-selector", "",
+		"Label selector of the Gateways, routes, ServiceExports and ServiceImports the controller watches.")
+	flag.StringVar(&configMapName, "config-map", "",
+		"Name of a ConfigMap in the controller namespace overriding the environment variables. "+
+			"Changes to the log level, default tags, target group GC interval and controller limits apply without restart.")
 	flag.Parse()
 
-	logLevel := logLevel()
-	log := gwlog.NewLogger(logLevel)
+	log := gwlog.NewLogger(config.ParseLogLevel(os.Getenv(config.LOG_LEVEL)))
 	ctrl.SetLogger(zapr.NewLogger(log.InnerLogger.Desugar()).WithName("runtime"))
 
 	ctx := context.Background()
-	log.Infof(ctx, "log level set to %s", logLevel)
-
 	setupLog := log.InnerLogger.Named("setup")
 
+	var configMap types.NamespacedName
+	var env map[string]string
+	if configMapName != "" {
+		namespace, err := controllerNamespace()
+		if err != nil {
+			setupLog.Fatalf("config ConfigMap setup failed: %s", err)
+		}
+		configMap = types.NamespacedName{Namespace: namespace, Name: configMapName}
+		if env, err = loadConfigMap(ctx, log.Named("setup"), configMap); err != nil {
+			setupLog.Fatalf("config ConfigMap %s read failed: %s", configMap, err)
+		}
+	}
+
 	err := config.ConfigInit()
 	if err != nil {
 		setupLog.Fatalf("init config failed: %s", err)
 	}
+	log.SetLevel(config.LogLevel())
+	log.Infof(ctx, "log level set to %s", config.LogLevel())
+	if err := config.ParseWatchScope(watchNamespaces, watchLabelSelector); err != nil {
+		setupLog.Fatalf("init config failed: %s", err)
+	}
 	setupLog.Infow("init config",
 		"VpcId", config.VpcID,
 		"Region", config.Region,
 		"AccountId", config.AccountID,
 		"DefaultServiceNetwork", config.DefaultServiceNetwork,
 		"ClusterName", config.ClusterName,
-		"LogLevel", logLevel,
+		"LogLevel", config.LogLevel(),
+		"ConfigMap", configMapName,
 		"DisableTaggingServiceAPI", config.DisableTaggingServiceAPI,
+		"WatchNamespaces", config.WatchNamespaces,
+		"WatchLabelSelector", config.WatchLabelSelector.String(),
+		"RouteShards", config.RouteShards,
 	)
 
+	shutdownTracing, err := tracing.Setup(ctx)
+	if err != nil {
+		setupLog.Fatalf("tracing setup failed: %s", err)
+	}
+	defer shutdownTracing(context.Background())
+	setupLog.Infow("init tracing", "Enabled", tracing.Enabled())
+
 	cloud, err := aws.NewCloud(log.Named("cloud"), aws.CloudConfig{
 		VpcId:                     config.VpcID,
 		AccountId:                 config.AccountID,
 		Region:                    config.Region,
 		ClusterName:               config.ClusterName,
 		TaggingServiceAPIDisabled: config.DisableTaggingServiceAPI,
+		APIRateLimits:             config.LatticeAPIRateLimits,
+		CacheResyncPeriod:         config.LatticeCacheResyncPeriod,
 	}, metrics.Registry)
 	if err != nil {
 		setupLog.Fatal("cloud client setup failed: %s", err)
@@ -207,6 +344,7 @@ func main() {
 		Metrics: metricsserver.Options{
 			BindAddress: metricsAddr,
 		},
+		Cache:                  cacheOptions(),
 		WebhookServer:          webhookServer,
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
@@ -227,6 +365,23 @@ func main() {
 			logger,
 		)
 		webhook.NewPodMutator(logger, scheme, readinessGateInjector).SetupWithManager(logger, mgr)
+
+		routeValidatorLog := log.Named("route-validator")
+		webhook.NewRouteValidator(routeValidatorLog, scheme, mgr.GetClient()).SetupWithManager(routeValidatorLog, mgr)
+		policyValidatorLog := log.Named("policy-validator")
+		webhook.NewPolicyValidator(policyValidatorLog, scheme, mgr.GetClient()).SetupWithManager(policyValidatorLog, mgr)
+	}
+
+	if cache, ok := cloud.Lattice().(*services.CachedLattice); ok {
+		if err := mgr.Add(cache); err != nil {
+			setupLog.Fatalf("lattice cache setup failed: %s", err)
+		}
+	}
+	// refreshes the Lattice caches of the accounts of CROSS_ACCOUNT_ROLES
+	if runnable, ok := cloud.(manager.Runnable); ok {
+		if err := mgr.Add(runnable); err != nil {
+			setupLog.Fatalf("lattice cache setup failed: %s", err)
+		}
 	}
 
 	finalizerManager := k8s.NewDefaultFinalizerManager(mgr.GetClient())
@@ -234,11 +389,16 @@ func main() {
 	// parent logging scope for all controllers
 	ctrlLog := log.Named("controller")
 
-	err = controllers.RegisterPodController(ctrlLog.Named("pod"), mgr)
+	err = controllers.RegisterPodController(ctrlLog.Named("pod"), cloud, mgr)
 	if err != nil {
 		setupLog.Fatalf("pod controller setup failed: %s", err)
 	}
 
+	err = controllers.RegisterTargetsController(ctrlLog.Named("targets"), cloud, mgr)
+	if err != nil {
+		setupLog.Fatalf("targets controller setup failed: %s", err)
+	}
+
 	err = controllers.RegisterServiceController(ctrlLog.Named("service"), cloud, finalizerManager, mgr)
 	if err != nil {
 		setupLog.Fatalf("service controller setup failed: %s", err)
@@ -254,12 +414,30 @@ func main() {
 		setupLog.Fatalf("gateway controller setup failed: %s", err)
 	}
 
-	err = controllers.RegisterAllRouteControllers(ctrlLog.Named("route"), cloud, finalizerManager, mgr)
+	var shardManager *shard.Manager
+	if config.RouteShards > 0 {
+		shardManager, err = newShardManager(log.Named("shard"), mgr)
+		if err != nil {
+			setupLog.Fatalf("shard manager setup failed: %s", err)
+		}
+		if err := mgr.Add(shardManager); err != nil {
+			setupLog.Fatalf("shard manager setup failed: %s", err)
+		}
+		if cache, ok := cloud.Lattice().(*services.CachedLattice); ok {
+			// the routes of an acquired shard were deployed by another replica, bypassing this cache.
+			// Registered first, so that the cache is cleared before the routes are requeued.
+			shardManager.OnAcquire(func(_ context.Context, _ int) {
+				cache.Clear()
+			})
+		}
+	}
+
+	err = controllers.RegisterAllRouteControllers(ctrlLog.Named("route"), cloud, finalizerManager, mgr, shardManager)
 	if err != nil {
 		setupLog.Fatalf("route controller setup failed: %s", err)
 	}
 
-	err = controllers.RegisterServiceImportController(ctrlLog.Named("service-import"), mgr, finalizerManager)
+	err = controllers.RegisterServiceImportController(ctrlLog.Named("service-import"), cloud, mgr, finalizerManager)
 	if err != nil {
 		setupLog.Fatalf("serviceimport controller setup failed: %s", err)
 	}
@@ -269,6 +447,13 @@ func main() {
 		setupLog.Fatalf("serviceexport controller setup failed: %s", err)
 	}
 
+	if len(config.ServiceImportDiscoveryNamespaces) > 0 {
+		err = controllers.RegisterServiceImportDiscovery(ctrlLog.Named("service-import-discovery"), cloud, mgr)
+		if err != nil {
+			setupLog.Fatalf("serviceimport discovery setup failed: %s", err)
+		}
+	}
+
 	err = controllers.RegisterAccessLogPolicyController(ctrlLog.Named("access-log-policy"), cloud, finalizerManager, mgr)
 	if err != nil {
 		setupLog.Fatalf("accesslogpolicy controller setup failed: %s", err)
@@ -284,10 +469,25 @@ func main() {
 		setupLog.Fatalf("target group policy controller setup failed: %s", err)
 	}
 
+	err = controllers.RegisterServiceImportPolicyController(ctrlLog.Named("service-import-policy"), mgr)
+	if err != nil {
+		setupLog.Fatalf("service import policy controller setup failed: %s", err)
+	}
+
 	err = controllers.RegisterVpcAssociationPolicyController(ctrlLog.Named("vpc-association-policy"), cloud, finalizerManager, mgr)
 	if err != nil {
 		setupLog.Fatalf("vpc association policy controller setup failed: %s", err)
 	}
+
+	if configMapName != "" {
+		err = controllers.RegisterConfigController(ctrlLog.Named("config"), mgr, configMap, env, config.Environment(), func() {
+			log.SetLevel(config.LogLevel())
+			controllers.UpdateControllerLimits()
+		})
+		if err != nil {
+			setupLog.Fatalf("config controller setup failed: %s", err)
+		}
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -306,17 +506,3 @@ func main() {
 	}
 
 }
-
-func logLevel() zapcore.Level {
-	level := os.Getenv("LOG_LEVEL")
-	switch level {
-	case "debug":
-		return zapcore.DebugLevel
-	case "error":
-		return zapcore.ErrorLevel
-	case "panic":
-		return zapcore.PanicLevel
-	default:
-		return zapcore.InfoLevel
-	}
-}
@@ -0,0 +1,55 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// lattice-local serves the VPC Lattice API operations used by the controller from memory,
+// so the controller can run against it with LATTICE_ENDPOINT instead of a real AWS account.
+package main
+
+import (
+	"context"
+	"flag"
+	"net/http"
+
+	"go.uber.org/zap/zapcore"
+
+	"github.com/aws/aws-application-networking-k8s/pkg/aws/services"
+	"github.com/aws/aws-application-networking-k8s/pkg/utils/gwlog"
+)
+
+func main() {
+	var listenAddr, account, region string
+	var debug bool
+	flag.StringVar(&listenAddr, "listen-address", ":8090", "The address the Lattice API is served on.")
+	flag.StringVar(&account, "account", "123456789012", "The AWS account id used in resource ARNs.")
+	flag.StringVar(&region, "region", "us-west-2", "The AWS region used in resource ARNs.")
+	flag.BoolVar(&debug, "debug", false, "Log every API call.")
+	flag.Parse()
+
+	logLevel := zapcore.InfoLevel
+	if debug {
+		logLevel = zapcore.DebugLevel
+	}
+	log := gwlog.NewLogger(logLevel).Named("lattice-local")
+
+	srv, err := newServer(log, services.NewFakeLattice(account, region))
+	if err != nil {
+		log.InnerLogger.Fatalf("server setup failed: %s", err)
+	}
+	log.Infof(context.TODO(), "Serving Lattice API on %s, resources can be inspected at %s", listenAddr, debugResourcesPath)
+	if err := http.ListenAndServe(listenAddr, srv); err != nil {
+		log.InnerLogger.Fatalf("server failed: %s", err)
+	}
+}
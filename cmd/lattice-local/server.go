@@ -0,0 +1,267 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/private/protocol/json/jsonutil"
+	"github.com/aws/aws-sdk-go/service/vpclattice"
+
+	"github.com/aws/aws-application-networking-k8s/pkg/aws/services"
+	"github.com/aws/aws-application-networking-k8s/pkg/utils/gwlog"
+)
+
+// operations served by the local server, which are the operations implemented by the fake Lattice
+var operations = []string{
+	"CreateServiceNetwork", "GetServiceNetwork", "UpdateServiceNetwork", "DeleteServiceNetwork", "ListServiceNetworks",
+	"CreateService", "GetService", "UpdateService", "DeleteService", "ListServices",
+	"CreateServiceNetworkServiceAssociation", "GetServiceNetworkServiceAssociation",
+	"DeleteServiceNetworkServiceAssociation", "ListServiceNetworkServiceAssociations",
+	"CreateServiceNetworkVpcAssociation", "GetServiceNetworkVpcAssociation", "UpdateServiceNetworkVpcAssociation",
+	"DeleteServiceNetworkVpcAssociation", "ListServiceNetworkVpcAssociations",
+	"CreateListener", "GetListener", "UpdateListener", "DeleteListener", "ListListeners",
+	"CreateRule", "GetRule", "UpdateRule", "BatchUpdateRule", "DeleteRule", "ListRules",
+	"CreateTargetGroup", "GetTargetGroup", "UpdateTargetGroup", "DeleteTargetGroup", "ListTargetGroups",
+	"RegisterTargets", "DeregisterTargets", "ListTargets",
+	"CreateAccessLogSubscription", "GetAccessLogSubscription", "UpdateAccessLogSubscription",
+	"DeleteAccessLogSubscription", "ListAccessLogSubscriptions",
+	"PutAuthPolicy", "GetAuthPolicy", "DeleteAuthPolicy",
+	"PutResourcePolicy", "GetResourcePolicy", "DeleteResourcePolicy",
+	"TagResource", "UntagResource", "ListTagsForResource",
+}
+
+const debugResourcesPath = "/debug/resources"
+
+type route struct {
+	operation string
+	method    string
+	segments  []string
+	inputType reflect.Type
+	call      reflect.Value
+}
+
+// server speaks the REST/JSON protocol of VPC Lattice on top of a fake Lattice
+type server struct {
+	log     gwlog.Logger
+	lattice *services.FakeLattice
+	routes  []route
+}
+
+// newServer builds the routing table from the HTTP bindings of the SDK client, so requests
+// are decoded with the same rules the SDK uses to encode them.
+func newServer(log gwlog.Logger, lattice *services.FakeLattice) (*server, error) {
+	sess, err := session.NewSession(&aws.Config{
+		Region:      aws.String("us-west-2"),
+		Credentials: credentials.AnonymousCredentials,
+	})
+	if err != nil {
+		return nil, err
+	}
+	client := reflect.ValueOf(vpclattice.New(sess))
+	fake := reflect.ValueOf(lattice)
+
+	s := &server{log: log, lattice: lattice}
+	for _, op := range operations {
+		requestFn := client.MethodByName(op + "Request")
+		call := fake.MethodByName(op + "WithContext")
+		if !requestFn.IsValid() || !call.IsValid() {
+			return nil, fmt.Errorf("unknown operation %s", op)
+		}
+		inputType := requestFn.Type().In(0).Elem()
+		req := requestFn.Call([]reflect.Value{reflect.New(inputType)})[0].Interface().(*request.Request)
+		s.routes = append(s.routes, route{
+			operation: op,
+			method:    req.Operation.HTTPMethod,
+			segments:  strings.Split(strings.Trim(req.Operation.HTTPPath, "/"), "/"),
+			inputType: inputType,
+			call:      call,
+		})
+	}
+	return s, nil
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet && r.URL.Path == debugResourcesPath {
+		s.serveSnapshot(w)
+		return
+	}
+
+	// path labels are escaped by the SDK, ARNs are sent as single segments
+	segments := strings.Split(strings.Trim(r.URL.EscapedPath(), "/"), "/")
+	for _, rt := range s.routes {
+		if rt.method != r.Method {
+			continue
+		}
+		labels, ok := matchPath(rt.segments, segments)
+		if !ok {
+			continue
+		}
+		s.serveOperation(w, r, rt, labels)
+		return
+	}
+	s.writeError(w, http.StatusNotFound, "UnknownOperationException",
+		&vpclattice.ValidationException{Message_: aws.String(fmt.Sprintf("no operation for %s %s", r.Method, r.URL.Path))})
+}
+
+func matchPath(template []string, segments []string) (map[string]string, bool) {
+	if len(template) != len(segments) {
+		return nil, false
+	}
+	labels := map[string]string{}
+	for i, t := range template {
+		if strings.HasPrefix(t, "{") && strings.HasSuffix(t, "}") {
+			value, err := url.PathUnescape(segments[i])
+			if err != nil {
+				return nil, false
+			}
+			labels[strings.Trim(t, "{}+")] = value
+			continue
+		}
+		if t != segments[i] {
+			return nil, false
+		}
+	}
+	return labels, true
+}
+
+func (s *server) serveOperation(w http.ResponseWriter, r *http.Request, rt route, labels map[string]string) {
+	input := reflect.New(rt.inputType)
+	if err := jsonutil.UnmarshalJSON(input.Interface(), r.Body); err != nil {
+		s.writeError(w, http.StatusBadRequest, vpclattice.ErrCodeValidationException,
+			&vpclattice.ValidationException{Message_: aws.String(err.Error())})
+		return
+	}
+	if err := setLocationFields(input.Elem(), labels, r.URL.Query()); err != nil {
+		s.writeError(w, http.StatusBadRequest, vpclattice.ErrCodeValidationException,
+			&vpclattice.ValidationException{Message_: aws.String(err.Error())})
+		return
+	}
+
+	results := rt.call.Call([]reflect.Value{reflect.ValueOf(r.Context()), input})
+	if err, _ := results[1].Interface().(error); err != nil {
+		s.log.Debugw(context.TODO(), "operation failed", "operation", rt.operation, "error", err.Error())
+		s.writeAPIError(w, err)
+		return
+	}
+	s.log.Debugw(context.TODO(), "operation succeeded", "operation", rt.operation)
+
+	body, err := jsonutil.BuildJSON(results[0].Interface())
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, vpclattice.ErrCodeInternalServerException,
+			&vpclattice.InternalServerException{Message_: aws.String(err.Error())})
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
+
+// setLocationFields sets the input fields bound to the URI path and the query string
+func setLocationFields(input reflect.Value, labels map[string]string, query url.Values) error {
+	t := input.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := field.Tag.Get("locationName")
+		var values []string
+		switch field.Tag.Get("location") {
+		case "uri":
+			if v, ok := labels[name]; ok {
+				values = []string{v}
+			}
+		case "querystring":
+			values = query[name]
+		default:
+			continue
+		}
+		if len(values) == 0 {
+			continue
+		}
+		if err := setField(input.Field(i), values); err != nil {
+			return fmt.Errorf("invalid %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func setField(field reflect.Value, values []string) error {
+	switch field.Interface().(type) {
+	case *string:
+		field.Set(reflect.ValueOf(aws.String(values[0])))
+	case *int64:
+		n, err := strconv.ParseInt(values[0], 10, 64)
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(aws.Int64(n)))
+	case *bool:
+		b, err := strconv.ParseBool(values[0])
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(aws.Bool(b)))
+	case []*string:
+		field.Set(reflect.ValueOf(aws.StringSlice(values)))
+	default:
+		return fmt.Errorf("unsupported field type %s", field.Type())
+	}
+	return nil
+}
+
+func (s *server) writeAPIError(w http.ResponseWriter, err error) {
+	aerr, ok := err.(awserr.Error)
+	if !ok {
+		s.writeError(w, http.StatusInternalServerError, vpclattice.ErrCodeInternalServerException,
+			&vpclattice.InternalServerException{Message_: aws.String(err.Error())})
+		return
+	}
+	status := http.StatusBadRequest
+	switch aerr.Code() {
+	case vpclattice.ErrCodeResourceNotFoundException:
+		status = http.StatusNotFound
+	case vpclattice.ErrCodeConflictException:
+		status = http.StatusConflict
+	case vpclattice.ErrCodeAccessDeniedException:
+		status = http.StatusForbidden
+	case vpclattice.ErrCodeThrottlingException:
+		status = http.StatusTooManyRequests
+	case vpclattice.ErrCodeInternalServerException:
+		status = http.StatusInternalServerError
+	case services.FakeNotImplementedErrorCode:
+		status = http.StatusNotImplemented
+	}
+	s.writeError(w, status, aerr.Code(), err)
+}
+
+// writeError writes the error the way the SDK unmarshals typed errors, with the code in
+// the error type header and the exception fields in the body.
+func (s *server) writeError(w http.ResponseWriter, status int, code string, exception any) {
+	body, err := jsonutil.BuildJSON(exception)
+	if err != nil {
+		body = []byte("{}")
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Amzn-Errortype", code)
+	w.WriteHeader(status)
+	w.Write(body)
+}
+
+func (s *server) serveSnapshot(w http.ResponseWriter) {
+	body, err := json.MarshalIndent(s.lattice.Snapshot(), "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(body)
+}
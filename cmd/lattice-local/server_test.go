@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/vpclattice"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aws/aws-application-networking-k8s/pkg/aws/services"
+	"github.com/aws/aws-application-networking-k8s/pkg/utils/gwlog"
+)
+
+func newTestClient(t *testing.T) (services.Lattice, *httptest.Server) {
+	srv, err := newServer(gwlog.FallbackLogger, services.NewFakeLattice("111111111111", "us-west-2"))
+	assert.NoError(t, err)
+	ts := httptest.NewServer(srv)
+	t.Cleanup(ts.Close)
+
+	t.Setenv("LATTICE_ENDPOINT", ts.URL)
+	sess, err := session.NewSession(&aws.Config{
+		Credentials: credentials.NewStaticCredentials("id", "secret", ""),
+	})
+	assert.NoError(t, err)
+	return services.NewDefaultLattice(sess, "111111111111", "us-west-2"), ts
+}
+
+func TestServer_Operations(t *testing.T) {
+	ctx := context.TODO()
+	lattice, ts := newTestClient(t)
+
+	svc, err := lattice.CreateServiceWithContext(ctx, &vpclattice.CreateServiceInput{
+		Name: aws.String("svc"),
+		Tags: services.Tags{"k": aws.String("v")},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "arn:aws:vpc-lattice:us-west-2:111111111111:service/"+*svc.Id, *svc.Arn)
+
+	_, err = lattice.CreateServiceWithContext(ctx, &vpclattice.CreateServiceInput{Name: aws.String("svc")})
+	assert.IsType(t, &vpclattice.ConflictException{}, err)
+
+	listener, err := lattice.CreateListenerWithContext(ctx, &vpclattice.CreateListenerInput{
+		ServiceIdentifier: svc.Arn,
+		Name:              aws.String("listener"),
+		Protocol:          aws.String(vpclattice.ListenerProtocolHttp),
+		DefaultAction: &vpclattice.RuleAction{
+			FixedResponse: &vpclattice.FixedResponseAction{StatusCode: aws.Int64(404)},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(80), aws.Int64Value(listener.Port))
+
+	// query string parameters and pagination
+	for _, name := range []string{"tg-1", "tg-2", "tg-3"} {
+		_, err := lattice.CreateTargetGroupWithContext(ctx, &vpclattice.CreateTargetGroupInput{
+			Name:   aws.String(name),
+			Type:   aws.String(vpclattice.TargetGroupTypeIp),
+			Config: &vpclattice.TargetGroupConfig{VpcIdentifier: aws.String("vpc-1"), Port: aws.Int64(80)},
+			Tags:   services.Tags{"k": aws.String("v"), "l": aws.String("w")},
+		})
+		assert.NoError(t, err)
+	}
+	page, err := lattice.ListTargetGroupsWithContext(ctx, &vpclattice.ListTargetGroupsInput{MaxResults: aws.Int64(2)})
+	assert.NoError(t, err)
+	assert.Len(t, page.Items, 2)
+	assert.NotNil(t, page.NextToken)
+	tgs, err := lattice.ListTargetGroupsAsList(ctx, &vpclattice.ListTargetGroupsInput{VpcIdentifier: aws.String("vpc-1")})
+	assert.NoError(t, err)
+	assert.Len(t, tgs, 3)
+
+	// ARNs in the path
+	tags, err := lattice.ListTagsForResourceWithContext(ctx, &vpclattice.ListTagsForResourceInput{ResourceArn: svc.Arn})
+	assert.NoError(t, err)
+	assert.Equal(t, "v", aws.StringValue(tags.Tags["k"]))
+	_, err = lattice.UntagResourceWithContext(ctx, &vpclattice.UntagResourceInput{ResourceArn: tgs[0].Arn, TagKeys: aws.StringSlice([]string{"k"})})
+	assert.NoError(t, err)
+
+	_, err = lattice.RegisterTargetsWithContext(ctx, &vpclattice.RegisterTargetsInput{
+		TargetGroupIdentifier: tgs[0].Id,
+		Targets:               []*vpclattice.Target{{Id: aws.String("10.0.0.1"), Port: aws.Int64(8080)}},
+	})
+	assert.NoError(t, err)
+	targets, err := lattice.ListTargetsAsList(ctx, &vpclattice.ListTargetsInput{TargetGroupIdentifier: tgs[0].Arn})
+	assert.NoError(t, err)
+	assert.Len(t, targets, 1)
+	assert.Equal(t, vpclattice.TargetStatusUnused, aws.StringValue(targets[0].Status))
+
+	_, err = lattice.DeleteServiceWithContext(ctx, &vpclattice.DeleteServiceInput{ServiceIdentifier: svc.Id})
+	assert.NoError(t, err)
+	_, err = lattice.GetServiceWithContext(ctx, &vpclattice.GetServiceInput{ServiceIdentifier: svc.Id})
+	assert.True(t, services.IsLatticeAPINotFoundErr(err))
+	notFound, ok := err.(*vpclattice.ResourceNotFoundException)
+	assert.True(t, ok)
+	assert.Equal(t, "SERVICE", aws.StringValue(notFound.ResourceType))
+
+	resp, err := http.Get(ts.URL + debugResourcesPath)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	snapshot := &services.FakeLatticeSnapshot{}
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(snapshot))
+	assert.Empty(t, snapshot.Services)
+	assert.Len(t, snapshot.TargetGroups, 3)
+	assert.Len(t, snapshot.Targets[*tgs[0].Id], 1)
+	assert.Equal(t, services.Tags{"l": aws.String("w")}, snapshot.Tags[*tgs[0].Arn])
+}
+
+func TestMatchPath(t *testing.T) {
+	template := []string{"services", "{serviceIdentifier}", "listeners", "{listenerIdentifier}"}
+
+	labels, ok := matchPath(template, []string{"services", "svc-1", "listeners", "arn%3Aaws%3Avpc-lattice%3A%3A1%3Aservice%2Fsvc-1%2Flistener%2Flistener-1"})
+	assert.True(t, ok)
+	assert.Equal(t, "svc-1", labels["serviceIdentifier"])
+	assert.Equal(t, "arn:aws:vpc-lattice::1:service/svc-1/listener/listener-1", labels["listenerIdentifier"])
+
+	_, ok = matchPath(template, []string{"services", "svc-1", "listeners"})
+	assert.False(t, ok)
+	_, ok = matchPath(template, []string{"services", "svc-1", "rules", "rule-1"})
+	assert.False(t, ok)
+}
@@ -34,4 +34,6 @@ kubectl apply -f config/crds/bases/application-networking.k8s.aws_targetgrouppol
 kubectl apply -f config/crds/bases/application-networking.k8s.aws_vpcassociationpolicies.yaml
 kubectl apply -f config/crds/bases/application-networking.k8s.aws_accesslogpolicies.yaml
 kubectl apply -f config/crds/bases/application-networking.k8s.aws_iamauthpolicies.yaml
+kubectl apply -f config/crds/bases/application-networking.k8s.aws_latticeservicestatuses.yaml
+kubectl apply -f config/crds/bases/application-networking.k8s.aws_serviceimportpolicies.yaml
 kubens aws-application-networking-system
\ No newline at end of file
@@ -64,10 +64,14 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&AccessLogPolicyList{},
 		&IAMAuthPolicy{},
 		&IAMAuthPolicyList{},
+		&LatticeServiceStatus{},
+		&LatticeServiceStatusList{},
 		&ServiceExport{},
 		&ServiceExportList{},
 		&ServiceImport{},
 		&ServiceImportList{},
+		&ServiceImportPolicy{},
+		&ServiceImportPolicyList{},
 		&TargetGroupPolicy{},
 		&TargetGroupPolicyList{},
 		&VpcAssociationPolicy{},
@@ -0,0 +1,151 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gwv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+const (
+	LatticeServiceStatusKind = "LatticeServiceStatus"
+)
+
+// +genclient
+// +kubebuilder:object:root=true
+
+// LatticeServiceStatus reports the VPC Lattice resources the controller created for a route.
+// It is created and owned by the controller, one per route, with the same name and namespace as the route.
+//
+// +kubebuilder:resource:categories=gateway-api,shortName=lss
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="Kind",type=string,JSONPath=`.spec.routeRef.kind`
+// +kubebuilder:printcolumn:name="DNS",type=string,JSONPath=`.status.dnsName`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// +kubebuilder:subresource:status
+type LatticeServiceStatus struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec LatticeServiceStatusSpec `json:"spec"`
+
+	// +optional
+	Status LatticeServiceObservedStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// LatticeServiceStatusList contains a list of LatticeServiceStatuses.
+type LatticeServiceStatusList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []LatticeServiceStatus `json:"items"`
+}
+
+// LatticeServiceStatusSpec identifies the route the Lattice resources belong to.
+type LatticeServiceStatusSpec struct {
+	// RouteRef is the route in the same namespace which the Lattice service was created for.
+	RouteRef LatticeServiceRouteReference `json:"routeRef"`
+}
+
+type LatticeServiceRouteReference struct {
+	// Kind is the kind of the route, e.g. HTTPRoute.
+	Kind gwv1.Kind `json:"kind"`
+
+	// Name is the name of the route.
+	Name gwv1.ObjectName `json:"name"`
+}
+
+// LatticeServiceObservedStatus is the state of the Lattice resources after the last successful deployment.
+type LatticeServiceObservedStatus struct {
+	// ObservedGeneration is the generation of the route this status was built from.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// LastUpdateTime is the last time any of the reported values changed.
+	// +optional
+	LastUpdateTime *metav1.Time `json:"lastUpdateTime,omitempty"`
+
+	// ServiceName is the name of the Lattice service.
+	// +optional
+	ServiceName string `json:"serviceName,omitempty"`
+
+	// ServiceArn is the ARN of the Lattice service.
+	// +optional
+	ServiceArn string `json:"serviceArn,omitempty"`
+
+	// ServiceId is the id of the Lattice service.
+	// +optional
+	ServiceId string `json:"serviceId,omitempty"`
+
+	// DnsName is the domain name Lattice assigned to the service.
+	// +optional
+	DnsName string `json:"dnsName,omitempty"`
+
+	// CustomDomainName is the custom domain name of the service, if any.
+	// +optional
+	CustomDomainName string `json:"customDomainName,omitempty"`
+
+	// Listeners of the Lattice service, ordered by port.
+	// +optional
+	Listeners []LatticeListenerStatus `json:"listeners,omitempty"`
+
+	// TargetGroups created for the route backends, ordered by name.
+	// Target groups of ServiceImport backends are owned by the exporting cluster and are not listed.
+	// +optional
+	TargetGroups []LatticeTargetGroupStatus `json:"targetGroups,omitempty"`
+}
+
+type LatticeListenerStatus struct {
+	Name     string `json:"name"`
+	Id       string `json:"id"`
+	Arn      string `json:"arn"`
+	Port     int64  `json:"port"`
+	Protocol string `json:"protocol"`
+
+	// Rules of the listener, ordered by priority.
+	// +optional
+	Rules []LatticeRuleStatus `json:"rules,omitempty"`
+}
+
+type LatticeRuleStatus struct {
+	Name     string `json:"name"`
+	Id       string `json:"id"`
+	Arn      string `json:"arn"`
+	Priority int64  `json:"priority"`
+
+	// TargetGroupIds are the ids of the target groups this rule forwards to.
+	// +optional
+	TargetGroupIds []string `json:"targetGroupIds,omitempty"`
+}
+
+type LatticeTargetGroupStatus struct {
+	Name string `json:"name"`
+	Id   string `json:"id"`
+	Arn  string `json:"arn"`
+
+	// ServiceName is the name of the Kubernetes Service backing the target group.
+	ServiceName string `json:"serviceName"`
+
+	// ServiceNamespace is the namespace of the Kubernetes Service backing the target group.
+	ServiceNamespace string `json:"serviceNamespace"`
+
+	Protocol string `json:"protocol"`
+
+	// +optional
+	ProtocolVersion string `json:"protocolVersion,omitempty"`
+
+	// TargetHealth counts the registered targets per Lattice target status.
+	// +optional
+	TargetHealth *LatticeTargetHealth `json:"targetHealth,omitempty"`
+}
+
+type LatticeTargetHealth struct {
+	Healthy     int32 `json:"healthy"`
+	Unhealthy   int32 `json:"unhealthy"`
+	Initial     int32 `json:"initial"`
+	Unavailable int32 `json:"unavailable"`
+	Unused      int32 `json:"unused"`
+	Draining    int32 `json:"draining"`
+
+	// ReasonCodes counts the targets per health check reason code, e.g. HealthCheckFailed.
+	// +optional
+	ReasonCodes map[string]int32 `json:"reasonCodes,omitempty"`
+}
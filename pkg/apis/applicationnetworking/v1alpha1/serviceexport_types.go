@@ -26,6 +26,8 @@ import (
 
 // ServiceExport declares that the Service with the same name and namespace
 // as this export should be consumable from other clusters.
+//
+// +kubebuilder:subresource:status
 type ServiceExport struct {
 	apimachineryv1.TypeMeta `json:",inline"`
 	// +optional
@@ -64,6 +66,14 @@ const (
 	// Users should not expect detailed per-cluster information in the
 	// conflict message.
 	ServiceExportConflict ServiceExportConditionType = "Conflict"
+	// ServiceExportTargetsHealthy reports the health of the exported
+	// Service's targets in the VPC Lattice target group. The message
+	// contains the number of targets per status and health check reason codes.
+	ServiceExportTargetsHealthy ServiceExportConditionType = "TargetsHealthy"
+	// ServiceExportExported means that the VPC Lattice target groups of
+	// the exported Service ports are deployed. When "True", the condition
+	// message contains the target group ARNs.
+	ServiceExportExported ServiceExportConditionType = "Exported"
 )
 
 // ServiceExportCondition contains details for the current condition of this
@@ -25,6 +25,8 @@ import (
 // +kubebuilder:object:root=true
 
 // ServiceImport describes a service imported from clusters in a ClusterSet.
+//
+// +kubebuilder:subresource:status
 type ServiceImport struct {
 	apimachineryv1.TypeMeta `json:",inline"`
 	// +optional
@@ -71,6 +73,11 @@ type ServiceImportSpec struct {
 	// sessionAffinityConfig contains session affinity configuration.
 	// +optional
 	SessionAffinityConfig *corev1.SessionAffinityConfig `json:"sessionAffinityConfig,omitempty"`
+	// awsAccountId is the AWS account exporting the service, when not the account of the controller.
+	// Takes precedence over the application-networking.k8s.aws/aws-account-id annotation.
+	// +kubebuilder:validation:Pattern=`^[0-9]{12}$`
+	// +optional
+	AwsAccountId string `json:"awsAccountId,omitempty"`
 }
 
 // ServicePort represents the port on which the service is exposed
@@ -112,6 +119,19 @@ type ServiceImportStatus struct {
 	// +listType=map
 	// +listMapKey=cluster
 	Clusters []ClusterStatus `json:"clusters,omitempty"`
+
+	// Conditions describe the current conditions of the ServiceImport.
+	//
+	// Known condition types are:
+	//
+	// * "Exported"
+	// * "PortsValid"
+	//
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	// +kubebuilder:validation:MaxItems=8
+	Conditions []apimachineryv1.Condition `json:"conditions,omitempty"`
 }
 
 // ClusterStatus contains service configuration mapped to a specific source cluster
@@ -119,6 +139,32 @@ type ClusterStatus struct {
 	// cluster is the name of the exporting cluster. Must be a valid RFC-1123 DNS
 	// label.
 	Cluster string `json:"cluster"`
+	// vpcId is the VPC of the target groups exported by the cluster.
+	// +optional
+	VpcId string `json:"vpcId,omitempty"`
+}
+
+const (
+	// ServiceImportExported is True when at least one cluster exports the service.
+	ServiceImportExported = "Exported"
+	// ServiceImportPortsValid is False when a port of the spec is not exported by any cluster.
+	ServiceImportPortsValid = "PortsValid"
+	// ServiceImportShared is False when the target groups exported from another account are not shared with
+	// the account of the controller, or cannot be looked up in that account.
+	ServiceImportShared = "Shared"
+)
+
+// ServiceImportAccountAnnotation is the AWS account exporting the service, when not the account of the controller.
+// Superseded by spec.awsAccountId.
+const ServiceImportAccountAnnotation = "application-networking.k8s.aws/aws-account-id"
+
+// AccountId returns the AWS account exporting the service, from the spec or else the annotation,
+// empty for the account of the controller
+func (s *ServiceImport) AccountId() string {
+	if s.Spec.AwsAccountId != "" {
+		return s.Spec.AwsAccountId
+	}
+	return s.Annotations[ServiceImportAccountAnnotation]
 }
 
 // +kubebuilder:object:root=true
@@ -0,0 +1,31 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apimachineryv1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestServiceImportAccountId(t *testing.T) {
+	annotated := apimachineryv1.ObjectMeta{
+		Annotations: map[string]string{ServiceImportAccountAnnotation: "111122223333"},
+	}
+
+	tests := []struct {
+		name      string
+		svcImport ServiceImport
+		want      string
+	}{
+		{"account of the controller", ServiceImport{}, ""},
+		{"annotation", ServiceImport{ObjectMeta: annotated}, "111122223333"},
+		{"spec", ServiceImport{Spec: ServiceImportSpec{AwsAccountId: "444455556666"}}, "444455556666"},
+		{"spec over annotation", ServiceImport{ObjectMeta: annotated, Spec: ServiceImportSpec{AwsAccountId: "444455556666"}}, "444455556666"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.svcImport.AccountId())
+		})
+	}
+}
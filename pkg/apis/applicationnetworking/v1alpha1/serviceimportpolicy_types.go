@@ -0,0 +1,126 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gwv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+)
+
+const (
+	ServiceImportPolicyKind = "ServiceImportPolicy"
+)
+
+// +genclient
+// +kubebuilder:object:root=true
+
+// ServiceImportPolicy splits the traffic of the route backendRefs to a ServiceImport across the clusters
+// exporting the service, by weight or as primary and standby clusters.
+//
+// +kubebuilder:resource:categories=gateway-api,shortName=sip
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="Mode",type=string,JSONPath=`.spec.mode`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// +kubebuilder:subresource:status
+type ServiceImportPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ServiceImportPolicySpec `json:"spec"`
+
+	// Status defines the current state of ServiceImportPolicy.
+	//
+	// +kubebuilder:default={conditions: {{type: "Accepted", status: "Unknown", reason:"NotReconciled", message:"Waiting for controller", lastTransitionTime: "1970-01-01T00:00:00Z"}}}
+	Status ServiceImportPolicyStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// ServiceImportPolicyList contains a list of ServiceImportPolicies.
+type ServiceImportPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ServiceImportPolicy `json:"items"`
+}
+
+// ServiceImportPolicySpec defines the desired state of ServiceImportPolicy.
+type ServiceImportPolicySpec struct {
+	// TargetRef points to the ServiceImport resource that will have this policy attached.
+	//
+	// This field is following the guidelines of Kubernetes Gateway API policy attachment.
+	TargetRef *gwv1alpha2.NamespacedPolicyTargetReference `json:"targetRef"`
+
+	// Mode is how the traffic of a backendRef is split across the clusters.
+	// Weighted sends traffic to every cluster in proportion to its weight.
+	// Failover sends all traffic to the first cluster of the list with healthy targets, or to the
+	// first cluster when none has healthy targets.
+	//
+	// +optional
+	// +kubebuilder:default=Weighted
+	Mode *ServiceImportRoutingMode `json:"mode,omitempty"`
+
+	// Clusters are the exporting clusters receiving the traffic, in priority order for the Failover mode.
+	// Clusters not in the list receive no traffic.
+	//
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=10
+	Clusters []ServiceImportClusterTarget `json:"clusters"`
+}
+
+// ServiceImportClusterTarget is a cluster exporting the service of the ServiceImport.
+type ServiceImportClusterTarget struct {
+	// Name is the cluster name of the exporting cluster, the CLUSTER_NAME of its controller.
+	//
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=100
+	Name string `json:"name"`
+
+	// Weight is the proportion of the traffic sent to the cluster in the Weighted mode. The weight of
+	// the Lattice target group of the cluster is the backendRef weight multiplied by this weight, the weights
+	// of the rule being scaled down together when one is above 999.
+	// Ignored in the Failover mode.
+	//
+	// +optional
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=999
+	Weight *int64 `json:"weight,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=Weighted;Failover
+type ServiceImportRoutingMode string
+
+const (
+	ServiceImportRoutingModeWeighted ServiceImportRoutingMode = "Weighted"
+	ServiceImportRoutingModeFailover ServiceImportRoutingMode = "Failover"
+)
+
+// ServiceImportPolicyStatus defines the observed state of ServiceImportPolicy.
+type ServiceImportPolicyStatus struct {
+	// Conditions describe the current conditions of the ServiceImportPolicy.
+	//
+	// Known condition types are:
+	//
+	// * "Accepted"
+	//
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	// +kubebuilder:validation:MaxItems=8
+	// +kubebuilder:default={{type: "Accepted", status: "Unknown", reason:"Pending", message:"Waiting for controller", lastTransitionTime: "1970-01-01T00:00:00Z"}}
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+func (p *ServiceImportPolicy) GetTargetRef() *gwv1alpha2.NamespacedPolicyTargetReference {
+	return p.Spec.TargetRef
+}
+
+func (p *ServiceImportPolicy) GetStatusConditions() *[]metav1.Condition {
+	return &p.Status.Conditions
+}
+
+func (pl *ServiceImportPolicyList) GetItems() []*ServiceImportPolicy {
+	return toPtrSlice(pl.Items)
+}
+
+// IsFailover returns whether the traffic goes to the first cluster with healthy targets
+func (p *ServiceImportPolicy) IsFailover() bool {
+	return p.Spec.Mode != nil && *p.Spec.Mode == ServiceImportRoutingModeFailover
+}
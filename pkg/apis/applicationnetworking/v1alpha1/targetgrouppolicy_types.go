@@ -7,6 +7,11 @@ import (
 
 const (
 	TargetGroupPolicyKind = "TargetGroupPolicy"
+
+	// TargetGroupPolicyReplaced reports the replacement of the target groups of the policy after a change of
+	// immutable fields: False while the new target groups wait for healthy targets, True once traffic shifted,
+	// with the reason ReplacementCompleted once the replaced target groups no longer receive traffic.
+	TargetGroupPolicyReplaced = "TargetGroupsReplaced"
 )
 
 // +genclient
@@ -62,6 +67,45 @@ type TargetGroupPolicySpec struct {
 	// Changes to this value will update VPC Lattice resource in place.
 	// +optional
 	HealthCheck *HealthCheckConfig `json:"healthCheck,omitempty"`
+
+	// PortOverrides replace the protocol, protocol version and health check settings of the policy for the
+	// target groups of given Service ports, e.g. HTTP1 health checks on an admin port and GRPC on an API port.
+	// The first override matching the port applies. Health check fields it leaves unset are taken from the policy.
+	// +optional
+	// +listType=atomic
+	// +kubebuilder:validation:MaxItems=16
+	PortOverrides []TargetGroupPortOverride `json:"portOverrides,omitempty"`
+}
+
+// TargetGroupPortOverride defines the target group configuration of a Service port, selected by sectionName
+// or port.
+type TargetGroupPortOverride struct {
+	// SectionName is the name of the Service port.
+	// +optional
+	SectionName *gwv1alpha2.SectionName `json:"sectionName,omitempty"`
+
+	// Port is the number of the Service port, when SectionName is not set.
+	// +optional
+	Port *gwv1alpha2.PortNumber `json:"port,omitempty"`
+
+	// The protocol to use for routing traffic to the targets of the port. When set, the protocolVersion of the
+	// policy is not inherited.
+	//
+	// Changes to this value results in a replacement of VPC Lattice target group.
+	// +optional
+	Protocol *string `json:"protocol,omitempty"`
+
+	// The protocol version to use for the port.
+	//
+	// Changes to this value results in a replacement of VPC Lattice target group.
+	// +optional
+	ProtocolVersion *string `json:"protocolVersion,omitempty"`
+
+	// The health check configuration of the port, merged with the health check of the policy.
+	//
+	// Changes to this value will update VPC Lattice resource in place.
+	// +optional
+	HealthCheck *HealthCheckConfig `json:"healthCheck,omitempty"`
 }
 
 // HealthCheckConfig defines health check configuration for given VPC Lattice target group.
@@ -138,6 +182,29 @@ type TargetGroupPolicyStatus struct {
 	// +kubebuilder:validation:MaxItems=8
 	// +kubebuilder:default={{type: "Accepted", status: "Unknown", reason:"Pending", message:"Waiting for controller", lastTransitionTime: "1970-01-01T00:00:00Z"},{type: "Programmed", status: "Unknown", reason:"Pending", message:"Waiting for controller", lastTransitionTime: "1970-01-01T00:00:00Z"}}
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Replacements are the target groups of routes being replaced after a change of immutable fields,
+	// which the TargetGroupsReplaced condition reports on.
+	//
+	// +optional
+	// +kubebuilder:validation:MaxItems=100
+	Replacements []TargetGroupReplacement `json:"replacements,omitempty"`
+}
+
+// TargetGroupReplacement is the replacement of a target group of a route forwarding to the Service.
+type TargetGroupReplacement struct {
+	// Route is the route whose rules forward to the target groups, as kind/namespace/name.
+	Route string `json:"route"`
+
+	// Id is the id of the new target group.
+	Id string `json:"id"`
+
+	// ReplacedId is the id of the target group being replaced.
+	ReplacedId string `json:"replacedId"`
+
+	// Ready is true once the traffic shifted to the new target group.
+	// +optional
+	Ready bool `json:"ready,omitempty"`
 }
 
 // +kubebuilder:validation:Enum=HTTP;HTTPS
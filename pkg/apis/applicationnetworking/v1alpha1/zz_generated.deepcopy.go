@@ -298,6 +298,213 @@ func (in *IAMAuthPolicyStatus) DeepCopy() *IAMAuthPolicyStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LatticeListenerStatus) DeepCopyInto(out *LatticeListenerStatus) {
+	*out = *in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]LatticeRuleStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LatticeListenerStatus.
+func (in *LatticeListenerStatus) DeepCopy() *LatticeListenerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LatticeListenerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LatticeRuleStatus) DeepCopyInto(out *LatticeRuleStatus) {
+	*out = *in
+	if in.TargetGroupIds != nil {
+		in, out := &in.TargetGroupIds, &out.TargetGroupIds
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LatticeRuleStatus.
+func (in *LatticeRuleStatus) DeepCopy() *LatticeRuleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LatticeRuleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LatticeServiceObservedStatus) DeepCopyInto(out *LatticeServiceObservedStatus) {
+	*out = *in
+	if in.LastUpdateTime != nil {
+		in, out := &in.LastUpdateTime, &out.LastUpdateTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Listeners != nil {
+		in, out := &in.Listeners, &out.Listeners
+		*out = make([]LatticeListenerStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TargetGroups != nil {
+		in, out := &in.TargetGroups, &out.TargetGroups
+		*out = make([]LatticeTargetGroupStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LatticeServiceObservedStatus.
+func (in *LatticeServiceObservedStatus) DeepCopy() *LatticeServiceObservedStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LatticeServiceObservedStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LatticeServiceRouteReference) DeepCopyInto(out *LatticeServiceRouteReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LatticeServiceRouteReference.
+func (in *LatticeServiceRouteReference) DeepCopy() *LatticeServiceRouteReference {
+	if in == nil {
+		return nil
+	}
+	out := new(LatticeServiceRouteReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LatticeServiceStatus) DeepCopyInto(out *LatticeServiceStatus) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LatticeServiceStatus.
+func (in *LatticeServiceStatus) DeepCopy() *LatticeServiceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LatticeServiceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *LatticeServiceStatus) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LatticeServiceStatusList) DeepCopyInto(out *LatticeServiceStatusList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]LatticeServiceStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LatticeServiceStatusList.
+func (in *LatticeServiceStatusList) DeepCopy() *LatticeServiceStatusList {
+	if in == nil {
+		return nil
+	}
+	out := new(LatticeServiceStatusList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *LatticeServiceStatusList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LatticeServiceStatusSpec) DeepCopyInto(out *LatticeServiceStatusSpec) {
+	*out = *in
+	out.RouteRef = in.RouteRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LatticeServiceStatusSpec.
+func (in *LatticeServiceStatusSpec) DeepCopy() *LatticeServiceStatusSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LatticeServiceStatusSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LatticeTargetGroupStatus) DeepCopyInto(out *LatticeTargetGroupStatus) {
+	*out = *in
+	if in.TargetHealth != nil {
+		in, out := &in.TargetHealth, &out.TargetHealth
+		*out = new(LatticeTargetHealth)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LatticeTargetGroupStatus.
+func (in *LatticeTargetGroupStatus) DeepCopy() *LatticeTargetGroupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LatticeTargetGroupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LatticeTargetHealth) DeepCopyInto(out *LatticeTargetHealth) {
+	*out = *in
+	if in.ReasonCodes != nil {
+		in, out := &in.ReasonCodes, &out.ReasonCodes
+		*out = make(map[string]int32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LatticeTargetHealth.
+func (in *LatticeTargetHealth) DeepCopy() *LatticeTargetHealth {
+	if in == nil {
+		return nil
+	}
+	out := new(LatticeTargetHealth)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServiceExport) DeepCopyInto(out *ServiceExport) {
 	*out = *in
@@ -434,6 +641,26 @@ func (in *ServiceImport) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceImportClusterTarget) DeepCopyInto(out *ServiceImportClusterTarget) {
+	*out = *in
+	if in.Weight != nil {
+		in, out := &in.Weight, &out.Weight
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceImportClusterTarget.
+func (in *ServiceImportClusterTarget) DeepCopy() *ServiceImportClusterTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceImportClusterTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServiceImportList) DeepCopyInto(out *ServiceImportList) {
 	*out = *in
@@ -466,6 +693,119 @@ func (in *ServiceImportList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceImportPolicy) DeepCopyInto(out *ServiceImportPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceImportPolicy.
+func (in *ServiceImportPolicy) DeepCopy() *ServiceImportPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceImportPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ServiceImportPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceImportPolicyList) DeepCopyInto(out *ServiceImportPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ServiceImportPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceImportPolicyList.
+func (in *ServiceImportPolicyList) DeepCopy() *ServiceImportPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceImportPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ServiceImportPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceImportPolicySpec) DeepCopyInto(out *ServiceImportPolicySpec) {
+	*out = *in
+	if in.TargetRef != nil {
+		in, out := &in.TargetRef, &out.TargetRef
+		*out = new(v1alpha2.NamespacedPolicyTargetReference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Mode != nil {
+		in, out := &in.Mode, &out.Mode
+		*out = new(ServiceImportRoutingMode)
+		**out = **in
+	}
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]ServiceImportClusterTarget, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceImportPolicySpec.
+func (in *ServiceImportPolicySpec) DeepCopy() *ServiceImportPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceImportPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceImportPolicyStatus) DeepCopyInto(out *ServiceImportPolicyStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceImportPolicyStatus.
+func (in *ServiceImportPolicyStatus) DeepCopy() *ServiceImportPolicyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceImportPolicyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServiceImportSpec) DeepCopyInto(out *ServiceImportSpec) {
 	*out = *in
@@ -506,6 +846,13 @@ func (in *ServiceImportStatus) DeepCopyInto(out *ServiceImportStatus) {
 		*out = make([]ClusterStatus, len(*in))
 		copy(*out, *in)
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceImportStatus.
@@ -620,6 +967,13 @@ func (in *TargetGroupPolicySpec) DeepCopyInto(out *TargetGroupPolicySpec) {
 		*out = new(HealthCheckConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.PortOverrides != nil {
+		in, out := &in.PortOverrides, &out.PortOverrides
+		*out = make([]TargetGroupPortOverride, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TargetGroupPolicySpec.
@@ -642,6 +996,11 @@ func (in *TargetGroupPolicyStatus) DeepCopyInto(out *TargetGroupPolicyStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Replacements != nil {
+		in, out := &in.Replacements, &out.Replacements
+		*out = make([]TargetGroupReplacement, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TargetGroupPolicyStatus.
@@ -654,6 +1013,61 @@ func (in *TargetGroupPolicyStatus) DeepCopy() *TargetGroupPolicyStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TargetGroupReplacement) DeepCopyInto(out *TargetGroupReplacement) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TargetGroupReplacement.
+func (in *TargetGroupReplacement) DeepCopy() *TargetGroupReplacement {
+	if in == nil {
+		return nil
+	}
+	out := new(TargetGroupReplacement)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TargetGroupPortOverride) DeepCopyInto(out *TargetGroupPortOverride) {
+	*out = *in
+	if in.SectionName != nil {
+		in, out := &in.SectionName, &out.SectionName
+		*out = new(v1alpha2.SectionName)
+		**out = **in
+	}
+	if in.Port != nil {
+		in, out := &in.Port, &out.Port
+		*out = new(v1alpha2.PortNumber)
+		**out = **in
+	}
+	if in.Protocol != nil {
+		in, out := &in.Protocol, &out.Protocol
+		*out = new(string)
+		**out = **in
+	}
+	if in.ProtocolVersion != nil {
+		in, out := &in.ProtocolVersion, &out.ProtocolVersion
+		*out = new(string)
+		**out = **in
+	}
+	if in.HealthCheck != nil {
+		in, out := &in.HealthCheck, &out.HealthCheck
+		*out = new(HealthCheckConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TargetGroupPortOverride.
+func (in *TargetGroupPortOverride) DeepCopy() *TargetGroupPortOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(TargetGroupPortOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VpcAssociationPolicy) DeepCopyInto(out *VpcAssociationPolicy) {
 	*out = *in
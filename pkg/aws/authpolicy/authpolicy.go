@@ -0,0 +1,446 @@
+// Package authpolicy parses and validates VPC Lattice auth policies, and evaluates them locally.
+// Validation covers the subset of the IAM policy grammar that Lattice accepts, so that mistakes are
+// reported with their location in the document instead of as an opaque PutAuthPolicy error.
+package authpolicy
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// MaxPolicySize is the Lattice limit on the size of an auth policy, in characters
+	MaxPolicySize = 10240
+
+	ActionPrefix  = "vpc-lattice-svcs:"
+	ActionInvoke  = ActionPrefix + "Invoke"
+	ActionConnect = ActionPrefix + "Connect"
+
+	EffectAllow = "Allow"
+	EffectDeny  = "Deny"
+)
+
+var (
+	versions      = []string{"2012-10-17", "2008-10-17"}
+	actions       = []string{ActionInvoke, ActionConnect}
+	principalKeys = []string{"AWS", "Service", "Federated", "CanonicalUser"}
+
+	// Lattice specific condition keys, the ones ending with "/" are followed by a header or query parameter name
+	latticeConditionKeys = []string{
+		"Port",
+		"RequestMethod",
+		"RequestHeader/",
+		"RequestQueryString/",
+		"ServiceArn",
+		"ServiceNetworkArn",
+		"SourceVpc",
+		"SourceVpcOwnerAccount",
+	}
+
+	conditionOperators = []string{
+		"StringEquals", "StringNotEquals", "StringEqualsIgnoreCase", "StringNotEqualsIgnoreCase",
+		"StringLike", "StringNotLike",
+		"NumericEquals", "NumericNotEquals", "NumericLessThan", "NumericLessThanEquals",
+		"NumericGreaterThan", "NumericGreaterThanEquals",
+		"DateEquals", "DateNotEquals", "DateLessThan", "DateLessThanEquals",
+		"DateGreaterThan", "DateGreaterThanEquals",
+		"Bool", "BinaryEquals",
+		"IpAddress", "NotIpAddress",
+		"ArnEquals", "ArnLike", "ArnNotEquals", "ArnNotLike",
+		"Null",
+	}
+)
+
+// Document is a parsed auth policy
+type Document struct {
+	Version    string
+	Id         string
+	Statements []Statement
+}
+
+type Statement struct {
+	Sid    string
+	Effect string
+
+	// exactly one of Principal and NotPrincipal is set
+	Principal    *Principal
+	NotPrincipal *Principal
+
+	// exactly one of Action and NotAction is set
+	Action    []string
+	NotAction []string
+
+	// at most one of Resource and NotResource is set, none of them matches every resource
+	Resource    []string
+	NotResource []string
+
+	Conditions []Condition
+}
+
+// Principal is either "*" or principals per type, e.g. "AWS" or "Service". A principal
+// written as a plain string other than "*" is an AWS principal.
+type Principal struct {
+	Any    bool
+	Values map[string][]string
+}
+
+type Condition struct {
+	// Operator as written, including ForAnyValue:/ForAllValues: prefix and IfExists suffix
+	Operator string
+	Key      string
+	Values   []string
+}
+
+// Parse parses and validates an auth policy
+func Parse(policy string) (*Document, error) {
+	if size := utf8.RuneCountInString(policy); size > MaxPolicySize {
+		return nil, &Error{Msg: fmt.Sprintf("policy has %d characters, more than the limit of %d", size, MaxPolicySize)}
+	}
+	data := []byte(policy)
+	root, err := parseJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	v := &validator{data: data}
+	doc := v.document(root)
+	if v.err != nil {
+		return nil, v.err
+	}
+	return doc, nil
+}
+
+// validator walks the JSON tree, keeping the first grammar error
+type validator struct {
+	data []byte
+	err  *Error
+}
+
+func (v *validator) fail(offset int, format string, args ...any) {
+	if v.err == nil {
+		v.err = newError(v.data, offset, format, args...)
+	}
+}
+
+func (v *validator) object(n *node, name string) *object {
+	obj, ok := n.value.(*object)
+	if !ok {
+		v.fail(n.offset, "%s must be an object, but was %s", name, typeName(n))
+		return nil
+	}
+	return obj
+}
+
+func (v *validator) string(n *node, name string) string {
+	s, ok := n.value.(string)
+	if !ok {
+		v.fail(n.offset, "%s must be a string, but was %s", name, typeName(n))
+	}
+	return s
+}
+
+// strings accepts a string or an array of strings
+func (v *validator) strings(n *node, name string) []string {
+	if s, ok := n.value.(string); ok {
+		return []string{s}
+	}
+	items, ok := n.value.([]*node)
+	if !ok {
+		v.fail(n.offset, "%s must be a string or an array of strings, but was %s", name, typeName(n))
+		return nil
+	}
+	if len(items) == 0 {
+		v.fail(n.offset, "%s must not be empty", name)
+	}
+	var values []string
+	for _, item := range items {
+		values = append(values, v.string(item, name))
+	}
+	return values
+}
+
+// conditionValues accepts a string, a boolean, a number or an array of them
+func (v *validator) conditionValues(n *node, name string) []string {
+	items, ok := n.value.([]*node)
+	if !ok {
+		items = []*node{n}
+	}
+	var values []string
+	for _, item := range items {
+		switch value := item.value.(type) {
+		case string:
+			values = append(values, value)
+		case bool:
+			values = append(values, fmt.Sprint(value))
+		case json.Number:
+			values = append(values, value.String())
+		default:
+			v.fail(item.offset, "%s values must be strings, booleans or numbers, but was %s", name, typeName(item))
+		}
+	}
+	return values
+}
+
+func (v *validator) checkKeys(obj *object, name string, allowed ...string) {
+	for _, key := range obj.keys {
+		if !slices.Contains(allowed, key) {
+			v.fail(obj.keyOffsets[key], "unknown %s element %q, expected one of %s", name, key, strings.Join(allowed, ", "))
+		}
+	}
+}
+
+// exclusive returns the element among two mutually exclusive ones, failing when both are set
+func (v *validator) exclusive(n *node, obj *object, name, notName string, required bool) (*node, bool) {
+	value, hasValue := obj.values[name]
+	notValue, hasNotValue := obj.values[notName]
+	switch {
+	case hasValue && hasNotValue:
+		v.fail(obj.keyOffsets[notName], "statement cannot have both %s and %s", name, notName)
+	case hasValue:
+		return value, false
+	case hasNotValue:
+		return notValue, true
+	case required:
+		v.fail(n.offset, "statement must have %s or %s", name, notName)
+	}
+	return nil, false
+}
+
+func (v *validator) document(n *node) *Document {
+	obj := v.object(n, "policy")
+	if obj == nil {
+		return nil
+	}
+	v.checkKeys(obj, "policy", "Version", "Id", "Statement")
+	doc := &Document{}
+	if version, ok := obj.values["Version"]; ok {
+		doc.Version = v.string(version, "Version")
+		if v.err == nil && !slices.Contains(versions, doc.Version) {
+			v.fail(version.offset, "unsupported Version %q, expected one of %s", doc.Version, strings.Join(versions, ", "))
+		}
+	}
+	if id, ok := obj.values["Id"]; ok {
+		doc.Id = v.string(id, "Id")
+	}
+	statements, ok := obj.values["Statement"]
+	if !ok {
+		v.fail(n.offset, "policy must have a Statement")
+		return doc
+	}
+	items, ok := statements.value.([]*node)
+	if !ok {
+		items = []*node{statements}
+	}
+	if len(items) == 0 {
+		v.fail(statements.offset, "Statement must not be empty")
+	}
+	for _, item := range items {
+		doc.Statements = append(doc.Statements, v.statement(item))
+	}
+	return doc
+}
+
+func (v *validator) statement(n *node) Statement {
+	s := Statement{}
+	obj := v.object(n, "Statement")
+	if obj == nil {
+		return s
+	}
+	v.checkKeys(obj, "statement", "Sid", "Effect", "Principal", "NotPrincipal",
+		"Action", "NotAction", "Resource", "NotResource", "Condition")
+
+	if sid, ok := obj.values["Sid"]; ok {
+		s.Sid = v.string(sid, "Sid")
+	}
+	if effect, ok := obj.values["Effect"]; !ok {
+		v.fail(n.offset, "statement must have an Effect")
+	} else {
+		s.Effect = v.string(effect, "Effect")
+		if v.err == nil && s.Effect != EffectAllow && s.Effect != EffectDeny {
+			v.fail(effect.offset, "Effect must be %s or %s, but was %q", EffectAllow, EffectDeny, s.Effect)
+		}
+	}
+
+	if principal, not := v.exclusive(n, obj, "Principal", "NotPrincipal", true); principal != nil {
+		if not {
+			s.NotPrincipal = v.principal(principal, "NotPrincipal")
+		} else {
+			s.Principal = v.principal(principal, "Principal")
+		}
+	}
+
+	if action, not := v.exclusive(n, obj, "Action", "NotAction", true); action != nil {
+		name := "Action"
+		if not {
+			name = "NotAction"
+		}
+		values := v.strings(action, name)
+		for _, value := range values {
+			if !v.isLatticeAction(value) {
+				v.fail(action.offset, "%s %q is not a VPC Lattice action, expected %s, %s or %s*",
+					name, value, ActionInvoke, ActionConnect, ActionPrefix)
+			}
+		}
+		if not {
+			s.NotAction = values
+		} else {
+			s.Action = values
+		}
+	}
+
+	if resource, not := v.exclusive(n, obj, "Resource", "NotResource", false); resource != nil {
+		name := "Resource"
+		if not {
+			name = "NotResource"
+		}
+		values := v.strings(resource, name)
+		for _, value := range values {
+			if value != "*" && !strings.HasPrefix(value, "arn:") {
+				v.fail(resource.offset, "%s %q must be \"*\" or an ARN", name, value)
+			}
+		}
+		if not {
+			s.NotResource = values
+		} else {
+			s.Resource = values
+		}
+	}
+
+	if condition, ok := obj.values["Condition"]; ok {
+		s.Conditions = v.conditions(condition)
+	}
+	return s
+}
+
+func (v *validator) isLatticeAction(action string) bool {
+	if action == "*" {
+		return true
+	}
+	for _, known := range actions {
+		if wildcardMatch(strings.ToLower(action), strings.ToLower(known)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (v *validator) principal(n *node, name string) *Principal {
+	if s, ok := n.value.(string); ok {
+		if s == "*" {
+			return &Principal{Any: true}
+		}
+		// Lattice accepts a single AWS principal, e.g. an account, without its type
+		return &Principal{Values: map[string][]string{"AWS": {s}}}
+	}
+	obj := v.object(n, name)
+	if obj == nil {
+		return nil
+	}
+	if len(obj.keys) == 0 {
+		v.fail(n.offset, "%s must not be empty", name)
+	}
+	v.checkKeys(obj, name, principalKeys...)
+	p := &Principal{Values: map[string][]string{}}
+	for _, key := range obj.keys {
+		p.Values[key] = v.strings(obj.values[key], name+"."+key)
+	}
+	return p
+}
+
+func (v *validator) conditions(n *node) []Condition {
+	obj := v.object(n, "Condition")
+	if obj == nil {
+		return nil
+	}
+	var conditions []Condition
+	for _, operator := range obj.keys {
+		if _, ok := parseOperator(operator); !ok {
+			v.fail(obj.keyOffsets[operator], "unknown condition operator %q", operator)
+			continue
+		}
+		keys := v.object(obj.values[operator], operator)
+		if keys == nil {
+			continue
+		}
+		for _, key := range keys.keys {
+			if err := validateConditionKey(key); err != "" {
+				v.fail(keys.keyOffsets[key], "%s", err)
+			}
+			conditions = append(conditions, Condition{
+				Operator: operator,
+				Key:      key,
+				Values:   v.conditionValues(keys.values[key], operator+"."+key),
+			})
+		}
+	}
+	return conditions
+}
+
+// validateConditionKey returns why a condition key is not supported by Lattice, or an empty string
+func validateConditionKey(key string) string {
+	lower := strings.ToLower(key)
+	switch {
+	case strings.HasPrefix(lower, "aws:"):
+		return ""
+	case strings.HasPrefix(lower, strings.ToLower(ActionPrefix)):
+		name := lower[len(ActionPrefix):]
+		for _, known := range latticeConditionKeys {
+			known = strings.ToLower(known)
+			if name == known || (strings.HasSuffix(known, "/") && strings.HasPrefix(name, known) && len(name) > len(known)) {
+				return ""
+			}
+		}
+		return fmt.Sprintf("unknown condition key %q, expected one of %s%s", key, ActionPrefix,
+			strings.Join(latticeConditionKeys, ", "+ActionPrefix))
+	default:
+		return fmt.Sprintf("unsupported condition key %q, expected a global aws: key or a %s key", key, ActionPrefix)
+	}
+}
+
+// operator is a condition operator without its set prefix and IfExists suffix
+type operator struct {
+	name     string
+	forAll   bool
+	forAny   bool
+	ifExists bool
+}
+
+func parseOperator(s string) (operator, bool) {
+	op := operator{name: s}
+	if rest, ok := strings.CutPrefix(op.name, "ForAllValues:"); ok {
+		op.name, op.forAll = rest, true
+	} else if rest, ok := strings.CutPrefix(op.name, "ForAnyValue:"); ok {
+		op.name, op.forAny = rest, true
+	}
+	if rest, ok := strings.CutSuffix(op.name, "IfExists"); ok {
+		op.name, op.ifExists = rest, true
+	}
+	return op, slices.Contains(conditionOperators, op.name)
+}
+
+// wildcardMatch matches a value against a pattern with * and ? wildcards
+func wildcardMatch(pattern, value string) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case '*':
+			for i := len(value); i >= 0; i-- {
+				if wildcardMatch(pattern[1:], value[i:]) {
+					return true
+				}
+			}
+			return false
+		case '?':
+			if len(value) == 0 {
+				return false
+			}
+		default:
+			if len(value) == 0 || pattern[0] != value[0] {
+				return false
+			}
+		}
+		pattern, value = pattern[1:], value[1:]
+	}
+	return len(value) == 0
+}
@@ -0,0 +1,181 @@
+package authpolicy
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	doc, err := Parse(`{
+  "Version": "2012-10-17",
+  "Statement": [
+    {
+      "Effect": "Allow",
+      "Principal": {"AWS": ["arn:aws:iam::123456789012:role/client", "111111111111"]},
+      "Action": "vpc-lattice-svcs:Invoke",
+      "Resource": "arn:aws:vpc-lattice:us-west-2:123456789012:service/svc-1/*",
+      "Condition": {
+        "StringEqualsIgnoreCase": {"vpc-lattice-svcs:RequestMethod": ["GET", "HEAD"]},
+        "ForAnyValue:StringLike": {"vpc-lattice-svcs:RequestHeader/x-team": "payments-*"},
+        "Bool": {"aws:SecureTransport": true},
+        "NumericLessThanIfExists": {"vpc-lattice-svcs:Port": 8080}
+      }
+    },
+    {"Effect": "Deny", "Principal": "*", "NotAction": "vpc-lattice-svcs:*", "NotResource": "*"}
+  ]
+}`)
+	assert.NoError(t, err)
+	assert.Equal(t, "2012-10-17", doc.Version)
+	assert.Len(t, doc.Statements, 2)
+
+	s := doc.Statements[0]
+	assert.Equal(t, EffectAllow, s.Effect)
+	assert.Equal(t, []string{"arn:aws:iam::123456789012:role/client", "111111111111"}, s.Principal.Values["AWS"])
+	assert.Equal(t, []string{ActionInvoke}, s.Action)
+	assert.Equal(t, []Condition{
+		{Operator: "StringEqualsIgnoreCase", Key: "vpc-lattice-svcs:RequestMethod", Values: []string{"GET", "HEAD"}},
+		{Operator: "ForAnyValue:StringLike", Key: "vpc-lattice-svcs:RequestHeader/x-team", Values: []string{"payments-*"}},
+		{Operator: "Bool", Key: "aws:SecureTransport", Values: []string{"true"}},
+		{Operator: "NumericLessThanIfExists", Key: "vpc-lattice-svcs:Port", Values: []string{"8080"}},
+	}, s.Conditions)
+	assert.True(t, doc.Statements[1].Principal.Any)
+	assert.Equal(t, []string{"*"}, doc.Statements[1].NotResource)
+
+	// a single statement object, with an AWS principal without its type
+	doc, err = Parse(`{"Statement": {"Effect": "Allow", "Principal": "123456789012", "Action": "*"}}`)
+	assert.NoError(t, err)
+	assert.Len(t, doc.Statements, 1)
+	assert.Equal(t, []string{"123456789012"}, doc.Statements[0].Principal.Values["AWS"])
+
+	// the size limit is in characters, not bytes
+	sid := strings.Repeat("é", MaxPolicySize/2)
+	doc, err = Parse(`{"Statement": {"Sid": "` + sid + `", "Effect": "Allow", "Principal": "*", "Action": "*"}}`)
+	assert.NoError(t, err)
+	assert.Equal(t, sid, doc.Statements[0].Sid)
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy string
+		want   string
+	}{
+		{
+			name:   "invalid JSON",
+			policy: "{\n  \"Statement\": [\n    {\"Effect\": \"Allow\",}\n  ]\n}",
+			want:   "line 3, column 24: invalid JSON",
+		},
+		{
+			name:   "truncated JSON",
+			policy: `{"Statement": [`,
+			want:   "line 1, column 16: invalid JSON: unexpected end of JSON input",
+		},
+		{
+			name:   "trailing data",
+			policy: `{"Statement": {"Effect": "Allow", "Principal": "*", "Action": "*"}} {}`,
+			want:   "line 1, column 69: unexpected data after the policy document",
+		},
+		{
+			name:   "not an object",
+			policy: `[]`,
+			want:   "line 1, column 1: policy must be an object, but was an array",
+		},
+		{
+			name:   "unknown element",
+			policy: `{"Version": "2012-10-17", "Statements": []}`,
+			want:   `line 1, column 27: unknown policy element "Statements"`,
+		},
+		{
+			name:   "unsupported version",
+			policy: `{"Version": "2020-01-01", "Statement": []}`,
+			want:   `line 1, column 13: unsupported Version "2020-01-01"`,
+		},
+		{
+			name:   "missing statement",
+			policy: `{"Version": "2012-10-17"}`,
+			want:   "line 1, column 1: policy must have a Statement",
+		},
+		{
+			name:   "duplicate element",
+			policy: `{"Statement": [], "Statement": []}`,
+			want:   `line 1, column 19: duplicate element "Statement"`,
+		},
+		{
+			name:   "typo in effect",
+			policy: "{\"Statement\": [\n  {\"Effect\": \"Alow\", \"Principal\": \"*\", \"Action\": \"*\"}\n]}",
+			want:   `line 2, column 14: Effect must be Allow or Deny, but was "Alow"`,
+		},
+		{
+			name:   "missing principal",
+			policy: `{"Statement": [{"Effect": "Allow", "Action": "*"}]}`,
+			want:   "line 1, column 16: statement must have Principal or NotPrincipal",
+		},
+		{
+			name:   "principal and not principal",
+			policy: `{"Statement": [{"Effect": "Allow", "Principal": "*", "NotPrincipal": "*", "Action": "*"}]}`,
+			want:   "statement cannot have both Principal and NotPrincipal",
+		},
+		{
+			name:   "principal of wrong type",
+			policy: `{"Statement": [{"Effect": "Allow", "Principal": ["*"], "Action": "*"}]}`,
+			want:   "Principal must be an object, but was an array",
+		},
+		{
+			name:   "unknown principal type",
+			policy: `{"Statement": [{"Effect": "Allow", "Principal": {"Users": "bob"}, "Action": "*"}]}`,
+			want:   `unknown Principal element "Users"`,
+		},
+		{
+			name:   "non lattice action",
+			policy: `{"Statement": [{"Effect": "Allow", "Principal": "*", "Action": ["vpc-lattice-svcs:Invoke", "s3:GetObject"]}]}`,
+			want:   `Action "s3:GetObject" is not a VPC Lattice action`,
+		},
+		{
+			name:   "unknown lattice action",
+			policy: `{"Statement": [{"Effect": "Allow", "Principal": "*", "Action": "vpc-lattice-svcs:Invok"}]}`,
+			want:   `Action "vpc-lattice-svcs:Invok" is not a VPC Lattice action`,
+		},
+		{
+			name:   "invalid resource",
+			policy: `{"Statement": [{"Effect": "Allow", "Principal": "*", "Action": "*", "Resource": "svc-1"}]}`,
+			want:   `Resource "svc-1" must be "*" or an ARN`,
+		},
+		{
+			name:   "unknown condition operator",
+			policy: `{"Statement": [{"Effect": "Allow", "Principal": "*", "Action": "*", "Condition": {"StringEqual": {"aws:PrincipalAccount": "1"}}}]}`,
+			want:   `unknown condition operator "StringEqual"`,
+		},
+		{
+			name:   "unknown condition key",
+			policy: `{"Statement": [{"Effect": "Allow", "Principal": "*", "Action": "*", "Condition": {"StringEquals": {"vpc-lattice-svcs:RequestPath": "/"}}}]}`,
+			want:   `unknown condition key "vpc-lattice-svcs:RequestPath"`,
+		},
+		{
+			name:   "condition key of another service",
+			policy: `{"Statement": [{"Effect": "Allow", "Principal": "*", "Action": "*", "Condition": {"StringEquals": {"s3:prefix": "/"}}}]}`,
+			want:   `unsupported condition key "s3:prefix"`,
+		},
+		{
+			name:   "too large",
+			policy: `{"Id": "` + strings.Repeat("a", MaxPolicySize) + `"}`,
+			want:   "more than the limit of 10240",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.policy)
+			assert.ErrorContains(t, err, tt.want)
+			assert.IsType(t, &Error{}, err)
+		})
+	}
+}
+
+func TestWildcardMatch(t *testing.T) {
+	assert.True(t, wildcardMatch("*", ""))
+	assert.True(t, wildcardMatch("svc-*/path", "svc-1/path"))
+	assert.True(t, wildcardMatch("a?c", "abc"))
+	assert.False(t, wildcardMatch("a?c", "ac"))
+	assert.False(t, wildcardMatch("svc-*/path", "svc-1/other"))
+}
@@ -0,0 +1,276 @@
+package authpolicy
+
+import (
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+)
+
+// Decision is the outcome of evaluating a policy for a request
+type Decision string
+
+const (
+	Allow        Decision = "Allow"
+	ExplicitDeny Decision = "ExplicitDeny"
+	ImplicitDeny Decision = "ImplicitDeny"
+)
+
+func (d Decision) Allowed() bool {
+	return d == Allow
+}
+
+// Request is a call to evaluate a policy for
+type Request struct {
+	// Principal is the ARN of the IAM caller, empty for anonymous callers
+	Principal string
+	Action    string
+	Resource  string
+	// Context holds the values of the condition keys, case insensitive
+	Context map[string][]string
+}
+
+// InvokeRequest builds the request of a principal calling a path of a Lattice service,
+// with the request method and headers as condition keys
+func InvokeRequest(principal, serviceArn, method, path string, headers map[string]string) Request {
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	ctx := map[string][]string{
+		ActionPrefix + "ServiceArn":    {serviceArn},
+		ActionPrefix + "RequestMethod": {method},
+	}
+	for name, value := range headers {
+		ctx[ActionPrefix+"RequestHeader/"+name] = []string{value}
+	}
+	if principal != "" {
+		ctx["aws:PrincipalArn"] = []string{principal}
+		ctx["aws:PrincipalAccount"] = []string{accountOf(principal)}
+	}
+	return Request{
+		Principal: principal,
+		Action:    ActionInvoke,
+		Resource:  serviceArn + path,
+		Context:   ctx,
+	}
+}
+
+// Evaluate evaluates the policy like Lattice does: an explicit deny wins over any allow,
+// and requests without a matching statement are implicitly denied.
+func (d *Document) Evaluate(req Request) (Decision, error) {
+	ctx := map[string][]string{}
+	for key, values := range req.Context {
+		ctx[strings.ToLower(key)] = values
+	}
+
+	decision := ImplicitDeny
+	for i, s := range d.Statements {
+		matches, err := s.matches(req, ctx)
+		if err != nil {
+			return ImplicitDeny, fmt.Errorf("statement %d: %w", i, err)
+		}
+		if !matches {
+			continue
+		}
+		if s.Effect == EffectDeny {
+			return ExplicitDeny, nil
+		}
+		decision = Allow
+	}
+	return decision, nil
+}
+
+func (s *Statement) matches(req Request, ctx map[string][]string) (bool, error) {
+	if s.Principal != nil && !s.Principal.matches(req.Principal) {
+		return false, nil
+	}
+	if s.NotPrincipal != nil && s.NotPrincipal.matches(req.Principal) {
+		return false, nil
+	}
+	if s.Action != nil && !anyMatch(s.Action, req.Action, true) {
+		return false, nil
+	}
+	if s.NotAction != nil && anyMatch(s.NotAction, req.Action, true) {
+		return false, nil
+	}
+	if s.Resource != nil && !anyMatch(s.Resource, req.Resource, false) {
+		return false, nil
+	}
+	if s.NotResource != nil && anyMatch(s.NotResource, req.Resource, false) {
+		return false, nil
+	}
+	for _, c := range s.Conditions {
+		matches, err := c.matches(ctx)
+		if err != nil || !matches {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func (p *Principal) matches(principal string) bool {
+	if p.Any {
+		return true
+	}
+	if principal == "" {
+		return false
+	}
+	for _, value := range p.Values["AWS"] {
+		switch {
+		case value == "*" || value == principal:
+			return true
+		case value == accountOf(principal) || value == "arn:aws:iam::"+accountOf(principal)+":root":
+			return true
+		case value == roleOf(principal):
+			return true
+		}
+	}
+	return false
+}
+
+// accountOf returns the account of an ARN
+func accountOf(arn string) string {
+	parts := strings.SplitN(arn, ":", 6)
+	if len(parts) < 6 {
+		return ""
+	}
+	return parts[4]
+}
+
+// roleOf returns the role ARN of an assumed role session ARN
+func roleOf(arn string) string {
+	parts := strings.SplitN(arn, ":", 6)
+	if len(parts) < 6 || parts[2] != "sts" || !strings.HasPrefix(parts[5], "assumed-role/") {
+		return ""
+	}
+	role := strings.Split(parts[5], "/")[1]
+	return fmt.Sprintf("arn:%s:iam::%s:role/%s", parts[1], parts[4], role)
+}
+
+func anyMatch(patterns []string, value string, ignoreCase bool) bool {
+	for _, pattern := range patterns {
+		if ignoreCase {
+			pattern, value = strings.ToLower(pattern), strings.ToLower(value)
+		}
+		if wildcardMatch(pattern, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Condition) matches(ctx map[string][]string) (bool, error) {
+	op, _ := parseOperator(c.Operator)
+	values, exists := ctx[strings.ToLower(c.Key)]
+
+	if op.name == "Null" {
+		if len(c.Values) != 1 {
+			return false, fmt.Errorf("condition Null expects a single value")
+		}
+		return strconv.FormatBool(!exists) == strings.ToLower(c.Values[0]), nil
+	}
+
+	compare, negated, err := comparator(op.name)
+	if err != nil {
+		return false, err
+	}
+	matchesAny := func(value string) (bool, error) {
+		for _, expected := range c.Values {
+			ok, err := compare(value, expected)
+			if err != nil {
+				return false, fmt.Errorf("condition %s %s: %w", c.Operator, c.Key, err)
+			}
+			if ok {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+	// the result of the operator for one request value, negated operators match when no value matches
+	test := func(value string) (bool, error) {
+		ok, err := matchesAny(value)
+		return ok != negated, err
+	}
+
+	switch {
+	case !exists:
+		return op.ifExists || op.forAll || (negated && !op.forAny), nil
+	case op.forAll:
+		for _, value := range values {
+			if ok, err := test(value); err != nil || !ok {
+				return false, err
+			}
+		}
+		return true, nil
+	default:
+		// single valued keys, and ForAnyValue
+		for _, value := range values {
+			if ok, err := test(value); err != nil || ok {
+				return ok, err
+			}
+		}
+		return false, nil
+	}
+}
+
+// comparator returns how a request value is compared to a condition value for an operator,
+// and whether the operator negates the comparison
+func comparator(name string) (func(value, expected string) (bool, error), bool, error) {
+	switch name {
+	case "StringEquals", "StringNotEquals":
+		return func(value, expected string) (bool, error) {
+			return value == expected, nil
+		}, name == "StringNotEquals", nil
+	case "StringEqualsIgnoreCase", "StringNotEqualsIgnoreCase":
+		return func(value, expected string) (bool, error) {
+			return strings.EqualFold(value, expected), nil
+		}, name == "StringNotEqualsIgnoreCase", nil
+	case "StringLike", "StringNotLike", "ArnEquals", "ArnLike", "ArnNotEquals", "ArnNotLike":
+		return func(value, expected string) (bool, error) {
+			return wildcardMatch(expected, value), nil
+		}, strings.Contains(name, "Not"), nil
+	case "Bool":
+		return func(value, expected string) (bool, error) {
+			return strings.EqualFold(value, expected), nil
+		}, false, nil
+	case "NumericEquals", "NumericNotEquals", "NumericLessThan", "NumericLessThanEquals",
+		"NumericGreaterThan", "NumericGreaterThanEquals":
+		return func(value, expected string) (bool, error) {
+			v, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return false, nil
+			}
+			e, err := strconv.ParseFloat(expected, 64)
+			if err != nil {
+				return false, fmt.Errorf("invalid number %q", expected)
+			}
+			switch name {
+			case "NumericLessThan":
+				return v < e, nil
+			case "NumericLessThanEquals":
+				return v <= e, nil
+			case "NumericGreaterThan":
+				return v > e, nil
+			case "NumericGreaterThanEquals":
+				return v >= e, nil
+			default:
+				return v == e, nil
+			}
+		}, name == "NumericNotEquals", nil
+	case "IpAddress", "NotIpAddress":
+		return func(value, expected string) (bool, error) {
+			prefix, err := netip.ParsePrefix(expected)
+			if err != nil {
+				addr, err := netip.ParseAddr(expected)
+				if err != nil {
+					return false, fmt.Errorf("invalid IP address or CIDR %q", expected)
+				}
+				prefix = netip.PrefixFrom(addr, addr.BitLen())
+			}
+			addr, err := netip.ParseAddr(value)
+			return err == nil && prefix.Contains(addr), nil
+		}, name == "NotIpAddress", nil
+	default:
+		return nil, false, fmt.Errorf("condition operator %s is not supported by the local evaluator", name)
+	}
+}
@@ -0,0 +1,111 @@
+package authpolicy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testServiceArn = "arn:aws:vpc-lattice:us-west-2:123456789012:service/svc-0123456789abcdef0"
+	testClientRole = "arn:aws:iam::123456789012:role/client"
+)
+
+func TestDocument_Evaluate(t *testing.T) {
+	doc, err := Parse(`{
+  "Version": "2012-10-17",
+  "Statement": [
+    {
+      "Effect": "Allow",
+      "Principal": {"AWS": "arn:aws:iam::123456789012:role/client"},
+      "Action": "vpc-lattice-svcs:Invoke",
+      "Resource": "arn:aws:vpc-lattice:us-west-2:123456789012:service/svc-0123456789abcdef0/api/*",
+      "Condition": {"StringEquals": {"vpc-lattice-svcs:RequestMethod": ["GET", "POST"]}}
+    },
+    {
+      "Effect": "Allow",
+      "Principal": {"AWS": "111111111111"},
+      "Action": "vpc-lattice-svcs:*",
+      "Resource": "*"
+    },
+    {
+      "Effect": "Deny",
+      "Principal": "*",
+      "Action": "*",
+      "Resource": "arn:aws:vpc-lattice:us-west-2:123456789012:service/svc-0123456789abcdef0/api/admin*"
+    }
+  ]
+}`)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		principal string
+		method    string
+		path      string
+		want      Decision
+	}{
+		{"allowed path and method", testClientRole, "GET", "/api/orders", Allow},
+		{"assumed role session", "arn:aws:sts::123456789012:assumed-role/client/session", "POST", "/api/orders", Allow},
+		{"method not allowed", testClientRole, "DELETE", "/api/orders", ImplicitDeny},
+		{"path not allowed", testClientRole, "GET", "/health", ImplicitDeny},
+		{"other role", "arn:aws:iam::123456789012:role/other", "GET", "/api/orders", ImplicitDeny},
+		{"anonymous", "", "GET", "/api/orders", ImplicitDeny},
+		{"trusted account", "arn:aws:iam::111111111111:role/any", "DELETE", "/health", Allow},
+		{"explicit deny wins", "arn:aws:iam::111111111111:role/any", "GET", "/api/admin/users", ExplicitDeny},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := doc.Evaluate(InvokeRequest(tt.principal, testServiceArn, tt.method, tt.path, nil))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.want == Allow, got.Allowed())
+		})
+	}
+}
+
+func TestCondition_matches(t *testing.T) {
+	ctx := map[string][]string{
+		"aws:principalaccount":                  {"123456789012"},
+		"vpc-lattice-svcs:requestheader/x-team": {"payments"},
+		"vpc-lattice-svcs:port":                 {"8080"},
+		"aws:sourceip":                          {"10.0.1.5"},
+		"aws:principaltag/teams":                {"payments", "billing"},
+	}
+	tests := []struct {
+		operator string
+		key      string
+		values   []string
+		want     bool
+	}{
+		{"StringEquals", "aws:PrincipalAccount", []string{"123456789012"}, true},
+		{"StringNotEquals", "aws:PrincipalAccount", []string{"123456789012"}, false},
+		{"StringNotEquals", "aws:PrincipalOrgID", []string{"o-1"}, true},
+		{"StringEquals", "aws:PrincipalOrgID", []string{"o-1"}, false},
+		{"StringEqualsIfExists", "aws:PrincipalOrgID", []string{"o-1"}, true},
+		{"StringEqualsIgnoreCase", "vpc-lattice-svcs:RequestHeader/x-team", []string{"PAYMENTS"}, true},
+		{"StringLike", "vpc-lattice-svcs:RequestHeader/x-team", []string{"pay*"}, true},
+		{"StringNotLike", "vpc-lattice-svcs:RequestHeader/x-team", []string{"pay*"}, false},
+		{"NumericLessThan", "vpc-lattice-svcs:Port", []string{"9000"}, true},
+		{"NumericGreaterThanEquals", "vpc-lattice-svcs:Port", []string{"9000"}, false},
+		{"IpAddress", "aws:SourceIp", []string{"10.0.0.0/16"}, true},
+		{"NotIpAddress", "aws:SourceIp", []string{"10.0.0.0/16"}, false},
+		{"Null", "aws:PrincipalOrgID", []string{"true"}, true},
+		{"Null", "aws:PrincipalAccount", []string{"true"}, false},
+		{"ForAnyValue:StringEquals", "aws:PrincipalTag/teams", []string{"billing"}, true},
+		{"ForAllValues:StringEquals", "aws:PrincipalTag/teams", []string{"billing"}, false},
+		{"ForAllValues:StringEquals", "aws:PrincipalTag/teams", []string{"billing", "payments"}, true},
+		{"ForAnyValue:StringEquals", "aws:PrincipalTag/missing", []string{"billing"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.operator+" "+tt.key, func(t *testing.T) {
+			c := &Condition{Operator: tt.operator, Key: tt.key, Values: tt.values}
+			got, err := c.matches(ctx)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err := (&Condition{Operator: "DateLessThan", Key: "aws:CurrentTime", Values: []string{"2020-01-01T00:00:00Z"}}).matches(map[string][]string{"aws:currenttime": {"2019-01-01T00:00:00Z"}})
+	assert.Error(t, err)
+}
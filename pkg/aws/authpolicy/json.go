@@ -0,0 +1,156 @@
+package authpolicy
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Error is a policy error, located at a line and column of the policy document when possible
+type Error struct {
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (e *Error) Error() string {
+	if e.Line == 0 {
+		return e.Msg
+	}
+	return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, e.Msg)
+}
+
+// node is a JSON value together with its offset in the document, so that grammar errors can be located
+type node struct {
+	offset int
+	// string, bool, json.Number, nil, []*node or *object
+	value any
+}
+
+type object struct {
+	keys       []string
+	values     map[string]*node
+	keyOffsets map[string]int
+}
+
+type parser struct {
+	data []byte
+	dec  *json.Decoder
+}
+
+func parseJSON(data []byte) (*node, error) {
+	p := &parser{data: data, dec: json.NewDecoder(bytes.NewReader(data))}
+	p.dec.UseNumber()
+	n, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	if off := p.start(); off < len(data) {
+		return nil, p.errorAt(off, "unexpected data after the policy document")
+	}
+	return n, nil
+}
+
+// start returns the offset of the next token, skipping whitespaces and separators
+func (p *parser) start() int {
+	off := int(p.dec.InputOffset())
+	for off < len(p.data) && bytes.IndexByte([]byte(" \t\r\n:,"), p.data[off]) >= 0 {
+		off++
+	}
+	return off
+}
+
+func (p *parser) token() (json.Token, int, error) {
+	off := p.start()
+	tok, err := p.dec.Token()
+	if err != nil {
+		var syntaxErr *json.SyntaxError
+		switch {
+		case errors.As(err, &syntaxErr):
+			return nil, off, p.errorAt(int(syntaxErr.Offset), "invalid JSON: %s", syntaxErr.Error())
+		case errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF):
+			return nil, off, p.errorAt(len(p.data), "invalid JSON: unexpected end of document")
+		default:
+			return nil, off, p.errorAt(off, "invalid JSON: %s", err)
+		}
+	}
+	return tok, off, nil
+}
+
+func (p *parser) parseValue() (*node, error) {
+	tok, off, err := p.token()
+	if err != nil {
+		return nil, err
+	}
+	switch tok {
+	case json.Delim('{'):
+		obj := &object{values: map[string]*node{}, keyOffsets: map[string]int{}}
+		for p.dec.More() {
+			keyTok, keyOff, err := p.token()
+			if err != nil {
+				return nil, err
+			}
+			key := keyTok.(string)
+			if _, ok := obj.values[key]; ok {
+				return nil, p.errorAt(keyOff, "duplicate element %q", key)
+			}
+			value, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			obj.keys = append(obj.keys, key)
+			obj.values[key] = value
+			obj.keyOffsets[key] = keyOff
+		}
+		if _, _, err := p.token(); err != nil {
+			return nil, err
+		}
+		return &node{offset: off, value: obj}, nil
+	case json.Delim('['):
+		var items []*node
+		for p.dec.More() {
+			item, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		if _, _, err := p.token(); err != nil {
+			return nil, err
+		}
+		return &node{offset: off, value: items}, nil
+	default:
+		return &node{offset: off, value: tok}, nil
+	}
+}
+
+func (p *parser) errorAt(offset int, format string, args ...any) *Error {
+	return newError(p.data, offset, format, args...)
+}
+
+func newError(data []byte, offset int, format string, args ...any) *Error {
+	offset = min(offset, len(data))
+	line := 1 + bytes.Count(data[:offset], []byte("\n"))
+	column := offset - bytes.LastIndexByte(data[:offset], '\n')
+	return &Error{Line: line, Column: column, Msg: fmt.Sprintf(format, args...)}
+}
+
+// typeName describes the type of a JSON value in error messages
+func typeName(n *node) string {
+	switch n.value.(type) {
+	case string:
+		return "a string"
+	case bool:
+		return "a boolean"
+	case json.Number:
+		return "a number"
+	case []*node:
+		return "an array"
+	case *object:
+		return "an object"
+	default:
+		return "null"
+	}
+}
@@ -2,10 +2,16 @@ package aws
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
+	"sync"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
 	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/vpclattice"
@@ -13,7 +19,10 @@ import (
 
 	"github.com/aws/aws-application-networking-k8s/pkg/aws/metrics"
 	"github.com/aws/aws-application-networking-k8s/pkg/aws/services"
+	"github.com/aws/aws-application-networking-k8s/pkg/aws/throttle"
+	"github.com/aws/aws-application-networking-k8s/pkg/config"
 	"github.com/aws/aws-application-networking-k8s/pkg/utils/gwlog"
+	"github.com/aws/aws-application-networking-k8s/pkg/utils/tracing"
 )
 
 const (
@@ -29,6 +38,10 @@ type CloudConfig struct {
 	Region                    string
 	ClusterName               string
 	TaggingServiceAPIDisabled bool
+	// client side rate limits per API family, overriding the defaults
+	APIRateLimits map[string]config.APIRateLimit
+	// period of full Lattice resource cache refreshes, zero disables the cache
+	CacheResyncPeriod time.Duration
 }
 
 type Cloud interface {
@@ -42,16 +55,32 @@ type Cloud interface {
 	// creates lattice tags with default values populated and merges them with provided tags
 	DefaultTagsMergedWith(services.Tags) services.Tags
 
+	// creates the tags to look up the resources of the controller, the managedBy tag merged with provided tags.
+	// DEFAULT_TAGS are left out, resources created before they were set or changed do not have them
+	ManagedByTagsMergedWith(services.Tags) services.Tags
+
 	// check if managedBy tag set for lattice resource
 	IsArnManaged(ctx context.Context, arn string) (bool, error)
 
 	// check ownership and acquire if it is not owned by anyone.
 	TryOwn(ctx context.Context, arn string) (bool, error)
 	TryOwnFromTags(ctx context.Context, arn string, tags services.Tags) (bool, error)
+
+	// returns a Cloud acting in another account through its role of CROSS_ACCOUNT_ROLES
+	ForAccount(accountId string) (Cloud, error)
 }
 
+// ErrNoAccountRole is returned for the accounts without a role in CROSS_ACCOUNT_ROLES
+var ErrNoAccountRole = errors.New("no role to assume for the account in CROSS_ACCOUNT_ROLES")
+
 // NewCloud constructs new Cloud implementation.
 func NewCloud(log gwlog.Logger, cfg CloudConfig, metricsRegisterer prometheus.Registerer) (Cloud, error) {
+	if os.Getenv("LATTICE_ENDPOINT") == services.FakeLatticeEndpoint {
+		log.Infof(context.TODO(), "Using in-memory Lattice, no AWS resources are created")
+		lattice := services.NewFakeLattice(cfg.AccountId, cfg.Region)
+		return NewDefaultCloudWithTagging(lattice, lattice.Tagging(cfg.VpcId), cfg), nil
+	}
+
 	sess, err := session.NewSession()
 	if err != nil {
 		return nil, err
@@ -59,14 +88,14 @@ func NewCloud(log gwlog.Logger, cfg CloudConfig, metricsRegisterer prometheus.Re
 
 	sess.Handlers.Complete.PushFront(func(r *request.Request) {
 		if r.Error != nil {
-			log.Debugw(context.TODO(), "error",
+			log.Debugw(r.Context(), "error",
 				"error", r.Error.Error(),
 				"serviceName", r.ClientInfo.ServiceName,
 				"operation", r.Operation.Name,
 				"params", r.Params,
 			)
 		} else {
-			log.Debugw(context.TODO(), "response",
+			log.Debugw(r.Context(), "response",
 				"serviceName", r.ClientInfo.ServiceName,
 				"operation", r.Operation.Name,
 				"params", r.Params,
@@ -82,16 +111,35 @@ func NewCloud(log gwlog.Logger, cfg CloudConfig, metricsRegisterer prometheus.Re
 		metricsCollector.InjectHandlers(&sess.Handlers)
 	}
 
-	lattice := services.NewDefaultLattice(sess, cfg.AccountId, cfg.Region)
+	tracing.InjectHandlers(&sess.Handlers)
+
+	limiter, err := throttle.NewLimiter(cfg.AccountId, cfg.APIRateLimits, metricsRegisterer)
+	if err != nil {
+		return nil, err
+	}
+	limiter.InjectHandlers(&sess.Handlers)
+
+	var lattice services.Lattice = services.NewDefaultLattice(sess, cfg.AccountId, cfg.Region)
+	if cfg.CacheResyncPeriod > 0 {
+		lattice = services.NewCachedLattice(log.Named("lattice-cache"), lattice, cfg.CacheResyncPeriod)
+	}
 	var tagging services.Tagging
 
 	if cfg.TaggingServiceAPIDisabled {
-		tagging = services.NewLatticeTagging(sess, cfg.AccountId, cfg.Region, cfg.VpcId)
+		tagging = services.NewLatticeTagging(lattice, cfg.VpcId)
 	} else {
 		tagging = services.NewDefaultTagging(sess, cfg.Region)
 	}
 
-	cl := NewDefaultCloudWithTagging(lattice, tagging, cfg)
+	cl := &defaultCloud{
+		cfg:          cfg,
+		lattice:      lattice,
+		tagging:      tagging,
+		managedByTag: getManagedByTag(cfg),
+		log:          log,
+		sess:         sess,
+		limiter:      limiter,
+	}
 	return cl, nil
 }
 
@@ -118,6 +166,44 @@ type defaultCloud struct {
 	lattice      services.Lattice
 	tagging      services.Tagging
 	managedByTag string
+
+	log gwlog.Logger
+
+	// sess is the session of the AWS clients, nil with the in-memory Lattice and in tests
+	sess          *session.Session
+	limiter       *throttle.Limiter
+	accountClouds sync.Map
+
+	// the Lattice caches of other accounts, refreshed from Start
+	cachesLock sync.Mutex
+	cachesCtx  context.Context
+	caches     []*services.CachedLattice
+}
+
+// Start refreshes the Lattice caches of the Clouds of other accounts until the context is done.
+func (c *defaultCloud) Start(ctx context.Context) error {
+	c.cachesLock.Lock()
+	c.cachesCtx = ctx
+	for _, cache := range c.caches {
+		go cache.Start(ctx)
+	}
+	c.cachesLock.Unlock()
+	<-ctx.Done()
+	return nil
+}
+
+// NeedLeaderElection is false, the Clouds of other accounts are used by every replica that reconciles routes
+func (c *defaultCloud) NeedLeaderElection() bool {
+	return false
+}
+
+func (c *defaultCloud) startCache(cache *services.CachedLattice) {
+	c.cachesLock.Lock()
+	defer c.cachesLock.Unlock()
+	c.caches = append(c.caches, cache)
+	if c.cachesCtx != nil {
+		go cache.Start(c.cachesCtx)
+	}
 }
 
 func (c *defaultCloud) Lattice() services.Lattice {
@@ -132,8 +218,13 @@ func (c *defaultCloud) Config() CloudConfig {
 	return c.cfg
 }
 
+// DefaultTags returns the tags of the resources the controller creates, DEFAULT_TAGS and the ManagedBy tag.
+// They are only set on create, resources are looked up with ManagedByTagsMergedWith.
 func (c *defaultCloud) DefaultTags() services.Tags {
 	tags := services.Tags{}
+	for key, value := range config.DefaultTags() {
+		tags[key] = &value
+	}
 	tags[TagManagedBy] = &c.managedByTag
 	return tags
 }
@@ -144,6 +235,12 @@ func (c *defaultCloud) DefaultTagsMergedWith(tags services.Tags) services.Tags {
 	return newTags
 }
 
+func (c *defaultCloud) ManagedByTagsMergedWith(tags services.Tags) services.Tags {
+	newTags := services.Tags{TagManagedBy: &c.managedByTag}
+	maps.Copy(newTags, tags)
+	return newTags
+}
+
 func (c *defaultCloud) getTags(ctx context.Context, arn string) (services.Tags, error) {
 	tagsReq := &vpclattice.ListTagsForResourceInput{ResourceArn: &arn}
 	resp, err := c.lattice.ListTagsForResourceWithContext(ctx, tagsReq)
@@ -203,6 +300,47 @@ func (c *defaultCloud) isOwner(managedBy string) bool {
 	return managedBy == c.managedByTag
 }
 
+// ForAccount returns a Cloud whose clients assume the role of the account, this Cloud for its own account.
+// Clouds are kept per account, the credentials are refreshed by the assume role provider. Their calls are
+// rate limited against the quotas of their account, and cached like the calls of this Cloud.
+func (c *defaultCloud) ForAccount(accountId string) (Cloud, error) {
+	if accountId == c.cfg.AccountId {
+		return c, nil
+	}
+	roleArn, ok := config.CrossAccountRoles[accountId]
+	if !ok || c.sess == nil {
+		return nil, fmt.Errorf("%w: %s", ErrNoAccountRole, accountId)
+	}
+	if cl, ok := c.accountClouds.Load(roleArn); ok {
+		return cl.(Cloud), nil
+	}
+
+	sess := c.sess.Copy(&aws.Config{Credentials: stscreds.NewCredentials(c.sess, roleArn)})
+	if c.limiter != nil {
+		c.limiter.ForAccount(accountId).InjectHandlers(&sess.Handlers)
+	}
+	var lattice services.Lattice = services.NewDefaultLattice(sess, accountId, c.cfg.Region)
+	var cache *services.CachedLattice
+	if c.cfg.CacheResyncPeriod > 0 {
+		cache = services.NewCachedLattice(c.log.Named("lattice-cache").Named(accountId), lattice, c.cfg.CacheResyncPeriod)
+		lattice = cache
+	}
+	var tagging services.Tagging
+	if c.cfg.TaggingServiceAPIDisabled {
+		// the target groups of other accounts are in their own VPCs, not in the VPC of the cluster
+		tagging = services.NewLatticeTagging(lattice, "")
+	} else {
+		tagging = services.NewDefaultTagging(sess, c.cfg.Region)
+	}
+	cfg := c.cfg
+	cfg.AccountId = accountId
+	cl, loaded := c.accountClouds.LoadOrStore(roleArn, NewDefaultCloudWithTagging(lattice, tagging, cfg))
+	if !loaded && cache != nil {
+		c.startCache(cache)
+	}
+	return cl.(Cloud), nil
+}
+
 func getManagedByTag(cfg CloudConfig) string {
 	return fmt.Sprintf("%s/%s/%s", cfg.AccountId, cfg.ClusterName, cfg.VpcId)
 }
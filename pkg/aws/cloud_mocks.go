@@ -77,6 +77,21 @@ func (mr *MockCloudMockRecorder) DefaultTagsMergedWith(arg0 interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DefaultTagsMergedWith", reflect.TypeOf((*MockCloud)(nil).DefaultTagsMergedWith), arg0)
 }
 
+// ForAccount mocks base method.
+func (m *MockCloud) ForAccount(arg0 string) (Cloud, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForAccount", arg0)
+	ret0, _ := ret[0].(Cloud)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ForAccount indicates an expected call of ForAccount.
+func (mr *MockCloudMockRecorder) ForAccount(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForAccount", reflect.TypeOf((*MockCloud)(nil).ForAccount), arg0)
+}
+
 // IsArnManaged mocks base method.
 func (m *MockCloud) IsArnManaged(arg0 context.Context, arg1 string) (bool, error) {
 	m.ctrl.T.Helper()
@@ -106,6 +121,20 @@ func (mr *MockCloudMockRecorder) Lattice() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lattice", reflect.TypeOf((*MockCloud)(nil).Lattice))
 }
 
+// ManagedByTagsMergedWith mocks base method.
+func (m *MockCloud) ManagedByTagsMergedWith(arg0 map[string]*string) map[string]*string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ManagedByTagsMergedWith", arg0)
+	ret0, _ := ret[0].(map[string]*string)
+	return ret0
+}
+
+// ManagedByTagsMergedWith indicates an expected call of ManagedByTagsMergedWith.
+func (mr *MockCloudMockRecorder) ManagedByTagsMergedWith(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ManagedByTagsMergedWith", reflect.TypeOf((*MockCloud)(nil).ManagedByTagsMergedWith), arg0)
+}
+
 // Tagging mocks base method.
 func (m *MockCloud) Tagging() services.Tagging {
 	m.ctrl.T.Helper()
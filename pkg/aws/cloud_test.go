@@ -2,9 +2,14 @@ package aws
 
 import (
 	"errors"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/vpclattice"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -13,6 +18,9 @@ import (
 	"fmt"
 
 	"github.com/aws/aws-application-networking-k8s/pkg/aws/services"
+	"github.com/aws/aws-application-networking-k8s/pkg/aws/throttle"
+	"github.com/aws/aws-application-networking-k8s/pkg/config"
+	"github.com/aws/aws-application-networking-k8s/pkg/utils/gwlog"
 )
 
 func TestGetManagedByTag(t *testing.T) {
@@ -30,7 +38,7 @@ func TestGetManagedByTag(t *testing.T) {
 }
 
 func TestDefaultTags(t *testing.T) {
-	cfg := CloudConfig{"acc", "vpc", "region", "cluster", false}
+	cfg := CloudConfig{"acc", "vpc", "region", "cluster", false, nil, 0}
 	c := NewDefaultCloud(nil, cfg)
 	tags := c.DefaultTags()
 	tagWant := getManagedByTag(cfg)
@@ -195,3 +203,87 @@ func Test_TryOwnFromTags(t *testing.T) {
 		})
 	}
 }
+
+func Test_ForAccount(t *testing.T) {
+	roles := config.CrossAccountRoles
+	defer func() { config.CrossAccountRoles = roles }()
+	config.CrossAccountRoles = map[string]string{"222233334444": "arn:aws:iam::222233334444:role/lattice-reader"}
+
+	sess, err := session.NewSession(&aws.Config{Region: aws.String("us-west-2")})
+	assert.NoError(t, err)
+	limiter, err := throttle.NewLimiter("111122223333", nil, nil)
+	assert.NoError(t, err)
+	c := &defaultCloud{
+		cfg:     CloudConfig{AccountId: "111122223333", Region: "us-west-2", TaggingServiceAPIDisabled: true, CacheResyncPeriod: time.Minute},
+		log:     gwlog.FallbackLogger,
+		sess:    sess,
+		limiter: limiter,
+	}
+
+	own, err := c.ForAccount("111122223333")
+	assert.NoError(t, err)
+	assert.Same(t, c, own)
+
+	_, err = c.ForAccount("555566667777")
+	assert.ErrorIs(t, err, ErrNoAccountRole)
+
+	other, err := c.ForAccount("222233334444")
+	assert.NoError(t, err)
+	assert.Equal(t, "222233334444", other.Config().AccountId)
+	assert.IsType(t, &services.CachedLattice{}, other.Lattice())
+	again, err := c.ForAccount("222233334444")
+	assert.NoError(t, err)
+	assert.Same(t, other, again)
+	assert.Len(t, c.caches, 1)
+
+	// the cache of the account is refreshed once the Cloud is started
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.NoError(t, c.Start(ctx))
+	assert.Equal(t, ctx, c.cachesCtx)
+}
+
+func Test_ForAccount_LatticeTagging(t *testing.T) {
+	roles := config.CrossAccountRoles
+	defer func() { config.CrossAccountRoles = roles }()
+	config.CrossAccountRoles = map[string]string{"222233334444": "arn:aws:iam::222233334444:role/lattice-reader"}
+
+	// serves the role credentials and the target groups of the account
+	var vpcFiltered []bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/targetgroups" {
+			vpcFiltered = append(vpcFiltered, r.URL.Query().Has("vpcIdentifier"))
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"items":[]}`)
+			return
+		}
+		w.Header().Set("Content-Type", "text/xml")
+		fmt.Fprintf(w, `<AssumeRoleResponse><AssumeRoleResult><Credentials>
+<AccessKeyId>key</AccessKeyId><SecretAccessKey>secret</SecretAccessKey><SessionToken>token</SessionToken>
+<Expiration>%s</Expiration></Credentials></AssumeRoleResult></AssumeRoleResponse>`,
+			time.Now().Add(time.Hour).UTC().Format(time.RFC3339))
+	}))
+	defer server.Close()
+	t.Setenv("LATTICE_ENDPOINT", server.URL)
+
+	sess, err := session.NewSession(&aws.Config{
+		Region:      aws.String("us-west-2"),
+		Endpoint:    aws.String(server.URL),
+		Credentials: credentials.NewStaticCredentials("key", "secret", ""),
+	})
+	assert.NoError(t, err)
+	c := &defaultCloud{
+		cfg:  CloudConfig{AccountId: "111122223333", Region: "us-west-2", VpcId: "vpc-id", TaggingServiceAPIDisabled: true},
+		log:  gwlog.FallbackLogger,
+		sess: sess,
+	}
+
+	other, err := c.ForAccount("222233334444")
+	assert.NoError(t, err)
+	arns, err := other.Tagging().FindResourcesByTags(context.Background(), services.ResourceTypeTargetGroup,
+		services.Tags{"application-networking.k8s.aws/ServiceName": aws.String("svc")})
+	assert.NoError(t, err)
+	assert.Empty(t, arns)
+	// the target groups of the account are not in the VPC of the cluster
+	assert.Equal(t, []bool{false}, vpcFiltered)
+}
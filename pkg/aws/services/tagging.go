@@ -32,7 +32,7 @@ type Tagging interface {
 	// Receives a list of arns and returns arn-to-tags map.
 	GetTagsForArns(ctx context.Context, arns []string) (map[string]Tags, error)
 
-	// Finds one resource that matches the given set of tags.
+	// Finds all resources that match the given set of tags.
 	FindResourcesByTags(ctx context.Context, resourceType ResourceType, tags Tags) ([]string, error)
 }
 
@@ -71,13 +71,16 @@ func (t *defaultTagging) FindResourcesByTags(ctx context.Context, resourceType R
 		TagFilters:          convertTagsToFilter(tags),
 		ResourceTypeFilters: []*string{aws.String(string(resourceType))},
 	}
-	resp, err := t.GetResourcesWithContext(ctx, input)
+	matchingArns := []string{}
+	err := t.GetResourcesPagesWithContext(ctx, input, func(page *taggingapi.GetResourcesOutput, lastPage bool) bool {
+		for _, r := range page.ResourceTagMappingList {
+			matchingArns = append(matchingArns, aws.StringValue(r.ResourceARN))
+		}
+		return true
+	})
 	if err != nil {
 		return nil, err
 	}
-	matchingArns := utils.SliceMap(resp.ResourceTagMappingList, func(t *taggingapi.ResourceTagMapping) string {
-		return aws.StringValue(t.ResourceARN)
-	})
 	return matchingArns, nil
 }
 
@@ -86,10 +89,10 @@ func NewDefaultTagging(sess *session.Session, region string) *defaultTagging {
 	return &defaultTagging{ResourceGroupsTaggingAPIAPI: api}
 }
 
-// Use VPC Lattice API instead of the Resource Groups Tagging API
-func NewLatticeTagging(sess *session.Session, acc string, region string, vpcId string) *latticeTagging {
-	api := NewDefaultLattice(sess, acc, region)
-	return &latticeTagging{Lattice: api, vpcId: vpcId}
+// Use VPC Lattice API instead of the Resource Groups Tagging API.
+// Only the target groups of the VPC are searched, or of all VPCs when vpcId is empty.
+func NewLatticeTagging(lattice Lattice, vpcId string) *latticeTagging {
+	return &latticeTagging{Lattice: lattice, vpcId: vpcId}
 }
 
 func (t *latticeTagging) GetTagsForArns(ctx context.Context, arns []string) (map[string]Tags, error) {
@@ -112,9 +115,11 @@ func (t *latticeTagging) FindResourcesByTags(ctx context.Context, resourceType R
 		return nil, fmt.Errorf("unsupported resource type %q for FindResourcesByTags", resourceType)
 	}
 
-	tgs, err := t.ListTargetGroupsAsList(ctx, &vpclattice.ListTargetGroupsInput{
-		VpcIdentifier: aws.String(t.vpcId),
-	})
+	input := &vpclattice.ListTargetGroupsInput{}
+	if t.vpcId != "" {
+		input.VpcIdentifier = aws.String(t.vpcId)
+	}
+	tgs, err := t.ListTargetGroupsAsList(ctx, input)
 	if err != nil {
 		return nil, err
 	}
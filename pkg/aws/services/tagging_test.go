@@ -6,6 +6,8 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/request"
+	taggingapi "github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
+	taggingapiiface "github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi/resourcegroupstaggingapiiface"
 	"github.com/aws/aws-sdk-go/service/vpclattice"
 	gomock "github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -429,3 +431,47 @@ func Test_latticeTagging_FindResourcesByTags(t *testing.T) {
 		})
 	}
 }
+
+// pagedTaggingAPI returns one page per GetResources call
+type pagedTaggingAPI struct {
+	taggingapiiface.ResourceGroupsTaggingAPIAPI
+	pages [][]string
+	err   error
+}
+
+func (p *pagedTaggingAPI) GetResourcesPagesWithContext(ctx aws.Context, input *taggingapi.GetResourcesInput,
+	fn func(*taggingapi.GetResourcesOutput, bool) bool, opts ...request.Option) error {
+
+	for i, arns := range p.pages {
+		if i > 0 && p.err != nil {
+			return p.err
+		}
+		page := &taggingapi.GetResourcesOutput{}
+		for _, arn := range arns {
+			page.ResourceTagMappingList = append(page.ResourceTagMappingList, &taggingapi.ResourceTagMapping{ResourceARN: aws.String(arn)})
+		}
+		if !fn(page, i == len(p.pages)-1) {
+			return nil
+		}
+	}
+	return nil
+}
+
+func Test_defaultTagging_FindResourcesByTags_AllPages(t *testing.T) {
+	ctx := context.TODO()
+	tagging := &defaultTagging{ResourceGroupsTaggingAPIAPI: &pagedTaggingAPI{
+		pages: [][]string{{"tg-arn-1", "tg-arn-2"}, {"tg-arn-3"}},
+	}}
+	arns, err := tagging.FindResourcesByTags(ctx, ResourceTypeTargetGroup, Tags{"Key1": aws.String("Value1")})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"tg-arn-1", "tg-arn-2", "tg-arn-3"}, arns)
+
+	// no partial result when a page fails
+	tagging = &defaultTagging{ResourceGroupsTaggingAPIAPI: &pagedTaggingAPI{
+		pages: [][]string{{"tg-arn-1"}, {"tg-arn-2"}},
+		err:   ErrInternal,
+	}}
+	arns, err = tagging.FindResourcesByTags(ctx, ResourceTypeTargetGroup, Tags{"Key1": aws.String("Value1")})
+	assert.ErrorIs(t, err, ErrInternal)
+	assert.Nil(t, arns)
+}
@@ -270,6 +270,14 @@ func (d *defaultLattice) TagResourceWithContext(ctx context.Context, input *vpcl
 	return d.VPCLatticeAPI.TagResourceWithContext(ctx, input, option...)
 }
 
+func (d *defaultLattice) UntagResourceWithContext(ctx context.Context, input *vpclattice.UntagResourceInput, option ...request.Option) (*vpclattice.UntagResourceOutput, error) {
+	if d.cache != nil {
+		key := tagCacheKey(*input.ResourceArn)
+		d.cache.Remove(key)
+	}
+	return d.VPCLatticeAPI.UntagResourceWithContext(ctx, input, option...)
+}
+
 func (d *defaultLattice) ListTargetsAsList(ctx context.Context, input *vpclattice.ListTargetsInput) ([]*vpclattice.TargetSummary, error) {
 	result := []*vpclattice.TargetSummary{}
 
@@ -0,0 +1,789 @@
+package services
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/vpclattice"
+
+	"github.com/aws/aws-application-networking-k8s/pkg/utils/gwlog"
+)
+
+// CachedLattice serves the Lattice reads issued on every reconcile from memory: services, target groups,
+// listeners per service, rules per listener and tags. Collections are listed on first use, kept up to date
+// by the writes going through the cache, dropped when a write fails and refreshed every resync period, so
+// changes made outside of the controller are seen after at most one resync period.
+// Only the WithContext and AsList operations use the cache, all other operations are passed through.
+type CachedLattice struct {
+	Lattice
+	log          gwlog.Logger
+	resyncPeriod time.Duration
+
+	lock sync.Mutex
+	// incremented on every change, lists started before a change are returned but not cached
+	version      uint64
+	services     map[string]*vpclattice.ServiceSummary
+	targetGroups map[string]*vpclattice.TargetGroupSummary
+	listeners    map[string]map[string]*vpclattice.ListenerSummary // by service id
+	rules        map[string]map[string]*vpclattice.GetRuleOutput   // by listener id
+	tags         map[string]Tags                                   // by ARN
+}
+
+func NewCachedLattice(log gwlog.Logger, lattice Lattice, resyncPeriod time.Duration) *CachedLattice {
+	return &CachedLattice{
+		Lattice:      lattice,
+		log:          log,
+		resyncPeriod: resyncPeriod,
+		listeners:    map[string]map[string]*vpclattice.ListenerSummary{},
+		rules:        map[string]map[string]*vpclattice.GetRuleOutput{},
+		tags:         map[string]Tags{},
+	}
+}
+
+// Start refreshes the cache every resync period until the context is done.
+func (c *CachedLattice) Start(ctx context.Context) error {
+	ticker := time.NewTicker(c.resyncPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := c.Resync(ctx); err != nil {
+				c.log.Errorf(ctx, "Lattice cache resync failed, cache cleared: %s", err)
+			}
+		}
+	}
+}
+
+// Resync lists services and target groups with their tags again, and clears listeners and rules.
+// Nothing is listed until the cache is used.
+func (c *CachedLattice) Resync(ctx context.Context) error {
+	c.lock.Lock()
+	version := c.version
+	used := c.services != nil || c.targetGroups != nil
+	c.lock.Unlock()
+	if !used {
+		return nil
+	}
+
+	svcs, err := c.Lattice.ListServicesAsList(ctx, &vpclattice.ListServicesInput{})
+	if err != nil {
+		c.clear()
+		return err
+	}
+	tgs, err := c.Lattice.ListTargetGroupsAsList(ctx, &vpclattice.ListTargetGroupsInput{})
+	if err != nil {
+		c.clear()
+		return err
+	}
+	arns := append(
+		idsOf(svcs, func(s *vpclattice.ServiceSummary) *string { return s.Arn }),
+		idsOf(tgs, func(tg *vpclattice.TargetGroupSummary) *string { return tg.Arn })...)
+	tags := map[string]Tags{}
+	for _, arn := range arns {
+		out, err := c.Lattice.ListTagsForResourceWithContext(ctx, &vpclattice.ListTagsForResourceInput{ResourceArn: aws.String(arn)})
+		if err != nil {
+			// fetched again on use
+			c.log.Debugf(ctx, "Failed to list tags of %s during Lattice cache resync: %s", arn, err)
+			continue
+		}
+		tags[arn] = cloneTags(out.Tags)
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.version != version {
+		c.clearLocked()
+		return nil
+	}
+	c.services = byId(svcs, serviceSummaryId)
+	c.targetGroups = byId(tgs, targetGroupSummaryId)
+	c.listeners = map[string]map[string]*vpclattice.ListenerSummary{}
+	c.rules = map[string]map[string]*vpclattice.GetRuleOutput{}
+	c.tags = tags
+	c.log.Debugf(ctx, "Lattice cache resynced, %d services and %d target groups", len(svcs), len(tgs))
+	return nil
+}
+
+// NeedLeaderElection is false, with route shards every replica reconciles routes through its cache.
+// The cache of a replica that does not reconcile is never used, and so never listed.
+func (c *CachedLattice) NeedLeaderElection() bool {
+	return false
+}
+
+// Clear drops the cached state, everything is listed again on use. Used when this replica takes over
+// resources another replica changed, e.g. when it acquires a shard of routes.
+func (c *CachedLattice) Clear() {
+	c.clear()
+}
+
+func (c *CachedLattice) clear() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.clearLocked()
+}
+
+func (c *CachedLattice) clearLocked() {
+	c.version++
+	c.services = nil
+	c.targetGroups = nil
+	c.listeners = map[string]map[string]*vpclattice.ListenerSummary{}
+	c.rules = map[string]map[string]*vpclattice.GetRuleOutput{}
+	c.tags = map[string]Tags{}
+}
+
+// update applies a change to the cached state under the lock
+func (c *CachedLattice) update(change func()) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.version++
+	change()
+}
+
+// cached returns the items of a collection, listing and caching them when they are not cached yet
+func cached[T any](c *CachedLattice, get func() map[string]*T, set func(map[string]*T),
+	list func() ([]*T, error), id func(*T) *string) ([]*T, error) {
+	c.lock.Lock()
+	if items := get(); items != nil {
+		defer c.lock.Unlock()
+		return sortedCopies(items), nil
+	}
+	version := c.version
+	c.lock.Unlock()
+
+	listed, err := list()
+	if err != nil {
+		return nil, err
+	}
+	items := byId(listed, id)
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.version == version {
+		set(items)
+	}
+	return sortedCopies(items), nil
+}
+
+func byId[T any](items []*T, id func(*T) *string) map[string]*T {
+	result := make(map[string]*T, len(items))
+	for _, item := range items {
+		result[aws.StringValue(id(item))] = item
+	}
+	return result
+}
+
+// sortedCopies returns shallow copies of the items, so callers cannot change the cached ones
+func sortedCopies[T any](items map[string]*T) []*T {
+	result := make([]*T, 0, len(items))
+	for _, key := range slices.Sorted(maps.Keys(items)) {
+		item := *items[key]
+		result = append(result, &item)
+	}
+	return result
+}
+
+func cloneTags(tags Tags) Tags {
+	if tags == nil {
+		return Tags{}
+	}
+	return maps.Clone(tags)
+}
+
+func idsOf[T any](items []*T, id func(*T) *string) []string {
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		result = append(result, aws.StringValue(id(item)))
+	}
+	return result
+}
+
+// idOf returns the id of a resource from its id or ARN
+func idOf(identifier *string) string {
+	s := aws.StringValue(identifier)
+	return s[strings.LastIndex(s, "/")+1:]
+}
+
+// serviceArnOf returns the service ARN from a listener or rule ARN
+func serviceArnOf(arn *string) string {
+	s := aws.StringValue(arn)
+	if i := strings.Index(s, "/listener/"); i >= 0 {
+		return s[:i]
+	}
+	return ""
+}
+
+func isPaged(maxResults *int64, nextToken *string) bool {
+	return maxResults != nil || nextToken != nil
+}
+
+func serviceSummaryId(s *vpclattice.ServiceSummary) *string          { return s.Id }
+func targetGroupSummaryId(tg *vpclattice.TargetGroupSummary) *string { return tg.Id }
+func listenerSummaryId(l *vpclattice.ListenerSummary) *string        { return l.Id }
+func ruleOutputId(r *vpclattice.GetRuleOutput) *string               { return r.Id }
+
+func (c *CachedLattice) cachedServices(ctx context.Context) ([]*vpclattice.ServiceSummary, error) {
+	return cached(c,
+		func() map[string]*vpclattice.ServiceSummary { return c.services },
+		func(items map[string]*vpclattice.ServiceSummary) { c.services = items },
+		func() ([]*vpclattice.ServiceSummary, error) {
+			return c.Lattice.ListServicesAsList(ctx, &vpclattice.ListServicesInput{})
+		},
+		serviceSummaryId)
+}
+
+func (c *CachedLattice) cachedTargetGroups(ctx context.Context) ([]*vpclattice.TargetGroupSummary, error) {
+	return cached(c,
+		func() map[string]*vpclattice.TargetGroupSummary { return c.targetGroups },
+		func(items map[string]*vpclattice.TargetGroupSummary) { c.targetGroups = items },
+		func() ([]*vpclattice.TargetGroupSummary, error) {
+			return c.Lattice.ListTargetGroupsAsList(ctx, &vpclattice.ListTargetGroupsInput{})
+		},
+		targetGroupSummaryId)
+}
+
+func (c *CachedLattice) cachedListeners(ctx context.Context, svcIdentifier *string) ([]*vpclattice.ListenerSummary, error) {
+	svcId := idOf(svcIdentifier)
+	return cached(c,
+		func() map[string]*vpclattice.ListenerSummary { return c.listeners[svcId] },
+		func(items map[string]*vpclattice.ListenerSummary) { c.listeners[svcId] = items },
+		func() ([]*vpclattice.ListenerSummary, error) {
+			return c.Lattice.ListListenersAsList(ctx, &vpclattice.ListListenersInput{ServiceIdentifier: svcIdentifier})
+		},
+		listenerSummaryId)
+}
+
+func (c *CachedLattice) cachedRules(ctx context.Context, svcIdentifier, listenerIdentifier *string) ([]*vpclattice.GetRuleOutput, error) {
+	lId := idOf(listenerIdentifier)
+	return cached(c,
+		func() map[string]*vpclattice.GetRuleOutput { return c.rules[lId] },
+		func(items map[string]*vpclattice.GetRuleOutput) { c.rules[lId] = items },
+		func() ([]*vpclattice.GetRuleOutput, error) {
+			return c.Lattice.GetRulesAsList(ctx, &vpclattice.ListRulesInput{
+				ServiceIdentifier:  svcIdentifier,
+				ListenerIdentifier: listenerIdentifier,
+			})
+		},
+		ruleOutputId)
+}
+
+func (c *CachedLattice) ListServicesAsList(ctx context.Context, input *vpclattice.ListServicesInput) ([]*vpclattice.ServiceSummary, error) {
+	if isPaged(input.MaxResults, input.NextToken) {
+		return c.Lattice.ListServicesAsList(ctx, input)
+	}
+	return c.cachedServices(ctx)
+}
+
+func (c *CachedLattice) FindService(ctx context.Context, latticeServiceName string) (*vpclattice.ServiceSummary, error) {
+	svcs, err := c.cachedServices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, svc := range svcs {
+		if aws.StringValue(svc.Name) == latticeServiceName {
+			return svc, nil
+		}
+	}
+	return nil, NewNotFoundError("Service", latticeServiceName)
+}
+
+func (c *CachedLattice) ListTargetGroupsAsList(ctx context.Context, input *vpclattice.ListTargetGroupsInput) ([]*vpclattice.TargetGroupSummary, error) {
+	if isPaged(input.MaxResults, input.NextToken) {
+		return c.Lattice.ListTargetGroupsAsList(ctx, input)
+	}
+	tgs, err := c.cachedTargetGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := []*vpclattice.TargetGroupSummary{}
+	for _, tg := range tgs {
+		if input.VpcIdentifier != nil && aws.StringValue(tg.VpcIdentifier) != *input.VpcIdentifier {
+			continue
+		}
+		if input.TargetGroupType != nil && aws.StringValue(tg.Type) != *input.TargetGroupType {
+			continue
+		}
+		result = append(result, tg)
+	}
+	return result, nil
+}
+
+func (c *CachedLattice) ListListenersAsList(ctx context.Context, input *vpclattice.ListListenersInput) ([]*vpclattice.ListenerSummary, error) {
+	if isPaged(input.MaxResults, input.NextToken) {
+		return c.Lattice.ListListenersAsList(ctx, input)
+	}
+	return c.cachedListeners(ctx, input.ServiceIdentifier)
+}
+
+// ListListenersWithContext returns all listeners in a single page when no page is requested
+func (c *CachedLattice) ListListenersWithContext(ctx context.Context, input *vpclattice.ListListenersInput, option ...request.Option) (*vpclattice.ListListenersOutput, error) {
+	if isPaged(input.MaxResults, input.NextToken) || len(option) > 0 {
+		return c.Lattice.ListListenersWithContext(ctx, input, option...)
+	}
+	listeners, err := c.cachedListeners(ctx, input.ServiceIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	return &vpclattice.ListListenersOutput{Items: listeners}, nil
+}
+
+func (c *CachedLattice) GetRulesAsList(ctx context.Context, input *vpclattice.ListRulesInput) ([]*vpclattice.GetRuleOutput, error) {
+	if isPaged(input.MaxResults, input.NextToken) {
+		return c.Lattice.GetRulesAsList(ctx, input)
+	}
+	return c.cachedRules(ctx, input.ServiceIdentifier, input.ListenerIdentifier)
+}
+
+// ListRulesAsList is served from memory only when the rules of the listener are already cached,
+// listing them with GetRulesAsList would cost one call per rule.
+func (c *CachedLattice) ListRulesAsList(ctx context.Context, input *vpclattice.ListRulesInput) ([]*vpclattice.RuleSummary, error) {
+	c.lock.Lock()
+	rules := c.rules[idOf(input.ListenerIdentifier)]
+	if rules == nil || isPaged(input.MaxResults, input.NextToken) {
+		c.lock.Unlock()
+		return c.Lattice.ListRulesAsList(ctx, input)
+	}
+	defer c.lock.Unlock()
+	result := []*vpclattice.RuleSummary{}
+	for _, r := range sortedCopies(rules) {
+		result = append(result, &vpclattice.RuleSummary{
+			Arn:           r.Arn,
+			CreatedAt:     r.CreatedAt,
+			Id:            r.Id,
+			IsDefault:     r.IsDefault,
+			LastUpdatedAt: r.LastUpdatedAt,
+			Name:          r.Name,
+			Priority:      r.Priority,
+		})
+	}
+	return result, nil
+}
+
+func (c *CachedLattice) GetRuleWithContext(ctx context.Context, input *vpclattice.GetRuleInput, option ...request.Option) (*vpclattice.GetRuleOutput, error) {
+	c.lock.Lock()
+	if rule, ok := c.rules[idOf(input.ListenerIdentifier)][idOf(input.RuleIdentifier)]; ok && len(option) == 0 {
+		defer c.lock.Unlock()
+		result := *rule
+		return &result, nil
+	}
+	c.lock.Unlock()
+	return c.Lattice.GetRuleWithContext(ctx, input, option...)
+}
+
+func (c *CachedLattice) ListTagsForResourceWithContext(ctx context.Context, input *vpclattice.ListTagsForResourceInput, option ...request.Option) (*vpclattice.ListTagsForResourceOutput, error) {
+	arn := aws.StringValue(input.ResourceArn)
+	c.lock.Lock()
+	if tags, ok := c.tags[arn]; ok && len(option) == 0 {
+		defer c.lock.Unlock()
+		return &vpclattice.ListTagsForResourceOutput{Tags: cloneTags(tags)}, nil
+	}
+	version := c.version
+	c.lock.Unlock()
+
+	out, err := c.Lattice.ListTagsForResourceWithContext(ctx, input, option...)
+	if err != nil {
+		return nil, err
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.version == version {
+		c.tags[arn] = cloneTags(out.Tags)
+	}
+	return out, nil
+}
+
+func (c *CachedLattice) GetServiceWithContext(ctx context.Context, input *vpclattice.GetServiceInput, option ...request.Option) (*vpclattice.GetServiceOutput, error) {
+	out, err := c.Lattice.GetServiceWithContext(ctx, input, option...)
+	if IsLatticeAPINotFoundErr(err) {
+		c.update(func() { c.removeService(idOf(input.ServiceIdentifier)) })
+	}
+	return out, err
+}
+
+func (c *CachedLattice) CreateServiceWithContext(ctx context.Context, input *vpclattice.CreateServiceInput, option ...request.Option) (*vpclattice.CreateServiceOutput, error) {
+	out, err := c.Lattice.CreateServiceWithContext(ctx, input, option...)
+	c.update(func() {
+		if err != nil {
+			c.services = nil
+			return
+		}
+		if c.services != nil {
+			c.services[aws.StringValue(out.Id)] = &vpclattice.ServiceSummary{
+				Arn:              out.Arn,
+				CustomDomainName: out.CustomDomainName,
+				DnsEntry:         out.DnsEntry,
+				Id:               out.Id,
+				Name:             out.Name,
+				Status:           out.Status,
+			}
+		}
+		c.tags[aws.StringValue(out.Arn)] = cloneTags(input.Tags)
+	})
+	return out, err
+}
+
+func (c *CachedLattice) UpdateServiceWithContext(ctx context.Context, input *vpclattice.UpdateServiceInput, option ...request.Option) (*vpclattice.UpdateServiceOutput, error) {
+	out, err := c.Lattice.UpdateServiceWithContext(ctx, input, option...)
+	if IsLatticeAPINotFoundErr(err) {
+		c.update(func() { c.removeService(idOf(input.ServiceIdentifier)) })
+	}
+	return out, err
+}
+
+func (c *CachedLattice) DeleteServiceWithContext(ctx context.Context, input *vpclattice.DeleteServiceInput, option ...request.Option) (*vpclattice.DeleteServiceOutput, error) {
+	out, err := c.Lattice.DeleteServiceWithContext(ctx, input, option...)
+	c.update(func() {
+		if err != nil && !IsLatticeAPINotFoundErr(err) {
+			c.services = nil
+			return
+		}
+		c.removeService(idOf(input.ServiceIdentifier))
+	})
+	return out, err
+}
+
+func (c *CachedLattice) removeService(svcId string) {
+	if svc, ok := c.services[svcId]; ok {
+		delete(c.tags, aws.StringValue(svc.Arn))
+		delete(c.services, svcId)
+	}
+	for listenerId := range c.listeners[svcId] {
+		delete(c.rules, listenerId)
+	}
+	delete(c.listeners, svcId)
+}
+
+func (c *CachedLattice) GetTargetGroupWithContext(ctx context.Context, input *vpclattice.GetTargetGroupInput, option ...request.Option) (*vpclattice.GetTargetGroupOutput, error) {
+	out, err := c.Lattice.GetTargetGroupWithContext(ctx, input, option...)
+	if IsLatticeAPINotFoundErr(err) {
+		c.update(func() { c.removeTargetGroup(idOf(input.TargetGroupIdentifier)) })
+	}
+	return out, err
+}
+
+func (c *CachedLattice) CreateTargetGroupWithContext(ctx context.Context, input *vpclattice.CreateTargetGroupInput, option ...request.Option) (*vpclattice.CreateTargetGroupOutput, error) {
+	out, err := c.Lattice.CreateTargetGroupWithContext(ctx, input, option...)
+	c.update(func() {
+		if err != nil {
+			c.targetGroups = nil
+			return
+		}
+		if c.targetGroups != nil {
+			tg := &vpclattice.TargetGroupSummary{
+				Arn:    out.Arn,
+				Id:     out.Id,
+				Name:   out.Name,
+				Status: out.Status,
+				Type:   out.Type,
+			}
+			if out.Config != nil {
+				tg.IpAddressType = out.Config.IpAddressType
+				tg.LambdaEventStructureVersion = out.Config.LambdaEventStructureVersion
+				tg.Port = out.Config.Port
+				tg.Protocol = out.Config.Protocol
+				tg.VpcIdentifier = out.Config.VpcIdentifier
+			}
+			c.targetGroups[aws.StringValue(out.Id)] = tg
+		}
+		c.tags[aws.StringValue(out.Arn)] = cloneTags(input.Tags)
+	})
+	return out, err
+}
+
+func (c *CachedLattice) UpdateTargetGroupWithContext(ctx context.Context, input *vpclattice.UpdateTargetGroupInput, option ...request.Option) (*vpclattice.UpdateTargetGroupOutput, error) {
+	out, err := c.Lattice.UpdateTargetGroupWithContext(ctx, input, option...)
+	if IsLatticeAPINotFoundErr(err) {
+		c.update(func() { c.removeTargetGroup(idOf(input.TargetGroupIdentifier)) })
+	}
+	return out, err
+}
+
+func (c *CachedLattice) DeleteTargetGroupWithContext(ctx context.Context, input *vpclattice.DeleteTargetGroupInput, option ...request.Option) (*vpclattice.DeleteTargetGroupOutput, error) {
+	out, err := c.Lattice.DeleteTargetGroupWithContext(ctx, input, option...)
+	c.update(func() {
+		if err != nil && !IsLatticeAPINotFoundErr(err) {
+			c.targetGroups = nil
+			return
+		}
+		c.removeTargetGroup(idOf(input.TargetGroupIdentifier))
+	})
+	return out, err
+}
+
+func (c *CachedLattice) removeTargetGroup(tgId string) {
+	if tg, ok := c.targetGroups[tgId]; ok {
+		delete(c.tags, aws.StringValue(tg.Arn))
+		delete(c.targetGroups, tgId)
+	}
+}
+
+// markTargetGroupsInUse adds the service to the target groups the action forwards to.
+func (c *CachedLattice) markTargetGroupsInUse(action *vpclattice.RuleAction, svcArn string) {
+	if action == nil || action.Forward == nil || svcArn == "" {
+		return
+	}
+	for _, wtg := range action.Forward.TargetGroups {
+		tg, ok := c.targetGroups[idOf(wtg.TargetGroupIdentifier)]
+		if ok && !slices.Contains(aws.StringValueSlice(tg.ServiceArns), svcArn) {
+			tg.ServiceArns = append(slices.Clone(tg.ServiceArns), aws.String(svcArn))
+		}
+	}
+}
+
+// releaseTargetGroups removes the service from the target groups the previous action of a rule forwarded
+// to and the current one does not, unless another cached rule of the service still forwards to them.
+// Listener default actions are not cached, a target group wrongly seen unused fails to delete, as Lattice
+// rejects the deletion of a target group still in use, and the failed delete drops the target groups.
+func (c *CachedLattice) releaseTargetGroups(previous, current *vpclattice.RuleAction, svcArn string) {
+	released := forwardedTargetGroups(previous)
+	for tgId := range forwardedTargetGroups(current) {
+		delete(released, tgId)
+	}
+	if len(released) == 0 || svcArn == "" {
+		return
+	}
+	for _, rules := range c.rules {
+		for _, rule := range rules {
+			if serviceArnOf(rule.Arn) != svcArn {
+				continue
+			}
+			for tgId := range forwardedTargetGroups(rule.Action) {
+				delete(released, tgId)
+			}
+		}
+	}
+	for tgId := range released {
+		if tg, ok := c.targetGroups[tgId]; ok {
+			tg.ServiceArns = slices.DeleteFunc(slices.Clone(tg.ServiceArns), func(arn *string) bool {
+				return aws.StringValue(arn) == svcArn
+			})
+		}
+	}
+}
+
+func forwardedTargetGroups(action *vpclattice.RuleAction) map[string]bool {
+	tgIds := map[string]bool{}
+	if action == nil || action.Forward == nil {
+		return tgIds
+	}
+	for _, wtg := range action.Forward.TargetGroups {
+		tgIds[idOf(wtg.TargetGroupIdentifier)] = true
+	}
+	return tgIds
+}
+
+func (c *CachedLattice) CreateListenerWithContext(ctx context.Context, input *vpclattice.CreateListenerInput, option ...request.Option) (*vpclattice.CreateListenerOutput, error) {
+	out, err := c.Lattice.CreateListenerWithContext(ctx, input, option...)
+	c.update(func() {
+		if err != nil {
+			delete(c.listeners, idOf(input.ServiceIdentifier))
+			return
+		}
+		if listeners := c.listeners[aws.StringValue(out.ServiceId)]; listeners != nil {
+			listeners[aws.StringValue(out.Id)] = &vpclattice.ListenerSummary{
+				Arn:      out.Arn,
+				Id:       out.Id,
+				Name:     out.Name,
+				Port:     out.Port,
+				Protocol: out.Protocol,
+			}
+		}
+		c.markTargetGroupsInUse(out.DefaultAction, aws.StringValue(out.ServiceArn))
+		c.tags[aws.StringValue(out.Arn)] = cloneTags(input.Tags)
+	})
+	return out, err
+}
+
+func (c *CachedLattice) UpdateListenerWithContext(ctx context.Context, input *vpclattice.UpdateListenerInput, option ...request.Option) (*vpclattice.UpdateListenerOutput, error) {
+	out, err := c.Lattice.UpdateListenerWithContext(ctx, input, option...)
+	c.update(func() {
+		if err != nil {
+			delete(c.listeners, idOf(input.ServiceIdentifier))
+			return
+		}
+		// the previous default action is not cached, the target groups it forwarded to are listed again
+		c.targetGroups = nil
+	})
+	return out, err
+}
+
+func (c *CachedLattice) DeleteListenerWithContext(ctx context.Context, input *vpclattice.DeleteListenerInput, option ...request.Option) (*vpclattice.DeleteListenerOutput, error) {
+	out, err := c.Lattice.DeleteListenerWithContext(ctx, input, option...)
+	c.update(func() {
+		svcId, listenerId := idOf(input.ServiceIdentifier), idOf(input.ListenerIdentifier)
+		if err != nil && !IsLatticeAPINotFoundErr(err) {
+			delete(c.listeners, svcId)
+			return
+		}
+		if listener, ok := c.listeners[svcId][listenerId]; ok {
+			delete(c.tags, aws.StringValue(listener.Arn))
+			delete(c.listeners[svcId], listenerId)
+		}
+		delete(c.rules, listenerId)
+		// the target groups the listener and its rules forwarded to are listed again
+		c.targetGroups = nil
+	})
+	return out, err
+}
+
+func (c *CachedLattice) CreateRuleWithContext(ctx context.Context, input *vpclattice.CreateRuleInput, option ...request.Option) (*vpclattice.CreateRuleOutput, error) {
+	out, err := c.Lattice.CreateRuleWithContext(ctx, input, option...)
+	c.update(func() {
+		listenerId := idOf(input.ListenerIdentifier)
+		if err != nil {
+			delete(c.rules, listenerId)
+			return
+		}
+		if rules := c.rules[listenerId]; rules != nil {
+			rules[aws.StringValue(out.Id)] = &vpclattice.GetRuleOutput{
+				Action:    out.Action,
+				Arn:       out.Arn,
+				Id:        out.Id,
+				IsDefault: aws.Bool(false),
+				Match:     out.Match,
+				Name:      out.Name,
+				Priority:  out.Priority,
+			}
+		}
+		c.markTargetGroupsInUse(out.Action, serviceArnOf(out.Arn))
+		c.tags[aws.StringValue(out.Arn)] = cloneTags(input.Tags)
+	})
+	return out, err
+}
+
+func (c *CachedLattice) UpdateRuleWithContext(ctx context.Context, input *vpclattice.UpdateRuleInput, option ...request.Option) (*vpclattice.UpdateRuleOutput, error) {
+	out, err := c.Lattice.UpdateRuleWithContext(ctx, input, option...)
+	c.update(func() {
+		listenerId := idOf(input.ListenerIdentifier)
+		if err != nil {
+			delete(c.rules, listenerId)
+			return
+		}
+		c.updateRule(listenerId, &vpclattice.RuleUpdateSuccess{
+			Action:    out.Action,
+			Arn:       out.Arn,
+			Id:        out.Id,
+			IsDefault: out.IsDefault,
+			Match:     out.Match,
+			Name:      out.Name,
+			Priority:  out.Priority,
+		})
+	})
+	return out, err
+}
+
+func (c *CachedLattice) BatchUpdateRuleWithContext(ctx context.Context, input *vpclattice.BatchUpdateRuleInput, option ...request.Option) (*vpclattice.BatchUpdateRuleOutput, error) {
+	out, err := c.Lattice.BatchUpdateRuleWithContext(ctx, input, option...)
+	c.update(func() {
+		listenerId := idOf(input.ListenerIdentifier)
+		if err != nil || len(out.Unsuccessful) > 0 {
+			delete(c.rules, listenerId)
+			return
+		}
+		for _, updated := range out.Successful {
+			c.updateRule(listenerId, updated)
+		}
+	})
+	return out, err
+}
+
+// updateRule sets the fields returned by an update and recomputes the services of the target groups the
+// rule forwards to. When the rule is not cached, its rules and the target groups are listed again.
+func (c *CachedLattice) updateRule(listenerId string, updated *vpclattice.RuleUpdateSuccess) {
+	rules := c.rules[listenerId]
+	if rules == nil {
+		if updated.Action != nil {
+			c.targetGroups = nil
+		}
+		return
+	}
+	rule, ok := rules[aws.StringValue(updated.Id)]
+	if !ok {
+		delete(c.rules, listenerId)
+		if updated.Action != nil {
+			c.targetGroups = nil
+		}
+		return
+	}
+	previous := rule.Action
+	rule = &vpclattice.GetRuleOutput{
+		Action:        rule.Action,
+		Arn:           rule.Arn,
+		CreatedAt:     rule.CreatedAt,
+		Id:            rule.Id,
+		IsDefault:     rule.IsDefault,
+		LastUpdatedAt: rule.LastUpdatedAt,
+		Match:         rule.Match,
+		Name:          rule.Name,
+		Priority:      rule.Priority,
+	}
+	if updated.Action != nil {
+		rule.Action = updated.Action
+	}
+	if updated.Match != nil {
+		rule.Match = updated.Match
+	}
+	if updated.Priority != nil {
+		rule.Priority = updated.Priority
+	}
+	rules[aws.StringValue(rule.Id)] = rule
+	c.markTargetGroupsInUse(rule.Action, serviceArnOf(rule.Arn))
+	c.releaseTargetGroups(previous, rule.Action, serviceArnOf(rule.Arn))
+}
+
+func (c *CachedLattice) DeleteRuleWithContext(ctx context.Context, input *vpclattice.DeleteRuleInput, option ...request.Option) (*vpclattice.DeleteRuleOutput, error) {
+	out, err := c.Lattice.DeleteRuleWithContext(ctx, input, option...)
+	c.update(func() {
+		listenerId, ruleId := idOf(input.ListenerIdentifier), idOf(input.RuleIdentifier)
+		if err != nil && !IsLatticeAPINotFoundErr(err) {
+			delete(c.rules, listenerId)
+			return
+		}
+		rule, ok := c.rules[listenerId][ruleId]
+		if !ok {
+			// the target groups the rule forwarded to are listed again
+			c.targetGroups = nil
+			return
+		}
+		delete(c.tags, aws.StringValue(rule.Arn))
+		delete(c.rules[listenerId], ruleId)
+		c.releaseTargetGroups(rule.Action, nil, serviceArnOf(rule.Arn))
+	})
+	return out, err
+}
+
+func (c *CachedLattice) TagResourceWithContext(ctx context.Context, input *vpclattice.TagResourceInput, option ...request.Option) (*vpclattice.TagResourceOutput, error) {
+	out, err := c.Lattice.TagResourceWithContext(ctx, input, option...)
+	c.update(func() {
+		arn := aws.StringValue(input.ResourceArn)
+		tags, ok := c.tags[arn]
+		if err != nil || !ok {
+			delete(c.tags, arn)
+			return
+		}
+		maps.Copy(tags, input.Tags)
+	})
+	return out, err
+}
+
+func (c *CachedLattice) UntagResourceWithContext(ctx context.Context, input *vpclattice.UntagResourceInput, option ...request.Option) (*vpclattice.UntagResourceOutput, error) {
+	out, err := c.Lattice.UntagResourceWithContext(ctx, input, option...)
+	c.update(func() {
+		arn := aws.StringValue(input.ResourceArn)
+		tags, ok := c.tags[arn]
+		if err != nil || !ok {
+			delete(c.tags, arn)
+			return
+		}
+		for _, key := range input.TagKeys {
+			delete(tags, aws.StringValue(key))
+		}
+	})
+	return out, err
+}
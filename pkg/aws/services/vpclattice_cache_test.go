@@ -0,0 +1,292 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/vpclattice"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aws/aws-application-networking-k8s/pkg/utils/gwlog"
+)
+
+// countingLattice counts the list calls reaching the fake Lattice
+type countingLattice struct {
+	*FakeLattice
+	calls map[string]int
+}
+
+func (l *countingLattice) ListServicesAsList(ctx context.Context, input *vpclattice.ListServicesInput) ([]*vpclattice.ServiceSummary, error) {
+	l.calls["ListServices"]++
+	return l.FakeLattice.ListServicesAsList(ctx, input)
+}
+
+func (l *countingLattice) ListTargetGroupsAsList(ctx context.Context, input *vpclattice.ListTargetGroupsInput) ([]*vpclattice.TargetGroupSummary, error) {
+	l.calls["ListTargetGroups"]++
+	return l.FakeLattice.ListTargetGroupsAsList(ctx, input)
+}
+
+func (l *countingLattice) ListListenersAsList(ctx context.Context, input *vpclattice.ListListenersInput) ([]*vpclattice.ListenerSummary, error) {
+	l.calls["ListListeners"]++
+	return l.FakeLattice.ListListenersAsList(ctx, input)
+}
+
+func (l *countingLattice) GetRulesAsList(ctx context.Context, input *vpclattice.ListRulesInput) ([]*vpclattice.GetRuleOutput, error) {
+	l.calls["GetRules"]++
+	return l.FakeLattice.GetRulesAsList(ctx, input)
+}
+
+func (l *countingLattice) ListTagsForResourceWithContext(ctx context.Context, input *vpclattice.ListTagsForResourceInput, option ...request.Option) (*vpclattice.ListTagsForResourceOutput, error) {
+	l.calls["ListTagsForResource"]++
+	return l.FakeLattice.ListTagsForResourceWithContext(ctx, input, option...)
+}
+
+func newTestCachedLattice() (*CachedLattice, *countingLattice) {
+	backend := &countingLattice{FakeLattice: NewFakeLattice("111111111111", "us-west-2"), calls: map[string]int{}}
+	return NewCachedLattice(gwlog.FallbackLogger, backend, 0), backend
+}
+
+func TestCachedLattice_Services(t *testing.T) {
+	ctx := context.TODO()
+	c, backend := newTestCachedLattice()
+
+	svc1, err := c.CreateServiceWithContext(ctx, &vpclattice.CreateServiceInput{Name: aws.String("svc-1")})
+	assert.NoError(t, err)
+
+	found, err := c.FindService(ctx, "svc-1")
+	assert.NoError(t, err)
+	assert.Equal(t, *svc1.Id, *found.Id)
+	assert.Equal(t, 1, backend.calls["ListServices"])
+
+	// write-through once listed
+	svc2, err := c.CreateServiceWithContext(ctx, &vpclattice.CreateServiceInput{Name: aws.String("svc-2")})
+	assert.NoError(t, err)
+	found, err = c.FindService(ctx, "svc-2")
+	assert.NoError(t, err)
+	assert.Equal(t, *svc2.DnsEntry.DomainName, *found.DnsEntry.DomainName)
+	_, err = c.DeleteServiceWithContext(ctx, &vpclattice.DeleteServiceInput{ServiceIdentifier: svc1.Arn})
+	assert.NoError(t, err)
+	_, err = c.FindService(ctx, "svc-1")
+	assert.True(t, IsNotFoundError(err))
+	svcs, err := c.ListServicesAsList(ctx, &vpclattice.ListServicesInput{})
+	assert.NoError(t, err)
+	assert.Len(t, svcs, 1)
+	assert.Equal(t, 1, backend.calls["ListServices"])
+
+	// returned items are copies
+	svcs[0].Name = aws.String("changed")
+	_, err = c.FindService(ctx, "svc-2")
+	assert.NoError(t, err)
+
+	// failed writes drop the collection
+	_, err = c.CreateServiceWithContext(ctx, &vpclattice.CreateServiceInput{Name: aws.String("svc-2")})
+	assert.Error(t, err)
+	_, err = c.FindService(ctx, "svc-2")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, backend.calls["ListServices"])
+
+	// changes made outside of the cache are seen after a resync
+	_, err = backend.CreateServiceWithContext(ctx, &vpclattice.CreateServiceInput{Name: aws.String("svc-3")})
+	assert.NoError(t, err)
+	_, err = c.FindService(ctx, "svc-3")
+	assert.True(t, IsNotFoundError(err))
+	assert.NoError(t, c.Resync(ctx))
+	_, err = c.FindService(ctx, "svc-3")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, backend.calls["ListServices"])
+
+	_, err = backend.CreateServiceWithContext(ctx, &vpclattice.CreateServiceInput{Name: aws.String("svc-4")})
+	assert.NoError(t, err)
+	c.Clear()
+	_, err = c.FindService(ctx, "svc-4")
+	assert.NoError(t, err)
+	assert.Equal(t, 4, backend.calls["ListServices"])
+}
+
+func TestCachedLattice_TargetGroupsAndTags(t *testing.T) {
+	ctx := context.TODO()
+	c, backend := newTestCachedLattice()
+
+	createTg := func(name, vpc string) *vpclattice.CreateTargetGroupOutput {
+		tg, err := c.CreateTargetGroupWithContext(ctx, &vpclattice.CreateTargetGroupInput{
+			Name:   aws.String(name),
+			Type:   aws.String(vpclattice.TargetGroupTypeIp),
+			Config: &vpclattice.TargetGroupConfig{VpcIdentifier: aws.String(vpc), Port: aws.Int64(80), Protocol: aws.String("HTTP")},
+			Tags:   Tags{"k": aws.String("v")},
+		})
+		assert.NoError(t, err)
+		return tg
+	}
+	tg1 := createTg("tg-1", "vpc-1")
+	tgs, err := c.ListTargetGroupsAsList(ctx, &vpclattice.ListTargetGroupsInput{})
+	assert.NoError(t, err)
+	assert.Len(t, tgs, 1)
+	tg2 := createTg("tg-2", "vpc-2")
+	tgs, err = c.ListTargetGroupsAsList(ctx, &vpclattice.ListTargetGroupsInput{VpcIdentifier: aws.String("vpc-2")})
+	assert.NoError(t, err)
+	assert.Len(t, tgs, 1)
+	assert.Equal(t, *tg2.Id, *tgs[0].Id)
+	assert.Equal(t, int64(80), aws.Int64Value(tgs[0].Port))
+	assert.Equal(t, 1, backend.calls["ListTargetGroups"])
+
+	// tags of created resources are known without listing them
+	tags, err := c.ListTagsForResourceWithContext(ctx, &vpclattice.ListTagsForResourceInput{ResourceArn: tg1.Arn})
+	assert.NoError(t, err)
+	assert.Equal(t, Tags{"k": aws.String("v")}, tags.Tags)
+	_, err = c.TagResourceWithContext(ctx, &vpclattice.TagResourceInput{ResourceArn: tg1.Arn, Tags: Tags{"l": aws.String("w")}})
+	assert.NoError(t, err)
+	_, err = c.UntagResourceWithContext(ctx, &vpclattice.UntagResourceInput{ResourceArn: tg1.Arn, TagKeys: aws.StringSlice([]string{"k"})})
+	assert.NoError(t, err)
+	tags, err = c.ListTagsForResourceWithContext(ctx, &vpclattice.ListTagsForResourceInput{ResourceArn: tg1.Arn})
+	assert.NoError(t, err)
+	assert.Equal(t, Tags{"l": aws.String("w")}, tags.Tags)
+	assert.Equal(t, 0, backend.calls["ListTagsForResource"])
+
+	// lattice tagging finds target groups from memory
+	arns, err := NewLatticeTagging(c, "vpc-1").FindResourcesByTags(ctx, ResourceTypeTargetGroup, Tags{"l": aws.String("w")})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{*tg1.Arn}, arns)
+	assert.Equal(t, 1, backend.calls["ListTargetGroups"])
+	assert.Equal(t, 0, backend.calls["ListTagsForResource"])
+
+	// resources not found are removed
+	_, err = backend.DeleteTargetGroupWithContext(ctx, &vpclattice.DeleteTargetGroupInput{TargetGroupIdentifier: tg2.Id})
+	assert.NoError(t, err)
+	_, err = c.GetTargetGroupWithContext(ctx, &vpclattice.GetTargetGroupInput{TargetGroupIdentifier: tg2.Arn})
+	assert.True(t, IsLatticeAPINotFoundErr(err))
+	tgs, err = c.ListTargetGroupsAsList(ctx, &vpclattice.ListTargetGroupsInput{})
+	assert.NoError(t, err)
+	assert.Len(t, tgs, 1)
+
+	// resync refreshes tags
+	assert.NoError(t, c.Resync(ctx))
+	assert.Equal(t, 1, backend.calls["ListTagsForResource"])
+	tags, err = c.ListTagsForResourceWithContext(ctx, &vpclattice.ListTagsForResourceInput{ResourceArn: tg1.Arn})
+	assert.NoError(t, err)
+	assert.Equal(t, Tags{"l": aws.String("w")}, tags.Tags)
+	assert.Equal(t, 1, backend.calls["ListTagsForResource"])
+}
+
+func TestCachedLattice_ListenersAndRules(t *testing.T) {
+	ctx := context.TODO()
+	c, backend := newTestCachedLattice()
+
+	svc, listener := createFakeService(t, backend.FakeLattice, "svc")
+	tg, err := backend.CreateTargetGroupWithContext(ctx, &vpclattice.CreateTargetGroupInput{
+		Name:   aws.String("tg"),
+		Type:   aws.String(vpclattice.TargetGroupTypeIp),
+		Config: &vpclattice.TargetGroupConfig{VpcIdentifier: aws.String("vpc-1"), Port: aws.Int64(80)},
+	})
+	assert.NoError(t, err)
+	tgs, err := c.ListTargetGroupsAsList(ctx, &vpclattice.ListTargetGroupsInput{})
+	assert.NoError(t, err)
+	assert.Empty(t, tgs[0].ServiceArns)
+
+	out, err := c.ListListenersWithContext(ctx, &vpclattice.ListListenersInput{ServiceIdentifier: svc.Id})
+	assert.NoError(t, err)
+	assert.Len(t, out.Items, 1)
+	listener2, err := c.CreateListenerWithContext(ctx, &vpclattice.CreateListenerInput{
+		ServiceIdentifier: svc.Arn,
+		Name:              aws.String("svc-443"),
+		Protocol:          aws.String(vpclattice.ListenerProtocolHttps),
+		Port:              aws.Int64(443),
+		DefaultAction: &vpclattice.RuleAction{
+			FixedResponse: &vpclattice.FixedResponseAction{StatusCode: aws.Int64(404)},
+		},
+	})
+	assert.NoError(t, err)
+	listeners, err := c.ListListenersAsList(ctx, &vpclattice.ListListenersInput{ServiceIdentifier: svc.Arn})
+	assert.NoError(t, err)
+	assert.Len(t, listeners, 2)
+	_, err = c.DeleteListenerWithContext(ctx, &vpclattice.DeleteListenerInput{ServiceIdentifier: svc.Id, ListenerIdentifier: listener2.Arn})
+	assert.NoError(t, err)
+	listeners, err = c.ListListenersAsList(ctx, &vpclattice.ListListenersInput{ServiceIdentifier: svc.Id})
+	assert.NoError(t, err)
+	assert.Len(t, listeners, 1)
+	assert.Equal(t, 1, backend.calls["ListListeners"])
+
+	ruleInput := &vpclattice.ListRulesInput{ServiceIdentifier: svc.Id, ListenerIdentifier: listener.Id}
+	rules, err := c.GetRulesAsList(ctx, ruleInput)
+	assert.NoError(t, err)
+	assert.Len(t, rules, 1)
+	rule, err := c.CreateRuleWithContext(ctx, &vpclattice.CreateRuleInput{
+		ServiceIdentifier:  svc.Id,
+		ListenerIdentifier: listener.Id,
+		Name:               aws.String("rule"),
+		Priority:           aws.Int64(1),
+		Match: &vpclattice.RuleMatch{HttpMatch: &vpclattice.HttpMatch{
+			PathMatch: &vpclattice.PathMatch{Match: &vpclattice.PathMatchType{Prefix: aws.String("/")}},
+		}},
+		Action: &vpclattice.RuleAction{Forward: &vpclattice.ForwardAction{
+			TargetGroups: []*vpclattice.WeightedTargetGroup{{TargetGroupIdentifier: tg.Id, Weight: aws.Int64(1)}},
+		}},
+	})
+	assert.NoError(t, err)
+	tgs, err = c.ListTargetGroupsAsList(ctx, &vpclattice.ListTargetGroupsInput{})
+	assert.NoError(t, err)
+	assert.Equal(t, []*string{svc.Arn}, tgs[0].ServiceArns)
+
+	_, err = c.BatchUpdateRuleWithContext(ctx, &vpclattice.BatchUpdateRuleInput{
+		ServiceIdentifier:  svc.Id,
+		ListenerIdentifier: listener.Id,
+		Rules:              []*vpclattice.RuleUpdate{{RuleIdentifier: rule.Id, Priority: aws.Int64(5)}},
+	})
+	assert.NoError(t, err)
+	got, err := c.GetRuleWithContext(ctx, &vpclattice.GetRuleInput{ServiceIdentifier: svc.Id, ListenerIdentifier: listener.Id, RuleIdentifier: rule.Arn})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), aws.Int64Value(got.Priority))
+	summaries, err := c.ListRulesAsList(ctx, ruleInput)
+	assert.NoError(t, err)
+	assert.Len(t, summaries, 2)
+
+	// target groups no rule of the service forwards to anymore are not in use by the service
+	_, err = c.UpdateRuleWithContext(ctx, &vpclattice.UpdateRuleInput{
+		ServiceIdentifier:  svc.Id,
+		ListenerIdentifier: listener.Id,
+		RuleIdentifier:     rule.Id,
+		Action:             &vpclattice.RuleAction{FixedResponse: &vpclattice.FixedResponseAction{StatusCode: aws.Int64(404)}},
+	})
+	assert.NoError(t, err)
+	tgs, err = c.ListTargetGroupsAsList(ctx, &vpclattice.ListTargetGroupsInput{})
+	assert.NoError(t, err)
+	assert.Empty(t, tgs[0].ServiceArns)
+	_, err = c.UpdateRuleWithContext(ctx, &vpclattice.UpdateRuleInput{
+		ServiceIdentifier:  svc.Id,
+		ListenerIdentifier: listener.Id,
+		RuleIdentifier:     rule.Id,
+		Action: &vpclattice.RuleAction{Forward: &vpclattice.ForwardAction{
+			TargetGroups: []*vpclattice.WeightedTargetGroup{{TargetGroupIdentifier: tg.Id, Weight: aws.Int64(1)}},
+		}},
+	})
+	assert.NoError(t, err)
+	tgs, err = c.ListTargetGroupsAsList(ctx, &vpclattice.ListTargetGroupsInput{})
+	assert.NoError(t, err)
+	assert.Equal(t, []*string{svc.Arn}, tgs[0].ServiceArns)
+
+	_, err = c.DeleteRuleWithContext(ctx, &vpclattice.DeleteRuleInput{ServiceIdentifier: svc.Id, ListenerIdentifier: listener.Id, RuleIdentifier: rule.Id})
+	assert.NoError(t, err)
+	tgs, err = c.ListTargetGroupsAsList(ctx, &vpclattice.ListTargetGroupsInput{})
+	assert.NoError(t, err)
+	assert.Empty(t, tgs[0].ServiceArns)
+	// listed again after the listener delete only
+	assert.Equal(t, 2, backend.calls["ListTargetGroups"])
+	rules, err = c.GetRulesAsList(ctx, ruleInput)
+	assert.NoError(t, err)
+	assert.Len(t, rules, 1)
+	assert.True(t, aws.BoolValue(rules[0].IsDefault))
+	assert.Equal(t, 1, backend.calls["GetRules"])
+
+	// conflicting priority drops the rules of the listener
+	_, err = c.CreateRuleWithContext(ctx, &vpclattice.CreateRuleInput{
+		ServiceIdentifier:  svc.Id,
+		ListenerIdentifier: listener.Id,
+		Name:               aws.String("invalid"),
+		Priority:           aws.Int64(0),
+	})
+	assert.Error(t, err)
+	_, err = c.GetRulesAsList(ctx, ruleInput)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, backend.calls["GetRules"])
+}
@@ -0,0 +1,2201 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/vpclattice"
+)
+
+// FakeLatticeEndpoint selects the in-memory Lattice implementation when set as LATTICE_ENDPOINT
+const FakeLatticeEndpoint = "fake://"
+
+// default page size of the fake list operations, same as the Lattice API maximum
+const fakeDefaultPageSize = 100
+
+// FakeLattice is a stateful in-memory implementation of the Lattice interface, for tests and local runs.
+// The list helpers and finders are the ones of the real implementation, running on top of the fake API.
+type FakeLattice struct {
+	*defaultLattice
+	api *fakeLatticeAPI
+}
+
+func NewFakeLattice(account string, region string) *FakeLattice {
+	api := newFakeLatticeAPI(account, region)
+	return &FakeLattice{
+		defaultLattice: &defaultLattice{
+			VPCLatticeAPI: api,
+			ownAccount:    account,
+		},
+		api: api,
+	}
+}
+
+// Tagging returns a Tagging implementation backed by the tags stored in the fake, same as
+// the Lattice API based tagging used when the Resource Groups Tagging API is disabled.
+func (f *FakeLattice) Tagging(vpcId string) Tagging {
+	return NewLatticeTagging(f, vpcId)
+}
+
+// SetPageSize changes the page size of list operations which do not set MaxResults.
+func (f *FakeLattice) SetPageSize(pageSize int) {
+	f.api.lock.Lock()
+	defer f.api.lock.Unlock()
+	f.api.pageSize = pageSize
+}
+
+// SetTargetStatus overrides the health status of a registered target. By default, targets are
+// UNUSED while their target group is not referenced by any rule, UNAVAILABLE when health checks
+// are disabled, and HEALTHY otherwise.
+func (f *FakeLattice) SetTargetStatus(tgIdentifier string, targetId string, port int64, status string, reasonCode string) error {
+	f.api.lock.Lock()
+	defer f.api.lock.Unlock()
+	tg, err := f.api.getTargetGroup(aws.String(tgIdentifier))
+	if err != nil {
+		return err
+	}
+	target, ok := tg.targets.get(fakeTargetKey(targetId, port))
+	if !ok {
+		return fakeNotFoundError("TARGET", fakeTargetKey(targetId, port))
+	}
+	target.Status = aws.String(status)
+	target.ReasonCode = nil
+	if reasonCode != "" {
+		target.ReasonCode = aws.String(reasonCode)
+	}
+	return nil
+}
+
+// FakeLatticeSnapshot is a copy of all resources of a fake Lattice, for inspection in tests and debugging.
+type FakeLatticeSnapshot struct {
+	ServiceNetworks        []*vpclattice.GetServiceNetworkOutput                   `json:"serviceNetworks"`
+	Services               []*vpclattice.GetServiceOutput                          `json:"services"`
+	ServiceAssociations    []*vpclattice.GetServiceNetworkServiceAssociationOutput `json:"serviceNetworkServiceAssociations"`
+	VpcAssociations        []*vpclattice.GetServiceNetworkVpcAssociationOutput     `json:"serviceNetworkVpcAssociations"`
+	Listeners              []*vpclattice.GetListenerOutput                         `json:"listeners"`
+	Rules                  []*vpclattice.GetRuleOutput                             `json:"rules"`
+	TargetGroups           []*vpclattice.GetTargetGroupOutput                      `json:"targetGroups"`
+	Targets                map[string][]*vpclattice.TargetSummary                  `json:"targets"`
+	AccessLogSubscriptions []*vpclattice.GetAccessLogSubscriptionOutput            `json:"accessLogSubscriptions"`
+	AuthPolicies           map[string]*vpclattice.GetAuthPolicyOutput              `json:"authPolicies"`
+	Tags                   map[string]Tags                                         `json:"tags"`
+}
+
+// Snapshot returns a copy of all resources, targets are keyed by target group id and auth policies by resource id.
+func (f *FakeLattice) Snapshot() *FakeLatticeSnapshot {
+	f.api.lock.Lock()
+	defer f.api.lock.Unlock()
+	snapshot := &FakeLatticeSnapshot{
+		Targets:      make(map[string][]*vpclattice.TargetSummary),
+		AuthPolicies: make(map[string]*vpclattice.GetAuthPolicyOutput),
+		Tags:         make(map[string]Tags),
+	}
+	for _, sn := range f.api.serviceNetworks.list() {
+		out := *sn
+		f.api.countServiceNetworkAssociations(&out)
+		snapshot.ServiceNetworks = append(snapshot.ServiceNetworks, &out)
+	}
+	for _, svc := range f.api.services.list() {
+		out := *svc
+		snapshot.Services = append(snapshot.Services, &out)
+	}
+	for _, snsa := range f.api.svcAssociations.list() {
+		out := *snsa
+		snapshot.ServiceAssociations = append(snapshot.ServiceAssociations, &out)
+	}
+	for _, snva := range f.api.vpcAssociations.list() {
+		out := *snva
+		snapshot.VpcAssociations = append(snapshot.VpcAssociations, &out)
+	}
+	for _, listener := range f.api.listeners.list() {
+		out := listener.GetListenerOutput
+		snapshot.Listeners = append(snapshot.Listeners, &out)
+	}
+	for _, rule := range f.api.rules.list() {
+		out := rule.GetRuleOutput
+		snapshot.Rules = append(snapshot.Rules, &out)
+	}
+	for _, tg := range f.api.targetGroups.list() {
+		out := tg.GetTargetGroupOutput
+		out.ServiceArns = f.api.targetGroupServiceArns(aws.StringValue(tg.Id))
+		snapshot.TargetGroups = append(snapshot.TargetGroups, &out)
+		targets := []*vpclattice.TargetSummary{}
+		for _, target := range tg.targets.list() {
+			t := *target
+			targets = append(targets, &t)
+		}
+		snapshot.Targets[aws.StringValue(tg.Id)] = targets
+	}
+	for _, als := range f.api.accessLogSubscriptions.list() {
+		out := *als
+		snapshot.AccessLogSubscriptions = append(snapshot.AccessLogSubscriptions, &out)
+	}
+	for _, policy := range f.api.authPolicies.list() {
+		out := policy.GetAuthPolicyOutput
+		snapshot.AuthPolicies[policy.resourceId] = &out
+	}
+	for resourceArn, tags := range f.api.tags {
+		out := make(Tags, len(tags))
+		for k, v := range tags {
+			out[k] = aws.String(aws.StringValue(v))
+		}
+		snapshot.Tags[resourceArn] = out
+	}
+	return snapshot
+}
+
+type fakeListener struct {
+	vpclattice.GetListenerOutput
+	defaultRuleId string
+}
+
+type fakeRule struct {
+	vpclattice.GetRuleOutput
+	serviceId  string
+	listenerId string
+}
+
+type fakeTargetGroup struct {
+	vpclattice.GetTargetGroupOutput
+	targets *fakeStore[*vpclattice.TargetSummary]
+}
+
+type fakeAuthPolicy struct {
+	vpclattice.GetAuthPolicyOutput
+	resourceId string
+}
+
+// fakeLatticeAPI keeps Lattice resources in memory and implements every operation of the Lattice API.
+// The request constructors are the exception, their requests fail with a NotImplemented error when sent.
+type fakeLatticeAPI struct {
+	lock     sync.Mutex
+	account  string
+	region   string
+	pageSize int
+	nextId   int64
+
+	serviceNetworks        *fakeStore[*vpclattice.GetServiceNetworkOutput]
+	services               *fakeStore[*vpclattice.GetServiceOutput]
+	svcAssociations        *fakeStore[*vpclattice.GetServiceNetworkServiceAssociationOutput]
+	vpcAssociations        *fakeStore[*vpclattice.GetServiceNetworkVpcAssociationOutput]
+	listeners              *fakeStore[*fakeListener]
+	rules                  *fakeStore[*fakeRule]
+	targetGroups           *fakeStore[*fakeTargetGroup]
+	accessLogSubscriptions *fakeStore[*vpclattice.GetAccessLogSubscriptionOutput]
+	authPolicies           *fakeStore[*fakeAuthPolicy]
+	resourcePolicies       map[string]string
+	tags                   map[string]Tags
+}
+
+func newFakeLatticeAPI(account string, region string) *fakeLatticeAPI {
+	if account == "" {
+		account = "123456789012"
+	}
+	if region == "" {
+		region = "us-west-2"
+	}
+	return &fakeLatticeAPI{
+		account:                account,
+		region:                 region,
+		pageSize:               fakeDefaultPageSize,
+		serviceNetworks:        newFakeStore[*vpclattice.GetServiceNetworkOutput](),
+		services:               newFakeStore[*vpclattice.GetServiceOutput](),
+		svcAssociations:        newFakeStore[*vpclattice.GetServiceNetworkServiceAssociationOutput](),
+		vpcAssociations:        newFakeStore[*vpclattice.GetServiceNetworkVpcAssociationOutput](),
+		listeners:              newFakeStore[*fakeListener](),
+		rules:                  newFakeStore[*fakeRule](),
+		targetGroups:           newFakeStore[*fakeTargetGroup](),
+		accessLogSubscriptions: newFakeStore[*vpclattice.GetAccessLogSubscriptionOutput](),
+		authPolicies:           newFakeStore[*fakeAuthPolicy](),
+		resourcePolicies:       make(map[string]string),
+		tags:                   make(map[string]Tags),
+	}
+}
+
+// fakeStore keeps items in creation order, so that list results are stable.
+type fakeStore[T any] struct {
+	items map[string]T
+	order []string
+}
+
+func newFakeStore[T any]() *fakeStore[T] {
+	return &fakeStore[T]{items: make(map[string]T)}
+}
+
+func (s *fakeStore[T]) get(id string) (T, bool) {
+	item, ok := s.items[id]
+	return item, ok
+}
+
+func (s *fakeStore[T]) put(id string, item T) {
+	if _, ok := s.items[id]; !ok {
+		s.order = append(s.order, id)
+	}
+	s.items[id] = item
+}
+
+func (s *fakeStore[T]) remove(id string) {
+	if _, ok := s.items[id]; !ok {
+		return
+	}
+	delete(s.items, id)
+	for i, orderedId := range s.order {
+		if orderedId == id {
+			s.order = append(s.order[:i], s.order[i+1:]...)
+			break
+		}
+	}
+}
+
+func (s *fakeStore[T]) list() []T {
+	result := make([]T, 0, len(s.order))
+	for _, id := range s.order {
+		result = append(result, s.items[id])
+	}
+	return result
+}
+
+func fakeNotFoundError(resourceType string, id string) error {
+	return &vpclattice.ResourceNotFoundException{
+		Message_:     aws.String(fmt.Sprintf("%s %s not found", resourceType, id)),
+		ResourceId:   aws.String(id),
+		ResourceType: aws.String(resourceType),
+	}
+}
+
+func fakeConflictError(resourceType string, id string, message string) error {
+	return &vpclattice.ConflictException{
+		Message_:     aws.String(message),
+		ResourceId:   aws.String(id),
+		ResourceType: aws.String(resourceType),
+	}
+}
+
+func fakeValidationError(message string) error {
+	return &vpclattice.ValidationException{
+		Message_: aws.String(message),
+		Reason:   aws.String(vpclattice.ValidationExceptionReasonFieldValidationFailed),
+	}
+}
+
+// fakeId accepts both ids and ARNs as identifiers, like the Lattice API
+func fakeId(identifier *string) string {
+	s := aws.StringValue(identifier)
+	if strings.HasPrefix(s, "arn:") {
+		return s[strings.LastIndex(s, "/")+1:]
+	}
+	return s
+}
+
+func fakeTargetKey(id string, port int64) string {
+	return fmt.Sprintf("%s:%d", id, port)
+}
+
+func fakePage[T any](items []T, pageSize int, maxResults *int64, nextToken *string) ([]T, *string, error) {
+	start := 0
+	if nextToken != nil {
+		n, err := strconv.Atoi(*nextToken)
+		if err != nil || n < 0 || n > len(items) {
+			return nil, nil, fakeValidationError("invalid nextToken " + *nextToken)
+		}
+		start = n
+	}
+	if maxResults != nil {
+		if *maxResults < 1 || *maxResults > fakeDefaultPageSize {
+			return nil, nil, fakeValidationError(fmt.Sprintf("maxResults must be between 1 and %d", fakeDefaultPageSize))
+		}
+		pageSize = int(*maxResults)
+	}
+	end := min(start+pageSize, len(items))
+	var next *string
+	if end < len(items) {
+		next = aws.String(strconv.Itoa(end))
+	}
+	return items[start:end], next, nil
+}
+
+func (f *fakeLatticeAPI) newId(prefix string) string {
+	f.nextId++
+	return fmt.Sprintf("%s-%017x", prefix, f.nextId)
+}
+
+func (f *fakeLatticeAPI) arn(resource string) string {
+	return arn.ARN{
+		Partition: "aws",
+		Service:   "vpc-lattice",
+		Region:    f.region,
+		AccountID: f.account,
+		Resource:  resource,
+	}.String()
+}
+
+func (f *fakeLatticeAPI) putTags(resourceArn string, tags Tags) {
+	stored := make(Tags, len(tags))
+	for k, v := range tags {
+		stored[k] = aws.String(aws.StringValue(v))
+	}
+	f.tags[resourceArn] = stored
+}
+
+func fakeNow() *time.Time {
+	t := time.Now()
+	return &t
+}
+
+// Service networks
+
+func (f *fakeLatticeAPI) getServiceNetwork(identifier *string) (*vpclattice.GetServiceNetworkOutput, error) {
+	sn, ok := f.serviceNetworks.get(fakeId(identifier))
+	if !ok {
+		return nil, fakeNotFoundError("SERVICE_NETWORK", aws.StringValue(identifier))
+	}
+	return sn, nil
+}
+
+func (f *fakeLatticeAPI) CreateServiceNetworkWithContext(_ aws.Context, input *vpclattice.CreateServiceNetworkInput, _ ...request.Option) (*vpclattice.CreateServiceNetworkOutput, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if aws.StringValue(input.Name) == "" {
+		return nil, fakeValidationError("name is required")
+	}
+	for _, sn := range f.serviceNetworks.list() {
+		if aws.StringValue(sn.Name) == aws.StringValue(input.Name) {
+			return nil, fakeConflictError("SERVICE_NETWORK", aws.StringValue(sn.Id), "service network name already exists")
+		}
+	}
+	authType := aws.StringValue(input.AuthType)
+	if authType == "" {
+		authType = vpclattice.AuthTypeNone
+	}
+	id := f.newId("sn")
+	sn := &vpclattice.GetServiceNetworkOutput{
+		Arn:                        aws.String(f.arn("servicenetwork/" + id)),
+		AuthType:                   aws.String(authType),
+		CreatedAt:                  fakeNow(),
+		Id:                         aws.String(id),
+		LastUpdatedAt:              fakeNow(),
+		Name:                       input.Name,
+		NumberOfAssociatedServices: aws.Int64(0),
+		NumberOfAssociatedVPCs:     aws.Int64(0),
+	}
+	f.serviceNetworks.put(id, sn)
+	f.putTags(*sn.Arn, input.Tags)
+	return &vpclattice.CreateServiceNetworkOutput{Arn: sn.Arn, AuthType: sn.AuthType, Id: sn.Id, Name: sn.Name}, nil
+}
+
+func (f *fakeLatticeAPI) CreateServiceNetwork(input *vpclattice.CreateServiceNetworkInput) (*vpclattice.CreateServiceNetworkOutput, error) {
+	return f.CreateServiceNetworkWithContext(context.Background(), input)
+}
+
+func (f *fakeLatticeAPI) GetServiceNetworkWithContext(_ aws.Context, input *vpclattice.GetServiceNetworkInput, _ ...request.Option) (*vpclattice.GetServiceNetworkOutput, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	sn, err := f.getServiceNetwork(input.ServiceNetworkIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	out := *sn
+	f.countServiceNetworkAssociations(&out)
+	return &out, nil
+}
+
+func (f *fakeLatticeAPI) GetServiceNetwork(input *vpclattice.GetServiceNetworkInput) (*vpclattice.GetServiceNetworkOutput, error) {
+	return f.GetServiceNetworkWithContext(context.Background(), input)
+}
+
+func (f *fakeLatticeAPI) countServiceNetworkAssociations(sn *vpclattice.GetServiceNetworkOutput) {
+	var svcs, vpcs int64
+	for _, snsa := range f.svcAssociations.list() {
+		if aws.StringValue(snsa.ServiceNetworkId) == aws.StringValue(sn.Id) {
+			svcs++
+		}
+	}
+	for _, snva := range f.vpcAssociations.list() {
+		if aws.StringValue(snva.ServiceNetworkId) == aws.StringValue(sn.Id) {
+			vpcs++
+		}
+	}
+	sn.NumberOfAssociatedServices = aws.Int64(svcs)
+	sn.NumberOfAssociatedVPCs = aws.Int64(vpcs)
+}
+
+func (f *fakeLatticeAPI) UpdateServiceNetworkWithContext(_ aws.Context, input *vpclattice.UpdateServiceNetworkInput, _ ...request.Option) (*vpclattice.UpdateServiceNetworkOutput, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	sn, err := f.getServiceNetwork(input.ServiceNetworkIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	sn.AuthType = input.AuthType
+	sn.LastUpdatedAt = fakeNow()
+	f.updateAuthPolicyState(aws.StringValue(sn.Id), aws.StringValue(sn.AuthType))
+	return &vpclattice.UpdateServiceNetworkOutput{Arn: sn.Arn, AuthType: sn.AuthType, Id: sn.Id, Name: sn.Name}, nil
+}
+
+func (f *fakeLatticeAPI) UpdateServiceNetwork(input *vpclattice.UpdateServiceNetworkInput) (*vpclattice.UpdateServiceNetworkOutput, error) {
+	return f.UpdateServiceNetworkWithContext(context.Background(), input)
+}
+
+func (f *fakeLatticeAPI) DeleteServiceNetworkWithContext(_ aws.Context, input *vpclattice.DeleteServiceNetworkInput, _ ...request.Option) (*vpclattice.DeleteServiceNetworkOutput, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	sn, err := f.getServiceNetwork(input.ServiceNetworkIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	out := *sn
+	f.countServiceNetworkAssociations(&out)
+	if *out.NumberOfAssociatedServices > 0 || *out.NumberOfAssociatedVPCs > 0 {
+		return nil, fakeConflictError("SERVICE_NETWORK", aws.StringValue(sn.Id), "service network has associations")
+	}
+	f.serviceNetworks.remove(aws.StringValue(sn.Id))
+	f.authPolicies.remove(aws.StringValue(sn.Id))
+	delete(f.tags, aws.StringValue(sn.Arn))
+	return &vpclattice.DeleteServiceNetworkOutput{}, nil
+}
+
+func (f *fakeLatticeAPI) DeleteServiceNetwork(input *vpclattice.DeleteServiceNetworkInput) (*vpclattice.DeleteServiceNetworkOutput, error) {
+	return f.DeleteServiceNetworkWithContext(context.Background(), input)
+}
+
+func (f *fakeLatticeAPI) ListServiceNetworksWithContext(_ aws.Context, input *vpclattice.ListServiceNetworksInput, _ ...request.Option) (*vpclattice.ListServiceNetworksOutput, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	var items []*vpclattice.ServiceNetworkSummary
+	for _, sn := range f.serviceNetworks.list() {
+		out := *sn
+		f.countServiceNetworkAssociations(&out)
+		items = append(items, &vpclattice.ServiceNetworkSummary{
+			Arn:                        out.Arn,
+			CreatedAt:                  out.CreatedAt,
+			Id:                         out.Id,
+			LastUpdatedAt:              out.LastUpdatedAt,
+			Name:                       out.Name,
+			NumberOfAssociatedServices: out.NumberOfAssociatedServices,
+			NumberOfAssociatedVPCs:     out.NumberOfAssociatedVPCs,
+		})
+	}
+	page, next, err := fakePage(items, f.pageSize, input.MaxResults, input.NextToken)
+	if err != nil {
+		return nil, err
+	}
+	return &vpclattice.ListServiceNetworksOutput{Items: page, NextToken: next}, nil
+}
+
+func (f *fakeLatticeAPI) ListServiceNetworks(input *vpclattice.ListServiceNetworksInput) (*vpclattice.ListServiceNetworksOutput, error) {
+	return f.ListServiceNetworksWithContext(context.Background(), input)
+}
+
+func (f *fakeLatticeAPI) ListServiceNetworksPagesWithContext(ctx aws.Context, input *vpclattice.ListServiceNetworksInput, fn func(*vpclattice.ListServiceNetworksOutput, bool) bool, _ ...request.Option) error {
+	in := *input
+	for {
+		out, err := f.ListServiceNetworksWithContext(ctx, &in)
+		if err != nil {
+			return err
+		}
+		lastPage := out.NextToken == nil
+		if !fn(out, lastPage) || lastPage {
+			return nil
+		}
+		in.NextToken = out.NextToken
+	}
+}
+
+func (f *fakeLatticeAPI) ListServiceNetworksPages(input *vpclattice.ListServiceNetworksInput, fn func(*vpclattice.ListServiceNetworksOutput, bool) bool) error {
+	return f.ListServiceNetworksPagesWithContext(context.Background(), input, fn)
+}
+
+// Services
+
+func (f *fakeLatticeAPI) getService(identifier *string) (*vpclattice.GetServiceOutput, error) {
+	svc, ok := f.services.get(fakeId(identifier))
+	if !ok {
+		return nil, fakeNotFoundError("SERVICE", aws.StringValue(identifier))
+	}
+	return svc, nil
+}
+
+func (f *fakeLatticeAPI) CreateServiceWithContext(_ aws.Context, input *vpclattice.CreateServiceInput, _ ...request.Option) (*vpclattice.CreateServiceOutput, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if aws.StringValue(input.Name) == "" {
+		return nil, fakeValidationError("name is required")
+	}
+	for _, svc := range f.services.list() {
+		if aws.StringValue(svc.Name) == aws.StringValue(input.Name) {
+			return nil, fakeConflictError("SERVICE", aws.StringValue(svc.Id), "service name already exists")
+		}
+	}
+	authType := aws.StringValue(input.AuthType)
+	if authType == "" {
+		authType = vpclattice.AuthTypeNone
+	}
+	id := f.newId("svc")
+	svc := &vpclattice.GetServiceOutput{
+		Arn:              aws.String(f.arn("service/" + id)),
+		AuthType:         aws.String(authType),
+		CertificateArn:   input.CertificateArn,
+		CreatedAt:        fakeNow(),
+		CustomDomainName: input.CustomDomainName,
+		DnsEntry: &vpclattice.DnsEntry{
+			DomainName:   aws.String(fmt.Sprintf("%s-%s.7d67968.vpc-lattice-svcs.%s.on.aws", aws.StringValue(input.Name), id[4:], f.region)),
+			HostedZoneId: aws.String("Z0000000000000000000"),
+		},
+		Id:            aws.String(id),
+		LastUpdatedAt: fakeNow(),
+		Name:          input.Name,
+		Status:        aws.String(vpclattice.ServiceStatusActive),
+	}
+	f.services.put(id, svc)
+	f.putTags(*svc.Arn, input.Tags)
+	return &vpclattice.CreateServiceOutput{
+		Arn:              svc.Arn,
+		AuthType:         svc.AuthType,
+		CertificateArn:   svc.CertificateArn,
+		CustomDomainName: svc.CustomDomainName,
+		DnsEntry:         svc.DnsEntry,
+		Id:               svc.Id,
+		Name:             svc.Name,
+		Status:           svc.Status,
+	}, nil
+}
+
+func (f *fakeLatticeAPI) CreateService(input *vpclattice.CreateServiceInput) (*vpclattice.CreateServiceOutput, error) {
+	return f.CreateServiceWithContext(context.Background(), input)
+}
+
+func (f *fakeLatticeAPI) GetServiceWithContext(_ aws.Context, input *vpclattice.GetServiceInput, _ ...request.Option) (*vpclattice.GetServiceOutput, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	svc, err := f.getService(input.ServiceIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	out := *svc
+	return &out, nil
+}
+
+func (f *fakeLatticeAPI) GetService(input *vpclattice.GetServiceInput) (*vpclattice.GetServiceOutput, error) {
+	return f.GetServiceWithContext(context.Background(), input)
+}
+
+func (f *fakeLatticeAPI) UpdateServiceWithContext(_ aws.Context, input *vpclattice.UpdateServiceInput, _ ...request.Option) (*vpclattice.UpdateServiceOutput, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	svc, err := f.getService(input.ServiceIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	if input.AuthType != nil {
+		svc.AuthType = input.AuthType
+		f.updateAuthPolicyState(aws.StringValue(svc.Id), aws.StringValue(svc.AuthType))
+	}
+	if input.CertificateArn != nil {
+		svc.CertificateArn = input.CertificateArn
+	}
+	svc.LastUpdatedAt = fakeNow()
+	return &vpclattice.UpdateServiceOutput{
+		Arn:              svc.Arn,
+		AuthType:         svc.AuthType,
+		CertificateArn:   svc.CertificateArn,
+		CustomDomainName: svc.CustomDomainName,
+		Id:               svc.Id,
+		Name:             svc.Name,
+	}, nil
+}
+
+func (f *fakeLatticeAPI) UpdateService(input *vpclattice.UpdateServiceInput) (*vpclattice.UpdateServiceOutput, error) {
+	return f.UpdateServiceWithContext(context.Background(), input)
+}
+
+func (f *fakeLatticeAPI) DeleteServiceWithContext(_ aws.Context, input *vpclattice.DeleteServiceInput, _ ...request.Option) (*vpclattice.DeleteServiceOutput, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	svc, err := f.getService(input.ServiceIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	svcId := aws.StringValue(svc.Id)
+	for _, snsa := range f.svcAssociations.list() {
+		if aws.StringValue(snsa.ServiceId) == svcId {
+			return nil, fakeConflictError("SERVICE", svcId, "service is associated with a service network")
+		}
+	}
+	for _, listener := range f.listeners.list() {
+		if aws.StringValue(listener.ServiceId) == svcId {
+			f.deleteListener(listener)
+		}
+	}
+	for _, als := range f.accessLogSubscriptions.list() {
+		if aws.StringValue(als.ResourceId) == svcId {
+			f.accessLogSubscriptions.remove(aws.StringValue(als.Id))
+			delete(f.tags, aws.StringValue(als.Arn))
+		}
+	}
+	f.services.remove(svcId)
+	f.authPolicies.remove(svcId)
+	delete(f.tags, aws.StringValue(svc.Arn))
+	return &vpclattice.DeleteServiceOutput{
+		Arn:    svc.Arn,
+		Id:     svc.Id,
+		Name:   svc.Name,
+		Status: aws.String(vpclattice.ServiceStatusDeleteInProgress),
+	}, nil
+}
+
+func (f *fakeLatticeAPI) DeleteService(input *vpclattice.DeleteServiceInput) (*vpclattice.DeleteServiceOutput, error) {
+	return f.DeleteServiceWithContext(context.Background(), input)
+}
+
+func (f *fakeLatticeAPI) ListServicesWithContext(_ aws.Context, input *vpclattice.ListServicesInput, _ ...request.Option) (*vpclattice.ListServicesOutput, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	var items []*vpclattice.ServiceSummary
+	for _, svc := range f.services.list() {
+		items = append(items, &vpclattice.ServiceSummary{
+			Arn:              svc.Arn,
+			CreatedAt:        svc.CreatedAt,
+			CustomDomainName: svc.CustomDomainName,
+			DnsEntry:         svc.DnsEntry,
+			Id:               svc.Id,
+			LastUpdatedAt:    svc.LastUpdatedAt,
+			Name:             svc.Name,
+			Status:           svc.Status,
+		})
+	}
+	page, next, err := fakePage(items, f.pageSize, input.MaxResults, input.NextToken)
+	if err != nil {
+		return nil, err
+	}
+	return &vpclattice.ListServicesOutput{Items: page, NextToken: next}, nil
+}
+
+func (f *fakeLatticeAPI) ListServices(input *vpclattice.ListServicesInput) (*vpclattice.ListServicesOutput, error) {
+	return f.ListServicesWithContext(context.Background(), input)
+}
+
+func (f *fakeLatticeAPI) ListServicesPagesWithContext(ctx aws.Context, input *vpclattice.ListServicesInput, fn func(*vpclattice.ListServicesOutput, bool) bool, _ ...request.Option) error {
+	in := *input
+	for {
+		out, err := f.ListServicesWithContext(ctx, &in)
+		if err != nil {
+			return err
+		}
+		lastPage := out.NextToken == nil
+		if !fn(out, lastPage) || lastPage {
+			return nil
+		}
+		in.NextToken = out.NextToken
+	}
+}
+
+func (f *fakeLatticeAPI) ListServicesPages(input *vpclattice.ListServicesInput, fn func(*vpclattice.ListServicesOutput, bool) bool) error {
+	return f.ListServicesPagesWithContext(context.Background(), input, fn)
+}
+
+// Service network service associations
+
+func (f *fakeLatticeAPI) CreateServiceNetworkServiceAssociationWithContext(_ aws.Context, input *vpclattice.CreateServiceNetworkServiceAssociationInput, _ ...request.Option) (*vpclattice.CreateServiceNetworkServiceAssociationOutput, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	svc, err := f.getService(input.ServiceIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	sn, err := f.getServiceNetwork(input.ServiceNetworkIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	for _, snsa := range f.svcAssociations.list() {
+		if aws.StringValue(snsa.ServiceId) == aws.StringValue(svc.Id) && aws.StringValue(snsa.ServiceNetworkId) == aws.StringValue(sn.Id) {
+			return nil, fakeConflictError("SERVICE_NETWORK_SERVICE_ASSOCIATION", aws.StringValue(snsa.Id), "service is already associated with the service network")
+		}
+	}
+	id := f.newId("snsa")
+	snsa := &vpclattice.GetServiceNetworkServiceAssociationOutput{
+		Arn:                aws.String(f.arn("servicenetworkserviceassociation/" + id)),
+		CreatedAt:          fakeNow(),
+		CreatedBy:          aws.String(f.account),
+		CustomDomainName:   svc.CustomDomainName,
+		DnsEntry:           svc.DnsEntry,
+		Id:                 aws.String(id),
+		ServiceArn:         svc.Arn,
+		ServiceId:          svc.Id,
+		ServiceName:        svc.Name,
+		ServiceNetworkArn:  sn.Arn,
+		ServiceNetworkId:   sn.Id,
+		ServiceNetworkName: sn.Name,
+		Status:             aws.String(vpclattice.ServiceNetworkServiceAssociationStatusActive),
+	}
+	f.svcAssociations.put(id, snsa)
+	f.putTags(*snsa.Arn, input.Tags)
+	return &vpclattice.CreateServiceNetworkServiceAssociationOutput{
+		Arn:              snsa.Arn,
+		CreatedBy:        snsa.CreatedBy,
+		CustomDomainName: snsa.CustomDomainName,
+		DnsEntry:         snsa.DnsEntry,
+		Id:               snsa.Id,
+		Status:           snsa.Status,
+	}, nil
+}
+
+func (f *fakeLatticeAPI) CreateServiceNetworkServiceAssociation(input *vpclattice.CreateServiceNetworkServiceAssociationInput) (*vpclattice.CreateServiceNetworkServiceAssociationOutput, error) {
+	return f.CreateServiceNetworkServiceAssociationWithContext(context.Background(), input)
+}
+
+func (f *fakeLatticeAPI) GetServiceNetworkServiceAssociationWithContext(_ aws.Context, input *vpclattice.GetServiceNetworkServiceAssociationInput, _ ...request.Option) (*vpclattice.GetServiceNetworkServiceAssociationOutput, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	snsa, ok := f.svcAssociations.get(fakeId(input.ServiceNetworkServiceAssociationIdentifier))
+	if !ok {
+		return nil, fakeNotFoundError("SERVICE_NETWORK_SERVICE_ASSOCIATION", aws.StringValue(input.ServiceNetworkServiceAssociationIdentifier))
+	}
+	out := *snsa
+	return &out, nil
+}
+
+func (f *fakeLatticeAPI) GetServiceNetworkServiceAssociation(input *vpclattice.GetServiceNetworkServiceAssociationInput) (*vpclattice.GetServiceNetworkServiceAssociationOutput, error) {
+	return f.GetServiceNetworkServiceAssociationWithContext(context.Background(), input)
+}
+
+func (f *fakeLatticeAPI) DeleteServiceNetworkServiceAssociationWithContext(_ aws.Context, input *vpclattice.DeleteServiceNetworkServiceAssociationInput, _ ...request.Option) (*vpclattice.DeleteServiceNetworkServiceAssociationOutput, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	snsa, ok := f.svcAssociations.get(fakeId(input.ServiceNetworkServiceAssociationIdentifier))
+	if !ok {
+		return nil, fakeNotFoundError("SERVICE_NETWORK_SERVICE_ASSOCIATION", aws.StringValue(input.ServiceNetworkServiceAssociationIdentifier))
+	}
+	f.svcAssociations.remove(aws.StringValue(snsa.Id))
+	delete(f.tags, aws.StringValue(snsa.Arn))
+	return &vpclattice.DeleteServiceNetworkServiceAssociationOutput{
+		Arn:    snsa.Arn,
+		Id:     snsa.Id,
+		Status: aws.String(vpclattice.ServiceNetworkServiceAssociationStatusDeleteInProgress),
+	}, nil
+}
+
+func (f *fakeLatticeAPI) DeleteServiceNetworkServiceAssociation(input *vpclattice.DeleteServiceNetworkServiceAssociationInput) (*vpclattice.DeleteServiceNetworkServiceAssociationOutput, error) {
+	return f.DeleteServiceNetworkServiceAssociationWithContext(context.Background(), input)
+}
+
+func (f *fakeLatticeAPI) ListServiceNetworkServiceAssociationsWithContext(_ aws.Context, input *vpclattice.ListServiceNetworkServiceAssociationsInput, _ ...request.Option) (*vpclattice.ListServiceNetworkServiceAssociationsOutput, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if input.ServiceIdentifier == nil && input.ServiceNetworkIdentifier == nil {
+		return nil, fakeValidationError("either serviceIdentifier or serviceNetworkIdentifier is required")
+	}
+	var items []*vpclattice.ServiceNetworkServiceAssociationSummary
+	for _, snsa := range f.svcAssociations.list() {
+		if input.ServiceIdentifier != nil && fakeId(input.ServiceIdentifier) != aws.StringValue(snsa.ServiceId) {
+			continue
+		}
+		if input.ServiceNetworkIdentifier != nil && fakeId(input.ServiceNetworkIdentifier) != aws.StringValue(snsa.ServiceNetworkId) {
+			continue
+		}
+		items = append(items, &vpclattice.ServiceNetworkServiceAssociationSummary{
+			Arn:                snsa.Arn,
+			CreatedAt:          snsa.CreatedAt,
+			CreatedBy:          snsa.CreatedBy,
+			CustomDomainName:   snsa.CustomDomainName,
+			DnsEntry:           snsa.DnsEntry,
+			Id:                 snsa.Id,
+			ServiceArn:         snsa.ServiceArn,
+			ServiceId:          snsa.ServiceId,
+			ServiceName:        snsa.ServiceName,
+			ServiceNetworkArn:  snsa.ServiceNetworkArn,
+			ServiceNetworkId:   snsa.ServiceNetworkId,
+			ServiceNetworkName: snsa.ServiceNetworkName,
+			Status:             snsa.Status,
+		})
+	}
+	page, next, err := fakePage(items, f.pageSize, input.MaxResults, input.NextToken)
+	if err != nil {
+		return nil, err
+	}
+	return &vpclattice.ListServiceNetworkServiceAssociationsOutput{Items: page, NextToken: next}, nil
+}
+
+func (f *fakeLatticeAPI) ListServiceNetworkServiceAssociations(input *vpclattice.ListServiceNetworkServiceAssociationsInput) (*vpclattice.ListServiceNetworkServiceAssociationsOutput, error) {
+	return f.ListServiceNetworkServiceAssociationsWithContext(context.Background(), input)
+}
+
+func (f *fakeLatticeAPI) ListServiceNetworkServiceAssociationsPagesWithContext(ctx aws.Context, input *vpclattice.ListServiceNetworkServiceAssociationsInput, fn func(*vpclattice.ListServiceNetworkServiceAssociationsOutput, bool) bool, _ ...request.Option) error {
+	in := *input
+	for {
+		out, err := f.ListServiceNetworkServiceAssociationsWithContext(ctx, &in)
+		if err != nil {
+			return err
+		}
+		lastPage := out.NextToken == nil
+		if !fn(out, lastPage) || lastPage {
+			return nil
+		}
+		in.NextToken = out.NextToken
+	}
+}
+
+func (f *fakeLatticeAPI) ListServiceNetworkServiceAssociationsPages(input *vpclattice.ListServiceNetworkServiceAssociationsInput, fn func(*vpclattice.ListServiceNetworkServiceAssociationsOutput, bool) bool) error {
+	return f.ListServiceNetworkServiceAssociationsPagesWithContext(context.Background(), input, fn)
+}
+
+// Service network VPC associations
+
+func (f *fakeLatticeAPI) getVpcAssociation(identifier *string) (*vpclattice.GetServiceNetworkVpcAssociationOutput, error) {
+	snva, ok := f.vpcAssociations.get(fakeId(identifier))
+	if !ok {
+		return nil, fakeNotFoundError("SERVICE_NETWORK_VPC_ASSOCIATION", aws.StringValue(identifier))
+	}
+	return snva, nil
+}
+
+func (f *fakeLatticeAPI) CreateServiceNetworkVpcAssociationWithContext(_ aws.Context, input *vpclattice.CreateServiceNetworkVpcAssociationInput, _ ...request.Option) (*vpclattice.CreateServiceNetworkVpcAssociationOutput, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	sn, err := f.getServiceNetwork(input.ServiceNetworkIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	if aws.StringValue(input.VpcIdentifier) == "" {
+		return nil, fakeValidationError("vpcIdentifier is required")
+	}
+	for _, snva := range f.vpcAssociations.list() {
+		if aws.StringValue(snva.VpcId) == aws.StringValue(input.VpcIdentifier) {
+			return nil, fakeConflictError("SERVICE_NETWORK_VPC_ASSOCIATION", aws.StringValue(snva.Id), "VPC is already associated with a service network")
+		}
+	}
+	id := f.newId("snva")
+	snva := &vpclattice.GetServiceNetworkVpcAssociationOutput{
+		Arn:                aws.String(f.arn("servicenetworkvpcassociation/" + id)),
+		CreatedAt:          fakeNow(),
+		CreatedBy:          aws.String(f.account),
+		Id:                 aws.String(id),
+		LastUpdatedAt:      fakeNow(),
+		SecurityGroupIds:   input.SecurityGroupIds,
+		ServiceNetworkArn:  sn.Arn,
+		ServiceNetworkId:   sn.Id,
+		ServiceNetworkName: sn.Name,
+		Status:             aws.String(vpclattice.ServiceNetworkVpcAssociationStatusActive),
+		VpcId:              input.VpcIdentifier,
+	}
+	f.vpcAssociations.put(id, snva)
+	f.putTags(*snva.Arn, input.Tags)
+	return &vpclattice.CreateServiceNetworkVpcAssociationOutput{
+		Arn:              snva.Arn,
+		CreatedBy:        snva.CreatedBy,
+		Id:               snva.Id,
+		SecurityGroupIds: snva.SecurityGroupIds,
+		Status:           snva.Status,
+	}, nil
+}
+
+func (f *fakeLatticeAPI) CreateServiceNetworkVpcAssociation(input *vpclattice.CreateServiceNetworkVpcAssociationInput) (*vpclattice.CreateServiceNetworkVpcAssociationOutput, error) {
+	return f.CreateServiceNetworkVpcAssociationWithContext(context.Background(), input)
+}
+
+func (f *fakeLatticeAPI) GetServiceNetworkVpcAssociationWithContext(_ aws.Context, input *vpclattice.GetServiceNetworkVpcAssociationInput, _ ...request.Option) (*vpclattice.GetServiceNetworkVpcAssociationOutput, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	snva, err := f.getVpcAssociation(input.ServiceNetworkVpcAssociationIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	out := *snva
+	return &out, nil
+}
+
+func (f *fakeLatticeAPI) GetServiceNetworkVpcAssociation(input *vpclattice.GetServiceNetworkVpcAssociationInput) (*vpclattice.GetServiceNetworkVpcAssociationOutput, error) {
+	return f.GetServiceNetworkVpcAssociationWithContext(context.Background(), input)
+}
+
+func (f *fakeLatticeAPI) UpdateServiceNetworkVpcAssociationWithContext(_ aws.Context, input *vpclattice.UpdateServiceNetworkVpcAssociationInput, _ ...request.Option) (*vpclattice.UpdateServiceNetworkVpcAssociationOutput, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	snva, err := f.getVpcAssociation(input.ServiceNetworkVpcAssociationIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	if len(input.SecurityGroupIds) == 0 {
+		return nil, fakeValidationError("securityGroupIds must not be empty")
+	}
+	snva.SecurityGroupIds = input.SecurityGroupIds
+	snva.LastUpdatedAt = fakeNow()
+	return &vpclattice.UpdateServiceNetworkVpcAssociationOutput{
+		Arn:              snva.Arn,
+		CreatedBy:        snva.CreatedBy,
+		Id:               snva.Id,
+		SecurityGroupIds: snva.SecurityGroupIds,
+		Status:           snva.Status,
+	}, nil
+}
+
+func (f *fakeLatticeAPI) UpdateServiceNetworkVpcAssociation(input *vpclattice.UpdateServiceNetworkVpcAssociationInput) (*vpclattice.UpdateServiceNetworkVpcAssociationOutput, error) {
+	return f.UpdateServiceNetworkVpcAssociationWithContext(context.Background(), input)
+}
+
+func (f *fakeLatticeAPI) DeleteServiceNetworkVpcAssociationWithContext(_ aws.Context, input *vpclattice.DeleteServiceNetworkVpcAssociationInput, _ ...request.Option) (*vpclattice.DeleteServiceNetworkVpcAssociationOutput, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	snva, err := f.getVpcAssociation(input.ServiceNetworkVpcAssociationIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	f.vpcAssociations.remove(aws.StringValue(snva.Id))
+	delete(f.tags, aws.StringValue(snva.Arn))
+	return &vpclattice.DeleteServiceNetworkVpcAssociationOutput{
+		Arn:    snva.Arn,
+		Id:     snva.Id,
+		Status: aws.String(vpclattice.ServiceNetworkVpcAssociationStatusDeleteInProgress),
+	}, nil
+}
+
+func (f *fakeLatticeAPI) DeleteServiceNetworkVpcAssociation(input *vpclattice.DeleteServiceNetworkVpcAssociationInput) (*vpclattice.DeleteServiceNetworkVpcAssociationOutput, error) {
+	return f.DeleteServiceNetworkVpcAssociationWithContext(context.Background(), input)
+}
+
+func (f *fakeLatticeAPI) ListServiceNetworkVpcAssociationsWithContext(_ aws.Context, input *vpclattice.ListServiceNetworkVpcAssociationsInput, _ ...request.Option) (*vpclattice.ListServiceNetworkVpcAssociationsOutput, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if input.ServiceNetworkIdentifier == nil && input.VpcIdentifier == nil {
+		return nil, fakeValidationError("either serviceNetworkIdentifier or vpcIdentifier is required")
+	}
+	var items []*vpclattice.ServiceNetworkVpcAssociationSummary
+	for _, snva := range f.vpcAssociations.list() {
+		if input.ServiceNetworkIdentifier != nil && fakeId(input.ServiceNetworkIdentifier) != aws.StringValue(snva.ServiceNetworkId) {
+			continue
+		}
+		if input.VpcIdentifier != nil && aws.StringValue(input.VpcIdentifier) != aws.StringValue(snva.VpcId) {
+			continue
+		}
+		items = append(items, &vpclattice.ServiceNetworkVpcAssociationSummary{
+			Arn:                snva.Arn,
+			CreatedAt:          snva.CreatedAt,
+			CreatedBy:          snva.CreatedBy,
+			Id:                 snva.Id,
+			LastUpdatedAt:      snva.LastUpdatedAt,
+			ServiceNetworkArn:  snva.ServiceNetworkArn,
+			ServiceNetworkId:   snva.ServiceNetworkId,
+			ServiceNetworkName: snva.ServiceNetworkName,
+			Status:             snva.Status,
+			VpcId:              snva.VpcId,
+		})
+	}
+	page, next, err := fakePage(items, f.pageSize, input.MaxResults, input.NextToken)
+	if err != nil {
+		return nil, err
+	}
+	return &vpclattice.ListServiceNetworkVpcAssociationsOutput{Items: page, NextToken: next}, nil
+}
+
+func (f *fakeLatticeAPI) ListServiceNetworkVpcAssociations(input *vpclattice.ListServiceNetworkVpcAssociationsInput) (*vpclattice.ListServiceNetworkVpcAssociationsOutput, error) {
+	return f.ListServiceNetworkVpcAssociationsWithContext(context.Background(), input)
+}
+
+func (f *fakeLatticeAPI) ListServiceNetworkVpcAssociationsPagesWithContext(ctx aws.Context, input *vpclattice.ListServiceNetworkVpcAssociationsInput, fn func(*vpclattice.ListServiceNetworkVpcAssociationsOutput, bool) bool, _ ...request.Option) error {
+	in := *input
+	for {
+		out, err := f.ListServiceNetworkVpcAssociationsWithContext(ctx, &in)
+		if err != nil {
+			return err
+		}
+		lastPage := out.NextToken == nil
+		if !fn(out, lastPage) || lastPage {
+			return nil
+		}
+		in.NextToken = out.NextToken
+	}
+}
+
+func (f *fakeLatticeAPI) ListServiceNetworkVpcAssociationsPages(input *vpclattice.ListServiceNetworkVpcAssociationsInput, fn func(*vpclattice.ListServiceNetworkVpcAssociationsOutput, bool) bool) error {
+	return f.ListServiceNetworkVpcAssociationsPagesWithContext(context.Background(), input, fn)
+}
+
+// Listeners
+
+func (f *fakeLatticeAPI) getListener(serviceIdentifier *string, listenerIdentifier *string) (*fakeListener, error) {
+	svc, err := f.getService(serviceIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	listener, ok := f.listeners.get(fakeId(listenerIdentifier))
+	if !ok || aws.StringValue(listener.ServiceId) != aws.StringValue(svc.Id) {
+		return nil, fakeNotFoundError("LISTENER", aws.StringValue(listenerIdentifier))
+	}
+	return listener, nil
+}
+
+func (f *fakeLatticeAPI) CreateListenerWithContext(_ aws.Context, input *vpclattice.CreateListenerInput, _ ...request.Option) (*vpclattice.CreateListenerOutput, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	svc, err := f.getService(input.ServiceIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	if aws.StringValue(input.Name) == "" || aws.StringValue(input.Protocol) == "" || input.DefaultAction == nil {
+		return nil, fakeValidationError("name, protocol and defaultAction are required")
+	}
+	port := aws.Int64Value(input.Port)
+	if input.Port == nil {
+		port = 80
+		if aws.StringValue(input.Protocol) != vpclattice.ListenerProtocolHttp {
+			port = 443
+		}
+	}
+	for _, listener := range f.listeners.list() {
+		if aws.StringValue(listener.ServiceId) != aws.StringValue(svc.Id) {
+			continue
+		}
+		if aws.StringValue(listener.Name) == aws.StringValue(input.Name) || aws.Int64Value(listener.Port) == port {
+			return nil, fakeConflictError("LISTENER", aws.StringValue(listener.Id), "listener name or port already exists")
+		}
+	}
+	id := f.newId("listener")
+	listenerArn := f.arn("service/" + aws.StringValue(svc.Id) + "/listener/" + id)
+	listener := &fakeListener{
+		GetListenerOutput: vpclattice.GetListenerOutput{
+			Arn:           aws.String(listenerArn),
+			CreatedAt:     fakeNow(),
+			DefaultAction: input.DefaultAction,
+			Id:            aws.String(id),
+			LastUpdatedAt: fakeNow(),
+			Name:          input.Name,
+			Port:          aws.Int64(port),
+			Protocol:      input.Protocol,
+			ServiceArn:    svc.Arn,
+			ServiceId:     svc.Id,
+		},
+	}
+	// the default action shows up as a rule of the listener
+	ruleId := f.newId("rule")
+	f.rules.put(ruleId, &fakeRule{
+		GetRuleOutput: vpclattice.GetRuleOutput{
+			Action:        input.DefaultAction,
+			Arn:           aws.String(listenerArn + "/rule/" + ruleId),
+			CreatedAt:     fakeNow(),
+			Id:            aws.String(ruleId),
+			IsDefault:     aws.Bool(true),
+			LastUpdatedAt: fakeNow(),
+			Name:          aws.String("default"),
+		},
+		serviceId:  aws.StringValue(svc.Id),
+		listenerId: id,
+	})
+	listener.defaultRuleId = ruleId
+	f.listeners.put(id, listener)
+	f.putTags(listenerArn, input.Tags)
+	return &vpclattice.CreateListenerOutput{
+		Arn:           listener.Arn,
+		DefaultAction: listener.DefaultAction,
+		Id:            listener.Id,
+		Name:          listener.Name,
+		Port:          listener.Port,
+		Protocol:      listener.Protocol,
+		ServiceArn:    listener.ServiceArn,
+		ServiceId:     listener.ServiceId,
+	}, nil
+}
+
+func (f *fakeLatticeAPI) CreateListener(input *vpclattice.CreateListenerInput) (*vpclattice.CreateListenerOutput, error) {
+	return f.CreateListenerWithContext(context.Background(), input)
+}
+
+func (f *fakeLatticeAPI) GetListenerWithContext(_ aws.Context, input *vpclattice.GetListenerInput, _ ...request.Option) (*vpclattice.GetListenerOutput, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	listener, err := f.getListener(input.ServiceIdentifier, input.ListenerIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	out := listener.GetListenerOutput
+	return &out, nil
+}
+
+func (f *fakeLatticeAPI) GetListener(input *vpclattice.GetListenerInput) (*vpclattice.GetListenerOutput, error) {
+	return f.GetListenerWithContext(context.Background(), input)
+}
+
+func (f *fakeLatticeAPI) UpdateListenerWithContext(_ aws.Context, input *vpclattice.UpdateListenerInput, _ ...request.Option) (*vpclattice.UpdateListenerOutput, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	listener, err := f.getListener(input.ServiceIdentifier, input.ListenerIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	if input.DefaultAction == nil {
+		return nil, fakeValidationError("defaultAction is required")
+	}
+	listener.DefaultAction = input.DefaultAction
+	listener.LastUpdatedAt = fakeNow()
+	if defaultRule, ok := f.rules.get(listener.defaultRuleId); ok {
+		defaultRule.Action = input.DefaultAction
+		defaultRule.LastUpdatedAt = fakeNow()
+	}
+	return &vpclattice.UpdateListenerOutput{
+		Arn:           listener.Arn,
+		DefaultAction: listener.DefaultAction,
+		Id:            listener.Id,
+		Name:          listener.Name,
+		Port:          listener.Port,
+		Protocol:      listener.Protocol,
+		ServiceArn:    listener.ServiceArn,
+		ServiceId:     listener.ServiceId,
+	}, nil
+}
+
+func (f *fakeLatticeAPI) UpdateListener(input *vpclattice.UpdateListenerInput) (*vpclattice.UpdateListenerOutput, error) {
+	return f.UpdateListenerWithContext(context.Background(), input)
+}
+
+func (f *fakeLatticeAPI) deleteListener(listener *fakeListener) {
+	for _, rule := range f.rules.list() {
+		if rule.listenerId == aws.StringValue(listener.Id) {
+			f.rules.remove(aws.StringValue(rule.Id))
+			delete(f.tags, aws.StringValue(rule.Arn))
+		}
+	}
+	f.listeners.remove(aws.StringValue(listener.Id))
+	delete(f.tags, aws.StringValue(listener.Arn))
+}
+
+func (f *fakeLatticeAPI) DeleteListenerWithContext(_ aws.Context, input *vpclattice.DeleteListenerInput, _ ...request.Option) (*vpclattice.DeleteListenerOutput, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	listener, err := f.getListener(input.ServiceIdentifier, input.ListenerIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	f.deleteListener(listener)
+	return &vpclattice.DeleteListenerOutput{}, nil
+}
+
+func (f *fakeLatticeAPI) DeleteListener(input *vpclattice.DeleteListenerInput) (*vpclattice.DeleteListenerOutput, error) {
+	return f.DeleteListenerWithContext(context.Background(), input)
+}
+
+func (f *fakeLatticeAPI) ListListenersWithContext(_ aws.Context, input *vpclattice.ListListenersInput, _ ...request.Option) (*vpclattice.ListListenersOutput, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	svc, err := f.getService(input.ServiceIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	var items []*vpclattice.ListenerSummary
+	for _, listener := range f.listeners.list() {
+		if aws.StringValue(listener.ServiceId) != aws.StringValue(svc.Id) {
+			continue
+		}
+		items = append(items, &vpclattice.ListenerSummary{
+			Arn:           listener.Arn,
+			CreatedAt:     listener.CreatedAt,
+			Id:            listener.Id,
+			LastUpdatedAt: listener.LastUpdatedAt,
+			Name:          listener.Name,
+			Port:          listener.Port,
+			Protocol:      listener.Protocol,
+		})
+	}
+	page, next, err := fakePage(items, f.pageSize, input.MaxResults, input.NextToken)
+	if err != nil {
+		return nil, err
+	}
+	return &vpclattice.ListListenersOutput{Items: page, NextToken: next}, nil
+}
+
+func (f *fakeLatticeAPI) ListListeners(input *vpclattice.ListListenersInput) (*vpclattice.ListListenersOutput, error) {
+	return f.ListListenersWithContext(context.Background(), input)
+}
+
+func (f *fakeLatticeAPI) ListListenersPagesWithContext(ctx aws.Context, input *vpclattice.ListListenersInput, fn func(*vpclattice.ListListenersOutput, bool) bool, _ ...request.Option) error {
+	in := *input
+	for {
+		out, err := f.ListListenersWithContext(ctx, &in)
+		if err != nil {
+			return err
+		}
+		lastPage := out.NextToken == nil
+		if !fn(out, lastPage) || lastPage {
+			return nil
+		}
+		in.NextToken = out.NextToken
+	}
+}
+
+func (f *fakeLatticeAPI) ListListenersPages(input *vpclattice.ListListenersInput, fn func(*vpclattice.ListListenersOutput, bool) bool) error {
+	return f.ListListenersPagesWithContext(context.Background(), input, fn)
+}
+
+// Rules
+
+func (f *fakeLatticeAPI) getRule(serviceIdentifier *string, listenerIdentifier *string, ruleIdentifier *string) (*fakeRule, error) {
+	listener, err := f.getListener(serviceIdentifier, listenerIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	rule, ok := f.rules.get(fakeId(ruleIdentifier))
+	if !ok || rule.listenerId != aws.StringValue(listener.Id) {
+		return nil, fakeNotFoundError("RULE", aws.StringValue(ruleIdentifier))
+	}
+	return rule, nil
+}
+
+// checkRulePriority returns a conflict when another rule of the listener already has the priority
+func (f *fakeLatticeAPI) checkRulePriority(listenerId string, ruleId string, priority int64) error {
+	if priority < 1 || priority > 100 {
+		return fakeValidationError(fmt.Sprintf("priority %d must be between 1 and 100", priority))
+	}
+	for _, rule := range f.rules.list() {
+		if rule.listenerId == listenerId && aws.StringValue(rule.Id) != ruleId &&
+			!aws.BoolValue(rule.IsDefault) && aws.Int64Value(rule.Priority) == priority {
+			return fakeConflictError("RULE", aws.StringValue(rule.Id), fmt.Sprintf("priority %d is already in use", priority))
+		}
+	}
+	return nil
+}
+
+func (f *fakeLatticeAPI) CreateRuleWithContext(_ aws.Context, input *vpclattice.CreateRuleInput, _ ...request.Option) (*vpclattice.CreateRuleOutput, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	listener, err := f.getListener(input.ServiceIdentifier, input.ListenerIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	if aws.StringValue(input.Name) == "" || input.Action == nil || input.Match == nil || input.Priority == nil {
+		return nil, fakeValidationError("name, action, match and priority are required")
+	}
+	listenerId := aws.StringValue(listener.Id)
+	for _, rule := range f.rules.list() {
+		if rule.listenerId == listenerId && aws.StringValue(rule.Name) == aws.StringValue(input.Name) {
+			return nil, fakeConflictError("RULE", aws.StringValue(rule.Id), "rule name already exists")
+		}
+	}
+	if err := f.checkRulePriority(listenerId, "", aws.Int64Value(input.Priority)); err != nil {
+		return nil, err
+	}
+	id := f.newId("rule")
+	rule := &fakeRule{
+		GetRuleOutput: vpclattice.GetRuleOutput{
+			Action:        input.Action,
+			Arn:           aws.String(aws.StringValue(listener.Arn) + "/rule/" + id),
+			CreatedAt:     fakeNow(),
+			Id:            aws.String(id),
+			IsDefault:     aws.Bool(false),
+			LastUpdatedAt: fakeNow(),
+			Match:         input.Match,
+			Name:          input.Name,
+			Priority:      input.Priority,
+		},
+		serviceId:  aws.StringValue(listener.ServiceId),
+		listenerId: listenerId,
+	}
+	f.rules.put(id, rule)
+	f.putTags(*rule.Arn, input.Tags)
+	return &vpclattice.CreateRuleOutput{
+		Action:   rule.Action,
+		Arn:      rule.Arn,
+		Id:       rule.Id,
+		Match:    rule.Match,
+		Name:     rule.Name,
+		Priority: rule.Priority,
+	}, nil
+}
+
+func (f *fakeLatticeAPI) CreateRule(input *vpclattice.CreateRuleInput) (*vpclattice.CreateRuleOutput, error) {
+	return f.CreateRuleWithContext(context.Background(), input)
+}
+
+func (f *fakeLatticeAPI) GetRuleWithContext(_ aws.Context, input *vpclattice.GetRuleInput, _ ...request.Option) (*vpclattice.GetRuleOutput, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	rule, err := f.getRule(input.ServiceIdentifier, input.ListenerIdentifier, input.RuleIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	out := rule.GetRuleOutput
+	return &out, nil
+}
+
+func (f *fakeLatticeAPI) GetRule(input *vpclattice.GetRuleInput) (*vpclattice.GetRuleOutput, error) {
+	return f.GetRuleWithContext(context.Background(), input)
+}
+
+func (f *fakeLatticeAPI) updateRule(rule *fakeRule, action *vpclattice.RuleAction, match *vpclattice.RuleMatch, priority *int64) error {
+	if aws.BoolValue(rule.IsDefault) && (match != nil || priority != nil) {
+		return fakeValidationError("only the action of the default rule can be updated")
+	}
+	if priority != nil {
+		if err := f.checkRulePriority(rule.listenerId, aws.StringValue(rule.Id), *priority); err != nil {
+			return err
+		}
+		rule.Priority = priority
+	}
+	if action != nil {
+		rule.Action = action
+	}
+	if match != nil {
+		rule.Match = match
+	}
+	rule.LastUpdatedAt = fakeNow()
+	return nil
+}
+
+func (f *fakeLatticeAPI) UpdateRuleWithContext(_ aws.Context, input *vpclattice.UpdateRuleInput, _ ...request.Option) (*vpclattice.UpdateRuleOutput, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	rule, err := f.getRule(input.ServiceIdentifier, input.ListenerIdentifier, input.RuleIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	if err := f.updateRule(rule, input.Action, input.Match, input.Priority); err != nil {
+		return nil, err
+	}
+	return &vpclattice.UpdateRuleOutput{
+		Action:    rule.Action,
+		Arn:       rule.Arn,
+		Id:        rule.Id,
+		IsDefault: rule.IsDefault,
+		Match:     rule.Match,
+		Name:      rule.Name,
+		Priority:  rule.Priority,
+	}, nil
+}
+
+func (f *fakeLatticeAPI) UpdateRule(input *vpclattice.UpdateRuleInput) (*vpclattice.UpdateRuleOutput, error) {
+	return f.UpdateRuleWithContext(context.Background(), input)
+}
+
+// BatchUpdateRuleWithContext applies the updates in order, so that priorities can be swapped
+// by moving one of the rules to a free priority first.
+func (f *fakeLatticeAPI) BatchUpdateRuleWithContext(_ aws.Context, input *vpclattice.BatchUpdateRuleInput, _ ...request.Option) (*vpclattice.BatchUpdateRuleOutput, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if _, err := f.getListener(input.ServiceIdentifier, input.ListenerIdentifier); err != nil {
+		return nil, err
+	}
+	out := &vpclattice.BatchUpdateRuleOutput{}
+	for _, update := range input.Rules {
+		rule, err := f.getRule(input.ServiceIdentifier, input.ListenerIdentifier, update.RuleIdentifier)
+		if err == nil {
+			err = f.updateRule(rule, update.Action, update.Match, update.Priority)
+		}
+		if err != nil {
+			failureCode := vpclattice.ErrCodeValidationException
+			if isFakeConflictError(err) {
+				failureCode = vpclattice.ErrCodeConflictException
+			} else if IsLatticeAPINotFoundErr(err) {
+				failureCode = vpclattice.ErrCodeResourceNotFoundException
+			}
+			out.Unsuccessful = append(out.Unsuccessful, &vpclattice.RuleUpdateFailure{
+				FailureCode:    aws.String(failureCode),
+				FailureMessage: aws.String(err.Error()),
+				RuleIdentifier: update.RuleIdentifier,
+			})
+			continue
+		}
+		out.Successful = append(out.Successful, &vpclattice.RuleUpdateSuccess{
+			Action:    rule.Action,
+			Arn:       rule.Arn,
+			Id:        rule.Id,
+			IsDefault: rule.IsDefault,
+			Match:     rule.Match,
+			Name:      rule.Name,
+			Priority:  rule.Priority,
+		})
+	}
+	return out, nil
+}
+
+func (f *fakeLatticeAPI) BatchUpdateRule(input *vpclattice.BatchUpdateRuleInput) (*vpclattice.BatchUpdateRuleOutput, error) {
+	return f.BatchUpdateRuleWithContext(context.Background(), input)
+}
+
+func isFakeConflictError(err error) bool {
+	_, ok := err.(*vpclattice.ConflictException)
+	return ok
+}
+
+func (f *fakeLatticeAPI) DeleteRuleWithContext(_ aws.Context, input *vpclattice.DeleteRuleInput, _ ...request.Option) (*vpclattice.DeleteRuleOutput, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	rule, err := f.getRule(input.ServiceIdentifier, input.ListenerIdentifier, input.RuleIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	if aws.BoolValue(rule.IsDefault) {
+		return nil, fakeValidationError("the default rule cannot be deleted")
+	}
+	f.rules.remove(aws.StringValue(rule.Id))
+	delete(f.tags, aws.StringValue(rule.Arn))
+	return &vpclattice.DeleteRuleOutput{}, nil
+}
+
+func (f *fakeLatticeAPI) DeleteRule(input *vpclattice.DeleteRuleInput) (*vpclattice.DeleteRuleOutput, error) {
+	return f.DeleteRuleWithContext(context.Background(), input)
+}
+
+func (f *fakeLatticeAPI) ListRulesWithContext(_ aws.Context, input *vpclattice.ListRulesInput, _ ...request.Option) (*vpclattice.ListRulesOutput, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	listener, err := f.getListener(input.ServiceIdentifier, input.ListenerIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	var items []*vpclattice.RuleSummary
+	for _, rule := range f.rules.list() {
+		if rule.listenerId != aws.StringValue(listener.Id) {
+			continue
+		}
+		items = append(items, &vpclattice.RuleSummary{
+			Arn:           rule.Arn,
+			CreatedAt:     rule.CreatedAt,
+			Id:            rule.Id,
+			IsDefault:     rule.IsDefault,
+			LastUpdatedAt: rule.LastUpdatedAt,
+			Name:          rule.Name,
+			Priority:      rule.Priority,
+		})
+	}
+	page, next, err := fakePage(items, f.pageSize, input.MaxResults, input.NextToken)
+	if err != nil {
+		return nil, err
+	}
+	return &vpclattice.ListRulesOutput{Items: page, NextToken: next}, nil
+}
+
+func (f *fakeLatticeAPI) ListRules(input *vpclattice.ListRulesInput) (*vpclattice.ListRulesOutput, error) {
+	return f.ListRulesWithContext(context.Background(), input)
+}
+
+func (f *fakeLatticeAPI) ListRulesPagesWithContext(ctx aws.Context, input *vpclattice.ListRulesInput, fn func(*vpclattice.ListRulesOutput, bool) bool, _ ...request.Option) error {
+	in := *input
+	for {
+		out, err := f.ListRulesWithContext(ctx, &in)
+		if err != nil {
+			return err
+		}
+		lastPage := out.NextToken == nil
+		if !fn(out, lastPage) || lastPage {
+			return nil
+		}
+		in.NextToken = out.NextToken
+	}
+}
+
+func (f *fakeLatticeAPI) ListRulesPages(input *vpclattice.ListRulesInput, fn func(*vpclattice.ListRulesOutput, bool) bool) error {
+	return f.ListRulesPagesWithContext(context.Background(), input, fn)
+}
+
+// Target groups
+
+func (f *fakeLatticeAPI) getTargetGroup(identifier *string) (*fakeTargetGroup, error) {
+	tg, ok := f.targetGroups.get(fakeId(identifier))
+	if !ok {
+		return nil, fakeNotFoundError("TARGET_GROUP", aws.StringValue(identifier))
+	}
+	return tg, nil
+}
+
+// targetGroupServiceArns returns the services which forward to the target group through any listener or rule
+func (f *fakeLatticeAPI) targetGroupServiceArns(tgId string) []*string {
+	var serviceArns []*string
+	seen := map[string]bool{}
+	for _, rule := range f.rules.list() {
+		if rule.Action == nil || rule.Action.Forward == nil || seen[rule.serviceId] {
+			continue
+		}
+		for _, weightedTg := range rule.Action.Forward.TargetGroups {
+			if fakeId(weightedTg.TargetGroupIdentifier) != tgId {
+				continue
+			}
+			if svc, ok := f.services.get(rule.serviceId); ok {
+				serviceArns = append(serviceArns, svc.Arn)
+				seen[rule.serviceId] = true
+			}
+			break
+		}
+	}
+	return serviceArns
+}
+
+func (f *fakeLatticeAPI) CreateTargetGroupWithContext(_ aws.Context, input *vpclattice.CreateTargetGroupInput, _ ...request.Option) (*vpclattice.CreateTargetGroupOutput, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if aws.StringValue(input.Name) == "" || aws.StringValue(input.Type) == "" {
+		return nil, fakeValidationError("name and type are required")
+	}
+	if aws.StringValue(input.Type) != vpclattice.TargetGroupTypeLambda && input.Config == nil {
+		return nil, fakeValidationError("config is required for target groups of type " + aws.StringValue(input.Type))
+	}
+	for _, tg := range f.targetGroups.list() {
+		if aws.StringValue(tg.Name) == aws.StringValue(input.Name) {
+			return nil, fakeConflictError("TARGET_GROUP", aws.StringValue(tg.Id), "target group name already exists")
+		}
+	}
+	config := input.Config
+	if config != nil && config.HealthCheck == nil {
+		config.HealthCheck = &vpclattice.HealthCheckConfig{Enabled: aws.Bool(true)}
+	}
+	id := f.newId("tg")
+	tg := &fakeTargetGroup{
+		GetTargetGroupOutput: vpclattice.GetTargetGroupOutput{
+			Arn:           aws.String(f.arn("targetgroup/" + id)),
+			Config:        config,
+			CreatedAt:     fakeNow(),
+			Id:            aws.String(id),
+			LastUpdatedAt: fakeNow(),
+			Name:          input.Name,
+			Status:        aws.String(vpclattice.TargetGroupStatusActive),
+			Type:          input.Type,
+		},
+		targets: newFakeStore[*vpclattice.TargetSummary](),
+	}
+	f.targetGroups.put(id, tg)
+	f.putTags(*tg.Arn, input.Tags)
+	return &vpclattice.CreateTargetGroupOutput{
+		Arn:    tg.Arn,
+		Config: tg.Config,
+		Id:     tg.Id,
+		Name:   tg.Name,
+		Status: tg.Status,
+		Type:   tg.Type,
+	}, nil
+}
+
+func (f *fakeLatticeAPI) CreateTargetGroup(input *vpclattice.CreateTargetGroupInput) (*vpclattice.CreateTargetGroupOutput, error) {
+	return f.CreateTargetGroupWithContext(context.Background(), input)
+}
+
+func (f *fakeLatticeAPI) GetTargetGroupWithContext(_ aws.Context, input *vpclattice.GetTargetGroupInput, _ ...request.Option) (*vpclattice.GetTargetGroupOutput, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	tg, err := f.getTargetGroup(input.TargetGroupIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	out := tg.GetTargetGroupOutput
+	out.ServiceArns = f.targetGroupServiceArns(aws.StringValue(tg.Id))
+	return &out, nil
+}
+
+func (f *fakeLatticeAPI) GetTargetGroup(input *vpclattice.GetTargetGroupInput) (*vpclattice.GetTargetGroupOutput, error) {
+	return f.GetTargetGroupWithContext(context.Background(), input)
+}
+
+func (f *fakeLatticeAPI) UpdateTargetGroupWithContext(_ aws.Context, input *vpclattice.UpdateTargetGroupInput, _ ...request.Option) (*vpclattice.UpdateTargetGroupOutput, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	tg, err := f.getTargetGroup(input.TargetGroupIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	if input.HealthCheck == nil {
+		return nil, fakeValidationError("healthCheck is required")
+	}
+	if tg.Config == nil {
+		return nil, fakeValidationError("health checks are not supported for target groups of type " + aws.StringValue(tg.Type))
+	}
+	tg.Config.HealthCheck = input.HealthCheck
+	tg.LastUpdatedAt = fakeNow()
+	return &vpclattice.UpdateTargetGroupOutput{
+		Arn:    tg.Arn,
+		Config: tg.Config,
+		Id:     tg.Id,
+		Name:   tg.Name,
+		Status: tg.Status,
+		Type:   tg.Type,
+	}, nil
+}
+
+func (f *fakeLatticeAPI) UpdateTargetGroup(input *vpclattice.UpdateTargetGroupInput) (*vpclattice.UpdateTargetGroupOutput, error) {
+	return f.UpdateTargetGroupWithContext(context.Background(), input)
+}
+
+func (f *fakeLatticeAPI) DeleteTargetGroupWithContext(_ aws.Context, input *vpclattice.DeleteTargetGroupInput, _ ...request.Option) (*vpclattice.DeleteTargetGroupOutput, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	tg, err := f.getTargetGroup(input.TargetGroupIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	if len(f.targetGroupServiceArns(aws.StringValue(tg.Id))) > 0 {
+		return nil, fakeConflictError("TARGET_GROUP", aws.StringValue(tg.Id), "target group is in use by a listener or rule")
+	}
+	f.targetGroups.remove(aws.StringValue(tg.Id))
+	delete(f.tags, aws.StringValue(tg.Arn))
+	return &vpclattice.DeleteTargetGroupOutput{
+		Arn:    tg.Arn,
+		Id:     tg.Id,
+		Status: aws.String(vpclattice.TargetGroupStatusDeleteInProgress),
+	}, nil
+}
+
+func (f *fakeLatticeAPI) DeleteTargetGroup(input *vpclattice.DeleteTargetGroupInput) (*vpclattice.DeleteTargetGroupOutput, error) {
+	return f.DeleteTargetGroupWithContext(context.Background(), input)
+}
+
+func (f *fakeLatticeAPI) ListTargetGroupsWithContext(_ aws.Context, input *vpclattice.ListTargetGroupsInput, _ ...request.Option) (*vpclattice.ListTargetGroupsOutput, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	var items []*vpclattice.TargetGroupSummary
+	for _, tg := range f.targetGroups.list() {
+		if input.TargetGroupType != nil && aws.StringValue(input.TargetGroupType) != aws.StringValue(tg.Type) {
+			continue
+		}
+		summary := &vpclattice.TargetGroupSummary{
+			Arn:           tg.Arn,
+			CreatedAt:     tg.CreatedAt,
+			Id:            tg.Id,
+			LastUpdatedAt: tg.LastUpdatedAt,
+			Name:          tg.Name,
+			ServiceArns:   f.targetGroupServiceArns(aws.StringValue(tg.Id)),
+			Status:        tg.Status,
+			Type:          tg.Type,
+		}
+		if tg.Config != nil {
+			summary.IpAddressType = tg.Config.IpAddressType
+			summary.Port = tg.Config.Port
+			summary.Protocol = tg.Config.Protocol
+			summary.VpcIdentifier = tg.Config.VpcIdentifier
+		}
+		if input.VpcIdentifier != nil && aws.StringValue(input.VpcIdentifier) != aws.StringValue(summary.VpcIdentifier) {
+			continue
+		}
+		items = append(items, summary)
+	}
+	page, next, err := fakePage(items, f.pageSize, input.MaxResults, input.NextToken)
+	if err != nil {
+		return nil, err
+	}
+	return &vpclattice.ListTargetGroupsOutput{Items: page, NextToken: next}, nil
+}
+
+func (f *fakeLatticeAPI) ListTargetGroups(input *vpclattice.ListTargetGroupsInput) (*vpclattice.ListTargetGroupsOutput, error) {
+	return f.ListTargetGroupsWithContext(context.Background(), input)
+}
+
+func (f *fakeLatticeAPI) ListTargetGroupsPagesWithContext(ctx aws.Context, input *vpclattice.ListTargetGroupsInput, fn func(*vpclattice.ListTargetGroupsOutput, bool) bool, _ ...request.Option) error {
+	in := *input
+	for {
+		out, err := f.ListTargetGroupsWithContext(ctx, &in)
+		if err != nil {
+			return err
+		}
+		lastPage := out.NextToken == nil
+		if !fn(out, lastPage) || lastPage {
+			return nil
+		}
+		in.NextToken = out.NextToken
+	}
+}
+
+func (f *fakeLatticeAPI) ListTargetGroupsPages(input *vpclattice.ListTargetGroupsInput, fn func(*vpclattice.ListTargetGroupsOutput, bool) bool) error {
+	return f.ListTargetGroupsPagesWithContext(context.Background(), input, fn)
+}
+
+// Targets
+
+func (f *fakeLatticeAPI) RegisterTargetsWithContext(_ aws.Context, input *vpclattice.RegisterTargetsInput, _ ...request.Option) (*vpclattice.RegisterTargetsOutput, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	tg, err := f.getTargetGroup(input.TargetGroupIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	if len(input.Targets) == 0 || len(input.Targets) > 100 {
+		return nil, fakeValidationError("between 1 and 100 targets must be specified")
+	}
+	out := &vpclattice.RegisterTargetsOutput{}
+	for _, target := range input.Targets {
+		port := aws.Int64Value(target.Port)
+		if target.Port == nil && tg.Config != nil {
+			port = aws.Int64Value(tg.Config.Port)
+		}
+		if aws.StringValue(target.Id) == "" {
+			out.Unsuccessful = append(out.Unsuccessful, &vpclattice.TargetFailure{
+				FailureCode:    aws.String("InvalidTarget"),
+				FailureMessage: aws.String("target id is required"),
+				Id:             target.Id,
+				Port:           target.Port,
+			})
+			continue
+		}
+		// registering a draining target again makes it active
+		tg.targets.put(fakeTargetKey(*target.Id, port), &vpclattice.TargetSummary{
+			Id:   target.Id,
+			Port: aws.Int64(port),
+		})
+		out.Successful = append(out.Successful, &vpclattice.Target{Id: target.Id, Port: aws.Int64(port)})
+	}
+	return out, nil
+}
+
+func (f *fakeLatticeAPI) RegisterTargets(input *vpclattice.RegisterTargetsInput) (*vpclattice.RegisterTargetsOutput, error) {
+	return f.RegisterTargetsWithContext(context.Background(), input)
+}
+
+func (f *fakeLatticeAPI) DeregisterTargetsWithContext(_ aws.Context, input *vpclattice.DeregisterTargetsInput, _ ...request.Option) (*vpclattice.DeregisterTargetsOutput, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	tg, err := f.getTargetGroup(input.TargetGroupIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	if len(input.Targets) == 0 || len(input.Targets) > 100 {
+		return nil, fakeValidationError("between 1 and 100 targets must be specified")
+	}
+	out := &vpclattice.DeregisterTargetsOutput{}
+	for _, target := range input.Targets {
+		port := aws.Int64Value(target.Port)
+		if target.Port == nil && tg.Config != nil {
+			port = aws.Int64Value(tg.Config.Port)
+		}
+		key := fakeTargetKey(aws.StringValue(target.Id), port)
+		if _, ok := tg.targets.get(key); !ok {
+			out.Unsuccessful = append(out.Unsuccessful, &vpclattice.TargetFailure{
+				FailureCode:    aws.String("TargetNotFound"),
+				FailureMessage: aws.String("target is not registered"),
+				Id:             target.Id,
+				Port:           target.Port,
+			})
+			continue
+		}
+		tg.targets.remove(key)
+		out.Successful = append(out.Successful, &vpclattice.Target{Id: target.Id, Port: aws.Int64(port)})
+	}
+	return out, nil
+}
+
+func (f *fakeLatticeAPI) DeregisterTargets(input *vpclattice.DeregisterTargetsInput) (*vpclattice.DeregisterTargetsOutput, error) {
+	return f.DeregisterTargetsWithContext(context.Background(), input)
+}
+
+func (f *fakeLatticeAPI) ListTargetsWithContext(_ aws.Context, input *vpclattice.ListTargetsInput, _ ...request.Option) (*vpclattice.ListTargetsOutput, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	tg, err := f.getTargetGroup(input.TargetGroupIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	defaultStatus := vpclattice.TargetStatusHealthy
+	if len(f.targetGroupServiceArns(aws.StringValue(tg.Id))) == 0 {
+		defaultStatus = vpclattice.TargetStatusUnused
+	} else if tg.Config != nil && tg.Config.HealthCheck != nil && !aws.BoolValue(tg.Config.HealthCheck.Enabled) {
+		defaultStatus = vpclattice.TargetStatusUnavailable
+	}
+
+	var filter map[string]bool
+	if len(input.Targets) > 0 {
+		filter = make(map[string]bool)
+		for _, target := range input.Targets {
+			filter[fakeTargetKey(aws.StringValue(target.Id), aws.Int64Value(target.Port))] = true
+		}
+	}
+	var items []*vpclattice.TargetSummary
+	for _, target := range tg.targets.list() {
+		if filter != nil && !filter[fakeTargetKey(aws.StringValue(target.Id), aws.Int64Value(target.Port))] {
+			continue
+		}
+		summary := *target
+		if summary.Status == nil {
+			summary.Status = aws.String(defaultStatus)
+		}
+		items = append(items, &summary)
+	}
+	page, next, err := fakePage(items, f.pageSize, input.MaxResults, input.NextToken)
+	if err != nil {
+		return nil, err
+	}
+	return &vpclattice.ListTargetsOutput{Items: page, NextToken: next}, nil
+}
+
+func (f *fakeLatticeAPI) ListTargets(input *vpclattice.ListTargetsInput) (*vpclattice.ListTargetsOutput, error) {
+	return f.ListTargetsWithContext(context.Background(), input)
+}
+
+func (f *fakeLatticeAPI) ListTargetsPagesWithContext(ctx aws.Context, input *vpclattice.ListTargetsInput, fn func(*vpclattice.ListTargetsOutput, bool) bool, _ ...request.Option) error {
+	in := *input
+	for {
+		out, err := f.ListTargetsWithContext(ctx, &in)
+		if err != nil {
+			return err
+		}
+		lastPage := out.NextToken == nil
+		if !fn(out, lastPage) || lastPage {
+			return nil
+		}
+		in.NextToken = out.NextToken
+	}
+}
+
+func (f *fakeLatticeAPI) ListTargetsPages(input *vpclattice.ListTargetsInput, fn func(*vpclattice.ListTargetsOutput, bool) bool) error {
+	return f.ListTargetsPagesWithContext(context.Background(), input, fn)
+}
+
+// Access log subscriptions
+
+func fakeDestinationType(destinationArn string) string {
+	a, err := arn.Parse(destinationArn)
+	if err != nil {
+		return ""
+	}
+	return a.Service
+}
+
+func (f *fakeLatticeAPI) getAccessLogSubscription(identifier *string) (*vpclattice.GetAccessLogSubscriptionOutput, error) {
+	als, ok := f.accessLogSubscriptions.get(fakeId(identifier))
+	if !ok {
+		return nil, fakeNotFoundError("ACCESS_LOG_SUBSCRIPTION", aws.StringValue(identifier))
+	}
+	return als, nil
+}
+
+// resolveLoggableResource finds the service network or service an access log subscription or auth policy is attached to
+func (f *fakeLatticeAPI) resolveLoggableResource(identifier *string) (string, string, error) {
+	id := fakeId(identifier)
+	if sn, ok := f.serviceNetworks.get(id); ok {
+		return aws.StringValue(sn.Id), aws.StringValue(sn.Arn), nil
+	}
+	if svc, ok := f.services.get(id); ok {
+		return aws.StringValue(svc.Id), aws.StringValue(svc.Arn), nil
+	}
+	resourceType := "SERVICE"
+	if strings.HasPrefix(id, "sn-") {
+		resourceType = "SERVICE_NETWORK"
+	}
+	return "", "", fakeNotFoundError(resourceType, aws.StringValue(identifier))
+}
+
+func (f *fakeLatticeAPI) CreateAccessLogSubscriptionWithContext(_ aws.Context, input *vpclattice.CreateAccessLogSubscriptionInput, _ ...request.Option) (*vpclattice.CreateAccessLogSubscriptionOutput, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	resourceId, resourceArn, err := f.resolveLoggableResource(input.ResourceIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	destinationType := fakeDestinationType(aws.StringValue(input.DestinationArn))
+	if destinationType == "" {
+		return nil, fakeValidationError("invalid destinationArn " + aws.StringValue(input.DestinationArn))
+	}
+	for _, als := range f.accessLogSubscriptions.list() {
+		if aws.StringValue(als.ResourceId) == resourceId && fakeDestinationType(aws.StringValue(als.DestinationArn)) == destinationType {
+			return nil, fakeConflictError("ACCESS_LOG_SUBSCRIPTION", aws.StringValue(als.Id), "resource already has an access log subscription of the destination type")
+		}
+	}
+	id := f.newId("als")
+	als := &vpclattice.GetAccessLogSubscriptionOutput{
+		Arn:            aws.String(f.arn("accesslogsubscription/" + id)),
+		CreatedAt:      fakeNow(),
+		DestinationArn: input.DestinationArn,
+		Id:             aws.String(id),
+		LastUpdatedAt:  fakeNow(),
+		ResourceArn:    aws.String(resourceArn),
+		ResourceId:     aws.String(resourceId),
+	}
+	f.accessLogSubscriptions.put(id, als)
+	f.putTags(*als.Arn, input.Tags)
+	return &vpclattice.CreateAccessLogSubscriptionOutput{
+		Arn:            als.Arn,
+		DestinationArn: als.DestinationArn,
+		Id:             als.Id,
+		ResourceArn:    als.ResourceArn,
+		ResourceId:     als.ResourceId,
+	}, nil
+}
+
+func (f *fakeLatticeAPI) CreateAccessLogSubscription(input *vpclattice.CreateAccessLogSubscriptionInput) (*vpclattice.CreateAccessLogSubscriptionOutput, error) {
+	return f.CreateAccessLogSubscriptionWithContext(context.Background(), input)
+}
+
+func (f *fakeLatticeAPI) GetAccessLogSubscriptionWithContext(_ aws.Context, input *vpclattice.GetAccessLogSubscriptionInput, _ ...request.Option) (*vpclattice.GetAccessLogSubscriptionOutput, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	als, err := f.getAccessLogSubscription(input.AccessLogSubscriptionIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	out := *als
+	return &out, nil
+}
+
+func (f *fakeLatticeAPI) GetAccessLogSubscription(input *vpclattice.GetAccessLogSubscriptionInput) (*vpclattice.GetAccessLogSubscriptionOutput, error) {
+	return f.GetAccessLogSubscriptionWithContext(context.Background(), input)
+}
+
+// UpdateAccessLogSubscriptionWithContext only allows changing the destination within the same destination type
+func (f *fakeLatticeAPI) UpdateAccessLogSubscriptionWithContext(_ aws.Context, input *vpclattice.UpdateAccessLogSubscriptionInput, _ ...request.Option) (*vpclattice.UpdateAccessLogSubscriptionOutput, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	als, err := f.getAccessLogSubscription(input.AccessLogSubscriptionIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	if fakeDestinationType(aws.StringValue(input.DestinationArn)) != fakeDestinationType(aws.StringValue(als.DestinationArn)) {
+		return nil, fakeConflictError("ACCESS_LOG_SUBSCRIPTION", aws.StringValue(als.Id), "destination type cannot be changed")
+	}
+	als.DestinationArn = input.DestinationArn
+	als.LastUpdatedAt = fakeNow()
+	return &vpclattice.UpdateAccessLogSubscriptionOutput{
+		Arn:            als.Arn,
+		DestinationArn: als.DestinationArn,
+		Id:             als.Id,
+		ResourceArn:    als.ResourceArn,
+		ResourceId:     als.ResourceId,
+	}, nil
+}
+
+func (f *fakeLatticeAPI) UpdateAccessLogSubscription(input *vpclattice.UpdateAccessLogSubscriptionInput) (*vpclattice.UpdateAccessLogSubscriptionOutput, error) {
+	return f.UpdateAccessLogSubscriptionWithContext(context.Background(), input)
+}
+
+func (f *fakeLatticeAPI) DeleteAccessLogSubscriptionWithContext(_ aws.Context, input *vpclattice.DeleteAccessLogSubscriptionInput, _ ...request.Option) (*vpclattice.DeleteAccessLogSubscriptionOutput, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	als, err := f.getAccessLogSubscription(input.AccessLogSubscriptionIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	f.accessLogSubscriptions.remove(aws.StringValue(als.Id))
+	delete(f.tags, aws.StringValue(als.Arn))
+	return &vpclattice.DeleteAccessLogSubscriptionOutput{}, nil
+}
+
+func (f *fakeLatticeAPI) DeleteAccessLogSubscription(input *vpclattice.DeleteAccessLogSubscriptionInput) (*vpclattice.DeleteAccessLogSubscriptionOutput, error) {
+	return f.DeleteAccessLogSubscriptionWithContext(context.Background(), input)
+}
+
+func (f *fakeLatticeAPI) ListAccessLogSubscriptionsWithContext(_ aws.Context, input *vpclattice.ListAccessLogSubscriptionsInput, _ ...request.Option) (*vpclattice.ListAccessLogSubscriptionsOutput, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	resourceId, _, err := f.resolveLoggableResource(input.ResourceIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	var items []*vpclattice.AccessLogSubscriptionSummary
+	for _, als := range f.accessLogSubscriptions.list() {
+		if aws.StringValue(als.ResourceId) != resourceId {
+			continue
+		}
+		items = append(items, &vpclattice.AccessLogSubscriptionSummary{
+			Arn:            als.Arn,
+			CreatedAt:      als.CreatedAt,
+			DestinationArn: als.DestinationArn,
+			Id:             als.Id,
+			LastUpdatedAt:  als.LastUpdatedAt,
+			ResourceArn:    als.ResourceArn,
+			ResourceId:     als.ResourceId,
+		})
+	}
+	page, next, err := fakePage(items, f.pageSize, input.MaxResults, input.NextToken)
+	if err != nil {
+		return nil, err
+	}
+	return &vpclattice.ListAccessLogSubscriptionsOutput{Items: page, NextToken: next}, nil
+}
+
+func (f *fakeLatticeAPI) ListAccessLogSubscriptions(input *vpclattice.ListAccessLogSubscriptionsInput) (*vpclattice.ListAccessLogSubscriptionsOutput, error) {
+	return f.ListAccessLogSubscriptionsWithContext(context.Background(), input)
+}
+
+func (f *fakeLatticeAPI) ListAccessLogSubscriptionsPagesWithContext(ctx aws.Context, input *vpclattice.ListAccessLogSubscriptionsInput, fn func(*vpclattice.ListAccessLogSubscriptionsOutput, bool) bool, _ ...request.Option) error {
+	in := *input
+	for {
+		out, err := f.ListAccessLogSubscriptionsWithContext(ctx, &in)
+		if err != nil {
+			return err
+		}
+		lastPage := out.NextToken == nil
+		if !fn(out, lastPage) || lastPage {
+			return nil
+		}
+		in.NextToken = out.NextToken
+	}
+}
+
+func (f *fakeLatticeAPI) ListAccessLogSubscriptionsPages(input *vpclattice.ListAccessLogSubscriptionsInput, fn func(*vpclattice.ListAccessLogSubscriptionsOutput, bool) bool) error {
+	return f.ListAccessLogSubscriptionsPagesWithContext(context.Background(), input, fn)
+}
+
+// Auth policies
+
+func fakeAuthPolicyState(authType string) string {
+	if authType == vpclattice.AuthTypeAwsIam {
+		return vpclattice.AuthPolicyStateActive
+	}
+	return vpclattice.AuthPolicyStateInactive
+}
+
+func (f *fakeLatticeAPI) updateAuthPolicyState(resourceId string, authType string) {
+	if policy, ok := f.authPolicies.get(resourceId); ok {
+		policy.State = aws.String(fakeAuthPolicyState(authType))
+	}
+}
+
+func (f *fakeLatticeAPI) PutAuthPolicyWithContext(_ aws.Context, input *vpclattice.PutAuthPolicyInput, _ ...request.Option) (*vpclattice.PutAuthPolicyOutput, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	resourceId, _, err := f.resolveLoggableResource(input.ResourceIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	if aws.StringValue(input.Policy) == "" {
+		return nil, fakeValidationError("policy is required")
+	}
+	authType := ""
+	if sn, ok := f.serviceNetworks.get(resourceId); ok {
+		authType = aws.StringValue(sn.AuthType)
+	} else if svc, ok := f.services.get(resourceId); ok {
+		authType = aws.StringValue(svc.AuthType)
+	}
+	policy, ok := f.authPolicies.get(resourceId)
+	if !ok {
+		policy = &fakeAuthPolicy{resourceId: resourceId}
+		policy.CreatedAt = fakeNow()
+	}
+	policy.Policy = input.Policy
+	policy.State = aws.String(fakeAuthPolicyState(authType))
+	policy.LastUpdatedAt = fakeNow()
+	f.authPolicies.put(resourceId, policy)
+	return &vpclattice.PutAuthPolicyOutput{Policy: policy.Policy, State: policy.State}, nil
+}
+
+func (f *fakeLatticeAPI) PutAuthPolicy(input *vpclattice.PutAuthPolicyInput) (*vpclattice.PutAuthPolicyOutput, error) {
+	return f.PutAuthPolicyWithContext(context.Background(), input)
+}
+
+func (f *fakeLatticeAPI) GetAuthPolicyWithContext(_ aws.Context, input *vpclattice.GetAuthPolicyInput, _ ...request.Option) (*vpclattice.GetAuthPolicyOutput, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	resourceId, _, err := f.resolveLoggableResource(input.ResourceIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	policy, ok := f.authPolicies.get(resourceId)
+	if !ok {
+		return nil, fakeNotFoundError("AUTH_POLICY", aws.StringValue(input.ResourceIdentifier))
+	}
+	out := policy.GetAuthPolicyOutput
+	return &out, nil
+}
+
+func (f *fakeLatticeAPI) GetAuthPolicy(input *vpclattice.GetAuthPolicyInput) (*vpclattice.GetAuthPolicyOutput, error) {
+	return f.GetAuthPolicyWithContext(context.Background(), input)
+}
+
+func (f *fakeLatticeAPI) DeleteAuthPolicyWithContext(_ aws.Context, input *vpclattice.DeleteAuthPolicyInput, _ ...request.Option) (*vpclattice.DeleteAuthPolicyOutput, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	resourceId, _, err := f.resolveLoggableResource(input.ResourceIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	f.authPolicies.remove(resourceId)
+	return &vpclattice.DeleteAuthPolicyOutput{}, nil
+}
+
+func (f *fakeLatticeAPI) DeleteAuthPolicy(input *vpclattice.DeleteAuthPolicyInput) (*vpclattice.DeleteAuthPolicyOutput, error) {
+	return f.DeleteAuthPolicyWithContext(context.Background(), input)
+}
+
+// Resource policies
+
+func (f *fakeLatticeAPI) PutResourcePolicyWithContext(_ aws.Context, input *vpclattice.PutResourcePolicyInput, _ ...request.Option) (*vpclattice.PutResourcePolicyOutput, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	resourceArn := aws.StringValue(input.ResourceArn)
+	if _, ok := f.tags[resourceArn]; !ok {
+		return nil, fakeNotFoundError("RESOURCE", resourceArn)
+	}
+	if aws.StringValue(input.Policy) == "" {
+		return nil, fakeValidationError("policy is required")
+	}
+	f.resourcePolicies[resourceArn] = aws.StringValue(input.Policy)
+	return &vpclattice.PutResourcePolicyOutput{}, nil
+}
+
+func (f *fakeLatticeAPI) PutResourcePolicy(input *vpclattice.PutResourcePolicyInput) (*vpclattice.PutResourcePolicyOutput, error) {
+	return f.PutResourcePolicyWithContext(context.Background(), input)
+}
+
+func (f *fakeLatticeAPI) GetResourcePolicyWithContext(_ aws.Context, input *vpclattice.GetResourcePolicyInput, _ ...request.Option) (*vpclattice.GetResourcePolicyOutput, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	policy, ok := f.resourcePolicies[aws.StringValue(input.ResourceArn)]
+	if !ok {
+		return nil, fakeNotFoundError("RESOURCE_POLICY", aws.StringValue(input.ResourceArn))
+	}
+	return &vpclattice.GetResourcePolicyOutput{Policy: aws.String(policy)}, nil
+}
+
+func (f *fakeLatticeAPI) GetResourcePolicy(input *vpclattice.GetResourcePolicyInput) (*vpclattice.GetResourcePolicyOutput, error) {
+	return f.GetResourcePolicyWithContext(context.Background(), input)
+}
+
+func (f *fakeLatticeAPI) DeleteResourcePolicyWithContext(_ aws.Context, input *vpclattice.DeleteResourcePolicyInput, _ ...request.Option) (*vpclattice.DeleteResourcePolicyOutput, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	delete(f.resourcePolicies, aws.StringValue(input.ResourceArn))
+	return &vpclattice.DeleteResourcePolicyOutput{}, nil
+}
+
+func (f *fakeLatticeAPI) DeleteResourcePolicy(input *vpclattice.DeleteResourcePolicyInput) (*vpclattice.DeleteResourcePolicyOutput, error) {
+	return f.DeleteResourcePolicyWithContext(context.Background(), input)
+}
+
+// Tags
+
+func (f *fakeLatticeAPI) TagResourceWithContext(_ aws.Context, input *vpclattice.TagResourceInput, _ ...request.Option) (*vpclattice.TagResourceOutput, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	tags, ok := f.tags[aws.StringValue(input.ResourceArn)]
+	if !ok {
+		return nil, fakeNotFoundError("RESOURCE", aws.StringValue(input.ResourceArn))
+	}
+	for k, v := range input.Tags {
+		tags[k] = aws.String(aws.StringValue(v))
+	}
+	return &vpclattice.TagResourceOutput{}, nil
+}
+
+func (f *fakeLatticeAPI) TagResource(input *vpclattice.TagResourceInput) (*vpclattice.TagResourceOutput, error) {
+	return f.TagResourceWithContext(context.Background(), input)
+}
+
+func (f *fakeLatticeAPI) UntagResourceWithContext(_ aws.Context, input *vpclattice.UntagResourceInput, _ ...request.Option) (*vpclattice.UntagResourceOutput, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	tags, ok := f.tags[aws.StringValue(input.ResourceArn)]
+	if !ok {
+		return nil, fakeNotFoundError("RESOURCE", aws.StringValue(input.ResourceArn))
+	}
+	for _, k := range input.TagKeys {
+		delete(tags, aws.StringValue(k))
+	}
+	return &vpclattice.UntagResourceOutput{}, nil
+}
+
+func (f *fakeLatticeAPI) UntagResource(input *vpclattice.UntagResourceInput) (*vpclattice.UntagResourceOutput, error) {
+	return f.UntagResourceWithContext(context.Background(), input)
+}
+
+func (f *fakeLatticeAPI) ListTagsForResourceWithContext(_ aws.Context, input *vpclattice.ListTagsForResourceInput, _ ...request.Option) (*vpclattice.ListTagsForResourceOutput, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	tags, ok := f.tags[aws.StringValue(input.ResourceArn)]
+	if !ok {
+		return nil, fakeNotFoundError("RESOURCE", aws.StringValue(input.ResourceArn))
+	}
+	out := make(Tags, len(tags))
+	for k, v := range tags {
+		out[k] = aws.String(aws.StringValue(v))
+	}
+	return &vpclattice.ListTagsForResourceOutput{Tags: out}, nil
+}
+
+func (f *fakeLatticeAPI) ListTagsForResource(input *vpclattice.ListTagsForResourceInput) (*vpclattice.ListTagsForResourceOutput, error) {
+	return f.ListTagsForResourceWithContext(context.Background(), input)
+}
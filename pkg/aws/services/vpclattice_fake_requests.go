@@ -0,0 +1,239 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/client/metadata"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/vpclattice"
+	"github.com/aws/aws-sdk-go/service/vpclattice/vpclatticeiface"
+)
+
+// FakeNotImplementedErrorCode is the error code of the requests built by the fake, which runs the operations
+// directly instead
+const FakeNotImplementedErrorCode = "NotImplemented"
+
+var _ vpclatticeiface.VPCLatticeAPI = &fakeLatticeAPI{}
+
+func fakeNotImplementedError(operation string) error {
+	return awserr.New(FakeNotImplementedErrorCode,
+		fmt.Sprintf("%sRequest is not implemented by the fake VPC Lattice, use %sWithContext", operation, operation), nil)
+}
+
+// fakeNotImplementedRequest returns a request which fails when sent, the fake has no request constructors
+func fakeNotImplementedRequest(operation string) *request.Request {
+	req := request.New(aws.Config{}, metadata.ClientInfo{ServiceName: vpclattice.ServiceName}, request.Handlers{},
+		nil, &request.Operation{Name: operation}, nil, nil)
+	req.Error = fakeNotImplementedError(operation)
+	return req
+}
+
+func (f *fakeLatticeAPI) BatchUpdateRuleRequest(*vpclattice.BatchUpdateRuleInput) (*request.Request, *vpclattice.BatchUpdateRuleOutput) {
+	return fakeNotImplementedRequest("BatchUpdateRule"), &vpclattice.BatchUpdateRuleOutput{}
+}
+
+func (f *fakeLatticeAPI) CreateAccessLogSubscriptionRequest(*vpclattice.CreateAccessLogSubscriptionInput) (*request.Request, *vpclattice.CreateAccessLogSubscriptionOutput) {
+	return fakeNotImplementedRequest("CreateAccessLogSubscription"), &vpclattice.CreateAccessLogSubscriptionOutput{}
+}
+
+func (f *fakeLatticeAPI) CreateListenerRequest(*vpclattice.CreateListenerInput) (*request.Request, *vpclattice.CreateListenerOutput) {
+	return fakeNotImplementedRequest("CreateListener"), &vpclattice.CreateListenerOutput{}
+}
+
+func (f *fakeLatticeAPI) CreateRuleRequest(*vpclattice.CreateRuleInput) (*request.Request, *vpclattice.CreateRuleOutput) {
+	return fakeNotImplementedRequest("CreateRule"), &vpclattice.CreateRuleOutput{}
+}
+
+func (f *fakeLatticeAPI) CreateServiceRequest(*vpclattice.CreateServiceInput) (*request.Request, *vpclattice.CreateServiceOutput) {
+	return fakeNotImplementedRequest("CreateService"), &vpclattice.CreateServiceOutput{}
+}
+
+func (f *fakeLatticeAPI) CreateServiceNetworkRequest(*vpclattice.CreateServiceNetworkInput) (*request.Request, *vpclattice.CreateServiceNetworkOutput) {
+	return fakeNotImplementedRequest("CreateServiceNetwork"), &vpclattice.CreateServiceNetworkOutput{}
+}
+
+func (f *fakeLatticeAPI) CreateServiceNetworkServiceAssociationRequest(*vpclattice.CreateServiceNetworkServiceAssociationInput) (*request.Request, *vpclattice.CreateServiceNetworkServiceAssociationOutput) {
+	return fakeNotImplementedRequest("CreateServiceNetworkServiceAssociation"), &vpclattice.CreateServiceNetworkServiceAssociationOutput{}
+}
+
+func (f *fakeLatticeAPI) CreateServiceNetworkVpcAssociationRequest(*vpclattice.CreateServiceNetworkVpcAssociationInput) (*request.Request, *vpclattice.CreateServiceNetworkVpcAssociationOutput) {
+	return fakeNotImplementedRequest("CreateServiceNetworkVpcAssociation"), &vpclattice.CreateServiceNetworkVpcAssociationOutput{}
+}
+
+func (f *fakeLatticeAPI) CreateTargetGroupRequest(*vpclattice.CreateTargetGroupInput) (*request.Request, *vpclattice.CreateTargetGroupOutput) {
+	return fakeNotImplementedRequest("CreateTargetGroup"), &vpclattice.CreateTargetGroupOutput{}
+}
+
+func (f *fakeLatticeAPI) DeleteAccessLogSubscriptionRequest(*vpclattice.DeleteAccessLogSubscriptionInput) (*request.Request, *vpclattice.DeleteAccessLogSubscriptionOutput) {
+	return fakeNotImplementedRequest("DeleteAccessLogSubscription"), &vpclattice.DeleteAccessLogSubscriptionOutput{}
+}
+
+func (f *fakeLatticeAPI) DeleteAuthPolicyRequest(*vpclattice.DeleteAuthPolicyInput) (*request.Request, *vpclattice.DeleteAuthPolicyOutput) {
+	return fakeNotImplementedRequest("DeleteAuthPolicy"), &vpclattice.DeleteAuthPolicyOutput{}
+}
+
+func (f *fakeLatticeAPI) DeleteListenerRequest(*vpclattice.DeleteListenerInput) (*request.Request, *vpclattice.DeleteListenerOutput) {
+	return fakeNotImplementedRequest("DeleteListener"), &vpclattice.DeleteListenerOutput{}
+}
+
+func (f *fakeLatticeAPI) DeleteResourcePolicyRequest(*vpclattice.DeleteResourcePolicyInput) (*request.Request, *vpclattice.DeleteResourcePolicyOutput) {
+	return fakeNotImplementedRequest("DeleteResourcePolicy"), &vpclattice.DeleteResourcePolicyOutput{}
+}
+
+func (f *fakeLatticeAPI) DeleteRuleRequest(*vpclattice.DeleteRuleInput) (*request.Request, *vpclattice.DeleteRuleOutput) {
+	return fakeNotImplementedRequest("DeleteRule"), &vpclattice.DeleteRuleOutput{}
+}
+
+func (f *fakeLatticeAPI) DeleteServiceRequest(*vpclattice.DeleteServiceInput) (*request.Request, *vpclattice.DeleteServiceOutput) {
+	return fakeNotImplementedRequest("DeleteService"), &vpclattice.DeleteServiceOutput{}
+}
+
+func (f *fakeLatticeAPI) DeleteServiceNetworkRequest(*vpclattice.DeleteServiceNetworkInput) (*request.Request, *vpclattice.DeleteServiceNetworkOutput) {
+	return fakeNotImplementedRequest("DeleteServiceNetwork"), &vpclattice.DeleteServiceNetworkOutput{}
+}
+
+func (f *fakeLatticeAPI) DeleteServiceNetworkServiceAssociationRequest(*vpclattice.DeleteServiceNetworkServiceAssociationInput) (*request.Request, *vpclattice.DeleteServiceNetworkServiceAssociationOutput) {
+	return fakeNotImplementedRequest("DeleteServiceNetworkServiceAssociation"), &vpclattice.DeleteServiceNetworkServiceAssociationOutput{}
+}
+
+func (f *fakeLatticeAPI) DeleteServiceNetworkVpcAssociationRequest(*vpclattice.DeleteServiceNetworkVpcAssociationInput) (*request.Request, *vpclattice.DeleteServiceNetworkVpcAssociationOutput) {
+	return fakeNotImplementedRequest("DeleteServiceNetworkVpcAssociation"), &vpclattice.DeleteServiceNetworkVpcAssociationOutput{}
+}
+
+func (f *fakeLatticeAPI) DeleteTargetGroupRequest(*vpclattice.DeleteTargetGroupInput) (*request.Request, *vpclattice.DeleteTargetGroupOutput) {
+	return fakeNotImplementedRequest("DeleteTargetGroup"), &vpclattice.DeleteTargetGroupOutput{}
+}
+
+func (f *fakeLatticeAPI) DeregisterTargetsRequest(*vpclattice.DeregisterTargetsInput) (*request.Request, *vpclattice.DeregisterTargetsOutput) {
+	return fakeNotImplementedRequest("DeregisterTargets"), &vpclattice.DeregisterTargetsOutput{}
+}
+
+func (f *fakeLatticeAPI) GetAccessLogSubscriptionRequest(*vpclattice.GetAccessLogSubscriptionInput) (*request.Request, *vpclattice.GetAccessLogSubscriptionOutput) {
+	return fakeNotImplementedRequest("GetAccessLogSubscription"), &vpclattice.GetAccessLogSubscriptionOutput{}
+}
+
+func (f *fakeLatticeAPI) GetAuthPolicyRequest(*vpclattice.GetAuthPolicyInput) (*request.Request, *vpclattice.GetAuthPolicyOutput) {
+	return fakeNotImplementedRequest("GetAuthPolicy"), &vpclattice.GetAuthPolicyOutput{}
+}
+
+func (f *fakeLatticeAPI) GetListenerRequest(*vpclattice.GetListenerInput) (*request.Request, *vpclattice.GetListenerOutput) {
+	return fakeNotImplementedRequest("GetListener"), &vpclattice.GetListenerOutput{}
+}
+
+func (f *fakeLatticeAPI) GetResourcePolicyRequest(*vpclattice.GetResourcePolicyInput) (*request.Request, *vpclattice.GetResourcePolicyOutput) {
+	return fakeNotImplementedRequest("GetResourcePolicy"), &vpclattice.GetResourcePolicyOutput{}
+}
+
+func (f *fakeLatticeAPI) GetRuleRequest(*vpclattice.GetRuleInput) (*request.Request, *vpclattice.GetRuleOutput) {
+	return fakeNotImplementedRequest("GetRule"), &vpclattice.GetRuleOutput{}
+}
+
+func (f *fakeLatticeAPI) GetServiceRequest(*vpclattice.GetServiceInput) (*request.Request, *vpclattice.GetServiceOutput) {
+	return fakeNotImplementedRequest("GetService"), &vpclattice.GetServiceOutput{}
+}
+
+func (f *fakeLatticeAPI) GetServiceNetworkRequest(*vpclattice.GetServiceNetworkInput) (*request.Request, *vpclattice.GetServiceNetworkOutput) {
+	return fakeNotImplementedRequest("GetServiceNetwork"), &vpclattice.GetServiceNetworkOutput{}
+}
+
+func (f *fakeLatticeAPI) GetServiceNetworkServiceAssociationRequest(*vpclattice.GetServiceNetworkServiceAssociationInput) (*request.Request, *vpclattice.GetServiceNetworkServiceAssociationOutput) {
+	return fakeNotImplementedRequest("GetServiceNetworkServiceAssociation"), &vpclattice.GetServiceNetworkServiceAssociationOutput{}
+}
+
+func (f *fakeLatticeAPI) GetServiceNetworkVpcAssociationRequest(*vpclattice.GetServiceNetworkVpcAssociationInput) (*request.Request, *vpclattice.GetServiceNetworkVpcAssociationOutput) {
+	return fakeNotImplementedRequest("GetServiceNetworkVpcAssociation"), &vpclattice.GetServiceNetworkVpcAssociationOutput{}
+}
+
+func (f *fakeLatticeAPI) GetTargetGroupRequest(*vpclattice.GetTargetGroupInput) (*request.Request, *vpclattice.GetTargetGroupOutput) {
+	return fakeNotImplementedRequest("GetTargetGroup"), &vpclattice.GetTargetGroupOutput{}
+}
+
+func (f *fakeLatticeAPI) ListAccessLogSubscriptionsRequest(*vpclattice.ListAccessLogSubscriptionsInput) (*request.Request, *vpclattice.ListAccessLogSubscriptionsOutput) {
+	return fakeNotImplementedRequest("ListAccessLogSubscriptions"), &vpclattice.ListAccessLogSubscriptionsOutput{}
+}
+
+func (f *fakeLatticeAPI) ListListenersRequest(*vpclattice.ListListenersInput) (*request.Request, *vpclattice.ListListenersOutput) {
+	return fakeNotImplementedRequest("ListListeners"), &vpclattice.ListListenersOutput{}
+}
+
+func (f *fakeLatticeAPI) ListRulesRequest(*vpclattice.ListRulesInput) (*request.Request, *vpclattice.ListRulesOutput) {
+	return fakeNotImplementedRequest("ListRules"), &vpclattice.ListRulesOutput{}
+}
+
+func (f *fakeLatticeAPI) ListServiceNetworkServiceAssociationsRequest(*vpclattice.ListServiceNetworkServiceAssociationsInput) (*request.Request, *vpclattice.ListServiceNetworkServiceAssociationsOutput) {
+	return fakeNotImplementedRequest("ListServiceNetworkServiceAssociations"), &vpclattice.ListServiceNetworkServiceAssociationsOutput{}
+}
+
+func (f *fakeLatticeAPI) ListServiceNetworkVpcAssociationsRequest(*vpclattice.ListServiceNetworkVpcAssociationsInput) (*request.Request, *vpclattice.ListServiceNetworkVpcAssociationsOutput) {
+	return fakeNotImplementedRequest("ListServiceNetworkVpcAssociations"), &vpclattice.ListServiceNetworkVpcAssociationsOutput{}
+}
+
+func (f *fakeLatticeAPI) ListServiceNetworksRequest(*vpclattice.ListServiceNetworksInput) (*request.Request, *vpclattice.ListServiceNetworksOutput) {
+	return fakeNotImplementedRequest("ListServiceNetworks"), &vpclattice.ListServiceNetworksOutput{}
+}
+
+func (f *fakeLatticeAPI) ListServicesRequest(*vpclattice.ListServicesInput) (*request.Request, *vpclattice.ListServicesOutput) {
+	return fakeNotImplementedRequest("ListServices"), &vpclattice.ListServicesOutput{}
+}
+
+func (f *fakeLatticeAPI) ListTagsForResourceRequest(*vpclattice.ListTagsForResourceInput) (*request.Request, *vpclattice.ListTagsForResourceOutput) {
+	return fakeNotImplementedRequest("ListTagsForResource"), &vpclattice.ListTagsForResourceOutput{}
+}
+
+func (f *fakeLatticeAPI) ListTargetGroupsRequest(*vpclattice.ListTargetGroupsInput) (*request.Request, *vpclattice.ListTargetGroupsOutput) {
+	return fakeNotImplementedRequest("ListTargetGroups"), &vpclattice.ListTargetGroupsOutput{}
+}
+
+func (f *fakeLatticeAPI) ListTargetsRequest(*vpclattice.ListTargetsInput) (*request.Request, *vpclattice.ListTargetsOutput) {
+	return fakeNotImplementedRequest("ListTargets"), &vpclattice.ListTargetsOutput{}
+}
+
+func (f *fakeLatticeAPI) PutAuthPolicyRequest(*vpclattice.PutAuthPolicyInput) (*request.Request, *vpclattice.PutAuthPolicyOutput) {
+	return fakeNotImplementedRequest("PutAuthPolicy"), &vpclattice.PutAuthPolicyOutput{}
+}
+
+func (f *fakeLatticeAPI) PutResourcePolicyRequest(*vpclattice.PutResourcePolicyInput) (*request.Request, *vpclattice.PutResourcePolicyOutput) {
+	return fakeNotImplementedRequest("PutResourcePolicy"), &vpclattice.PutResourcePolicyOutput{}
+}
+
+func (f *fakeLatticeAPI) RegisterTargetsRequest(*vpclattice.RegisterTargetsInput) (*request.Request, *vpclattice.RegisterTargetsOutput) {
+	return fakeNotImplementedRequest("RegisterTargets"), &vpclattice.RegisterTargetsOutput{}
+}
+
+func (f *fakeLatticeAPI) TagResourceRequest(*vpclattice.TagResourceInput) (*request.Request, *vpclattice.TagResourceOutput) {
+	return fakeNotImplementedRequest("TagResource"), &vpclattice.TagResourceOutput{}
+}
+
+func (f *fakeLatticeAPI) UntagResourceRequest(*vpclattice.UntagResourceInput) (*request.Request, *vpclattice.UntagResourceOutput) {
+	return fakeNotImplementedRequest("UntagResource"), &vpclattice.UntagResourceOutput{}
+}
+
+func (f *fakeLatticeAPI) UpdateAccessLogSubscriptionRequest(*vpclattice.UpdateAccessLogSubscriptionInput) (*request.Request, *vpclattice.UpdateAccessLogSubscriptionOutput) {
+	return fakeNotImplementedRequest("UpdateAccessLogSubscription"), &vpclattice.UpdateAccessLogSubscriptionOutput{}
+}
+
+func (f *fakeLatticeAPI) UpdateListenerRequest(*vpclattice.UpdateListenerInput) (*request.Request, *vpclattice.UpdateListenerOutput) {
+	return fakeNotImplementedRequest("UpdateListener"), &vpclattice.UpdateListenerOutput{}
+}
+
+func (f *fakeLatticeAPI) UpdateRuleRequest(*vpclattice.UpdateRuleInput) (*request.Request, *vpclattice.UpdateRuleOutput) {
+	return fakeNotImplementedRequest("UpdateRule"), &vpclattice.UpdateRuleOutput{}
+}
+
+func (f *fakeLatticeAPI) UpdateServiceRequest(*vpclattice.UpdateServiceInput) (*request.Request, *vpclattice.UpdateServiceOutput) {
+	return fakeNotImplementedRequest("UpdateService"), &vpclattice.UpdateServiceOutput{}
+}
+
+func (f *fakeLatticeAPI) UpdateServiceNetworkRequest(*vpclattice.UpdateServiceNetworkInput) (*request.Request, *vpclattice.UpdateServiceNetworkOutput) {
+	return fakeNotImplementedRequest("UpdateServiceNetwork"), &vpclattice.UpdateServiceNetworkOutput{}
+}
+
+func (f *fakeLatticeAPI) UpdateServiceNetworkVpcAssociationRequest(*vpclattice.UpdateServiceNetworkVpcAssociationInput) (*request.Request, *vpclattice.UpdateServiceNetworkVpcAssociationOutput) {
+	return fakeNotImplementedRequest("UpdateServiceNetworkVpcAssociation"), &vpclattice.UpdateServiceNetworkVpcAssociationOutput{}
+}
+
+func (f *fakeLatticeAPI) UpdateTargetGroupRequest(*vpclattice.UpdateTargetGroupInput) (*request.Request, *vpclattice.UpdateTargetGroupOutput) {
+	return fakeNotImplementedRequest("UpdateTargetGroup"), &vpclattice.UpdateTargetGroupOutput{}
+}
@@ -0,0 +1,264 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/vpclattice"
+	"github.com/stretchr/testify/assert"
+)
+
+func createFakeService(t *testing.T, f *FakeLattice, name string) (*vpclattice.CreateServiceOutput, *vpclattice.CreateListenerOutput) {
+	ctx := context.TODO()
+	svc, err := f.CreateServiceWithContext(ctx, &vpclattice.CreateServiceInput{
+		Name: aws.String(name),
+		Tags: Tags{"k": aws.String("v")},
+	})
+	assert.NoError(t, err)
+	listener, err := f.CreateListenerWithContext(ctx, &vpclattice.CreateListenerInput{
+		ServiceIdentifier: svc.Id,
+		Name:              aws.String(name + "-80"),
+		Protocol:          aws.String(vpclattice.ListenerProtocolHttp),
+		Port:              aws.Int64(80),
+		DefaultAction: &vpclattice.RuleAction{
+			FixedResponse: &vpclattice.FixedResponseAction{StatusCode: aws.Int64(404)},
+		},
+	})
+	assert.NoError(t, err)
+	return svc, listener
+}
+
+func createFakeRule(f *FakeLattice, svcId *string, listenerId *string, name string, priority int64, tgId *string) (*vpclattice.CreateRuleOutput, error) {
+	return f.CreateRuleWithContext(context.TODO(), &vpclattice.CreateRuleInput{
+		ServiceIdentifier:  svcId,
+		ListenerIdentifier: listenerId,
+		Name:               aws.String(name),
+		Priority:           aws.Int64(priority),
+		Match: &vpclattice.RuleMatch{HttpMatch: &vpclattice.HttpMatch{
+			PathMatch: &vpclattice.PathMatch{Match: &vpclattice.PathMatchType{Prefix: aws.String("/")}},
+		}},
+		Action: &vpclattice.RuleAction{Forward: &vpclattice.ForwardAction{
+			TargetGroups: []*vpclattice.WeightedTargetGroup{{TargetGroupIdentifier: tgId, Weight: aws.Int64(1)}},
+		}},
+	})
+}
+
+func TestFakeLattice_ServiceLifecycle(t *testing.T) {
+	ctx := context.TODO()
+	f := NewFakeLattice("111111111111", "us-west-2")
+
+	svc, listener := createFakeService(t, f, "svc")
+	assert.Equal(t, fmt.Sprintf("arn:aws:vpc-lattice:us-west-2:111111111111:service/%s", *svc.Id), *svc.Arn)
+	assert.NotEmpty(t, aws.StringValue(svc.DnsEntry.DomainName))
+
+	_, err := f.CreateServiceWithContext(ctx, &vpclattice.CreateServiceInput{Name: aws.String("svc")})
+	assert.IsType(t, &vpclattice.ConflictException{}, err)
+
+	// identifiers can be ids or ARNs
+	got, err := f.GetServiceWithContext(ctx, &vpclattice.GetServiceInput{ServiceIdentifier: svc.Arn})
+	assert.NoError(t, err)
+	assert.Equal(t, svc.Id, got.Id)
+
+	found, err := f.FindService(ctx, "svc")
+	assert.NoError(t, err)
+	assert.Equal(t, svc.Arn, found.Arn)
+	_, err = f.FindService(ctx, "missing")
+	assert.True(t, IsNotFoundError(err))
+
+	tags, err := f.ListTagsForResourceWithContext(ctx, &vpclattice.ListTagsForResourceInput{ResourceArn: svc.Arn})
+	assert.NoError(t, err)
+	assert.Equal(t, "v", aws.StringValue(tags.Tags["k"]))
+
+	sn, err := f.CreateServiceNetworkWithContext(ctx, &vpclattice.CreateServiceNetworkInput{Name: aws.String("sn")})
+	assert.NoError(t, err)
+	snsa, err := f.CreateServiceNetworkServiceAssociationWithContext(ctx, &vpclattice.CreateServiceNetworkServiceAssociationInput{
+		ServiceIdentifier:        svc.Id,
+		ServiceNetworkIdentifier: sn.Arn,
+	})
+	assert.NoError(t, err)
+
+	// associated services and service networks cannot be deleted
+	_, err = f.DeleteServiceWithContext(ctx, &vpclattice.DeleteServiceInput{ServiceIdentifier: svc.Id})
+	assert.IsType(t, &vpclattice.ConflictException{}, err)
+	_, err = f.DeleteServiceNetworkWithContext(ctx, &vpclattice.DeleteServiceNetworkInput{ServiceNetworkIdentifier: sn.Id})
+	assert.IsType(t, &vpclattice.ConflictException{}, err)
+
+	_, err = f.DeleteServiceNetworkServiceAssociationWithContext(ctx, &vpclattice.DeleteServiceNetworkServiceAssociationInput{
+		ServiceNetworkServiceAssociationIdentifier: snsa.Id,
+	})
+	assert.NoError(t, err)
+	_, err = f.DeleteServiceWithContext(ctx, &vpclattice.DeleteServiceInput{ServiceIdentifier: svc.Id})
+	assert.NoError(t, err)
+
+	_, err = f.GetServiceWithContext(ctx, &vpclattice.GetServiceInput{ServiceIdentifier: svc.Id})
+	assert.True(t, IsLatticeAPINotFoundErr(err))
+	_, err = f.GetListenerWithContext(ctx, &vpclattice.GetListenerInput{ServiceIdentifier: svc.Id, ListenerIdentifier: listener.Id})
+	assert.True(t, IsLatticeAPINotFoundErr(err))
+	_, err = f.ListTagsForResourceWithContext(ctx, &vpclattice.ListTagsForResourceInput{ResourceArn: svc.Arn})
+	assert.True(t, IsLatticeAPINotFoundErr(err))
+}
+
+func TestFakeLattice_Pagination(t *testing.T) {
+	ctx := context.TODO()
+	f := NewFakeLattice("", "")
+	f.SetPageSize(2)
+
+	for i := 0; i < 5; i++ {
+		_, err := f.CreateTargetGroupWithContext(ctx, &vpclattice.CreateTargetGroupInput{
+			Name:   aws.String(fmt.Sprintf("tg-%d", i)),
+			Type:   aws.String(vpclattice.TargetGroupTypeIp),
+			Config: &vpclattice.TargetGroupConfig{VpcIdentifier: aws.String("vpc-1"), Port: aws.Int64(80)},
+		})
+		assert.NoError(t, err)
+	}
+
+	page, err := f.ListTargetGroupsWithContext(ctx, &vpclattice.ListTargetGroupsInput{})
+	assert.NoError(t, err)
+	assert.Len(t, page.Items, 2)
+	assert.NotNil(t, page.NextToken)
+
+	page, err = f.ListTargetGroupsWithContext(ctx, &vpclattice.ListTargetGroupsInput{MaxResults: aws.Int64(4), NextToken: page.NextToken})
+	assert.NoError(t, err)
+	assert.Len(t, page.Items, 3)
+	assert.Nil(t, page.NextToken)
+
+	all, err := f.ListTargetGroupsAsList(ctx, &vpclattice.ListTargetGroupsInput{})
+	assert.NoError(t, err)
+	assert.Len(t, all, 5)
+	for i, tg := range all {
+		assert.Equal(t, fmt.Sprintf("tg-%d", i), aws.StringValue(tg.Name))
+	}
+
+	_, err = f.ListTargetGroupsWithContext(ctx, &vpclattice.ListTargetGroupsInput{NextToken: aws.String("bogus")})
+	assert.IsType(t, &vpclattice.ValidationException{}, err)
+}
+
+func TestFakeLattice_RulePriorities(t *testing.T) {
+	ctx := context.TODO()
+	f := NewFakeLattice("", "")
+	svc, listener := createFakeService(t, f, "svc")
+
+	tg, err := f.CreateTargetGroupWithContext(ctx, &vpclattice.CreateTargetGroupInput{
+		Name:   aws.String("tg"),
+		Type:   aws.String(vpclattice.TargetGroupTypeIp),
+		Config: &vpclattice.TargetGroupConfig{VpcIdentifier: aws.String("vpc-1"), Port: aws.Int64(80)},
+	})
+	assert.NoError(t, err)
+
+	rule1, err := createFakeRule(f, svc.Id, listener.Id, "rule-1", 1, tg.Id)
+	assert.NoError(t, err)
+	rule2, err := createFakeRule(f, svc.Id, listener.Id, "rule-2", 2, tg.Id)
+	assert.NoError(t, err)
+	_, err = createFakeRule(f, svc.Id, listener.Id, "rule-3", 2, tg.Id)
+	assert.IsType(t, &vpclattice.ConflictException{}, err)
+
+	// the default rule is listed along the created rules
+	rules, err := f.ListRulesAsList(ctx, &vpclattice.ListRulesInput{ServiceIdentifier: svc.Id, ListenerIdentifier: listener.Id})
+	assert.NoError(t, err)
+	assert.Len(t, rules, 3)
+	assert.True(t, aws.BoolValue(rules[0].IsDefault))
+	_, err = f.DeleteRuleWithContext(ctx, &vpclattice.DeleteRuleInput{
+		ServiceIdentifier: svc.Id, ListenerIdentifier: listener.Id, RuleIdentifier: rules[0].Id,
+	})
+	assert.IsType(t, &vpclattice.ValidationException{}, err)
+
+	// priorities can be swapped through a free priority
+	out, err := f.BatchUpdateRuleWithContext(ctx, &vpclattice.BatchUpdateRuleInput{
+		ServiceIdentifier:  svc.Id,
+		ListenerIdentifier: listener.Id,
+		Rules: []*vpclattice.RuleUpdate{
+			{RuleIdentifier: rule1.Id, Priority: aws.Int64(2)},
+			{RuleIdentifier: rule2.Id, Priority: aws.Int64(3)},
+			{RuleIdentifier: rule1.Id, Priority: aws.Int64(2)},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, out.Unsuccessful, 1)
+	assert.Equal(t, vpclattice.ErrCodeConflictException, aws.StringValue(out.Unsuccessful[0].FailureCode))
+	assert.Len(t, out.Successful, 2)
+
+	// target groups in use cannot be deleted, and report their targets as used
+	_, err = f.RegisterTargetsWithContext(ctx, &vpclattice.RegisterTargetsInput{
+		TargetGroupIdentifier: tg.Id,
+		Targets:               []*vpclattice.Target{{Id: aws.String("10.0.0.1"), Port: aws.Int64(8080)}},
+	})
+	assert.NoError(t, err)
+	targets, err := f.ListTargetsAsList(ctx, &vpclattice.ListTargetsInput{TargetGroupIdentifier: tg.Id})
+	assert.NoError(t, err)
+	assert.Equal(t, vpclattice.TargetStatusHealthy, aws.StringValue(targets[0].Status))
+	assert.NoError(t, f.SetTargetStatus(*tg.Id, "10.0.0.1", 8080, vpclattice.TargetStatusUnhealthy, vpclattice.TargetStatusUnhealthy))
+	targets, err = f.ListTargetsAsList(ctx, &vpclattice.ListTargetsInput{TargetGroupIdentifier: tg.Arn})
+	assert.NoError(t, err)
+	assert.Equal(t, vpclattice.TargetStatusUnhealthy, aws.StringValue(targets[0].Status))
+
+	_, err = f.DeleteTargetGroupWithContext(ctx, &vpclattice.DeleteTargetGroupInput{TargetGroupIdentifier: tg.Id})
+	assert.IsType(t, &vpclattice.ConflictException{}, err)
+
+	_, err = f.DeleteListenerWithContext(ctx, &vpclattice.DeleteListenerInput{ServiceIdentifier: svc.Id, ListenerIdentifier: listener.Id})
+	assert.NoError(t, err)
+	targets, err = f.ListTargetsAsList(ctx, &vpclattice.ListTargetsInput{TargetGroupIdentifier: tg.Id})
+	assert.NoError(t, err)
+	assert.Equal(t, vpclattice.TargetStatusUnhealthy, aws.StringValue(targets[0].Status))
+	_, err = f.DeleteTargetGroupWithContext(ctx, &vpclattice.DeleteTargetGroupInput{TargetGroupIdentifier: tg.Id})
+	assert.NoError(t, err)
+}
+
+func TestFakeLattice_Tagging(t *testing.T) {
+	ctx := context.TODO()
+	f := NewFakeLattice("", "")
+	for _, vpc := range []string{"vpc-1", "vpc-2"} {
+		_, err := f.CreateTargetGroupWithContext(ctx, &vpclattice.CreateTargetGroupInput{
+			Name:   aws.String("tg-" + vpc),
+			Type:   aws.String(vpclattice.TargetGroupTypeIp),
+			Config: &vpclattice.TargetGroupConfig{VpcIdentifier: aws.String(vpc), Port: aws.Int64(80)},
+			Tags:   Tags{"cluster": aws.String("c1")},
+		})
+		assert.NoError(t, err)
+	}
+
+	arns, err := f.Tagging("vpc-1").FindResourcesByTags(ctx, ResourceTypeTargetGroup, Tags{"cluster": aws.String("c1")})
+	assert.NoError(t, err)
+	assert.Len(t, arns, 1)
+
+	_, err = f.TagResourceWithContext(ctx, &vpclattice.TagResourceInput{ResourceArn: aws.String(arns[0]), Tags: Tags{"cluster": aws.String("c2")}})
+	assert.NoError(t, err)
+	arns, err = f.Tagging("vpc-1").FindResourcesByTags(ctx, ResourceTypeTargetGroup, Tags{"cluster": aws.String("c1")})
+	assert.NoError(t, err)
+	assert.Empty(t, arns)
+}
+
+func TestFakeLattice_ResourcePolicies(t *testing.T) {
+	ctx := context.TODO()
+	f := NewFakeLattice("", "")
+	svc, _ := createFakeService(t, f, "svc")
+
+	_, err := f.GetResourcePolicyWithContext(ctx, &vpclattice.GetResourcePolicyInput{ResourceArn: svc.Arn})
+	assert.True(t, IsNotFoundError(err))
+	_, err = f.PutResourcePolicyWithContext(ctx, &vpclattice.PutResourcePolicyInput{
+		ResourceArn: aws.String("arn:aws:vpc-lattice:us-west-2:123456789012:service/svc-missing"),
+		Policy:      aws.String("{}"),
+	})
+	assert.True(t, IsNotFoundError(err))
+
+	_, err = f.PutResourcePolicyWithContext(ctx, &vpclattice.PutResourcePolicyInput{ResourceArn: svc.Arn, Policy: aws.String("{}")})
+	assert.NoError(t, err)
+	policy, err := f.GetResourcePolicyWithContext(ctx, &vpclattice.GetResourcePolicyInput{ResourceArn: svc.Arn})
+	assert.NoError(t, err)
+	assert.Equal(t, "{}", aws.StringValue(policy.Policy))
+
+	_, err = f.DeleteResourcePolicyWithContext(ctx, &vpclattice.DeleteResourcePolicyInput{ResourceArn: svc.Arn})
+	assert.NoError(t, err)
+	_, err = f.GetResourcePolicyWithContext(ctx, &vpclattice.GetResourcePolicyInput{ResourceArn: svc.Arn})
+	assert.True(t, IsNotFoundError(err))
+}
+
+func TestFakeLattice_RequestsNotImplemented(t *testing.T) {
+	f := NewFakeLattice("", "")
+	req, _ := f.GetServiceRequest(&vpclattice.GetServiceInput{ServiceIdentifier: aws.String("svc-id")})
+	err := req.Send()
+	assert.Error(t, err)
+	assert.Equal(t, FakeNotImplementedErrorCode, err.(awserr.Error).Code())
+}
@@ -0,0 +1,284 @@
+// Package throttle rate limits AWS API calls on the client side, per API family, and backs off a
+// whole family when AWS throttles one of its calls. This keeps the controller within the account API
+// quotas it shares with other tooling, instead of retrying throttled calls until the quota is exhausted.
+package throttle
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	taggingapi "github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
+	"github.com/aws/aws-sdk-go/service/vpclattice"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/time/rate"
+
+	"github.com/aws/aws-application-networking-k8s/pkg/config"
+	"github.com/aws/aws-application-networking-k8s/pkg/utils/retry"
+)
+
+// API families, each with its own token bucket
+const (
+	FamilyRead    = "read"    // Lattice Get and List operations
+	FamilyWrite   = "write"   // Lattice Create, Update, Delete, Put and tagging operations
+	FamilyTargets = "targets" // Lattice RegisterTargets, DeregisterTargets and ListTargets
+	FamilyTagging = "tagging" // Resource Groups Tagging API
+)
+
+var DefaultRateLimits = map[string]config.APIRateLimit{
+	FamilyRead:    {QPS: 20, Burst: 40},
+	FamilyWrite:   {QPS: 10, Burst: 20},
+	FamilyTargets: {QPS: 10, Burst: 20},
+	FamilyTagging: {QPS: 5, Burst: 10},
+}
+
+const (
+	sdkHandlerWait    = "throttle.wait"
+	sdkHandlerObserve = "throttle.observe"
+
+	// once throttled, a family is paused for an exponentially growing delay, unless AWS asks for a longer one
+	minThrottleDelay = 500 * time.Millisecond
+	maxThrottleDelay = 20 * time.Second
+
+	// the rate of a throttled family is halved, down to this fraction of its configured rate,
+	// and recovers by a tenth of the configured rate on every successful call
+	minRateFraction      = 1.0 / 16
+	rateRecoveryFraction = 1.0 / 10
+)
+
+const (
+	metricSubsystemAWS         = "aws"
+	metricAPIThrottlesTotal    = "api_throttles_total"
+	metricRateLimitWaitSeconds = "api_rate_limit_wait_seconds"
+	metricRateLimitQPS         = "api_rate_limit_qps"
+
+	labelAccount   = "account"
+	labelFamily    = "family"
+	labelOperation = "operation"
+)
+
+// family is the token bucket of an API family, together with its adaptive backoff state
+type family struct {
+	name       string
+	limiter    *rate.Limiter
+	maxQPS     float64
+	backoff    *retry.SimpleBackoff
+	lock       sync.Mutex
+	pauseUntil time.Time
+}
+
+func (f *family) wait(ctx context.Context) error {
+	f.lock.Lock()
+	pause := time.Until(f.pauseUntil)
+	f.lock.Unlock()
+	if pause > 0 {
+		timer := time.NewTimer(pause)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	return f.limiter.Wait(ctx)
+}
+
+func (f *family) throttled(retryAfter time.Duration) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	delay := max(f.backoff.Duration(), retryAfter)
+	if until := time.Now().Add(delay); until.After(f.pauseUntil) {
+		f.pauseUntil = until
+	}
+	f.limiter.SetLimit(max(f.limiter.Limit()/2, rate.Limit(f.maxQPS*minRateFraction)))
+}
+
+func (f *family) succeeded() {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.backoff.Reset()
+	if limit := f.limiter.Limit(); limit < rate.Limit(f.maxQPS) {
+		f.limiter.SetLimit(min(limit+rate.Limit(f.maxQPS*rateRecoveryFraction), rate.Limit(f.maxQPS)))
+	}
+}
+
+// Limiter holds the token buckets of all API families of an account
+type Limiter struct {
+	account  string
+	limits   map[string]config.APIRateLimit
+	families map[string]*family
+
+	throttlesTotal   *prometheus.CounterVec
+	waitSeconds      *prometheus.HistogramVec
+	currentRateLimit *prometheus.GaugeVec
+}
+
+// NewLimiter creates a limiter of the account with the default rate limits, overridden by the given ones.
+// Metrics are registered when the registerer is not nil.
+func NewLimiter(account string, overrides map[string]config.APIRateLimit, registerer prometheus.Registerer) (*Limiter, error) {
+	l := &Limiter{
+		account:  account,
+		limits:   map[string]config.APIRateLimit{},
+		families: map[string]*family{},
+		throttlesTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Subsystem: metricSubsystemAWS,
+			Name:      metricAPIThrottlesTotal,
+			Help:      "Total number of AWS API calls throttled by AWS, per API family",
+		}, []string{labelFamily, labelOperation}),
+		waitSeconds: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Subsystem: metricSubsystemAWS,
+			Name:      metricRateLimitWaitSeconds,
+			Help:      "Time AWS API calls waited for the client side rate limiter, per API family",
+			Buckets:   []float64{0.001, 0.01, 0.05, 0.1, 0.5, 1, 2, 5, 10, 30},
+		}, []string{labelFamily}),
+		currentRateLimit: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Subsystem: metricSubsystemAWS,
+			Name:      metricRateLimitQPS,
+			Help:      "Current client side rate limit in calls per second, per account and API family, lowered while AWS throttles calls",
+		}, []string{labelAccount, labelFamily}),
+	}
+
+	for name, limit := range overrides {
+		if _, ok := DefaultRateLimits[name]; !ok {
+			return nil, fmt.Errorf("unknown API family %q, expected one of %s, %s, %s, %s",
+				name, FamilyRead, FamilyWrite, FamilyTargets, FamilyTagging)
+		}
+		l.limits[name] = limit
+	}
+	for name, limit := range DefaultRateLimits {
+		if _, ok := l.limits[name]; !ok {
+			l.limits[name] = limit
+		}
+	}
+	for name, limit := range l.limits {
+		l.families[name] = newFamily(name, limit)
+	}
+	l.reportRateLimits()
+
+	if registerer != nil {
+		for _, c := range []prometheus.Collector{l.throttlesTotal, l.waitSeconds, l.currentRateLimit} {
+			if err := registerer.Register(c); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return l, nil
+}
+
+func newFamily(name string, limit config.APIRateLimit) *family {
+	return &family{
+		name:    name,
+		limiter: rate.NewLimiter(rate.Limit(limit.QPS), limit.Burst),
+		maxQPS:  limit.QPS,
+		backoff: retry.NewSimpleBackoff(minThrottleDelay, maxThrottleDelay, 0.2, 2),
+	}
+}
+
+// ForAccount returns a limiter with the same rate limits and its own token buckets, for the calls made
+// to another account, which has its own API quotas. Its waits and throttles are counted in the metrics
+// of this limiter, its current rate limits are reported with the label of its account.
+func (l *Limiter) ForAccount(account string) *Limiter {
+	a := &Limiter{
+		account:          account,
+		limits:           l.limits,
+		families:         map[string]*family{},
+		throttlesTotal:   l.throttlesTotal,
+		waitSeconds:      l.waitSeconds,
+		currentRateLimit: l.currentRateLimit,
+	}
+	for name, limit := range l.limits {
+		a.families[name] = newFamily(name, limit)
+	}
+	a.reportRateLimits()
+	return a
+}
+
+func (l *Limiter) reportRateLimits() {
+	for name, f := range l.families {
+		l.currentRateLimit.WithLabelValues(l.account, name).Set(float64(f.limiter.Limit()))
+	}
+}
+
+// InjectHandlers rate limits every attempt of the requests sent with the given handlers,
+// replacing the limiter of handlers copied from another session.
+func (l *Limiter) InjectHandlers(handlers *request.Handlers) {
+	handlers.Sign.RemoveByName(sdkHandlerWait)
+	handlers.CompleteAttempt.RemoveByName(sdkHandlerObserve)
+	// waiting before signing, so that the signature does not age while waiting
+	handlers.Sign.PushFrontNamed(request.NamedHandler{
+		Name: sdkHandlerWait,
+		Fn:   l.wait,
+	})
+	handlers.CompleteAttempt.PushBackNamed(request.NamedHandler{
+		Name: sdkHandlerObserve,
+		Fn:   l.observe,
+	})
+}
+
+func (l *Limiter) familyFor(r *request.Request) *family {
+	if r.Operation == nil {
+		return nil
+	}
+	op := r.Operation.Name
+	switch r.ClientInfo.ServiceID {
+	case vpclattice.ServiceID:
+		switch {
+		case op == "RegisterTargets" || op == "DeregisterTargets" || op == "ListTargets":
+			return l.families[FamilyTargets]
+		case strings.HasPrefix(op, "Get") || strings.HasPrefix(op, "List"):
+			return l.families[FamilyRead]
+		default:
+			return l.families[FamilyWrite]
+		}
+	case taggingapi.ServiceID:
+		return l.families[FamilyTagging]
+	}
+	return nil
+}
+
+func (l *Limiter) wait(r *request.Request) {
+	f := l.familyFor(r)
+	if f == nil {
+		return
+	}
+	start := time.Now()
+	if err := f.wait(r.Context()); err != nil {
+		r.Error = awserr.New(request.CanceledErrorCode, "request context canceled while waiting for rate limiter", err)
+		return
+	}
+	l.waitSeconds.WithLabelValues(f.name).Observe(time.Since(start).Seconds())
+}
+
+func (l *Limiter) observe(r *request.Request) {
+	f := l.familyFor(r)
+	if f == nil {
+		return
+	}
+	if r.Error == nil {
+		f.succeeded()
+	} else if request.IsErrorThrottle(r.Error) {
+		l.throttlesTotal.WithLabelValues(f.name, r.Operation.Name).Inc()
+		f.throttled(retryAfter(r))
+	} else {
+		return
+	}
+	l.currentRateLimit.WithLabelValues(l.account, f.name).Set(float64(f.limiter.Limit()))
+}
+
+// retryAfter returns the delay requested by AWS, from the Lattice throttling error or the Retry-After header
+func retryAfter(r *request.Request) time.Duration {
+	if e, ok := r.Error.(*vpclattice.ThrottlingException); ok && e.RetryAfterSeconds != nil {
+		return time.Duration(*e.RetryAfterSeconds) * time.Second
+	}
+	if r.HTTPResponse != nil {
+		if seconds, err := strconv.Atoi(r.HTTPResponse.Header.Get("Retry-After")); err == nil && seconds > 0 {
+			return time.Duration(seconds) * time.Second
+		}
+	}
+	return 0
+}
@@ -0,0 +1,165 @@
+package throttle
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/client/metadata"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	taggingapi "github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
+	"github.com/aws/aws-sdk-go/service/vpclattice"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/time/rate"
+
+	"github.com/aws/aws-application-networking-k8s/pkg/config"
+)
+
+func TestNewLimiter(t *testing.T) {
+	l, err := NewLimiter("111122223333", map[string]config.APIRateLimit{FamilyWrite: {QPS: 1, Burst: 2}}, prometheus.NewRegistry())
+	assert.NoError(t, err)
+	assert.Equal(t, rate.Limit(1), l.families[FamilyWrite].limiter.Limit())
+	assert.Equal(t, 2, l.families[FamilyWrite].limiter.Burst())
+	assert.Equal(t, rate.Limit(DefaultRateLimits[FamilyRead].QPS), l.families[FamilyRead].limiter.Limit())
+
+	_, err = NewLimiter("111122223333", map[string]config.APIRateLimit{"unknown": {QPS: 1, Burst: 1}}, nil)
+	assert.Error(t, err)
+}
+
+func TestLimiter_familyFor(t *testing.T) {
+	l, err := NewLimiter("111122223333", nil, nil)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		serviceID string
+		operation string
+		family    string
+	}{
+		{vpclattice.ServiceID, "GetService", FamilyRead},
+		{vpclattice.ServiceID, "ListTargetGroups", FamilyRead},
+		{vpclattice.ServiceID, "ListTagsForResource", FamilyRead},
+		{vpclattice.ServiceID, "CreateRule", FamilyWrite},
+		{vpclattice.ServiceID, "BatchUpdateRule", FamilyWrite},
+		{vpclattice.ServiceID, "TagResource", FamilyWrite},
+		{vpclattice.ServiceID, "RegisterTargets", FamilyTargets},
+		{vpclattice.ServiceID, "ListTargets", FamilyTargets},
+		{taggingapi.ServiceID, "GetResources", FamilyTagging},
+		{"EC2", "DescribeTags", ""},
+	}
+	for _, tt := range tests {
+		r := &request.Request{Operation: &request.Operation{Name: tt.operation}}
+		r.ClientInfo.ServiceID = tt.serviceID
+		f := l.familyFor(r)
+		if tt.family == "" {
+			assert.Nil(t, f, tt.operation)
+		} else {
+			assert.Equal(t, tt.family, f.name, tt.operation)
+		}
+	}
+}
+
+func TestLimiter_BacksOffOnThrottling(t *testing.T) {
+	var calls atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("X-Amzn-Errortype", vpclattice.ErrCodeThrottlingException)
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"message":"Rate exceeded"}`))
+			return
+		}
+		w.Write([]byte(`{"id":"svc-0123456789abcdef0"}`))
+	}))
+	defer ts.Close()
+
+	l, err := NewLimiter("111122223333", map[string]config.APIRateLimit{FamilyRead: {QPS: 100, Burst: 100}}, prometheus.NewRegistry())
+	assert.NoError(t, err)
+	sess, err := session.NewSession(&aws.Config{
+		Region:      aws.String("us-west-2"),
+		Endpoint:    aws.String(ts.URL),
+		Credentials: credentials.NewStaticCredentials("id", "secret", ""),
+		MaxRetries:  aws.Int(0),
+	})
+	assert.NoError(t, err)
+	l.InjectHandlers(&sess.Handlers)
+	client := vpclattice.New(sess)
+
+	_, err = client.GetService(&vpclattice.GetServiceInput{ServiceIdentifier: aws.String("svc-0123456789abcdef0")})
+	throttlingErr, ok := err.(*vpclattice.ThrottlingException)
+	assert.True(t, ok)
+	assert.Equal(t, int64(1), aws.Int64Value(throttlingErr.RetryAfterSeconds))
+	assert.Equal(t, 1.0, testutil.ToFloat64(l.throttlesTotal.WithLabelValues(FamilyRead, "GetService")))
+	assert.Equal(t, 50.0, testutil.ToFloat64(l.currentRateLimit.WithLabelValues("111122223333", FamilyRead)))
+
+	// the whole family waits for the delay requested by AWS
+	start := time.Now()
+	out, err := client.GetService(&vpclattice.GetServiceInput{ServiceIdentifier: aws.String("svc-0123456789abcdef0")})
+	assert.NoError(t, err)
+	assert.Equal(t, "svc-0123456789abcdef0", aws.StringValue(out.Id))
+	assert.GreaterOrEqual(t, time.Since(start), 900*time.Millisecond)
+	assert.Equal(t, 60.0, testutil.ToFloat64(l.currentRateLimit.WithLabelValues("111122223333", FamilyRead)))
+
+	// other families are not affected
+	assert.True(t, l.families[FamilyWrite].pauseUntil.IsZero())
+}
+
+func TestLimiter_ForAccount(t *testing.T) {
+	l, err := NewLimiter("111122223333", map[string]config.APIRateLimit{FamilyWrite: {QPS: 1, Burst: 2}}, prometheus.NewRegistry())
+	assert.NoError(t, err)
+	a := l.ForAccount("222233334444")
+	assert.Equal(t, rate.Limit(1), a.families[FamilyWrite].limiter.Limit())
+	assert.Equal(t, rate.Limit(DefaultRateLimits[FamilyRead].QPS), a.families[FamilyRead].limiter.Limit())
+	assert.NotSame(t, l.families[FamilyWrite], a.families[FamilyWrite])
+
+	// throttles of the other account do not slow down this one
+	a.families[FamilyWrite].throttled(0)
+	assert.True(t, l.families[FamilyWrite].pauseUntil.IsZero())
+
+	// the rate limits of both accounts are reported by the registered metric
+	a.observe(&request.Request{
+		Operation:  &request.Operation{Name: "CreateService"},
+		ClientInfo: metadata.ClientInfo{ServiceID: vpclattice.ServiceID},
+		Error:      awserr.New(vpclattice.ErrCodeThrottlingException, "Rate exceeded", nil),
+	})
+	assert.Same(t, l.currentRateLimit, a.currentRateLimit)
+	assert.Equal(t, 1.0, testutil.ToFloat64(l.currentRateLimit.WithLabelValues("111122223333", FamilyWrite)))
+	assert.Equal(t, 0.25, testutil.ToFloat64(l.currentRateLimit.WithLabelValues("222233334444", FamilyWrite)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(l.throttlesTotal.WithLabelValues(FamilyWrite, "CreateService")))
+
+	// the limiter of a copied session is replaced
+	sess, err := session.NewSession()
+	assert.NoError(t, err)
+	l.InjectHandlers(&sess.Handlers)
+	copied := sess.Copy()
+	a.InjectHandlers(&copied.Handlers)
+	assert.Equal(t, sess.Handlers.Sign.Len(), copied.Handlers.Sign.Len())
+	assert.Equal(t, sess.Handlers.CompleteAttempt.Len(), copied.Handlers.CompleteAttempt.Len())
+}
+
+func TestFamily_Adapts(t *testing.T) {
+	f := newFamily(FamilyWrite, config.APIRateLimit{QPS: 16, Burst: 16})
+
+	for i := 0; i < 10; i++ {
+		f.throttled(0)
+	}
+	assert.Equal(t, rate.Limit(1), f.limiter.Limit())
+	assert.True(t, f.pauseUntil.After(time.Now()))
+
+	for i := 0; i < 20; i++ {
+		f.succeeded()
+	}
+	assert.Equal(t, rate.Limit(16), f.limiter.Limit())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Error(t, f.wait(ctx))
+}
@@ -4,12 +4,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"os"
+	"slices"
 	"strconv"
+	"time"
 
 	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
 	"github.com/aws/aws-sdk-go/aws/ec2metadata"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/ec2"
@@ -34,6 +38,16 @@ const (
 	DEV_MODE                        = "DEV_MODE"
 	WEBHOOK_ENABLED                 = "WEBHOOK_ENABLED"
 	ROUTE_MAX_CONCURRENT_RECONCILES = "ROUTE_MAX_CONCURRENT_RECONCILES"
+	ROUTE_SHARDS                    = "ROUTE_SHARDS"
+	LATTICE_API_RATE_LIMITS         = "LATTICE_API_RATE_LIMITS"
+	LATTICE_CACHE_RESYNC_PERIOD     = "LATTICE_CACHE_RESYNC_PERIOD"
+
+	SERVICE_IMPORT_DISCOVERY_NAMESPACES = "SERVICE_IMPORT_DISCOVERY_NAMESPACES"
+	SERVICE_IMPORT_DISCOVERY_INTERVAL   = "SERVICE_IMPORT_DISCOVERY_INTERVAL"
+	CROSS_ACCOUNT_ROLES                 = "CROSS_ACCOUNT_ROLES"
+
+	CONTROLLER_MAX_CONCURRENT_RECONCILES = "CONTROLLER_MAX_CONCURRENT_RECONCILES"
+	CONTROLLER_RATE_LIMITS               = "CONTROLLER_RATE_LIMITS"
 )
 
 var VpcID = ""
@@ -46,7 +60,38 @@ var WebhookEnabled = ""
 
 var DisableTaggingServiceAPI = false
 var ServiceNetworkOverrideMode = false
-var RouteMaxConcurrentReconciles = 1
+
+// RouteShards is the number of shards routes are split into across controller replicas, zero disables sharding
+var RouteShards = 0
+
+// LatticeCacheResyncPeriod is the period of full Lattice resource cache refreshes, zero disables the cache.
+// The cache is disabled by default, every refresh lists all Lattice services and target groups of the account.
+var LatticeCacheResyncPeriod time.Duration
+
+// ServiceImportDiscoveryNamespaces are the namespaces where ServiceImports are created for the discovered
+// ServiceExports, discovery is disabled when empty
+var ServiceImportDiscoveryNamespaces []string
+
+// ServiceImportDiscoveryInterval is the period of the discovery of ServiceExports across clusters
+var ServiceImportDiscoveryInterval = time.Minute
+
+// CrossAccountRoles are the IAM roles assumed to look up the target groups exported from other accounts, by account ID
+var CrossAccountRoles = map[string]string{}
+
+// APIRateLimit is a client side token bucket for a family of AWS API operations
+type APIRateLimit struct {
+	QPS   float64
+	Burst int
+}
+
+// LatticeAPIRateLimits overrides the default rate limits per API family, see LATTICE_API_RATE_LIMITS
+var LatticeAPIRateLimits = map[string]APIRateLimit{}
+
+// ControllerNames are the names of the controllers in CONTROLLER_MAX_CONCURRENT_RECONCILES and CONTROLLER_RATE_LIMITS
+var ControllerNames = []string{
+	"accesslogpolicy", "gateway", "gatewayclass", "iamauthpolicy", "pod", "route", "service",
+	"serviceexport", "serviceimport", "serviceimportpolicy", "targetgrouppolicy", "targets", "vpcassociationpolicy",
+}
 
 func ConfigInit() error {
 	sess, _ := session.NewSession()
@@ -111,18 +156,127 @@ func configInit(sess *session.Session, metadata EC2Metadata) error {
 		DisableTaggingServiceAPI = true
 	}
 
-	routeMaxConcurrentReconciles := os.Getenv(ROUTE_MAX_CONCURRENT_RECONCILES)
-	if routeMaxConcurrentReconciles != "" {
-		routeMaxConcurrentReconcilesInt, err := strconv.Atoi(routeMaxConcurrentReconciles)
-		if err != nil {
-			return fmt.Errorf("invalid value for ROUTE_MAX_CONCURRENT_RECONCILES: %s", err)
+	routeShards := os.Getenv(ROUTE_SHARDS)
+	if routeShards != "" {
+		routeShardsInt, err := strconv.Atoi(routeShards)
+		if err != nil || routeShardsInt < 0 {
+			return fmt.Errorf("invalid value for ROUTE_SHARDS: %s", routeShards)
 		}
-		RouteMaxConcurrentReconciles = routeMaxConcurrentReconcilesInt
+		RouteShards = routeShardsInt
+	}
+
+	if LatticeAPIRateLimits, err = parseAPIRateLimits(os.Getenv(LATTICE_API_RATE_LIMITS)); err != nil {
+		return fmt.Errorf("invalid value for LATTICE_API_RATE_LIMITS: %s", err)
+	}
+
+	for _, key := range LiveSettings {
+		if err := ApplyLive(key, os.Getenv(key)); err != nil {
+			return err
+		}
+	}
+
+	if ServiceImportDiscoveryNamespaces, err = parseNamespaces(os.Getenv(SERVICE_IMPORT_DISCOVERY_NAMESPACES)); err != nil {
+		return fmt.Errorf("invalid value for SERVICE_IMPORT_DISCOVERY_NAMESPACES: %s", err)
+	}
+
+	serviceImportDiscoveryInterval := os.Getenv(SERVICE_IMPORT_DISCOVERY_INTERVAL)
+	if serviceImportDiscoveryInterval != "" {
+		interval, err := time.ParseDuration(serviceImportDiscoveryInterval)
+		if err != nil || interval <= 0 {
+			return fmt.Errorf("invalid value for SERVICE_IMPORT_DISCOVERY_INTERVAL: %s", serviceImportDiscoveryInterval)
+		}
+		ServiceImportDiscoveryInterval = interval
+	}
+
+	if CrossAccountRoles, err = parseCrossAccountRoles(os.Getenv(CROSS_ACCOUNT_ROLES)); err != nil {
+		return fmt.Errorf("invalid value for CROSS_ACCOUNT_ROLES: %s", err)
+	}
+
+	latticeCacheResyncPeriod := os.Getenv(LATTICE_CACHE_RESYNC_PERIOD)
+	if latticeCacheResyncPeriod != "" {
+		period, err := time.ParseDuration(latticeCacheResyncPeriod)
+		if err != nil || period < 0 {
+			return fmt.Errorf("invalid value for LATTICE_CACHE_RESYNC_PERIOD: %s", latticeCacheResyncPeriod)
+		}
+		LatticeCacheResyncPeriod = period
 	}
 
 	return nil
 }
 
+// parseAPIRateLimits parses comma separated family=qps[:burst] entries, e.g. "read=20:40,write=5".
+// The burst defaults to the rounded up qps.
+func parseAPIRateLimits(value string) (map[string]APIRateLimit, error) {
+	limits := map[string]APIRateLimit{}
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		family, limit, found := strings.Cut(entry, "=")
+		if !found || family == "" {
+			return nil, fmt.Errorf("expected family=qps[:burst], got %q", entry)
+		}
+		qpsStr, burstStr, hasBurst := strings.Cut(limit, ":")
+		qps, err := strconv.ParseFloat(qpsStr, 64)
+		if err != nil || qps <= 0 {
+			return nil, fmt.Errorf("invalid qps in %q", entry)
+		}
+		burst := int(math.Ceil(qps))
+		if hasBurst {
+			if burst, err = strconv.Atoi(burstStr); err != nil || burst < 1 {
+				return nil, fmt.Errorf("invalid burst in %q", entry)
+			}
+		}
+		limits[strings.TrimSpace(family)] = APIRateLimit{QPS: qps, Burst: burst}
+	}
+	return limits, nil
+}
+
+// parseControllerConcurrency parses a comma separated list of controller=concurrency
+func parseControllerConcurrency(value string) (map[string]int, error) {
+	concurrency := map[string]int{}
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, nStr, found := strings.Cut(entry, "=")
+		name = strings.TrimSpace(name)
+		if !found || !slices.Contains(ControllerNames, name) {
+			return nil, fmt.Errorf("expected controller=concurrency with a known controller, got %q", entry)
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(nStr))
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("invalid concurrency in %q", entry)
+		}
+		concurrency[name] = n
+	}
+	return concurrency, nil
+}
+
+// parseCrossAccountRoles parses a comma separated list of accountId=roleArn
+func parseCrossAccountRoles(value string) (map[string]string, error) {
+	roles := map[string]string{}
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		accountId, roleArn, found := strings.Cut(entry, "=")
+		accountId = strings.TrimSpace(accountId)
+		roleArn = strings.TrimSpace(roleArn)
+		if !found || accountId == "" {
+			return nil, fmt.Errorf("expected accountId=roleArn, got %q", entry)
+		}
+		if parsed, err := arn.Parse(roleArn); err != nil || parsed.Service != "iam" {
+			return nil, fmt.Errorf("invalid role ARN in %q", entry)
+		}
+		roles[accountId] = roleArn
+	}
+	return roles, nil
+}
+
 // try to find cluster name, search in env then in ec2 instance tags
 func getClusterName(sess *session.Session, region string) (string, error) {
 	meta := ec2metadata.New(sess)
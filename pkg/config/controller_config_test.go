@@ -4,8 +4,10 @@ import (
 	"errors"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
 )
 
 type ec2MetadataUnavaialble struct {
@@ -68,6 +70,11 @@ func Test_config_init_with_all_env_var(t *testing.T) {
 	os.Setenv(AWS_ACCOUNT_ID, testAwsAccountId)
 	os.Setenv(CLUSTER_NAME, testClusterName)
 	os.Setenv(ROUTE_MAX_CONCURRENT_RECONCILES, testMaxRouteReconciles)
+	os.Setenv(LATTICE_API_RATE_LIMITS, "read=20:40, write=2.5")
+	os.Setenv(LATTICE_CACHE_RESYNC_PERIOD, "90s")
+	os.Setenv(ROUTE_SHARDS, "8")
+	os.Setenv(CONTROLLER_MAX_CONCURRENT_RECONCILES, "serviceexport=8, gateway=2")
+	os.Setenv(CONTROLLER_RATE_LIMITS, "serviceexport=50:500")
 	err := configInit(nil, ec2MetadataUnavailable())
 	assert.Nil(t, err)
 	assert.Equal(t, testRegion, Region)
@@ -75,7 +82,26 @@ func Test_config_init_with_all_env_var(t *testing.T) {
 	assert.Equal(t, testAwsAccountId, AccountID)
 	assert.Equal(t, testClusterLocalGateway, DefaultServiceNetwork)
 	assert.Equal(t, testClusterName, ClusterName)
-	assert.Equal(t, testMaxRouteReconcilesInt, RouteMaxConcurrentReconciles)
+	assert.Equal(t, map[string]APIRateLimit{
+		"read":  {QPS: 20, Burst: 40},
+		"write": {QPS: 2.5, Burst: 3},
+	}, LatticeAPIRateLimits)
+	assert.Equal(t, 90*time.Second, LatticeCacheResyncPeriod)
+	assert.Equal(t, 8, RouteShards)
+	assert.Equal(t, 8, MaxConcurrentReconciles("serviceexport"))
+	assert.Equal(t, 2, MaxConcurrentReconciles("gateway"))
+	assert.Equal(t, testMaxRouteReconcilesInt, MaxConcurrentReconciles("route"))
+	assert.Equal(t, 1, MaxConcurrentReconciles("accesslogpolicy"))
+	limit, ok := ControllerRateLimit("serviceexport")
+	assert.True(t, ok)
+	assert.Equal(t, APIRateLimit{QPS: 50, Burst: 500}, limit)
+	_, ok = ControllerRateLimit("gateway")
+	assert.False(t, ok)
+	os.Unsetenv(CONTROLLER_MAX_CONCURRENT_RECONCILES)
+	os.Unsetenv(CONTROLLER_RATE_LIMITS)
+	os.Unsetenv(LATTICE_API_RATE_LIMITS)
+	os.Unsetenv(LATTICE_CACHE_RESYNC_PERIOD)
+	os.Unsetenv(ROUTE_SHARDS)
 }
 
 func Test_bad_reconcile_value(t *testing.T) {
@@ -86,3 +112,110 @@ func Test_bad_reconcile_value(t *testing.T) {
 	err := configInit(nil, ec2MetadataUnavailable())
 	assert.NotNil(t, err)
 }
+
+func Test_bad_rate_limits_value(t *testing.T) {
+	os.Unsetenv(ROUTE_MAX_CONCURRENT_RECONCILES)
+	for _, value := range []string{"read", "read=", "read=0", "read=1:0", "=1", "read=fast"} {
+		os.Setenv(LATTICE_API_RATE_LIMITS, value)
+		err := configInit(nil, ec2MetadataUnavailable())
+		assert.NotNil(t, err, value)
+	}
+	os.Unsetenv(LATTICE_API_RATE_LIMITS)
+}
+
+func Test_bad_cache_resync_period_value(t *testing.T) {
+	os.Unsetenv(ROUTE_MAX_CONCURRENT_RECONCILES)
+	for _, value := range []string{"5", "-1m", "often"} {
+		os.Setenv(LATTICE_CACHE_RESYNC_PERIOD, value)
+		err := configInit(nil, ec2MetadataUnavailable())
+		assert.NotNil(t, err, value)
+	}
+	os.Unsetenv(LATTICE_CACHE_RESYNC_PERIOD)
+}
+
+func Test_bad_route_shards_value(t *testing.T) {
+	os.Unsetenv(ROUTE_MAX_CONCURRENT_RECONCILES)
+	for _, value := range []string{"-1", "many"} {
+		os.Setenv(ROUTE_SHARDS, value)
+		err := configInit(nil, ec2MetadataUnavailable())
+		assert.NotNil(t, err, value)
+	}
+	os.Unsetenv(ROUTE_SHARDS)
+}
+
+func Test_bad_controller_values(t *testing.T) {
+	os.Unsetenv(ROUTE_MAX_CONCURRENT_RECONCILES)
+	for _, value := range []string{"serviceexport", "serviceexport=0", "serviceexport=many", "exports=2"} {
+		os.Setenv(CONTROLLER_MAX_CONCURRENT_RECONCILES, value)
+		err := configInit(nil, ec2MetadataUnavailable())
+		assert.NotNil(t, err, value)
+	}
+	os.Unsetenv(CONTROLLER_MAX_CONCURRENT_RECONCILES)
+
+	for _, value := range []string{"serviceexport=0", "exports=10:100"} {
+		os.Setenv(CONTROLLER_RATE_LIMITS, value)
+		err := configInit(nil, ec2MetadataUnavailable())
+		assert.NotNil(t, err, value)
+	}
+	os.Unsetenv(CONTROLLER_RATE_LIMITS)
+}
+
+func Test_cross_account_roles_value(t *testing.T) {
+	os.Unsetenv(ROUTE_MAX_CONCURRENT_RECONCILES)
+	os.Setenv(CROSS_ACCOUNT_ROLES, "111122223333=arn:aws:iam::111122223333:role/lattice-lookup, 444455556666=arn:aws:iam::444455556666:role/other")
+	assert.Nil(t, configInit(nil, ec2MetadataUnavailable()))
+	assert.Equal(t, map[string]string{
+		"111122223333": "arn:aws:iam::111122223333:role/lattice-lookup",
+		"444455556666": "arn:aws:iam::444455556666:role/other",
+	}, CrossAccountRoles)
+
+	for _, value := range []string{"111122223333", "=arn:aws:iam::111122223333:role/x", "111122223333=role/x",
+		"111122223333=arn:aws:s3:::bucket"} {
+		os.Setenv(CROSS_ACCOUNT_ROLES, value)
+		err := configInit(nil, ec2MetadataUnavailable())
+		assert.NotNil(t, err, value)
+	}
+	os.Unsetenv(CROSS_ACCOUNT_ROLES)
+	CrossAccountRoles = map[string]string{}
+}
+
+func Test_live_settings(t *testing.T) {
+	assert.Nil(t, ApplyLive(DEFAULT_TAGS, "team=networking, env = prod"))
+	assert.Equal(t, map[string]string{"team": "networking", "env": "prod"}, DefaultTags())
+	assert.Nil(t, ApplyLive(TARGET_GROUP_GC_INTERVAL, "2m"))
+	assert.Equal(t, 2*time.Minute, TargetGroupGcInterval())
+	assert.Nil(t, ApplyLive(LOG_LEVEL, "debug"))
+	assert.Equal(t, zapcore.DebugLevel, LogLevel())
+
+	for _, value := range []string{"team", "=networking", "aws:team=networking"} {
+		assert.NotNil(t, ApplyLive(DEFAULT_TAGS, value), value)
+	}
+	for _, value := range []string{"30", "0s", "-1m"} {
+		assert.NotNil(t, ApplyLive(TARGET_GROUP_GC_INTERVAL, value), value)
+	}
+	assert.NotNil(t, ApplyLive(DEFAULT_SERVICE_NETWORK, "my-network"))
+
+	assert.Nil(t, ApplyLive(DEFAULT_TAGS, ""))
+	assert.Empty(t, DefaultTags())
+	assert.Nil(t, ApplyLive(TARGET_GROUP_GC_INTERVAL, ""))
+	assert.Equal(t, defaultTargetGroupGcInterval, TargetGroupGcInterval())
+	assert.Nil(t, ApplyLive(LOG_LEVEL, ""))
+}
+
+func Test_override_environment(t *testing.T) {
+	os.Setenv(DEFAULT_SERVICE_NETWORK, "default")
+	os.Unsetenv(DEFAULT_TAGS)
+	env, unknown := OverrideEnvironment(map[string]string{
+		DEFAULT_SERVICE_NETWORK: "my-network",
+		DEFAULT_TAGS:            "team=networking",
+		"PATH":                  "/tmp",
+		"SERVICE_NETWORK":       "my-network",
+	})
+	assert.Equal(t, []string{"PATH", "SERVICE_NETWORK"}, unknown)
+	assert.Equal(t, "default", env[DEFAULT_SERVICE_NETWORK])
+	assert.NotContains(t, env, DEFAULT_TAGS)
+	assert.Equal(t, "my-network", os.Getenv(DEFAULT_SERVICE_NETWORK))
+	assert.Equal(t, "team=networking", os.Getenv(DEFAULT_TAGS))
+	assert.NotEqual(t, "/tmp", os.Getenv("PATH"))
+	os.Unsetenv(DEFAULT_TAGS)
+}
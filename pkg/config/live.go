@@ -0,0 +1,230 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	LOG_LEVEL                = "LOG_LEVEL"
+	DEFAULT_TAGS             = "DEFAULT_TAGS"
+	TARGET_GROUP_GC_INTERVAL = "TARGET_GROUP_GC_INTERVAL"
+
+	defaultTargetGroupGcInterval = 30 * time.Second
+)
+
+// LiveSettings are the settings of the config ConfigMap applied without restarting the controller
+var LiveSettings = []string{
+	LOG_LEVEL,
+	DEFAULT_TAGS,
+	TARGET_GROUP_GC_INTERVAL,
+	ROUTE_MAX_CONCURRENT_RECONCILES,
+	CONTROLLER_MAX_CONCURRENT_RECONCILES,
+	CONTROLLER_RATE_LIMITS,
+}
+
+// Settings are all the environment variables the config ConfigMap can set
+var Settings = append([]string{
+	REGION,
+	AWS_REGION,
+	CLUSTER_VPC_ID,
+	CLUSTER_NAME,
+	DEFAULT_SERVICE_NETWORK,
+	DISABLE_TAGGING_SERVICE_API,
+	ENABLE_SERVICE_NETWORK_OVERRIDE,
+	AWS_ACCOUNT_ID,
+	DEV_MODE,
+	WEBHOOK_ENABLED,
+	ROUTE_SHARDS,
+	LATTICE_API_RATE_LIMITS,
+	LATTICE_CACHE_RESYNC_PERIOD,
+	SERVICE_IMPORT_DISCOVERY_NAMESPACES,
+	SERVICE_IMPORT_DISCOVERY_INTERVAL,
+}, LiveSettings...)
+
+// live settings read concurrently with their updates
+var liveLock sync.RWMutex
+var logLevel = zapcore.InfoLevel
+var defaultTags = map[string]string{}
+var targetGroupGcInterval = defaultTargetGroupGcInterval
+var routeMaxConcurrentReconciles = 1
+var controllerMaxConcurrentReconciles = map[string]int{}
+var controllerRateLimits = map[string]APIRateLimit{}
+
+// LogLevel returns the level of the controller logs, see LOG_LEVEL
+func LogLevel() zapcore.Level {
+	liveLock.RLock()
+	defer liveLock.RUnlock()
+	return logLevel
+}
+
+// DefaultTags returns the tags added to every Lattice resource the controller creates, see DEFAULT_TAGS
+func DefaultTags() map[string]string {
+	liveLock.RLock()
+	defer liveLock.RUnlock()
+	return maps.Clone(defaultTags)
+}
+
+// TargetGroupGcInterval returns the period of the deletion of unused target groups, see TARGET_GROUP_GC_INTERVAL
+func TargetGroupGcInterval() time.Duration {
+	liveLock.RLock()
+	defer liveLock.RUnlock()
+	return targetGroupGcInterval
+}
+
+// MaxConcurrentReconciles returns the number of concurrent reconciles of a controller, see
+// CONTROLLER_MAX_CONCURRENT_RECONCILES. ROUTE_MAX_CONCURRENT_RECONCILES still applies to the route controllers.
+func MaxConcurrentReconciles(controller string) int {
+	liveLock.RLock()
+	defer liveLock.RUnlock()
+	if n, ok := controllerMaxConcurrentReconciles[controller]; ok {
+		return n
+	}
+	if controller == "route" {
+		return routeMaxConcurrentReconciles
+	}
+	return 1
+}
+
+// ControllerRateLimit returns the workqueue rate limit of a controller, if overridden by CONTROLLER_RATE_LIMITS
+func ControllerRateLimit(controller string) (APIRateLimit, bool) {
+	liveLock.RLock()
+	defer liveLock.RUnlock()
+	limit, ok := controllerRateLimits[controller]
+	return limit, ok
+}
+
+// OverrideEnvironment sets the environment variables from the data of the config ConfigMap, before
+// ConfigInit reads them. It returns the environment before the overrides, and the unknown keys.
+func OverrideEnvironment(data map[string]string) (map[string]string, []string) {
+	env := Environment()
+	var unknown []string
+	for key, value := range data {
+		if !slices.Contains(Settings, key) {
+			unknown = append(unknown, key)
+			continue
+		}
+		os.Setenv(key, value)
+	}
+	slices.Sort(unknown)
+	return env, unknown
+}
+
+// Environment returns the value of all the settings in the environment
+func Environment() map[string]string {
+	env := map[string]string{}
+	for _, key := range Settings {
+		if value, ok := os.LookupEnv(key); ok {
+			env[key] = value
+		}
+	}
+	return env
+}
+
+// ApplyLive parses and applies the new value of a live setting
+func ApplyLive(key, value string) error {
+	switch key {
+	case LOG_LEVEL:
+		level := ParseLogLevel(value)
+		liveLock.Lock()
+		logLevel = level
+		liveLock.Unlock()
+	case DEFAULT_TAGS:
+		tags, err := parseTags(value)
+		if err != nil {
+			return fmt.Errorf("invalid value for %s: %s", key, err)
+		}
+		liveLock.Lock()
+		defaultTags = tags
+		liveLock.Unlock()
+	case TARGET_GROUP_GC_INTERVAL:
+		interval := defaultTargetGroupGcInterval
+		if value != "" {
+			var err error
+			if interval, err = time.ParseDuration(value); err != nil || interval <= 0 {
+				return fmt.Errorf("invalid value for %s: %s", key, value)
+			}
+		}
+		liveLock.Lock()
+		targetGroupGcInterval = interval
+		liveLock.Unlock()
+	case ROUTE_MAX_CONCURRENT_RECONCILES:
+		n := 1
+		if value != "" {
+			var err error
+			if n, err = strconv.Atoi(value); err != nil || n < 1 {
+				return fmt.Errorf("invalid value for %s: %s", key, value)
+			}
+		}
+		liveLock.Lock()
+		routeMaxConcurrentReconciles = n
+		liveLock.Unlock()
+	case CONTROLLER_MAX_CONCURRENT_RECONCILES:
+		concurrency, err := parseControllerConcurrency(value)
+		if err != nil {
+			return fmt.Errorf("invalid value for %s: %s", key, err)
+		}
+		liveLock.Lock()
+		controllerMaxConcurrentReconciles = concurrency
+		liveLock.Unlock()
+	case CONTROLLER_RATE_LIMITS:
+		limits, err := parseAPIRateLimits(value)
+		if err != nil {
+			return fmt.Errorf("invalid value for %s: %s", key, err)
+		}
+		for name := range limits {
+			if !slices.Contains(ControllerNames, name) {
+				return fmt.Errorf("invalid value for %s: unknown controller %q", key, name)
+			}
+		}
+		liveLock.Lock()
+		controllerRateLimits = limits
+		liveLock.Unlock()
+	default:
+		return fmt.Errorf("%s cannot be changed without a restart", key)
+	}
+	return nil
+}
+
+// ParseLogLevel parses LOG_LEVEL, info by default
+func ParseLogLevel(level string) zapcore.Level {
+	switch level {
+	case "debug":
+		return zapcore.DebugLevel
+	case "error":
+		return zapcore.ErrorLevel
+	case "panic":
+		return zapcore.PanicLevel
+	default:
+		return zapcore.InfoLevel
+	}
+}
+
+// parseTags parses a comma separated list of key=value
+func parseTags(value string) (map[string]string, error) {
+	tags := map[string]string{}
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		key, tagValue, found := strings.Cut(entry, "=")
+		key = strings.TrimSpace(key)
+		if !found || key == "" {
+			return nil, fmt.Errorf("expected key=value, got %q", entry)
+		}
+		if strings.HasPrefix(strings.ToLower(key), "aws:") {
+			return nil, fmt.Errorf("tag keys cannot start with aws:, got %q", key)
+		}
+		tags[key] = strings.TrimSpace(tagValue)
+	}
+	return tags, nil
+}
@@ -0,0 +1,75 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// WatchNamespaces are the namespaces the controller watches, every namespace when empty
+var WatchNamespaces []string
+
+// WatchLabelSelector restricts the Gateway API and VPC Lattice resources the controller watches
+var WatchLabelSelector = labels.Everything()
+
+// ParseWatchScope parses a comma separated list of namespaces and a label selector,
+// both given on the command line
+func ParseWatchScope(namespaces, labelSelector string) error {
+	var err error
+	if WatchNamespaces, err = parseNamespaces(namespaces); err != nil {
+		return fmt.Errorf("invalid watch namespace: %w", err)
+	}
+
+	selector, err := labels.Parse(labelSelector)
+	if err != nil {
+		return fmt.Errorf("invalid watch label selector %q: %w", labelSelector, err)
+	}
+	WatchLabelSelector = selector
+	return nil
+}
+
+// WatchLabels returns labels matching the watch label selector, for the resources the controller creates and
+// watches itself. It fails when the selector cannot be satisfied by fixed labels, e.g. with `tier>1`.
+func WatchLabels() (labels.Set, error) {
+	set := labels.Set{}
+	requirements, _ := WatchLabelSelector.Requirements()
+	for _, req := range requirements {
+		switch req.Operator() {
+		case selection.Equals, selection.DoubleEquals, selection.In:
+			set[req.Key()] = req.Values().List()[0]
+		case selection.Exists:
+			set[req.Key()] = ""
+		}
+	}
+	if !WatchLabelSelector.Matches(set) {
+		return nil, fmt.Errorf("no labels match the watch label selector %q", WatchLabelSelector)
+	}
+	return set, nil
+}
+
+// parseNamespaces parses a comma separated list of namespaces
+func parseNamespaces(namespaces string) ([]string, error) {
+	var parsed []string
+	for _, ns := range strings.Split(namespaces, ",") {
+		ns = strings.TrimSpace(ns)
+		if ns == "" {
+			continue
+		}
+		if errs := validation.IsDNS1123Label(ns); len(errs) > 0 {
+			return nil, fmt.Errorf("%q: %s", ns, strings.Join(errs, ", "))
+		}
+		if !slices.Contains(parsed, ns) {
+			parsed = append(parsed, ns)
+		}
+	}
+	return parsed, nil
+}
+
+// IsNamespaceWatched returns whether the controller watches the resources of the namespace
+func IsNamespaceWatched(namespace string) bool {
+	return len(WatchNamespaces) == 0 || slices.Contains(WatchNamespaces, namespace)
+}
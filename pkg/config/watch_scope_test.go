@@ -0,0 +1,42 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+func Test_ParseWatchScope(t *testing.T) {
+	defer ParseWatchScope("", "")
+
+	assert.NoError(t, ParseWatchScope("", ""))
+	assert.Empty(t, WatchNamespaces)
+	assert.True(t, WatchLabelSelector.Empty())
+	assert.True(t, IsNamespaceWatched("any"))
+	watchLabels, err := WatchLabels()
+	assert.NoError(t, err)
+	assert.Empty(t, watchLabels)
+
+	assert.NoError(t, ParseWatchScope(" team-a, team-b,,team-a", "tenant=a,tier!=test"))
+	assert.Equal(t, []string{"team-a", "team-b"}, WatchNamespaces)
+	assert.True(t, IsNamespaceWatched("team-b"))
+	assert.False(t, IsNamespaceWatched("team-c"))
+	assert.True(t, WatchLabelSelector.Matches(labels.Set{"tenant": "a"}))
+	assert.False(t, WatchLabelSelector.Matches(labels.Set{"tenant": "a", "tier": "test"}))
+	watchLabels, err = WatchLabels()
+	assert.NoError(t, err)
+	assert.Equal(t, labels.Set{"tenant": "a"}, watchLabels)
+
+	assert.NoError(t, ParseWatchScope("", "tenant in (b,a),managed,!legacy"))
+	watchLabels, err = WatchLabels()
+	assert.NoError(t, err)
+	assert.Equal(t, labels.Set{"tenant": "a", "managed": ""}, watchLabels)
+
+	assert.NoError(t, ParseWatchScope("", "tier>1"))
+	_, err = WatchLabels()
+	assert.Error(t, err)
+
+	assert.Error(t, ParseWatchScope("Team_A", ""))
+	assert.Error(t, ParseWatchScope("", "tenant in a"))
+}
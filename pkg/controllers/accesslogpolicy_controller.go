@@ -21,7 +21,6 @@ import (
 	"fmt"
 	"reflect"
 
-	"golang.org/x/exp/slices"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -44,6 +43,7 @@ import (
 	"github.com/aws/aws-application-networking-k8s/pkg/deploy"
 	"github.com/aws/aws-application-networking-k8s/pkg/gateway"
 	"github.com/aws/aws-application-networking-k8s/pkg/k8s"
+	"github.com/aws/aws-application-networking-k8s/pkg/k8s/policyhelper"
 	"github.com/aws/aws-application-networking-k8s/pkg/model/core"
 	model "github.com/aws/aws-application-networking-k8s/pkg/model/lattice"
 	lattice_runtime "github.com/aws/aws-application-networking-k8s/pkg/runtime"
@@ -95,6 +95,8 @@ func RegisterAccessLogPolicyController(
 
 	builder := ctrl.NewControllerManagedBy(mgr).
 		For(&anv1alpha1.AccessLogPolicy{}, pkg_builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&anv1alpha1.AccessLogPolicy{}, lattice_runtime.EnqueuePriorityRequests(lattice_runtime.IsBeingDeleted)).
+		WithOptions(controllerOptions("accesslogpolicy")).
 		Watches(&gwv1.Gateway{}, handler.EnqueueRequestsFromMapFunc(r.findImpactedAccessLogPolicies), pkg_builder.WithPredicates(predicate.GenerationChangedPredicate{})).
 		Watches(&gwv1.HTTPRoute{}, handler.EnqueueRequestsFromMapFunc(r.findImpactedAccessLogPolicies), pkg_builder.WithPredicates(predicate.GenerationChangedPredicate{})).
 		Watches(&gwv1.GRPCRoute{}, handler.EnqueueRequestsFromMapFunc(r.findImpactedAccessLogPolicies), pkg_builder.WithPredicates(predicate.GenerationChangedPredicate{})).
@@ -103,10 +105,10 @@ func RegisterAccessLogPolicyController(
 	return builder.Complete(r)
 }
 
-func (r *accessLogPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *accessLogPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reconcileErr error) {
 	ctx = gwlog.StartReconcileTrace(ctx, r.log, "accesslogpolicy", req.Name, req.Namespace)
 	defer func() {
-		gwlog.EndReconcileTrace(ctx, r.log)
+		gwlog.EndReconcileTrace(ctx, r.log, reconcileErr)
 	}()
 
 	recErr := r.reconcile(ctx, req)
@@ -164,29 +166,13 @@ func (r *accessLogPolicyReconciler) reconcileUpsert(ctx context.Context, alp *an
 		return err
 	}
 
-	if alp.Spec.TargetRef.Group != gwv1.GroupName {
-		message := fmt.Sprintf("The targetRef's Group must be \"%s\" but was \"%s\"",
-			gwv1.GroupName, alp.Spec.TargetRef.Group)
-		r.eventRecorder.Event(alp, corev1.EventTypeWarning, k8s.FailedReconcileEvent, message)
-		return r.updateAccessLogPolicyStatus(ctx, alp, gwv1alpha2.PolicyReasonInvalid, message)
-	}
-
-	validKinds := []string{"Gateway", "HTTPRoute", "GRPCRoute"}
-	if !slices.Contains(validKinds, string(alp.Spec.TargetRef.Kind)) {
-		message := fmt.Sprintf("The targetRef's Kind must be \"Gateway\", \"HTTPRoute\", or \"GRPCRoute\""+
-			" but was \"%s\"", alp.Spec.TargetRef.Kind)
+	if err := policyhelper.ValidateAccessLogPolicyTargetRef(alp); err != nil {
+		message := err.Error()
 		r.eventRecorder.Event(alp, corev1.EventTypeWarning, k8s.FailedReconcileEvent, message)
 		return r.updateAccessLogPolicyStatus(ctx, alp, gwv1alpha2.PolicyReasonInvalid, message)
 	}
 
 	targetRefNamespace := k8s.NamespaceOrDefault(alp.Spec.TargetRef.Namespace)
-	if targetRefNamespace != alp.Namespace {
-		message := fmt.Sprintf("The targetRef's namespace, \"%s\", does not match the Access Log Policy's"+
-			" namespace, \"%s\"", string(*alp.Spec.TargetRef.Namespace), alp.Namespace)
-		r.eventRecorder.Event(alp, corev1.EventTypeWarning, k8s.FailedReconcileEvent, message)
-		return r.updateAccessLogPolicyStatus(ctx, alp, gwv1alpha2.PolicyReasonInvalid, message)
-	}
-
 	targetRefExists, err := r.targetRefExists(ctx, alp)
 	if err != nil {
 		return err
@@ -0,0 +1,223 @@
+package controllers
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/aws/aws-application-networking-k8s/pkg/config"
+	"github.com/aws/aws-application-networking-k8s/pkg/utils/gwlog"
+)
+
+const (
+	ReasonRestartRequired = "RestartRequired"
+	ReasonInvalidSetting  = "InvalidSetting"
+	ReasonUnknownSetting  = "UnknownSetting"
+
+	// ConfigStatusSuffix is appended to the name of the config ConfigMap to name the status ConfigMap
+	ConfigStatusSuffix = "-status"
+	// ConfigStatusRestartRequired lists the settings changed since the controller started, which need a restart
+	ConfigStatusRestartRequired = "restartRequired"
+)
+
+// configReconciler applies the changes of the config ConfigMap: the live settings are applied right away,
+// changes to the other settings are reported with a RestartRequired event. The elected replica publishes
+// the effective settings in the status ConfigMap.
+type configReconciler struct {
+	log           gwlog.Logger
+	reader        client.Reader
+	apiReader     client.Reader
+	client        client.Client
+	eventRecorder record.EventRecorder
+	name          types.NamespacedName
+	// env holds the settings of the environment, before the ConfigMap overrides
+	env map[string]string
+	// effective holds the settings in effect
+	effective    map[string]string
+	onLiveChange func()
+	isElected    func() bool
+}
+
+// RegisterConfigController watches the config ConfigMap on every replica. env is the environment before
+// the ConfigMap overrides, effective the settings the controller started with, onLiveChange is called
+// after live settings are changed.
+func RegisterConfigController(
+	log gwlog.Logger,
+	mgr ctrl.Manager,
+	name types.NamespacedName,
+	env map[string]string,
+	effective map[string]string,
+	onLiveChange func(),
+) error {
+	// a cache of the config ConfigMap only, the manager cache would hold all the ConfigMaps of the watched namespaces
+	cmCache, err := cache.New(mgr.GetConfig(), cache.Options{
+		Scheme:            mgr.GetScheme(),
+		Mapper:            mgr.GetRESTMapper(),
+		DefaultNamespaces: map[string]cache.Config{name.Namespace: {}},
+		ByObject: map[client.Object]cache.ByObject{
+			&corev1.ConfigMap{}: {Field: fields.OneTermEqualSelector("metadata.name", name.Name)},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if err := mgr.Add(cmCache); err != nil {
+		return err
+	}
+
+	r := &configReconciler{
+		log:           log,
+		reader:        cmCache,
+		apiReader:     mgr.GetAPIReader(),
+		client:        mgr.GetClient(),
+		eventRecorder: mgr.GetEventRecorderFor("config"),
+		name:          name,
+		env:           env,
+		effective:     maps.Clone(effective),
+		onLiveChange:  onLiveChange,
+		isElected: func() bool {
+			select {
+			case <-mgr.Elected():
+				return true
+			default:
+				return false
+			}
+		},
+	}
+
+	// reconcile once elected, to publish the status
+	elected := make(chan event.GenericEvent, 1)
+	go func() {
+		<-mgr.Elected()
+		elected <- event.GenericEvent{Object: &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: name.Namespace, Name: name.Name},
+		}}
+	}()
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("config").
+		WatchesRawSource(source.Kind(cmCache, client.Object(&corev1.ConfigMap{}), &handler.EnqueueRequestForObject{})).
+		WatchesRawSource(source.Channel(elected, &handler.EnqueueRequestForObject{})).
+		WithOptions(controller.Options{NeedLeaderElection: ptr.To(false)}).
+		Complete(r)
+}
+
+func (r *configReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reconcileErr error) {
+	ctx = gwlog.StartReconcileTrace(ctx, r.log, "config", req.Name, req.Namespace)
+	defer func() {
+		gwlog.EndReconcileTrace(ctx, r.log, reconcileErr)
+	}()
+
+	if req.NamespacedName != r.name {
+		return ctrl.Result{}, nil
+	}
+	cm := &corev1.ConfigMap{}
+	if err := r.reader.Get(ctx, r.name, cm); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		// without ConfigMap the settings are the ones of the environment
+		cm = nil
+	}
+
+	restartRequired := r.apply(ctx, cm)
+
+	if !r.isElected() {
+		return ctrl.Result{}, nil
+	}
+	if cm != nil && len(restartRequired) > 0 {
+		r.eventRecorder.Eventf(cm, corev1.EventTypeWarning, ReasonRestartRequired,
+			"Changes to %s apply after a restart of the controller", strings.Join(restartRequired, ", "))
+	}
+	return ctrl.Result{}, r.publishStatus(ctx, restartRequired)
+}
+
+// apply applies the changes of the live settings, and returns the other changed settings
+func (r *configReconciler) apply(ctx context.Context, cm *corev1.ConfigMap) []string {
+	desired := maps.Clone(r.env)
+	if cm != nil {
+		for key, value := range cm.Data {
+			if !slices.Contains(config.Settings, key) {
+				r.eventRecorder.Eventf(cm, corev1.EventTypeWarning, ReasonUnknownSetting, "Unknown setting %s", key)
+				continue
+			}
+			desired[key] = value
+		}
+	}
+
+	var restartRequired []string
+	liveChanged := false
+	for _, key := range config.Settings {
+		if desired[key] == r.effective[key] {
+			continue
+		}
+		if !slices.Contains(config.LiveSettings, key) {
+			restartRequired = append(restartRequired, key)
+			continue
+		}
+		if err := config.ApplyLive(key, desired[key]); err != nil {
+			r.log.Warnf(ctx, "Ignoring config change: %s", err)
+			if cm != nil {
+				r.eventRecorder.Event(cm, corev1.EventTypeWarning, ReasonInvalidSetting, err.Error())
+			}
+			continue
+		}
+		r.log.Infof(ctx, "Applied config change %s=%q", key, desired[key])
+		setOrDelete(r.effective, key, desired[key])
+		liveChanged = true
+	}
+	if liveChanged && r.onLiveChange != nil {
+		r.onLiveChange()
+	}
+	return restartRequired
+}
+
+// publishStatus writes the effective settings in the status ConfigMap
+func (r *configReconciler) publishStatus(ctx context.Context, restartRequired []string) error {
+	data := maps.Clone(r.effective)
+	data[ConfigStatusRestartRequired] = strings.Join(restartRequired, ",")
+
+	status := &corev1.ConfigMap{}
+	key := types.NamespacedName{Namespace: r.name.Namespace, Name: r.name.Name + ConfigStatusSuffix}
+	// read directly, ConfigMaps are not in the cache of the manager
+	err := r.apiReader.Get(ctx, key, status)
+	if apierrors.IsNotFound(err) {
+		status = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+			Data:       data,
+		}
+		return r.client.Create(ctx, status)
+	}
+	if err != nil {
+		return err
+	}
+	if maps.Equal(status.Data, data) {
+		return nil
+	}
+	status.Data = data
+	return r.client.Update(ctx, status)
+}
+
+func setOrDelete(m map[string]string, key, value string) {
+	if value == "" {
+		delete(m, key)
+	} else {
+		m[key] = value
+	}
+}
@@ -82,6 +82,10 @@ func (r *resourceMapper) TargetGroupPolicyToService(ctx context.Context, tgp *an
 	return policyToTargetRefObj(r, ctx, tgp, &corev1.Service{})
 }
 
+func (r *resourceMapper) ServiceImportPolicyToServiceImport(ctx context.Context, sip *anv1alpha1.ServiceImportPolicy) *anv1alpha1.ServiceImport {
+	return policyToTargetRefObj(r, ctx, sip, &anv1alpha1.ServiceImport{})
+}
+
 func (r *resourceMapper) VpcAssociationPolicyToGateway(ctx context.Context, vap *anv1alpha1.VpcAssociationPolicy) *gwv1.Gateway {
 	return policyToTargetRefObj(r, ctx, vap, &gwv1.Gateway{})
 }
@@ -151,6 +155,8 @@ func k8sResourceTypeToGroupAndKind(obj client.Object) (gwv1.Group, gwv1.Kind, er
 		return corev1.GroupName, serviceKind, nil
 	case *gwv1.Gateway:
 		return gwv1.GroupName, gatewayKind, nil
+	case *anv1alpha1.ServiceImport:
+		return anv1alpha1.GroupName, serviceImportKind, nil
 	default:
 		return "", "", fmt.Errorf("un-registered obj type: %T", obj)
 	}
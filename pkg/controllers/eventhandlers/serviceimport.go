@@ -34,15 +34,26 @@ func (h *serviceImportEventHandler) MapToRoute(routeType core.RouteType) handler
 	})
 }
 
+func (h *serviceImportEventHandler) mapToServiceImport(ctx context.Context, obj client.Object) *anv1alpha1.ServiceImport {
+	switch typed := obj.(type) {
+	case *anv1alpha1.ServiceImport:
+		return typed
+	case *anv1alpha1.ServiceImportPolicy:
+		return h.mapper.ServiceImportPolicyToServiceImport(ctx, typed)
+	}
+	return nil
+}
+
 func (h *serviceImportEventHandler) mapToRoute(ctx context.Context, obj client.Object, routeType core.RouteType) []reconcile.Request {
-	routes := h.mapper.ServiceImportToRoutes(ctx, obj.(*anv1alpha1.ServiceImport), routeType)
+	svcImport := h.mapToServiceImport(ctx, obj)
+	routes := h.mapper.ServiceImportToRoutes(ctx, svcImport, routeType)
 
 	var requests []reconcile.Request
 	for _, route := range routes {
 		routeName := k8s.NamespacedName(route.K8sObject())
 		requests = append(requests, reconcile.Request{NamespacedName: routeName})
 		h.log.Infow(ctx, "ServiceImport resource change triggered Route update",
-			"serviceName", obj.GetNamespace()+"/"+obj.GetName(), "routeName", routeName, "routeType", routeType)
+			"serviceName", svcImport.Namespace+"/"+svcImport.Name, "routeName", routeName, "routeType", routeType)
 	}
 	return requests
 }
@@ -107,7 +107,9 @@ func RegisterGatewayController(
 	gwClassEventHandler := eventhandlers.NewEnqueueRequestsForGatewayClassEvent(log, mgrClient)
 	vpcAssociationPolicyEventHandler := eventhandlers.NewVpcAssociationPolicyEventHandler(log, mgrClient)
 	builder := ctrl.NewControllerManagedBy(mgr).
-		For(&gwv1.Gateway{}, pkg_builder.WithPredicates(predicate.GenerationChangedPredicate{}))
+		For(&gwv1.Gateway{}, pkg_builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&gwv1.Gateway{}, lattice_runtime.EnqueuePriorityRequests(lattice_runtime.IsBeingDeleted)).
+		WithOptions(controllerOptions("gateway"))
 	builder.Watches(&gwv1.GatewayClass{}, gwClassEventHandler)
 
 	//Watch VpcAssociationPolicy CRD if it is installed
@@ -127,10 +129,10 @@ func RegisterGatewayController(
 //+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=gateways/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=gateways/finalizers,verbs=update
 
-func (r *gatewayReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *gatewayReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reconcileErr error) {
 	ctx = gwlog.StartReconcileTrace(ctx, r.log, "gateway", req.Name, req.Namespace)
 	defer func() {
-		gwlog.EndReconcileTrace(ctx, r.log)
+		gwlog.EndReconcileTrace(ctx, r.log, reconcileErr)
 	}()
 
 	recErr := r.reconcile(ctx, req)
@@ -253,7 +255,7 @@ func (r *gatewayReconciler) reconcileUpsert(ctx context.Context, gw *gwv1.Gatewa
 		return err
 	}
 
-	err = r.updateGatewayProgrammedStatus(ctx, gw, gwv1.GatewayReasonProgrammed, fmt.Sprintf("aws-service-network-arn: %s", *snInfo.SvcNetwork.Arn))
+	err = r.updateGatewayProgrammedStatus(ctx, gw, gwv1.GatewayReasonProgrammed, model.ServiceNetworkArnMessagePrefix+*snInfo.SvcNetwork.Arn)
 	if err != nil {
 		return err
 	}
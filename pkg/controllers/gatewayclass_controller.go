@@ -57,6 +57,7 @@ func RegisterGatewayClassController(log gwlog.Logger, mgr ctrl.Manager) error {
 	}
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&gwv1.GatewayClass{}).
+		WithOptions(controllerOptions("gatewayclass")).
 		Complete(r)
 }
 
@@ -64,10 +65,10 @@ func RegisterGatewayClassController(log gwlog.Logger, mgr ctrl.Manager) error {
 //+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=gatewayclasses/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=gatewayclasses/finalizers,verbs=update
 
-func (r *gatewayClassReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *gatewayClassReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reconcileErr error) {
 	ctx = gwlog.StartReconcileTrace(ctx, r.log, "gatewayclass", req.Name, req.Namespace)
 	defer func() {
-		gwlog.EndReconcileTrace(ctx, r.log)
+		gwlog.EndReconcileTrace(ctx, r.log, reconcileErr)
 	}()
 
 	gwClass := &gwv1.GatewayClass{}
@@ -10,6 +10,7 @@ import (
 	"github.com/aws/aws-application-networking-k8s/pkg/k8s"
 	policy "github.com/aws/aws-application-networking-k8s/pkg/k8s/policyhelper"
 	model "github.com/aws/aws-application-networking-k8s/pkg/model/lattice"
+	lattice_runtime "github.com/aws/aws-application-networking-k8s/pkg/runtime"
 	"github.com/aws/aws-application-networking-k8s/pkg/utils/gwlog"
 
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -53,7 +54,9 @@ func RegisterIAMAuthPolicyController(log gwlog.Logger, mgr ctrl.Manager, cloud p
 
 	b := ctrl.
 		NewControllerManagedBy(mgr).
-		For(&anv1alpha1.IAMAuthPolicy{}, builder.WithPredicates(predicate.GenerationChangedPredicate{}))
+		For(&anv1alpha1.IAMAuthPolicy{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&anv1alpha1.IAMAuthPolicy{}, lattice_runtime.EnqueuePriorityRequests(lattice_runtime.IsBeingDeleted)).
+		WithOptions(controllerOptions("iamauthpolicy"))
 	ph.AddWatchers(b, &gwv1.Gateway{}, &gwv1.HTTPRoute{}, &gwv1.GRPCRoute{})
 	err := b.Complete(controller)
 	return err
@@ -61,8 +64,9 @@ func RegisterIAMAuthPolicyController(log gwlog.Logger, mgr ctrl.Manager, cloud p
 
 // Reconciles IAMAuthPolicy CRD.
 //
-// IAMAuthPolicy has a plain text policy field and targetRef.Content of policy is not validated by
-// controller, but Lattice API.
+// IAMAuthPolicy has a plain text policy field and targetRef. Content of policy is validated against
+// the IAM grammar accepted by Lattice before it is put, an invalid policy results in Invalid status
+// with the line and column of the error.
 //
 // TargetRef Kind can be Gatbeway, HTTPRoute, or GRPCRoute. Other Kinds will result in Invalid
 // status.  Policy can be attached to single targetRef only. Attempt to attach more than 1 policy
@@ -76,10 +80,10 @@ func RegisterIAMAuthPolicyController(log gwlog.Logger, mgr ctrl.Manager, cloud p
 // Resouce
 //
 // Policy Attachment Spec is defined in [GEP-713]: https://gateway-api.sigs.k8s.io/geps/gep-713/.
-func (c *IAMAuthPolicyController) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (c *IAMAuthPolicyController) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reconcileErr error) {
 	ctx = gwlog.StartReconcileTrace(ctx, c.log, "iamauthpolicy", req.Name, req.Namespace)
 	defer func() {
-		gwlog.EndReconcileTrace(ctx, c.log)
+		gwlog.EndReconcileTrace(ctx, c.log, reconcileErr)
 	}()
 
 	k8sPolicy := &anv1alpha1.IAMAuthPolicy{}
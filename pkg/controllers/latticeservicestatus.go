@@ -0,0 +1,229 @@
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	gwv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	anv1alpha1 "github.com/aws/aws-application-networking-k8s/pkg/apis/applicationnetworking/v1alpha1"
+	"github.com/aws/aws-application-networking-k8s/pkg/k8s"
+	"github.com/aws/aws-application-networking-k8s/pkg/model/core"
+	model "github.com/aws/aws-application-networking-k8s/pkg/model/lattice"
+	"github.com/aws/aws-application-networking-k8s/pkg/utils"
+)
+
+//+kubebuilder:rbac:groups=application-networking.k8s.aws,resources=latticeservicestatuses,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=application-networking.k8s.aws,resources=latticeservicestatuses/status,verbs=get;update;patch
+
+// latticeServiceStatusHashLength is the length of the hash suffix of LatticeServiceStatus names
+// which are truncated to fit the Kubernetes object name limit
+const latticeServiceStatusHashLength = 8
+
+// updateLatticeServiceStatus writes the deployed Lattice resources of the route into its
+// LatticeServiceStatus object, named after the route and its kind. The object is owned by the route,
+// so it is garbage collected along with it. An object of that name owned by someone else is left
+// untouched and reported with a warning event on the route, the deployment itself succeeded.
+func (r *routeReconciler) updateLatticeServiceStatus(ctx context.Context, route core.Route, stack core.Stack) error {
+	observed, err := buildLatticeServiceStatus(stack)
+	if err != nil {
+		return err
+	}
+	observed.ObservedGeneration = route.K8sObject().GetGeneration()
+
+	lss := &anv1alpha1.LatticeServiceStatus{}
+	key := types.NamespacedName{Name: latticeServiceStatusName(route.Name(), r.routeType), Namespace: route.Namespace()}
+	if err := r.client.Get(ctx, key, lss); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		lss = &anv1alpha1.LatticeServiceStatus{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      key.Name,
+				Namespace: key.Namespace,
+			},
+			Spec: anv1alpha1.LatticeServiceStatusSpec{
+				RouteRef: anv1alpha1.LatticeServiceRouteReference{
+					Kind: routeKind(r.routeType),
+					Name: gwv1.ObjectName(route.Name()),
+				},
+			},
+		}
+		if err := controllerutil.SetControllerReference(route.K8sObject(), lss, r.scheme); err != nil {
+			return err
+		}
+		if err := r.client.Create(ctx, lss); err != nil {
+			return fmt.Errorf("failed to create LatticeServiceStatus %s due to %w", key, err)
+		}
+	} else if !metav1.IsControlledBy(lss, route.K8sObject()) {
+		// never adopt an object created by a user or for another route
+		msg := fmt.Sprintf("LatticeServiceStatus %s is not owned by %s %s, its status is not updated", key, routeKind(r.routeType), route.Name())
+		r.log.Warnf(ctx, "%s", msg)
+		r.eventRecorder.Event(route.K8sObject(), corev1.EventTypeWarning, k8s.RouteEventReasonLatticeServiceStatusConflict, msg)
+		return nil
+	}
+
+	observed.LastUpdateTime = lss.Status.LastUpdateTime
+	if equality.Semantic.DeepEqual(lss.Status, observed) {
+		return nil
+	}
+	now := metav1.Now()
+	observed.LastUpdateTime = &now
+	lss.Status = observed
+	if err := r.client.Status().Update(ctx, lss); err != nil {
+		return fmt.Errorf("failed to update LatticeServiceStatus %s due to %w", key, err)
+	}
+	r.log.Debugf(ctx, "Updated LatticeServiceStatus %s", key)
+	return nil
+}
+
+// latticeServiceStatusName suffixes the route name with its kind, so that routes of different kinds
+// with the same name do not share a LatticeServiceStatus. Names longer than the Kubernetes object name
+// limit are truncated and get a hash of the route name so that they do not collide.
+func latticeServiceStatusName(routeName string, routeType core.RouteType) string {
+	suffix := "-" + strings.ToLower(string(routeKind(routeType)))
+	if len(routeName)+len(suffix) <= validation.DNS1123SubdomainMaxLength {
+		return routeName + suffix
+	}
+	hash := sha256.Sum256([]byte(routeName))
+	truncated := utils.Truncate(routeName, validation.DNS1123SubdomainMaxLength-len(suffix)-latticeServiceStatusHashLength-1)
+	return fmt.Sprintf("%s-%s%s", truncated, hex.EncodeToString(hash[:])[:latticeServiceStatusHashLength], suffix)
+}
+
+func routeKind(routeType core.RouteType) gwv1.Kind {
+	switch routeType {
+	case core.GrpcRouteType:
+		return "GRPCRoute"
+	case core.TlsRouteType:
+		return "TLSRoute"
+	default:
+		return "HTTPRoute"
+	}
+}
+
+// buildLatticeServiceStatus collects the statuses the synthesizers recorded on the deployed stack.
+// Resources without a status were not deployed and are left out.
+func buildLatticeServiceStatus(stack core.Stack) (anv1alpha1.LatticeServiceObservedStatus, error) {
+	var observed anv1alpha1.LatticeServiceObservedStatus
+
+	var services []*model.Service
+	if err := stack.ListResources(&services); err != nil {
+		return observed, err
+	}
+	routeSvcId := ""
+	for _, svc := range services {
+		// headless services of the clusters of a Headless ServiceImport are not reported
+		if svc.IsDeleted || svc.Status == nil || svc.Spec.IsHeadless() {
+			continue
+		}
+		routeSvcId = svc.ID()
+		observed.ServiceName = svc.LatticeServiceName()
+		observed.ServiceArn = svc.Status.Arn
+		observed.ServiceId = svc.Status.Id
+		observed.DnsName = svc.Status.Dns
+		observed.CustomDomainName = svc.Spec.CustomerDomainName
+	}
+
+	var rules []*model.Rule
+	if err := stack.ListResources(&rules); err != nil {
+		return observed, err
+	}
+	var listeners []*model.Listener
+	if err := stack.ListResources(&listeners); err != nil {
+		return observed, err
+	}
+	for _, listener := range listeners {
+		if listener.Status == nil || listener.Spec.StackServiceId != routeSvcId {
+			continue
+		}
+		ls := anv1alpha1.LatticeListenerStatus{
+			Name:     listener.Status.Name,
+			Id:       listener.Status.Id,
+			Arn:      listener.Status.ListenerArn,
+			Port:     listener.Spec.Port,
+			Protocol: listener.Spec.Protocol,
+		}
+		for _, rule := range rules {
+			if rule.Status == nil || rule.Spec.StackListenerId != listener.ID() {
+				continue
+			}
+			rs := anv1alpha1.LatticeRuleStatus{
+				Name:     rule.Status.Name,
+				Id:       rule.Status.Id,
+				Arn:      rule.Status.Arn,
+				Priority: rule.Status.Priority,
+			}
+			for _, ruleTg := range rule.Spec.Action.TargetGroups {
+				if ruleTg.LatticeTgId != "" && ruleTg.LatticeTgId != model.InvalidBackendRefTgId {
+					rs.TargetGroupIds = append(rs.TargetGroupIds, ruleTg.LatticeTgId)
+				}
+			}
+			ls.Rules = append(ls.Rules, rs)
+		}
+		sort.Slice(ls.Rules, func(i, j int) bool {
+			return ls.Rules[i].Priority < ls.Rules[j].Priority
+		})
+		observed.Listeners = append(observed.Listeners, ls)
+	}
+	sort.Slice(observed.Listeners, func(i, j int) bool {
+		return observed.Listeners[i].Port < observed.Listeners[j].Port
+	})
+
+	var resTargets []*model.Targets
+	if err := stack.ListResources(&resTargets); err != nil {
+		return observed, err
+	}
+	var tgs []*model.TargetGroup
+	if err := stack.ListResources(&tgs); err != nil {
+		return observed, err
+	}
+	for _, tg := range tgs {
+		if tg.IsDeleted || tg.Status == nil {
+			continue
+		}
+		tgStatus := anv1alpha1.LatticeTargetGroupStatus{
+			Name:             tg.Status.Name,
+			Id:               tg.Status.Id,
+			Arn:              tg.Status.Arn,
+			ServiceName:      tg.Spec.K8SServiceName,
+			ServiceNamespace: tg.Spec.K8SServiceNamespace,
+			Protocol:         tg.Spec.Protocol,
+			ProtocolVersion:  tg.Spec.ProtocolVersion,
+		}
+		for _, targets := range resTargets {
+			if targets.Status == nil || targets.Spec.StackTargetGroupId != tg.ID() {
+				continue
+			}
+			tgStatus.TargetHealth = latticeTargetHealth(targets.Status)
+		}
+		observed.TargetGroups = append(observed.TargetGroups, tgStatus)
+	}
+	sort.Slice(observed.TargetGroups, func(i, j int) bool {
+		return observed.TargetGroups[i].Name < observed.TargetGroups[j].Name
+	})
+
+	return observed, nil
+}
+
+func latticeTargetHealth(status *model.TargetsStatus) *anv1alpha1.LatticeTargetHealth {
+	return &anv1alpha1.LatticeTargetHealth{
+		Healthy:     status.Healthy,
+		Unhealthy:   status.Unhealthy,
+		Initial:     status.Initial,
+		Unavailable: status.Unavailable,
+		Unused:      status.Unused,
+		Draining:    status.Draining,
+		ReasonCodes: status.ReasonCodes,
+	}
+}
@@ -0,0 +1,86 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gwv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gwv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	anv1alpha1 "github.com/aws/aws-application-networking-k8s/pkg/apis/applicationnetworking/v1alpha1"
+	"github.com/aws/aws-application-networking-k8s/pkg/model/core"
+	"github.com/aws/aws-application-networking-k8s/pkg/utils/gwlog"
+)
+
+func TestUpdateLatticeServiceStatus_RouteKinds(t *testing.T) {
+	ctx := context.TODO()
+
+	k8sScheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(k8sScheme)
+	gwv1.Install(k8sScheme)
+	addOptionalCRDs(k8sScheme)
+
+	httpRoute := &gwv1.HTTPRoute{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "ns", UID: "http-uid"}}
+	grpcRoute := &gwv1.GRPCRoute{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "ns", UID: "grpc-uid"}}
+	k8sClient := testclient.NewClientBuilder().WithScheme(k8sScheme).
+		WithStatusSubresource(&anv1alpha1.LatticeServiceStatus{}).
+		WithObjects(
+			httpRoute,
+			grpcRoute,
+			// created by a user for a TLSRoute of the same name
+			&anv1alpha1.LatticeServiceStatus{ObjectMeta: metav1.ObjectMeta{Name: "app-tlsroute", Namespace: "ns"}},
+		).Build()
+
+	stack := core.NewDefaultStack(core.StackID{Name: "app", Namespace: "ns"})
+	for _, tt := range []struct {
+		routeType core.RouteType
+		route     core.Route
+	}{
+		{core.HttpRouteType, core.NewHTTPRoute(*httpRoute)},
+		{core.GrpcRouteType, core.NewGRPCRoute(*grpcRoute)},
+	} {
+		r := &routeReconciler{log: gwlog.FallbackLogger, client: k8sClient, scheme: k8sScheme, routeType: tt.routeType, eventRecorder: record.NewFakeRecorder(1)}
+		assert.NoError(t, r.updateLatticeServiceStatus(ctx, tt.route, stack))
+	}
+
+	lss := &anv1alpha1.LatticeServiceStatus{}
+	assert.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Namespace: "ns", Name: "app-httproute"}, lss))
+	assert.Equal(t, gwv1.Kind("HTTPRoute"), lss.Spec.RouteRef.Kind)
+	assert.Equal(t, types.UID("http-uid"), lss.OwnerReferences[0].UID)
+	assert.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Namespace: "ns", Name: "app-grpcroute"}, lss))
+	assert.Equal(t, gwv1.Kind("GRPCRoute"), lss.Spec.RouteRef.Kind)
+	assert.Equal(t, types.UID("grpc-uid"), lss.OwnerReferences[0].UID)
+
+	tlsRoute := gwv1alpha2.TLSRoute{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "ns", UID: "tls-uid"}}
+	recorder := record.NewFakeRecorder(1)
+	r := &routeReconciler{log: gwlog.FallbackLogger, client: k8sClient, scheme: k8sScheme, routeType: core.TlsRouteType, eventRecorder: recorder}
+	assert.NoError(t, r.updateLatticeServiceStatus(ctx, core.NewTLSRoute(tlsRoute), stack))
+	assert.Contains(t, <-recorder.Events, "LatticeServiceStatusConflict LatticeServiceStatus ns/app-tlsroute is not owned by TLSRoute app")
+	assert.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Namespace: "ns", Name: "app-tlsroute"}, lss))
+	assert.Empty(t, lss.OwnerReferences)
+	assert.Nil(t, lss.Status.LastUpdateTime)
+}
+
+func TestLatticeServiceStatusName(t *testing.T) {
+	assert.Equal(t, "app-grpcroute", latticeServiceStatusName("app", core.GrpcRouteType))
+
+	longName := strings.Repeat("a", 250)
+	name := latticeServiceStatusName(longName, core.HttpRouteType)
+	assert.Len(t, name, validation.DNS1123SubdomainMaxLength)
+	assert.True(t, strings.HasSuffix(name, "-httproute"))
+	assert.Empty(t, validation.IsDNS1123Subdomain(name))
+
+	// truncated names of different routes do not collide
+	otherName := latticeServiceStatusName(longName+"b", core.HttpRouteType)
+	assert.Len(t, otherName, validation.DNS1123SubdomainMaxLength)
+	assert.NotEqual(t, name, otherName)
+}
@@ -0,0 +1,80 @@
+package controllers
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/aws/aws-application-networking-k8s/pkg/config"
+	lattice_runtime "github.com/aws/aws-application-networking-k8s/pkg/runtime"
+)
+
+// maxLiveConcurrency is the number of workers of every controller, so the highest concurrency
+// a controller can be changed to without restart, unless it starts with a higher one
+const maxLiveConcurrency = 32
+
+// default overall rate limit of the controller-runtime work queues
+const (
+	defaultControllerQPS   = 10
+	defaultControllerBurst = 100
+)
+
+// controllerLimits are the limits of a controller, changed live by UpdateControllerLimits
+type controllerLimits struct {
+	concurrency *lattice_runtime.ConcurrencyLimit
+	rate        *rate.Limiter
+}
+
+// limits of the registered controllers by name, there is one route controller per route type
+var (
+	limitsLock sync.Mutex
+	limits     = map[string][]*controllerLimits{}
+)
+
+// controllerOptions returns the options of a controller, with its concurrency and workqueue
+// rate limits from the config, and a queue reconciling the requests added with priority first
+func controllerOptions(name string) controller.Options {
+	qps, burst := controllerRateLimit(name)
+	l := &controllerLimits{
+		concurrency: lattice_runtime.NewConcurrencyLimit(config.MaxConcurrentReconciles(name)),
+		rate:        rate.NewLimiter(qps, burst),
+	}
+	limitsLock.Lock()
+	limits[name] = append(limits[name], l)
+	limitsLock.Unlock()
+
+	return controller.Options{
+		MaxConcurrentReconciles: max(maxLiveConcurrency, l.concurrency.Get()),
+		NewQueue:                lattice_runtime.NewPriorityQueueWithLimit(l.concurrency),
+		// same per item backoff as the controller-runtime default, with the configured overall limit
+		RateLimiter: workqueue.NewTypedMaxOfRateLimiter(
+			workqueue.NewTypedItemExponentialFailureRateLimiter[reconcile.Request](5*time.Millisecond, 1000*time.Second),
+			&workqueue.TypedBucketRateLimiter[reconcile.Request]{Limiter: l.rate},
+		),
+	}
+}
+
+// UpdateControllerLimits applies the concurrency and rate limits of the config to the running controllers
+func UpdateControllerLimits() {
+	limitsLock.Lock()
+	defer limitsLock.Unlock()
+	for name, controllerLimits := range limits {
+		qps, burst := controllerRateLimit(name)
+		for _, l := range controllerLimits {
+			l.concurrency.Set(config.MaxConcurrentReconciles(name))
+			l.rate.SetLimit(qps)
+			l.rate.SetBurst(burst)
+		}
+	}
+}
+
+func controllerRateLimit(name string) (rate.Limit, int) {
+	if limit, ok := config.ControllerRateLimit(name); ok {
+		return rate.Limit(limit.QPS), limit.Burst
+	}
+	return defaultControllerQPS, defaultControllerBurst
+}
@@ -18,28 +18,61 @@ package controllers
 
 import (
 	"context"
+	"time"
 
-	"github.com/aws/aws-application-networking-k8s/pkg/utils/gwlog"
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/vpclattice"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/aws/aws-application-networking-k8s/pkg/aws"
+	"github.com/aws/aws-application-networking-k8s/pkg/aws/services"
+	"github.com/aws/aws-application-networking-k8s/pkg/deploy/lattice"
+	model "github.com/aws/aws-application-networking-k8s/pkg/model/lattice"
+	lattice_runtime "github.com/aws/aws-application-networking-k8s/pkg/runtime"
+	"github.com/aws/aws-application-networking-k8s/pkg/utils"
+	"github.com/aws/aws-application-networking-k8s/pkg/utils/gwlog"
 )
 
 type podReconciler struct {
 	log    gwlog.Logger
 	client client.Client
 	scheme *runtime.Scheme
+	cloud  aws.Cloud
 }
 
-func RegisterPodController(log gwlog.Logger, mgr ctrl.Manager) error {
+// interval to check the Lattice health of pods which are not ready yet
+const podReadinessRequeueInterval = 10 * time.Second
+
+func RegisterPodController(log gwlog.Logger, cloud aws.Cloud, mgr ctrl.Manager) error {
 	pr := &podReconciler{
 		log:    log,
 		client: mgr.GetClient(),
 		scheme: mgr.GetScheme(),
+		cloud:  cloud,
+	}
+	hasReadinessGate := predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		pod, ok := obj.(*corev1.Pod)
+		return ok && utils.PodHasReadinessGate(pod, lattice.LatticeReadinessGateConditionType)
+	})
+	isWaitingOnReadinessGate := func(obj client.Object) bool {
+		pod, ok := obj.(*corev1.Pod)
+		if !ok || !utils.PodHasReadinessGate(pod, lattice.LatticeReadinessGateConditionType) {
+			return false
+		}
+		cond := utils.FindPodStatusCondition(pod.Status.Conditions, lattice.LatticeReadinessGateConditionType)
+		return cond == nil || cond.Status != corev1.ConditionTrue
 	}
 	err := ctrl.NewControllerManagedBy(mgr).
-		For(&corev1.Pod{}).
+		For(&corev1.Pod{}, builder.WithPredicates(hasReadinessGate)).
+		// pods waiting on their readiness gate go first, they hold rollouts back
+		Watches(&corev1.Pod{}, lattice_runtime.EnqueuePriorityRequests(isWaitingOnReadinessGate)).
+		WithOptions(controllerOptions("pod")).
 		Complete(pr)
 	return err
 }
@@ -48,11 +81,143 @@ func RegisterPodController(log gwlog.Logger, mgr ctrl.Manager) error {
 //+kubebuilder:rbac:groups=core,resources=pods/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=core,resources=pods/finalizers,verbs=update
 
-func (r *podReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *podReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reconcileErr error) {
+	ctx = gwlog.StartReconcileTrace(ctx, r.log, "pod", req.Name, req.Namespace)
+	defer func() {
+		gwlog.EndReconcileTrace(ctx, r.log, reconcileErr)
+	}()
+
+	pending, err := r.reconcile(ctx, req)
+	if err != nil {
+		r.log.Infow(ctx, "reconcile error", "name", req.Name, "message", err.Error())
+		return lattice_runtime.HandleReconcileError(err)
+	}
+	if pending {
+		return ctrl.Result{RequeueAfter: podReadinessRequeueInterval}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+// reconcile sets the readiness gate condition of the pod from the Lattice status of its targets.
+// Returns true while the targets are not registered or their health checks are pending.
+func (r *podReconciler) reconcile(ctx context.Context, req ctrl.Request) (bool, error) {
 	pod := &corev1.Pod{}
 	if err := r.client.Get(ctx, req.NamespacedName, pod); err != nil {
-		return ctrl.Result{}, client.IgnoreNotFound(err)
+		return false, client.IgnoreNotFound(err)
+	}
+	if !utils.PodHasReadinessGate(pod, lattice.LatticeReadinessGateConditionType) ||
+		!pod.DeletionTimestamp.IsZero() || pod.Status.PodIP == "" {
+		return false, nil
+	}
 
+	// Once the pod became ready, the condition is kept. Flipping it back would take the pod out of
+	// its Service endpoints, which in turn deregisters the target from Lattice.
+	cond := utils.FindPodStatusCondition(pod.Status.Conditions, lattice.LatticeReadinessGateConditionType)
+	if cond != nil && cond.Status == corev1.ConditionTrue {
+		return false, nil
 	}
-	return ctrl.Result{}, nil
+
+	latticeTargets, tgFound, err := r.findPodTargets(ctx, pod)
+	if err != nil {
+		return false, err
+	}
+	newCond, pending := podReadinessCondition(latticeTargets)
+	if !tgFound {
+		// Nothing to poll until a target group of the pod's Services is deployed. Registering the
+		// pod's targets sets their health condition on the pod, which triggers the next reconcile.
+		pending = false
+	}
+
+	if cond == nil || cond.Status != newCond.Status || cond.Reason != newCond.Reason || cond.Message != newCond.Message {
+		utils.SetPodStatusCondition(&pod.Status.Conditions, newCond)
+		if err := r.client.Status().Update(ctx, pod); err != nil {
+			return false, err
+		}
+		r.log.Debugf(ctx, "Updated readiness gate of pod %s to %s, reason %s", req.NamespacedName, newCond.Status, newCond.Reason)
+	}
+	return pending, nil
+}
+
+// podReadinessCondition combines the conditions of the pod's targets across target groups.
+// A pending target holds the pod back, otherwise the pod is ready when any of its targets is in use and healthy.
+func podReadinessCondition(latticeTargets []*vpclattice.TargetSummary) (corev1.PodCondition, bool) {
+	if len(latticeTargets) == 0 {
+		return lattice.ReadinessGateCondition(nil)
+	}
+	var ready, unused *corev1.PodCondition
+	for _, latticeTarget := range latticeTargets {
+		cond, pending := lattice.ReadinessGateCondition(latticeTarget)
+		if pending {
+			return cond, true
+		}
+		if cond.Status == corev1.ConditionTrue {
+			ready = &cond
+		} else {
+			unused = &cond
+		}
+	}
+	if ready != nil {
+		return *ready, false
+	}
+	return *unused, false
+}
+
+// findPodTargets finds the pod's targets in the target groups of all Services the pod is an endpoint of.
+// Also returns whether any of these Services has a target group.
+func (r *podReconciler) findPodTargets(ctx context.Context, pod *corev1.Pod) ([]*vpclattice.TargetSummary, bool, error) {
+	epSlices := &discoveryv1.EndpointSliceList{}
+	if err := r.client.List(ctx, epSlices, client.InNamespace(pod.Namespace)); err != nil {
+		return nil, false, err
+	}
+
+	svcPorts := make(map[string][]*vpclattice.Target)
+	for _, epSlice := range epSlices.Items {
+		svcName, ok := epSlice.Labels[discoveryv1.LabelServiceName]
+		if !ok {
+			continue
+		}
+		for _, ep := range epSlice.Endpoints {
+			if ep.TargetRef == nil || ep.TargetRef.Kind != "Pod" || ep.TargetRef.Name != pod.Name {
+				continue
+			}
+			for _, port := range epSlice.Ports {
+				if port.Port == nil {
+					continue
+				}
+				svcPorts[svcName] = append(svcPorts[svcName], &vpclattice.Target{
+					Id:   &pod.Status.PodIP,
+					Port: awssdk.Int64(int64(*port.Port)),
+				})
+			}
+		}
+	}
+
+	var latticeTargets []*vpclattice.TargetSummary
+	tgFound := false
+	for svcName, targets := range svcPorts {
+		tgArns, err := r.cloud.Tagging().FindResourcesByTags(ctx, services.ResourceTypeTargetGroup,
+			r.cloud.ManagedByTagsMergedWith(services.Tags{
+				model.K8SClusterNameKey:      awssdk.String(r.cloud.Config().ClusterName),
+				model.K8SServiceNameKey:      awssdk.String(svcName),
+				model.K8SServiceNamespaceKey: awssdk.String(pod.Namespace),
+			}))
+		if err != nil {
+			return nil, false, err
+		}
+		for _, tgArn := range tgArns {
+			found, err := r.cloud.Lattice().ListTargetsAsList(ctx, &vpclattice.ListTargetsInput{
+				TargetGroupIdentifier: awssdk.String(tgArn),
+				Targets:               targets,
+			})
+			if err != nil {
+				if services.IsLatticeAPINotFoundErr(err) {
+					continue
+				}
+				return nil, false, err
+			}
+			tgFound = true
+			latticeTargets = append(latticeTargets, found...)
+		}
+	}
+	return latticeTargets, tgFound, nil
 }
@@ -0,0 +1,316 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/vpclattice"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	aws2 "github.com/aws/aws-application-networking-k8s/pkg/aws"
+	mocks "github.com/aws/aws-application-networking-k8s/pkg/aws/services"
+	"github.com/aws/aws-application-networking-k8s/pkg/config"
+	"github.com/aws/aws-application-networking-k8s/pkg/deploy/lattice"
+	"github.com/aws/aws-application-networking-k8s/pkg/utils"
+	"github.com/aws/aws-application-networking-k8s/pkg/utils/gwlog"
+)
+
+func TestPodReconciler_ReadinessGate(t *testing.T) {
+	newPod := func(hasGate bool, ready bool) *corev1.Pod {
+		var readinessGates []corev1.PodReadinessGate
+		if hasGate {
+			readinessGates = append(readinessGates, corev1.PodReadinessGate{
+				ConditionType: lattice.LatticeReadinessGateConditionType,
+			})
+		}
+		condition := corev1.PodCondition{
+			Type:   lattice.LatticeReadinessGateConditionType,
+			Status: corev1.ConditionFalse,
+			Reason: lattice.ReadinessReasonUnhealthy,
+		}
+		if ready {
+			condition = corev1.PodCondition{
+				Type:   lattice.LatticeReadinessGateConditionType,
+				Status: corev1.ConditionTrue,
+				Reason: lattice.ReadinessReasonHealthy,
+			}
+		}
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "ns",
+				Name:      "pod1",
+			},
+			Spec: corev1.PodSpec{
+				ReadinessGates: readinessGates,
+			},
+			Status: corev1.PodStatus{
+				PodIP:      "10.10.1.1",
+				Conditions: []corev1.PodCondition{condition},
+			},
+		}
+	}
+	newLatticeTarget := func(status string) *vpclattice.TargetSummary {
+		return &vpclattice.TargetSummary{
+			Id:     aws.String("10.10.1.1"),
+			Port:   aws.Int64(8675),
+			Status: aws.String(status),
+		}
+	}
+
+	tests := []struct {
+		name           string
+		lattice        []*vpclattice.TargetSummary
+		pod            *corev1.Pod
+		expectLookup   bool
+		missingTarget  bool
+		expectedStatus corev1.ConditionStatus
+		expectedReason string
+		requeue        bool
+	}{
+		{
+			name:           "Healthy targets make pod ready",
+			lattice:        []*vpclattice.TargetSummary{newLatticeTarget(vpclattice.TargetStatusHealthy)},
+			pod:            newPod(true, false),
+			expectLookup:   true,
+			expectedStatus: corev1.ConditionTrue,
+			expectedReason: lattice.ReadinessReasonHealthy,
+		},
+		{
+			name:           "Unavailable targets make pod ready",
+			lattice:        []*vpclattice.TargetSummary{newLatticeTarget(vpclattice.TargetStatusUnavailable)},
+			pod:            newPod(true, false),
+			expectLookup:   true,
+			expectedStatus: corev1.ConditionTrue,
+			expectedReason: lattice.ReadinessReasonHealthCheckUnavailable,
+		},
+		{
+			name:           "Initial targets do not make pod ready",
+			lattice:        []*vpclattice.TargetSummary{newLatticeTarget(vpclattice.TargetStatusInitial)},
+			pod:            newPod(true, false),
+			expectLookup:   true,
+			expectedStatus: corev1.ConditionFalse,
+			expectedReason: lattice.ReadinessReasonInitial,
+			requeue:        true,
+		},
+		{
+			name:           "Unhealthy targets do not make pod ready",
+			lattice:        []*vpclattice.TargetSummary{newLatticeTarget(vpclattice.TargetStatusUnhealthy)},
+			pod:            newPod(true, false),
+			expectLookup:   true,
+			expectedStatus: corev1.ConditionFalse,
+			expectedReason: lattice.ReadinessReasonUnhealthy,
+			requeue:        true,
+		},
+		{
+			name:           "Draining(unhealthy) targets do not make pod ready",
+			lattice:        []*vpclattice.TargetSummary{newLatticeTarget(vpclattice.TargetStatusDraining)},
+			pod:            newPod(true, false),
+			expectLookup:   true,
+			expectedStatus: corev1.ConditionFalse,
+			expectedReason: lattice.ReadinessReasonUnhealthy,
+			requeue:        true,
+		},
+		{
+			name:           "Requeues if target not found",
+			lattice:        []*vpclattice.TargetSummary{},
+			pod:            newPod(true, false),
+			expectLookup:   true,
+			missingTarget:  true,
+			expectedStatus: corev1.ConditionFalse,
+			expectedReason: lattice.ReadinessReasonTargetNotFound,
+			requeue:        true,
+		},
+		{
+			name:           "Does not requeue without target group",
+			lattice:        []*vpclattice.TargetSummary{},
+			pod:            newPod(true, false),
+			expectLookup:   true,
+			expectedStatus: corev1.ConditionFalse,
+			expectedReason: lattice.ReadinessReasonTargetNotFound,
+		},
+		{
+			name:           "Pending target in one target group holds the pod back",
+			lattice:        []*vpclattice.TargetSummary{newLatticeTarget(vpclattice.TargetStatusHealthy), newLatticeTarget(vpclattice.TargetStatusInitial)},
+			pod:            newPod(true, false),
+			expectLookup:   true,
+			expectedStatus: corev1.ConditionFalse,
+			expectedReason: lattice.ReadinessReasonInitial,
+			requeue:        true,
+		},
+		{
+			name:           "Unused target group does not hold the pod back",
+			lattice:        []*vpclattice.TargetSummary{newLatticeTarget(vpclattice.TargetStatusUnused), newLatticeTarget(vpclattice.TargetStatusHealthy)},
+			pod:            newPod(true, false),
+			expectLookup:   true,
+			expectedStatus: corev1.ConditionTrue,
+			expectedReason: lattice.ReadinessReasonHealthy,
+		},
+		{
+			name:           "Unused pods keep condition",
+			lattice:        []*vpclattice.TargetSummary{newLatticeTarget(vpclattice.TargetStatusUnused)},
+			pod:            newPod(true, false),
+			expectLookup:   true,
+			expectedStatus: corev1.ConditionFalse,
+			expectedReason: lattice.ReadinessReasonUnused,
+		},
+		{
+			name:           "Pod without gate does not change condition",
+			pod:            newPod(false, false),
+			expectedStatus: corev1.ConditionFalse,
+			expectedReason: lattice.ReadinessReasonUnhealthy,
+		},
+		{
+			name:           "Ready pods keep condition (even if target is unhealthy)",
+			pod:            newPod(true, true),
+			expectedStatus: corev1.ConditionTrue,
+			expectedReason: lattice.ReadinessReasonHealthy,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			ctx := context.TODO()
+
+			k8sClient := testclient.NewClientBuilder().WithStatusSubresource(&corev1.Pod{}).Build()
+			assert.NoError(t, k8sClient.Create(ctx, tt.pod))
+			assert.NoError(t, k8sClient.Create(ctx, &discoveryv1.EndpointSlice{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "ns",
+					Name:      "svc-abcde",
+					Labels:    map[string]string{discoveryv1.LabelServiceName: "svc"},
+				},
+				AddressType: discoveryv1.AddressTypeIPv4,
+				Ports:       []discoveryv1.EndpointPort{{Port: aws.Int32(8675)}},
+				Endpoints: []discoveryv1.Endpoint{{
+					Addresses: []string{"10.10.1.1"},
+					TargetRef: &corev1.ObjectReference{Kind: "Pod", Namespace: "ns", Name: "pod1"},
+				}},
+			}))
+
+			mockCloud := aws2.NewMockCloud(c)
+			mockLattice := mocks.NewMockLattice(c)
+			mockTagging := mocks.NewMockTagging(c)
+			mockCloud.EXPECT().Lattice().Return(mockLattice).AnyTimes()
+			mockCloud.EXPECT().Tagging().Return(mockTagging).AnyTimes()
+			mockCloud.EXPECT().Config().Return(aws2.CloudConfig{ClusterName: "cluster"}).AnyTimes()
+			mockCloud.EXPECT().ManagedByTagsMergedWith(gomock.Any()).DoAndReturn(func(tags mocks.Tags) mocks.Tags {
+				return tags
+			}).AnyTimes()
+			if tt.expectLookup {
+				var tgArns []string
+				for i, latticeTarget := range tt.lattice {
+					tgArn := "tg-arn-" + string(rune('a'+i))
+					tgArns = append(tgArns, tgArn)
+					mockLattice.EXPECT().ListTargetsAsList(gomock.Any(), &vpclattice.ListTargetsInput{
+						TargetGroupIdentifier: aws.String(tgArn),
+						Targets:               []*vpclattice.Target{{Id: aws.String("10.10.1.1"), Port: aws.Int64(8675)}},
+					}).Return([]*vpclattice.TargetSummary{latticeTarget}, nil)
+				}
+				if tt.missingTarget {
+					tgArns = append(tgArns, "tg-arn-missing")
+					mockLattice.EXPECT().ListTargetsAsList(gomock.Any(), gomock.Any()).Return(nil, nil)
+				}
+				mockTagging.EXPECT().FindResourcesByTags(gomock.Any(), mocks.ResourceTypeTargetGroup, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ mocks.ResourceType, tags mocks.Tags) ([]string, error) {
+						assert.Equal(t, "svc", aws.StringValue(tags["application-networking.k8s.aws/ServiceName"]))
+						assert.Equal(t, "ns", aws.StringValue(tags["application-networking.k8s.aws/ServiceNamespace"]))
+						return tgArns, nil
+					})
+			}
+
+			reconciler := &podReconciler{
+				log:    gwlog.FallbackLogger,
+				client: k8sClient,
+				cloud:  mockCloud,
+			}
+			podName := types.NamespacedName{Namespace: "ns", Name: "pod1"}
+			result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: podName})
+			assert.Nil(t, err)
+			if tt.requeue {
+				assert.Equal(t, podReadinessRequeueInterval, result.RequeueAfter)
+			} else {
+				assert.Zero(t, result.RequeueAfter)
+			}
+
+			pod := &corev1.Pod{}
+			assert.NoError(t, k8sClient.Get(ctx, podName, pod))
+			cond := utils.FindPodStatusCondition(pod.Status.Conditions, lattice.LatticeReadinessGateConditionType)
+			assert.NotNil(t, cond)
+			assert.Equal(t, tt.expectedStatus, cond.Status)
+			assert.Equal(t, tt.expectedReason, cond.Reason)
+		})
+	}
+}
+
+func TestPodReconciler_DefaultTags(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+	ctx := context.TODO()
+
+	// target groups created before DEFAULT_TAGS were set do not have them
+	assert.NoError(t, config.ApplyLive(config.DEFAULT_TAGS, "team=networking"))
+	defer config.ApplyLive(config.DEFAULT_TAGS, "")
+
+	k8sClient := testclient.NewClientBuilder().WithStatusSubresource(&corev1.Pod{}).Build()
+	assert.NoError(t, k8sClient.Create(ctx, &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "pod1"},
+		Spec: corev1.PodSpec{
+			ReadinessGates: []corev1.PodReadinessGate{{ConditionType: lattice.LatticeReadinessGateConditionType}},
+		},
+		Status: corev1.PodStatus{PodIP: "10.10.1.1"},
+	}))
+	assert.NoError(t, k8sClient.Create(ctx, &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "svc-abcde",
+			Labels:    map[string]string{discoveryv1.LabelServiceName: "svc"},
+		},
+		AddressType: discoveryv1.AddressTypeIPv4,
+		Ports:       []discoveryv1.EndpointPort{{Port: aws.Int32(8675)}},
+		Endpoints: []discoveryv1.Endpoint{{
+			Addresses: []string{"10.10.1.1"},
+			TargetRef: &corev1.ObjectReference{Kind: "Pod", Namespace: "ns", Name: "pod1"},
+		}},
+	}))
+
+	mockLattice := mocks.NewMockLattice(c)
+	mockTagging := mocks.NewMockTagging(c)
+	cloud := aws2.NewDefaultCloudWithTagging(mockLattice, mockTagging, aws2.CloudConfig{ClusterName: "cluster", AccountId: "account-id"})
+	mockTagging.EXPECT().FindResourcesByTags(gomock.Any(), mocks.ResourceTypeTargetGroup, mocks.Tags{
+		aws2.TagManagedBy: aws.String("account-id/cluster/"),
+		"application-networking.k8s.aws/ClusterName":      aws.String("cluster"),
+		"application-networking.k8s.aws/ServiceName":      aws.String("svc"),
+		"application-networking.k8s.aws/ServiceNamespace": aws.String("ns"),
+	}).Return([]string{"tg-arn"}, nil)
+	mockLattice.EXPECT().ListTargetsAsList(gomock.Any(), gomock.Any()).Return([]*vpclattice.TargetSummary{{
+		Id:     aws.String("10.10.1.1"),
+		Port:   aws.Int64(8675),
+		Status: aws.String(vpclattice.TargetStatusHealthy),
+	}}, nil)
+
+	reconciler := &podReconciler{
+		log:    gwlog.FallbackLogger,
+		client: k8sClient,
+		cloud:  cloud,
+	}
+	podName := types.NamespacedName{Namespace: "ns", Name: "pod1"}
+	result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: podName})
+	assert.Nil(t, err)
+	assert.Zero(t, result.RequeueAfter)
+
+	pod := &corev1.Pod{}
+	assert.NoError(t, k8sClient.Get(ctx, podName, pod))
+	cond := utils.FindPodStatusCondition(pod.Status.Conditions, lattice.LatticeReadinessGateConditionType)
+	assert.NotNil(t, cond)
+	assert.Equal(t, corev1.ConditionTrue, cond.Status)
+}
@@ -19,8 +19,7 @@ package controllers
 import (
 	"context"
 	"fmt"
-
-	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"time"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
@@ -30,16 +29,18 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 	"sigs.k8s.io/external-dns/endpoint"
 	gwv1 "sigs.k8s.io/gateway-api/apis/v1"
 	gwv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 
-	discoveryv1 "k8s.io/api/discovery/v1"
-
 	anv1alpha1 "github.com/aws/aws-application-networking-k8s/pkg/apis/applicationnetworking/v1alpha1"
 	"github.com/aws/aws-application-networking-k8s/pkg/aws"
 	"github.com/aws/aws-application-networking-k8s/pkg/aws/services"
@@ -50,7 +51,9 @@ import (
 	"github.com/aws/aws-application-networking-k8s/pkg/gateway"
 	"github.com/aws/aws-application-networking-k8s/pkg/k8s"
 	"github.com/aws/aws-application-networking-k8s/pkg/model/core"
+	model "github.com/aws/aws-application-networking-k8s/pkg/model/lattice"
 	lattice_runtime "github.com/aws/aws-application-networking-k8s/pkg/runtime"
+	"github.com/aws/aws-application-networking-k8s/pkg/shard"
 	k8sutils "github.com/aws/aws-application-networking-k8s/pkg/utils"
 	"github.com/aws/aws-application-networking-k8s/pkg/utils/gwlog"
 )
@@ -72,10 +75,23 @@ type routeReconciler struct {
 	stackDeployer    deploy.StackDeployer
 	stackMarshaller  deploy.StackMarshaller
 	cloud            aws.Cloud
+	// shardManager is set when routes are sharded across replicas, the reconciler
+	// then ignores the routes of the shards this replica does not own
+	shardManager *shard.Manager
+
+	latticeServiceStatusEnabled bool
 }
 
 const (
 	LatticeAssignedDomainName = "application-networking.k8s.aws/lattice-assigned-domain-name"
+
+	// failoverCheckInterval is the period of the target health checks of the routes with ServiceImportPolicy
+	// failover, the traffic moves to a standby cluster on the next reconcile after its primary lost its targets
+	failoverCheckInterval = 30 * time.Second
+
+	// replacementCheckInterval is the period of the target health checks of the target groups replacing
+	// others after a change of immutable fields, the traffic shifts on the next reconcile after they are healthy
+	replacementCheckInterval = 15 * time.Second
 )
 
 func RegisterAllRouteControllers(
@@ -83,6 +99,7 @@ func RegisterAllRouteControllers(
 	cloud aws.Cloud,
 	finalizerManager k8s.FinalizerManager,
 	mgr ctrl.Manager,
+	shardManager *shard.Manager,
 ) error {
 	mgrClient := mgr.GetClient()
 
@@ -126,6 +143,7 @@ func RegisterAllRouteControllers(
 			stackDeployer:    deploy.NewLatticeServiceStackDeploy(log, cloud, mgrClient),
 			stackMarshaller:  deploy.NewDefaultStackMarshaller(),
 			cloud:            cloud,
+			shardManager:     shardManager,
 		}
 
 		svcImportEventHandler := eventhandlers.NewServiceImportEventHandler(log, mgrClient)
@@ -135,10 +153,20 @@ func RegisterAllRouteControllers(
 			Watches(&gwv1.Gateway{}, gwEventHandler).
 			Watches(&corev1.Service{}, svcEventHandler.MapToRoute(routeInfo.routeType)).
 			Watches(&anv1alpha1.ServiceImport{}, svcImportEventHandler.MapToRoute(routeInfo.routeType)).
-			Watches(&discoveryv1.EndpointSlice{}, svcEventHandler.MapToRoute(routeInfo.routeType)).
-			WithOptions(controller.Options{
-				MaxConcurrentReconciles: config.RouteMaxConcurrentReconciles,
+			Watches(routeInfo.gatewayApiType, lattice_runtime.EnqueuePriorityRequests(lattice_runtime.IsBeingDeleted))
+
+		options := controllerOptions("route")
+		// with sharding, every replica reconciles the routes of its shards
+		options.NeedLeaderElection = ptr.To(shardManager == nil)
+		builder.WithOptions(options)
+
+		if shardManager != nil {
+			shardEvents := make(chan event.GenericEvent)
+			shardManager.OnAcquire(func(ctx context.Context, shard int) {
+				go reconciler.requeueShard(ctx, shard, shardEvents)
 			})
+			builder.WatchesRawSource(source.Channel(shardEvents, &handler.EnqueueRequestForObject{}))
+		}
 
 		if ok, err := k8s.IsGVKSupported(mgr, anv1alpha1.GroupVersion.String(), anv1alpha1.TargetGroupPolicyKind); ok {
 			builder.Watches(&anv1alpha1.TargetGroupPolicy{}, svcEventHandler.MapToRoute(routeInfo.routeType))
@@ -149,6 +177,25 @@ func RegisterAllRouteControllers(
 			log.Infof(context.TODO(), "TargetGroupPolicy CRD is not installed, skipping watch")
 		}
 
+		if ok, err := k8s.IsGVKSupported(mgr, anv1alpha1.GroupVersion.String(), anv1alpha1.ServiceImportPolicyKind); ok {
+			builder.Watches(&anv1alpha1.ServiceImportPolicy{}, svcImportEventHandler.MapToRoute(routeInfo.routeType))
+		} else {
+			if err != nil {
+				return err
+			}
+			log.Infof(context.TODO(), "ServiceImportPolicy CRD is not installed, skipping watch")
+		}
+
+		// the route is not re-queued on changes of its LatticeServiceStatus, it is rewritten on the next reconcile
+		if ok, err := k8s.IsGVKSupported(mgr, anv1alpha1.GroupVersion.String(), anv1alpha1.LatticeServiceStatusKind); ok {
+			reconciler.latticeServiceStatusEnabled = true
+		} else {
+			if err != nil {
+				return err
+			}
+			log.Infof(context.TODO(), "LatticeServiceStatus CRD is not installed, route resource status will not be reported")
+		}
+
 		if ok, err := k8s.IsGVKSupported(mgr, "externaldns.k8s.io/v1alpha1", "DNSEndpoint"); ok {
 			builder.Owns(&endpoint.DNSEndpoint{})
 		} else {
@@ -164,23 +211,50 @@ func RegisterAllRouteControllers(
 		}
 	}
 
-	return nil
+	return mgr.Add(deploy.TargetGroupGc())
+}
+
+// requeueShard queues the routes of a shard this replica just acquired, their events
+// were ignored while the shard was owned by another replica
+func (r *routeReconciler) requeueShard(ctx context.Context, shard int, events chan<- event.GenericEvent) {
+	routes, err := r.listRoutes(ctx)
+	if err != nil {
+		r.log.Errorf(ctx, "failed to list routes of shard %d: %s", shard, err)
+		return
+	}
+	for _, route := range routes {
+		if r.shardManager.ShardOf(route.Namespace(), route.Name()) != shard {
+			continue
+		}
+		select {
+		case events <- event.GenericEvent{Object: route.K8sObject()}:
+		case <-ctx.Done():
+			return
+		}
+	}
 }
 
-func (r *routeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *routeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reconcileErr error) {
 	ctx = gwlog.StartReconcileTrace(ctx, r.log, "route", req.Name, req.Namespace)
 	defer func() {
-		gwlog.EndReconcileTrace(ctx, r.log)
+		gwlog.EndReconcileTrace(ctx, r.log, reconcileErr)
 	}()
 
 	recErr := r.reconcile(ctx, req)
-	if recErr != nil {
+	var requeueAfter *lattice_runtime.RequeueNeededAfter
+	if recErr != nil && !errors.As(recErr, &requeueAfter) {
 		r.log.Infow(ctx, "reconcile error", "name", req.Name, "message", recErr.Error())
 	}
 	return lattice_runtime.HandleReconcileError(recErr)
 }
 
 func (r *routeReconciler) reconcile(ctx context.Context, req ctrl.Request) error {
+	if r.shardManager != nil && !r.shardManager.Owns(req.Namespace, req.Name) {
+		r.log.Debugf(ctx, "Route %s is in shard %d, not owned by this replica",
+			req.NamespacedName, r.shardManager.ShardOf(req.Namespace, req.Name))
+		return nil
+	}
+
 	route, err := r.getRoute(ctx, req)
 	if err != nil {
 		return client.IgnoreNotFound(err)
@@ -217,6 +291,19 @@ func (r *routeReconciler) reconcileDelete(ctx context.Context, req ctrl.Request,
 	return r.finalizerManager.RemoveFinalizers(ctx, route.K8sObject(), routeTypeToFinalizer[r.routeType])
 }
 
+func (r *routeReconciler) listRoutes(ctx context.Context) ([]core.Route, error) {
+	switch r.routeType {
+	case core.HttpRouteType:
+		return core.ListHTTPRoutes(ctx, r.client)
+	case core.GrpcRouteType:
+		return core.ListGRPCRoutes(ctx, r.client)
+	case core.TlsRouteType:
+		return core.ListTLSRoutes(ctx, r.client)
+	default:
+		return nil, fmt.Errorf("unknown route type for type %s", string(r.routeType))
+	}
+}
+
 func (r *routeReconciler) getRoute(ctx context.Context, req ctrl.Request) (core.Route, error) {
 	switch r.routeType {
 	case core.HttpRouteType:
@@ -347,7 +434,7 @@ func (r *routeReconciler) reconcileUpsert(ctx context.Context, req ctrl.Request,
 		r.log.Infof(ctx, "route: %s: %s", route.Name(), err)
 	}
 
-	backendRefIPFamiliesErr := r.validateBackendRefsIpFamilies(ctx, route)
+	backendRefIPFamiliesErr := gateway.ValidateBackendRefsIpFamilies(ctx, r.client, route)
 
 	if backendRefIPFamiliesErr != nil {
 		httpRouteOld := route.DeepCopy()
@@ -369,7 +456,8 @@ func (r *routeReconciler) reconcileUpsert(ctx context.Context, req ctrl.Request,
 		return backendRefIPFamiliesErr
 	}
 
-	if _, err := r.buildAndDeployModel(ctx, route); err != nil {
+	stack, err := r.buildAndDeployModel(ctx, route)
+	if err != nil {
 		if services.IsConflictError(err) {
 			// Stop reconciliation of this route if the route cannot be owned / has conflict
 			route.Status().UpdateParentRefs(route.Spec().ParentRefs()[0], config.LatticeGatewayControllerName)
@@ -406,10 +494,65 @@ func (r *routeReconciler) reconcileUpsert(ctx context.Context, req ctrl.Request,
 		return err
 	}
 
+	if r.latticeServiceStatusEnabled {
+		if err := r.updateLatticeServiceStatus(ctx, route, stack); err != nil {
+			return err
+		}
+	}
+
 	r.log.Infow(ctx, "reconciled", "name", req.Name)
+	if hasFailoverTargetGroups(stack) {
+		return lattice_runtime.NewRequeueNeededAfter("failover target health check", failoverCheckInterval)
+	}
+	if hasReplacedTargetGroups(stack) {
+		return lattice_runtime.NewRequeueNeededAfter("target group replacement", replacementCheckInterval)
+	}
 	return nil
 }
 
+// hasReplacedTargetGroups returns whether a target group of the stack replaces one the service still forwards
+// to, until the rules no longer reference the replaced target group
+func hasReplacedTargetGroups(stack core.Stack) bool {
+	var tgs []*model.TargetGroup
+	if err := stack.ListResources(&tgs); err != nil {
+		return false
+	}
+	for _, tg := range tgs {
+		if tg.Status != nil && tg.Status.ReplacedId != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// hasFailoverTargetGroups returns whether the rules or listeners of the stack send traffic to a ServiceImport
+// with ServiceImportPolicy failover
+func hasFailoverTargetGroups(stack core.Stack) bool {
+	var ruleActions []*model.RuleAction
+	var rules []*model.Rule
+	if err := stack.ListResources(&rules); err == nil {
+		for _, rule := range rules {
+			ruleActions = append(ruleActions, &rule.Spec.Action)
+		}
+	}
+	var listeners []*model.Listener
+	if err := stack.ListResources(&listeners); err == nil {
+		for _, listener := range listeners {
+			if listener.Spec.DefaultAction != nil && listener.Spec.DefaultAction.Forward != nil {
+				ruleActions = append(ruleActions, listener.Spec.DefaultAction.Forward)
+			}
+		}
+	}
+	for _, ruleAction := range ruleActions {
+		for _, ruleTg := range ruleAction.TargetGroups {
+			if ruleTg.Failover {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func (r *routeReconciler) updateRouteAnnotation(ctx context.Context, dns string, route core.Route) error {
 	r.log.Debugf(ctx, "Updating route %s-%s with DNS %s", route.Name(), route.Namespace(), dns)
 	routeOld := route.DeepCopy()
@@ -427,33 +570,6 @@ func (r *routeReconciler) updateRouteAnnotation(ctx context.Context, dns string,
 	return nil
 }
 
-func (r *routeReconciler) validateBackendRefsIpFamilies(ctx context.Context, route core.Route) error {
-	rules := route.Spec().Rules()
-
-	for _, rule := range rules {
-		backendRefs := rule.BackendRefs()
-
-		for _, backendRef := range backendRefs {
-			// For now we skip checking service import
-			if *backendRef.Kind() == "ServiceImport" {
-				continue
-			}
-
-			svc, err := gateway.GetServiceForBackendRef(ctx, r.client, route, backendRef)
-			if err != nil {
-				// Ignore error since Service might not be created yet
-				continue
-			}
-
-			if len(svc.Spec.IPFamilies) > 1 {
-				return errors.New("Invalid IpFamilies, Lattice Target Group doesn't support dual stack ip addresses")
-			}
-		}
-	}
-
-	return nil
-}
-
 var (
 	ErrValidation          = errors.New("validation")
 	ErrParentRefsNotFound  = errors.New("parentRefs are not found")
@@ -607,6 +723,10 @@ func (r *routeReconciler) validateBackedRefs(ctx context.Context, route core.Rou
 			if ref.Namespace() != nil {
 				namespace = string(*ref.Namespace())
 			}
+			if !config.IsNamespaceWatched(namespace) {
+				msg := fmt.Sprintf("backendRef name: %s, namespace %s is not watched by the controller", ref.Name(), namespace)
+				return r.newCondition(route, gwv1.RouteConditionResolvedRefs, gwv1.RouteReasonRefNotPermitted, msg), nil
+			}
 			objKey := types.NamespacedName{
 				Namespace: namespace,
 				Name:      string(ref.Name()),
@@ -628,11 +748,30 @@ func (r *routeReconciler) validateBackedRefs(ctx context.Context, route core.Rou
 					return r.newCondition(route, gwv1.RouteConditionResolvedRefs, gwv1.RouteReasonBackendNotFound, msg), nil
 				}
 			}
+			if svcImport, ok := obj.(*anv1alpha1.ServiceImport); ok && err == nil {
+				if reason := unresolvedServiceImportReason(svcImport); reason != "" {
+					msg := fmt.Sprintf("backendRef name: %s, %s", ref.Name(), reason)
+					return r.newCondition(route, gwv1.RouteConditionResolvedRefs, gwv1.RouteReasonBackendNotFound, msg), nil
+				}
+			}
 		}
 	}
 	return r.newCondition(route, gwv1.RouteConditionResolvedRefs, gwv1.RouteReasonResolvedRefs, ""), nil
 }
 
+// unresolvedServiceImportReason tells why the target groups of a ServiceImport cannot be resolved from its
+// status, empty when they can
+func unresolvedServiceImportReason(svcImport *anv1alpha1.ServiceImport) string {
+	if meta.IsStatusConditionFalse(svcImport.Status.Conditions, anv1alpha1.ServiceImportExported) {
+		return "no cluster exports the service"
+	}
+	if shared := meta.FindStatusCondition(svcImport.Status.Conditions, anv1alpha1.ServiceImportShared); shared != nil &&
+		shared.Status == metav1.ConditionFalse {
+		return shared.Message
+	}
+	return ""
+}
+
 func (r *routeReconciler) newCondition(route core.Route, t gwv1.RouteConditionType, reason gwv1.RouteConditionReason, msg string) metav1.Condition {
 	status := metav1.ConditionTrue
 	if reason != gwv1.RouteReasonAccepted && reason != gwv1.RouteReasonResolvedRefs {
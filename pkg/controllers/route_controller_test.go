@@ -48,7 +48,7 @@ func TestRouteReconciler_ReconcileCreates(t *testing.T) {
 	k8sClient := testclient.
 		NewClientBuilder().
 		WithScheme(k8sScheme).
-		WithStatusSubresource(&gwv1.HTTPRoute{}).
+		WithStatusSubresource(&gwv1.HTTPRoute{}, &anv1alpha1.LatticeServiceStatus{}).
 		Build()
 
 	gwClass := &gwv1.GatewayClass{
@@ -177,12 +177,14 @@ func TestRouteReconciler_ReconcileCreates(t *testing.T) {
 	mockLattice.EXPECT().ListTargetsAsList(gomock.Any(), gomock.Any()).Return(
 		[]*vpclattice.TargetSummary{
 			{
-				Id:   aws.String("192.0.2.22"),
-				Port: aws.Int64(8090),
+				Id:     aws.String("192.0.2.22"),
+				Port:   aws.Int64(8090),
+				Status: aws.String(vpclattice.TargetStatusHealthy),
 			},
 			{
-				Id:   aws.String("192.0.2.33"),
-				Port: aws.Int64(8090),
+				Id:     aws.String("192.0.2.33"),
+				Port:   aws.Int64(8090),
+				Status: aws.String(vpclattice.TargetStatusInitial),
 			},
 		}, nil)
 	mockLattice.EXPECT().RegisterTargetsWithContext(gomock.Any(), gomock.Any()).Return(
@@ -206,6 +208,8 @@ func TestRouteReconciler_ReconcileCreates(t *testing.T) {
 				Name: aws.String("sn-name"),
 			},
 		}, nil)
+	mockLattice.EXPECT().FindService(gomock.Any(), gomock.Any()).Return(
+		nil, mocks.NewNotFoundError("Service", "svc-name")) // never had headless services
 	mockLattice.EXPECT().FindService(gomock.Any(), gomock.Any()).Return(
 		nil, mocks.NewNotFoundError("Service", "svc-name")) // will trigger create
 	mockLattice.EXPECT().CreateServiceWithContext(gomock.Any(), gomock.Any()).Return(
@@ -234,6 +238,7 @@ func TestRouteReconciler_ReconcileCreates(t *testing.T) {
 		}, nil) // will trigger DNS Update
 
 	mockTagging.EXPECT().FindResourcesByTags(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
+	mockTagging.EXPECT().FindResourcesByTags(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil) // no replaced target group
 	mockLattice.EXPECT().ListTargetGroupsAsList(gomock.Any(), gomock.Any()).Return(
 		[]*vpclattice.TargetGroupSummary{}, nil).AnyTimes() // this will cause us to skip "unused delete" step
 	mockLattice.EXPECT().CreateTargetGroupWithContext(gomock.Any(), gomock.Any()).Return(
@@ -297,6 +302,8 @@ func TestRouteReconciler_ReconcileCreates(t *testing.T) {
 		stackDeployer:    deploy.NewLatticeServiceStackDeploy(gwlog.FallbackLogger, mockCloud, k8sClient),
 		stackMarshaller:  deploy.NewDefaultStackMarshaller(),
 		cloud:            mockCloud,
+
+		latticeServiceStatusEnabled: true,
 	}
 
 	routeName := k8s.NamespacedName(&route)
@@ -304,6 +311,26 @@ func TestRouteReconciler_ReconcileCreates(t *testing.T) {
 	assert.Nil(t, err)
 	assert.False(t, result.Requeue)
 
+	lss := &anv1alpha1.LatticeServiceStatus{}
+	lssName := routeName
+	lssName.Name = "my-route-httproute"
+	assert.Nil(t, k8sClient.Get(ctx, lssName, lss))
+	assert.Equal(t, gwv1.Kind("HTTPRoute"), lss.Spec.RouteRef.Kind)
+	assert.Equal(t, "my-route", lss.OwnerReferences[0].Name)
+	assert.Equal(t, "svc-arn", lss.Status.ServiceArn)
+	assert.Equal(t, "svc-id", lss.Status.ServiceId)
+	assert.Len(t, lss.Status.Listeners, 1)
+	assert.Equal(t, "listener-id", lss.Status.Listeners[0].Id)
+	assert.Equal(t, int64(80), lss.Status.Listeners[0].Port)
+	assert.Len(t, lss.Status.Listeners[0].Rules, 1)
+	assert.Equal(t, "rule-id", lss.Status.Listeners[0].Rules[0].Id)
+	assert.Equal(t, int64(1), lss.Status.Listeners[0].Rules[0].Priority)
+	assert.Equal(t, []string{"tg-id"}, lss.Status.Listeners[0].Rules[0].TargetGroupIds)
+	assert.Len(t, lss.Status.TargetGroups, 1)
+	assert.Equal(t, "tg-arn", lss.Status.TargetGroups[0].Arn)
+	assert.Equal(t, "my-service", lss.Status.TargetGroups[0].ServiceName)
+	assert.Equal(t, &anv1alpha1.LatticeTargetHealth{Healthy: 1, Initial: 1}, lss.Status.TargetGroups[0].TargetHealth)
+	assert.NotNil(t, lss.Status.LastUpdateTime)
 }
 
 func addOptionalCRDs(scheme *runtime.Scheme) {
@@ -323,4 +350,7 @@ func addOptionalCRDs(scheme *runtime.Scheme) {
 
 	scheme.AddKnownTypes(awsGatewayControllerCRDGroupVersion, &anv1alpha1.VpcAssociationPolicy{}, &anv1alpha1.VpcAssociationPolicyList{})
 	metav1.AddToGroupVersion(scheme, awsGatewayControllerCRDGroupVersion)
+
+	scheme.AddKnownTypes(awsGatewayControllerCRDGroupVersion, &anv1alpha1.LatticeServiceStatus{}, &anv1alpha1.LatticeServiceStatusList{})
+	metav1.AddToGroupVersion(scheme, awsGatewayControllerCRDGroupVersion)
 }
@@ -18,14 +18,22 @@ package controllers
 
 import (
 	"context"
+	"fmt"
+	"maps"
 
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
+	anv1alpha1 "github.com/aws/aws-application-networking-k8s/pkg/apis/applicationnetworking/v1alpha1"
 	"github.com/aws/aws-application-networking-k8s/pkg/aws"
+	"github.com/aws/aws-application-networking-k8s/pkg/config"
 	"github.com/aws/aws-application-networking-k8s/pkg/k8s"
 	lattice_runtime "github.com/aws/aws-application-networking-k8s/pkg/runtime"
 	"github.com/aws/aws-application-networking-k8s/pkg/utils/gwlog"
@@ -33,6 +41,13 @@ import (
 
 const (
 	serviceFinalizer = "service.ki8s.aws/resources"
+
+	// FederationAnnotation opts a ServiceExport into the VPC Lattice export. On a Service, the controller
+	// creates the ServiceExport of the Service, so that it is exported without a separate object.
+	FederationAnnotation      = k8s.AnnotationPrefix + "federation"
+	FederationAnnotationValue = "amazon-vpc-lattice"
+	// exportPortAnnotation is copied from the Service to the ServiceExport it creates
+	exportPortAnnotation = k8s.AnnotationPrefix + "port"
 )
 
 type serviceReconciler struct {
@@ -61,6 +76,9 @@ func RegisterServiceController(
 	}
 	err := ctrl.NewControllerManagedBy(mgr).
 		For(&corev1.Service{}).
+		Watches(&corev1.Service{}, lattice_runtime.EnqueuePriorityRequests(lattice_runtime.IsBeingDeleted)).
+		Owns(&anv1alpha1.ServiceExport{}).
+		WithOptions(controllerOptions("service")).
 		Complete(sr)
 	return err
 }
@@ -72,10 +90,10 @@ func RegisterServiceController(
 //+kubebuilder:rbac:groups=core,resources=endpoints/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=core,resources=configmaps, verbs=create;delete;patch;update;get;list;watch
 
-func (r *serviceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *serviceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reconcileErr error) {
 	ctx = gwlog.StartReconcileTrace(ctx, r.log, "service", req.Name, req.Namespace)
 	defer func() {
-		gwlog.EndReconcileTrace(ctx, r.log)
+		gwlog.EndReconcileTrace(ctx, r.log, reconcileErr)
 	}()
 
 	recErr := r.reconcile(ctx, req)
@@ -86,18 +104,112 @@ func (r *serviceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 }
 
 func (r *serviceReconciler) reconcile(ctx context.Context, req ctrl.Request) error {
-	// today, target registration is handled through the route controller
-	// if that proves not to be fast enough, we can look to do quicker
-	// target registration with the service controller
+	// target registration on endpoint changes is handled by the targets controller
 
 	svc := &corev1.Service{}
 	if err := r.client.Get(ctx, req.NamespacedName, svc); err != nil {
 		return client.IgnoreNotFound(err)
 	}
 	if !svc.DeletionTimestamp.IsZero() {
+		// the ServiceExport created for the annotation is garbage collected with the Service
 		r.finalizerManager.RemoveFinalizers(ctx, svc, serviceFinalizer)
+	} else if err := r.reconcileServiceExport(ctx, svc); err != nil {
+		return err
 	}
 
 	r.log.Infow(ctx, "reconciled", "name", req.Name)
 	return nil
 }
+
+// reconcileServiceExport creates the ServiceExport of a Service with the federation annotation, and deletes it
+// when the annotation is removed. The ServiceExport has the labels of the Service, so that it is watched when the
+// Service matches the watch label selector. ServiceExports created by users are never changed.
+func (r *serviceReconciler) reconcileServiceExport(ctx context.Context, svc *corev1.Service) error {
+	svcExport := &anv1alpha1.ServiceExport{}
+	err := r.client.Get(ctx, k8s.NamespacedName(svc), svcExport)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	exists := err == nil
+	exported := svc.Annotations[FederationAnnotation] == FederationAnnotationValue
+
+	if exists && !metav1.IsControlledBy(svcExport, svc) {
+		if exported {
+			r.log.Debugf(ctx, "ServiceExport %s-%s was not created for the Service annotation, skipping",
+				svc.Name, svc.Namespace)
+		}
+		return nil
+	}
+
+	if !exported {
+		if !exists {
+			return nil
+		}
+		if err := r.client.Delete(ctx, svcExport); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete ServiceExport %s-%s due to %w", svc.Name, svc.Namespace, err)
+		}
+		r.log.Infof(ctx, "Deleted ServiceExport %s-%s, the Service is not annotated anymore", svc.Name, svc.Namespace)
+		return nil
+	}
+
+	if !exists && !config.WatchLabelSelector.Matches(labels.Set(svc.Labels)) {
+		// the ServiceExport gets the labels of the Service, it would not be watched by the controller
+		r.log.Infof(ctx, "Service %s-%s does not match the watch label selector %s, not creating its ServiceExport",
+			svc.Name, svc.Namespace, config.WatchLabelSelector)
+		return nil
+	}
+
+	if !exists {
+		svcExport = &anv1alpha1.ServiceExport{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      svc.Name,
+				Namespace: svc.Namespace,
+			},
+		}
+	}
+	changed := setExportAnnotations(svc, svcExport)
+	if !maps.Equal(svcExport.Labels, svc.Labels) {
+		svcExport.Labels = maps.Clone(svc.Labels)
+		changed = true
+	}
+
+	if !exists {
+		if err := controllerutil.SetControllerReference(svc, svcExport, r.scheme); err != nil {
+			return err
+		}
+		if err := r.client.Create(ctx, svcExport); err != nil {
+			return fmt.Errorf("failed to create ServiceExport %s-%s due to %w", svc.Name, svc.Namespace, err)
+		}
+		r.log.Infof(ctx, "Created ServiceExport %s-%s for the Service annotation", svc.Name, svc.Namespace)
+		return nil
+	}
+	if !changed {
+		return nil
+	}
+	if err := r.client.Update(ctx, svcExport); err != nil {
+		return fmt.Errorf("failed to update ServiceExport %s-%s due to %w", svc.Name, svc.Namespace, err)
+	}
+	return nil
+}
+
+// setExportAnnotations copies the export annotations of the Service, returns false when they are up-to-date
+func setExportAnnotations(svc *corev1.Service, svcExport *anv1alpha1.ServiceExport) bool {
+	if svcExport.Annotations == nil {
+		svcExport.Annotations = map[string]string{}
+	}
+	changed := false
+	for _, key := range []string{FederationAnnotation, exportPortAnnotation} {
+		value, ok := svc.Annotations[key]
+		existing, existingOk := svcExport.Annotations[key]
+		if ok == existingOk && value == existing {
+			continue
+		}
+		if ok {
+			svcExport.Annotations[key] = value
+		} else {
+			delete(svcExport.Annotations, key)
+		}
+		changed = true
+	}
+	return changed
+}
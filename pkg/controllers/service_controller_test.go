@@ -0,0 +1,120 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	anv1alpha1 "github.com/aws/aws-application-networking-k8s/pkg/apis/applicationnetworking/v1alpha1"
+	"github.com/aws/aws-application-networking-k8s/pkg/config"
+	"github.com/aws/aws-application-networking-k8s/pkg/k8s"
+	"github.com/aws/aws-application-networking-k8s/pkg/utils/gwlog"
+)
+
+func TestServiceReconciler_ServiceExportAnnotation(t *testing.T) {
+	ctx := context.TODO()
+	k8sScheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(k8sScheme)
+	anv1alpha1.AddToScheme(k8sScheme)
+
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "svc",
+			UID:       "svc-uid",
+			Labels:    map[string]string{"team": "a"},
+			Annotations: map[string]string{
+				FederationAnnotation: FederationAnnotationValue,
+				exportPortAnnotation: "80",
+			},
+		},
+	}
+	userSvc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "ns",
+			Name:        "user",
+			Annotations: map[string]string{FederationAnnotation: FederationAnnotationValue},
+		},
+	}
+	userExport := &anv1alpha1.ServiceExport{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "ns",
+			Name:        "user",
+			Annotations: map[string]string{FederationAnnotation: FederationAnnotationValue, exportPortAnnotation: "8080"},
+		},
+	}
+	k8sClient := testclient.NewClientBuilder().WithScheme(k8sScheme).WithObjects(svc, userSvc, userExport).Build()
+
+	r := &serviceReconciler{
+		log:    gwlog.FallbackLogger,
+		client: k8sClient,
+		scheme: k8sScheme,
+	}
+	reconcileSvc := func(name string) {
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: k8s.NamespacedName(&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name}})})
+		assert.NoError(t, err)
+	}
+
+	// created for the annotation, owned by the Service
+	reconcileSvc("svc")
+	svcExport := &anv1alpha1.ServiceExport{}
+	assert.NoError(t, k8sClient.Get(ctx, k8s.NamespacedName(svc), svcExport))
+	assert.Equal(t, FederationAnnotationValue, svcExport.Annotations[FederationAnnotation])
+	assert.Equal(t, "80", svcExport.Annotations[exportPortAnnotation])
+	assert.Equal(t, map[string]string{"team": "a"}, svcExport.Labels)
+	assert.True(t, metav1.IsControlledBy(svcExport, svc))
+
+	// port annotation removed
+	assert.NoError(t, k8sClient.Get(ctx, k8s.NamespacedName(svc), svc))
+	delete(svc.Annotations, exportPortAnnotation)
+	assert.NoError(t, k8sClient.Update(ctx, svc))
+	reconcileSvc("svc")
+	assert.NoError(t, k8sClient.Get(ctx, k8s.NamespacedName(svc), svcExport))
+	assert.NotContains(t, svcExport.Annotations, exportPortAnnotation)
+
+	// labels follow the Service
+	svc.Labels["team"] = "b"
+	assert.NoError(t, k8sClient.Update(ctx, svc))
+	reconcileSvc("svc")
+	assert.NoError(t, k8sClient.Get(ctx, k8s.NamespacedName(svc), svcExport))
+	assert.Equal(t, map[string]string{"team": "b"}, svcExport.Labels)
+
+	// export annotation removed
+	delete(svc.Annotations, FederationAnnotation)
+	assert.NoError(t, k8sClient.Update(ctx, svc))
+	reconcileSvc("svc")
+	err := k8sClient.Get(ctx, k8s.NamespacedName(svc), svcExport)
+	assert.True(t, err != nil && client.IgnoreNotFound(err) == nil)
+
+	// ServiceExport created by a user, left untouched
+	reconcileSvc("user")
+	assert.NoError(t, k8sClient.Get(ctx, k8s.NamespacedName(userExport), svcExport))
+	assert.Equal(t, "8080", svcExport.Annotations[exportPortAnnotation])
+	assert.Empty(t, svcExport.OwnerReferences)
+
+	// not created for a Service outside of the watch label selector
+	defer func() { config.WatchLabelSelector = labels.Everything() }()
+	config.WatchLabelSelector = labels.SelectorFromSet(labels.Set{"team": "a"})
+	unwatchedSvc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "ns",
+			Name:        "unwatched",
+			Labels:      map[string]string{"team": "b"},
+			Annotations: map[string]string{FederationAnnotation: FederationAnnotationValue},
+		},
+	}
+	assert.NoError(t, k8sClient.Create(ctx, unwatchedSvc))
+	reconcileSvc("unwatched")
+	err = k8sClient.Get(ctx, k8s.NamespacedName(unwatchedSvc), svcExport)
+	assert.True(t, err != nil && client.IgnoreNotFound(err) == nil)
+}
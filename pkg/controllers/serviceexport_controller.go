@@ -33,14 +33,15 @@ import (
 	"github.com/aws/aws-application-networking-k8s/pkg/deploy"
 	"github.com/aws/aws-application-networking-k8s/pkg/gateway"
 	"github.com/aws/aws-application-networking-k8s/pkg/k8s"
+	"github.com/aws/aws-application-networking-k8s/pkg/model/core"
 	lattice_runtime "github.com/aws/aws-application-networking-k8s/pkg/runtime"
 	"github.com/aws/aws-application-networking-k8s/pkg/utils/gwlog"
-	discoveryv1 "k8s.io/api/discovery/v1"
 )
 
 type serviceExportReconciler struct {
 	log              gwlog.Logger
 	client           client.Client
+	cloud            aws.Cloud
 	Scheme           *runtime.Scheme
 	finalizerManager k8s.FinalizerManager
 	eventRecorder    record.EventRecorder
@@ -70,6 +71,7 @@ func RegisterServiceExportController(
 	r := &serviceExportReconciler{
 		log:              log,
 		client:           mgrClient,
+		cloud:            cloud,
 		Scheme:           scheme,
 		finalizerManager: finalizerManager,
 		modelBuilder:     modelBuilder,
@@ -82,8 +84,9 @@ func RegisterServiceExportController(
 
 	builder := ctrl.NewControllerManagedBy(mgr).
 		For(&anv1alpha1.ServiceExport{}).
+		Watches(&anv1alpha1.ServiceExport{}, lattice_runtime.EnqueuePriorityRequests(lattice_runtime.IsBeingDeleted)).
 		Watches(&corev1.Service{}, svcEventHandler.MapToServiceExport()).
-		Watches(&discoveryv1.EndpointSlice{}, svcEventHandler.MapToServiceExport())
+		WithOptions(controllerOptions("serviceexport"))
 
 	if ok, err := k8s.IsGVKSupported(mgr, anv1alpha1.GroupVersion.String(), anv1alpha1.TargetGroupPolicyKind); ok {
 		builder.Watches(&anv1alpha1.TargetGroupPolicy{}, svcEventHandler.MapToServiceExport())
@@ -101,10 +104,10 @@ func RegisterServiceExportController(
 //+kubebuilder:rbac:groups=application-networking.k8s.aws,resources=serviceexports/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=application-networking.k8s.aws,resources=serviceexports/finalizers,verbs=update
 
-func (r *serviceExportReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *serviceExportReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reconcileErr error) {
 	ctx = gwlog.StartReconcileTrace(ctx, r.log, "serviceexport", req.Name, req.Namespace)
 	defer func() {
-		gwlog.EndReconcileTrace(ctx, r.log)
+		gwlog.EndReconcileTrace(ctx, r.log, reconcileErr)
 	}()
 
 	recErr := r.reconcile(ctx, req)
@@ -121,13 +124,13 @@ func (r *serviceExportReconciler) reconcile(ctx context.Context, req ctrl.Reques
 		return client.IgnoreNotFound(err)
 	}
 
-	if srvExport.ObjectMeta.Annotations["application-networking.k8s.aws/federation"] != "amazon-vpc-lattice" {
+	if srvExport.ObjectMeta.Annotations[FederationAnnotation] != FederationAnnotationValue {
 		return nil
 	}
 	r.log.Debugf(ctx, "Found matching service export %s-%s", srvExport.Name, srvExport.Namespace)
 
 	if !srvExport.DeletionTimestamp.IsZero() {
-		if err := r.buildAndDeployModel(ctx, srvExport); err != nil {
+		if _, err := r.buildAndDeployModel(ctx, srvExport); err != nil {
 			return err
 		}
 		err := r.finalizerManager.RemoveFinalizers(ctx, srvExport, serviceExportFinalizer)
@@ -142,7 +145,14 @@ func (r *serviceExportReconciler) reconcile(ctx context.Context, req ctrl.Reques
 			return errors.New("TODO")
 		}
 
-		err := r.buildAndDeployModel(ctx, srvExport)
+		stack, err := r.buildAndDeployModel(ctx, srvExport)
+		if statusErr := r.updateStatus(ctx, srvExport, stack, err); statusErr != nil {
+			r.log.Infof(ctx, "Failed to update status of service export %s-%s due to %s",
+				srvExport.Name, srvExport.Namespace, statusErr)
+			if err == nil {
+				err = statusErr
+			}
+		}
 		return err
 	}
 }
@@ -150,7 +160,7 @@ func (r *serviceExportReconciler) reconcile(ctx context.Context, req ctrl.Reques
 func (r *serviceExportReconciler) buildAndDeployModel(
 	ctx context.Context,
 	srvExport *anv1alpha1.ServiceExport,
-) error {
+) (core.Stack, error) {
 	stack, err := r.modelBuilder.Build(ctx, srvExport)
 
 	if err != nil {
@@ -161,7 +171,7 @@ func (r *serviceExportReconciler) buildAndDeployModel(
 			k8s.GatewayEventReasonFailedBuildModel,
 			fmt.Sprintf("Failed BuildModel due to %s", err))
 
-		return err
+		return nil, err
 	}
 
 	json, err := r.stackMarshaller.Marshal(stack)
@@ -173,9 +183,9 @@ func (r *serviceExportReconciler) buildAndDeployModel(
 	if err := r.stackDeployer.Deploy(ctx, stack); err != nil {
 		r.eventRecorder.Event(srvExport, corev1.EventTypeWarning,
 			k8s.ServiceExportEventReasonFailedDeployModel, fmt.Sprintf("Failed deploy model due to %s", err))
-		return err
+		return stack, err
 	}
 
 	r.log.Debugf(ctx, "Successfully deployed model for service export %s-%s", srvExport.Name, srvExport.Namespace)
-	return err
+	return stack, nil
 }
@@ -0,0 +1,248 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	anv1alpha1 "github.com/aws/aws-application-networking-k8s/pkg/apis/applicationnetworking/v1alpha1"
+	"github.com/aws/aws-application-networking-k8s/pkg/aws/services"
+	"github.com/aws/aws-application-networking-k8s/pkg/config"
+	"github.com/aws/aws-application-networking-k8s/pkg/deploy/lattice"
+	"github.com/aws/aws-application-networking-k8s/pkg/k8s"
+	"github.com/aws/aws-application-networking-k8s/pkg/model/core"
+	model "github.com/aws/aws-application-networking-k8s/pkg/model/lattice"
+)
+
+const (
+	ServiceExportReasonValid                  = "Valid"
+	ServiceExportReasonServiceNotFound        = "ServiceNotFound"
+	ServiceExportReasonServiceTypeUnsupported = "ServiceTypeUnsupported"
+	ServiceExportReasonNoExportedPorts        = "NoExportedPorts"
+	ServiceExportReasonExported               = "Exported"
+	ServiceExportReasonNotExported            = "NotExported"
+	ServiceExportReasonFailed                 = "Failed"
+	ServiceExportReasonNoConflict             = "NoConflict"
+	ServiceExportReasonPortConflict           = "PortConflict"
+	ServiceExportReasonProtocolConflict       = "ProtocolConflict"
+)
+
+// clusterExport is what a cluster exports for a service, from the tags of its target groups
+type clusterExport struct {
+	ports            []string
+	protocolVersions []string
+}
+
+// updateStatus sets the Valid, Exported and Conflict conditions of the ServiceExport after a deployment
+func (r *serviceExportReconciler) updateStatus(ctx context.Context, srvExport *anv1alpha1.ServiceExport,
+	stack core.Stack, deployErr error) error {
+
+	var tgs []*model.TargetGroup
+	if stack != nil && deployErr == nil {
+		if err := stack.ListResources(&tgs); err != nil {
+			return err
+		}
+	}
+
+	validCondition, err := r.validCondition(ctx, srvExport, tgs, deployErr)
+	if err != nil {
+		return err
+	}
+	conditions := []anv1alpha1.ServiceExportCondition{validCondition, exportedCondition(tgs, deployErr)}
+	if len(tgs) > 0 {
+		conflictCondition, err := r.conflictCondition(ctx, srvExport, tgs)
+		if err != nil {
+			return err
+		}
+		conditions = append(conditions, conflictCondition)
+	}
+
+	// the targets synthesizer updates the TargetsHealthy condition during the deployment
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		latest := &anv1alpha1.ServiceExport{}
+		if err := r.client.Get(ctx, k8s.NamespacedName(srvExport), latest); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		changed := false
+		for _, condition := range conditions {
+			changed = lattice.SetServiceExportCondition(latest, condition) || changed
+		}
+		if !changed {
+			return nil
+		}
+		return r.client.Status().Update(ctx, latest)
+	})
+}
+
+// validCondition is True when the Service exists and has at least one exported port
+func (r *serviceExportReconciler) validCondition(ctx context.Context, srvExport *anv1alpha1.ServiceExport,
+	tgs []*model.TargetGroup, deployErr error) (anv1alpha1.ServiceExportCondition, error) {
+
+	svc := &corev1.Service{}
+	if err := r.client.Get(ctx, k8s.NamespacedName(srvExport), svc); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return anv1alpha1.ServiceExportCondition{}, err
+		}
+		return serviceExportCondition(anv1alpha1.ServiceExportValid, corev1.ConditionFalse,
+			ServiceExportReasonServiceNotFound, fmt.Sprintf("Service %s not found", k8s.NamespacedName(srvExport))), nil
+	}
+	if svc.Spec.Type == corev1.ServiceTypeExternalName {
+		return serviceExportCondition(anv1alpha1.ServiceExportValid, corev1.ConditionFalse,
+			ServiceExportReasonServiceTypeUnsupported, "Services of type ExternalName cannot be exported"), nil
+	}
+	if deployErr == nil && len(tgs) == 0 {
+		return serviceExportCondition(anv1alpha1.ServiceExportValid, corev1.ConditionFalse,
+			ServiceExportReasonNoExportedPorts, "No TCP port of the Service matches the port annotation"), nil
+	}
+	return serviceExportCondition(anv1alpha1.ServiceExportValid, corev1.ConditionTrue,
+		ServiceExportReasonValid, "Service is exportable"), nil
+}
+
+// exportedCondition lists the ARNs of the deployed target groups
+func exportedCondition(tgs []*model.TargetGroup, deployErr error) anv1alpha1.ServiceExportCondition {
+	if deployErr != nil {
+		return serviceExportCondition(anv1alpha1.ServiceExportExported, corev1.ConditionFalse,
+			ServiceExportReasonFailed, deployErr.Error())
+	}
+	var arns []string
+	for _, tg := range tgs {
+		if tg.Status != nil && tg.Status.Arn != "" {
+			arns = append(arns, tg.Status.Arn)
+		}
+	}
+	if len(arns) == 0 {
+		return serviceExportCondition(anv1alpha1.ServiceExportExported, corev1.ConditionFalse,
+			ServiceExportReasonNotExported, "No target group is exported")
+	}
+	slices.Sort(arns)
+	return serviceExportCondition(anv1alpha1.ServiceExportExported, corev1.ConditionTrue,
+		ServiceExportReasonExported, "Exported as target groups "+strings.Join(arns, ", "))
+}
+
+// conflictCondition compares the exported ports and protocol versions with the target groups the other
+// clusters export for a service of the same name. Clusters of earlier releases, without port tags, are ignored.
+func (r *serviceExportReconciler) conflictCondition(ctx context.Context, srvExport *anv1alpha1.ServiceExport,
+	tgs []*model.TargetGroup) (anv1alpha1.ServiceExportCondition, error) {
+
+	local := &clusterExport{}
+	for _, tg := range tgs {
+		local.add(tg.Spec.K8SServicePort, tg.Spec.K8SProtocolVersion)
+	}
+
+	others, err := r.findClusterExports(ctx, srvExport)
+	if err != nil {
+		return anv1alpha1.ServiceExportCondition{}, err
+	}
+
+	var portConflicts, protocolConflicts []string
+	clusters := make([]string, 0, len(others))
+	for cluster := range others {
+		clusters = append(clusters, cluster)
+	}
+	slices.Sort(clusters)
+	for _, cluster := range clusters {
+		other := others[cluster]
+		if !slices.Equal(local.ports, other.ports) {
+			portConflicts = append(portConflicts, fmt.Sprintf("cluster %s exports ports %s",
+				cluster, strings.Join(other.ports, ", ")))
+		}
+		if !slices.Equal(local.protocolVersions, other.protocolVersions) {
+			protocolConflicts = append(protocolConflicts, fmt.Sprintf("cluster %s exports protocol version %s",
+				cluster, strings.Join(other.protocolVersions, ", ")))
+		}
+	}
+
+	switch {
+	case len(portConflicts) > 0:
+		return serviceExportCondition(anv1alpha1.ServiceExportConflict, corev1.ConditionTrue,
+			ServiceExportReasonPortConflict, fmt.Sprintf("This cluster exports ports %s, %s",
+				strings.Join(local.ports, ", "), strings.Join(portConflicts, ", "))), nil
+	case len(protocolConflicts) > 0:
+		return serviceExportCondition(anv1alpha1.ServiceExportConflict, corev1.ConditionTrue,
+			ServiceExportReasonProtocolConflict, fmt.Sprintf("This cluster exports protocol version %s, %s",
+				strings.Join(local.protocolVersions, ", "), strings.Join(protocolConflicts, ", "))), nil
+	}
+	return serviceExportCondition(anv1alpha1.ServiceExportConflict, corev1.ConditionFalse,
+		ServiceExportReasonNoConflict, fmt.Sprintf("%d other clusters export the service", len(others))), nil
+}
+
+// findClusterExports returns what the other clusters export for the service, by cluster name
+func (r *serviceExportReconciler) findClusterExports(ctx context.Context,
+	srvExport *anv1alpha1.ServiceExport) (map[string]*clusterExport, error) {
+
+	arns, err := r.cloud.Tagging().FindResourcesByTags(ctx, services.ResourceTypeTargetGroup, services.Tags{
+		model.K8SSourceTypeKey:       aws.String(string(model.SourceTypeSvcExport)),
+		model.K8SServiceNameKey:      aws.String(srvExport.Name),
+		model.K8SServiceNamespaceKey: aws.String(srvExport.Namespace),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find target groups exported by other clusters: %w", err)
+	}
+	if len(arns) == 0 {
+		return nil, nil
+	}
+	tgTags, err := r.cloud.Tagging().GetTagsForArns(ctx, arns)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tags of target groups exported by other clusters: %w", err)
+	}
+
+	exports := map[string]*clusterExport{}
+	var legacy []string
+	for _, tags := range tgTags {
+		tagFields := model.TGTagFieldsFromTags(tags)
+		cluster := tagFields.K8SClusterName
+		if cluster == "" || cluster == config.ClusterName {
+			continue
+		}
+		if tagFields.K8SServicePort == "" {
+			legacy = append(legacy, cluster)
+			continue
+		}
+		export, ok := exports[cluster]
+		if !ok {
+			export = &clusterExport{}
+			exports[cluster] = export
+		}
+		export.add(tagFields.K8SServicePort, tagFields.K8SProtocolVersion)
+	}
+	for _, cluster := range legacy {
+		delete(exports, cluster)
+	}
+	return exports, nil
+}
+
+func (e *clusterExport) add(port, protocolVersion string) {
+	if !slices.Contains(e.ports, port) {
+		e.ports = append(e.ports, port)
+		slices.SortFunc(e.ports, comparePorts)
+	}
+	if !slices.Contains(e.protocolVersions, protocolVersion) {
+		e.protocolVersions = append(e.protocolVersions, protocolVersion)
+		slices.Sort(e.protocolVersions)
+	}
+}
+
+// comparePorts sorts ports numerically
+func comparePorts(a, b string) int {
+	if len(a) != len(b) {
+		return len(a) - len(b)
+	}
+	return strings.Compare(a, b)
+}
+
+func serviceExportCondition(conditionType anv1alpha1.ServiceExportConditionType, status corev1.ConditionStatus,
+	reason, message string) anv1alpha1.ServiceExportCondition {
+	return anv1alpha1.ServiceExportCondition{
+		Type:    conditionType,
+		Status:  status,
+		Reason:  aws.String(reason),
+		Message: aws.String(message),
+	}
+}
@@ -0,0 +1,200 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	anv1alpha1 "github.com/aws/aws-application-networking-k8s/pkg/apis/applicationnetworking/v1alpha1"
+	aws2 "github.com/aws/aws-application-networking-k8s/pkg/aws"
+	mocks "github.com/aws/aws-application-networking-k8s/pkg/aws/services"
+	"github.com/aws/aws-application-networking-k8s/pkg/config"
+	"github.com/aws/aws-application-networking-k8s/pkg/model/core"
+	model "github.com/aws/aws-application-networking-k8s/pkg/model/lattice"
+	"github.com/aws/aws-application-networking-k8s/pkg/utils/gwlog"
+)
+
+func findServiceExportCondition(svcExport *anv1alpha1.ServiceExport,
+	conditionType anv1alpha1.ServiceExportConditionType) *anv1alpha1.ServiceExportCondition {
+	for i := range svcExport.Status.Conditions {
+		if svcExport.Status.Conditions[i].Type == conditionType {
+			return &svcExport.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+func TestServiceExportUpdateStatus(t *testing.T) {
+	config.ClusterName = "cluster-a"
+	defer func() { config.ClusterName = "" }()
+
+	tgTags := func(cluster, port, protocolVersion string) mocks.Tags {
+		return model.TagsFromTGTagFields(model.TargetGroupTagFields{
+			K8SClusterName:      cluster,
+			K8SSourceType:       model.SourceTypeSvcExport,
+			K8SServiceName:      "svc",
+			K8SServiceNamespace: "ns",
+			K8SServicePort:      port,
+			K8SProtocolVersion:  protocolVersion,
+		})
+	}
+
+	tests := []struct {
+		name              string
+		svc               *corev1.Service
+		deployErr         error
+		ports             []string
+		otherTags         map[string]mocks.Tags
+		expectedValid     string
+		expectedExported  string
+		expectedConflict  string
+		expectedArnInMsg  string
+		expectNoConflicts bool
+	}{
+		{
+			name:             "exported without conflict",
+			svc:              &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "svc"}},
+			ports:            []string{"80", "443"},
+			otherTags:        map[string]mocks.Tags{"arn-b-80": tgTags("cluster-b", "80", "HTTP1"), "arn-b-443": tgTags("cluster-b", "443", "HTTP1")},
+			expectedValid:    ServiceExportReasonValid,
+			expectedExported: ServiceExportReasonExported,
+			expectedConflict: ServiceExportReasonNoConflict,
+			expectedArnInMsg: "arn-443, arn-80",
+		},
+		{
+			name:             "port conflict",
+			svc:              &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "svc"}},
+			ports:            []string{"80"},
+			otherTags:        map[string]mocks.Tags{"arn-b-8080": tgTags("cluster-b", "8080", "HTTP1")},
+			expectedValid:    ServiceExportReasonValid,
+			expectedExported: ServiceExportReasonExported,
+			expectedConflict: ServiceExportReasonPortConflict,
+		},
+		{
+			name:             "protocol conflict",
+			svc:              &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "svc"}},
+			ports:            []string{"80"},
+			otherTags:        map[string]mocks.Tags{"arn-b-80": tgTags("cluster-b", "80", "HTTP2")},
+			expectedValid:    ServiceExportReasonValid,
+			expectedExported: ServiceExportReasonExported,
+			expectedConflict: ServiceExportReasonProtocolConflict,
+		},
+		{
+			name:             "target groups of earlier releases are ignored",
+			svc:              &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "svc"}},
+			ports:            []string{"80"},
+			otherTags:        map[string]mocks.Tags{"arn-b": tgTags("cluster-b", "", "HTTP1")},
+			expectedValid:    ServiceExportReasonValid,
+			expectedExported: ServiceExportReasonExported,
+			expectedConflict: ServiceExportReasonNoConflict,
+		},
+		{
+			name:              "service not found",
+			deployErr:         errors.New("failed to find corresponding k8sService"),
+			expectedValid:     ServiceExportReasonServiceNotFound,
+			expectedExported:  ServiceExportReasonFailed,
+			expectNoConflicts: true,
+		},
+		{
+			name: "external name service",
+			svc: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "svc"},
+				Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeExternalName},
+			},
+			expectedValid:     ServiceExportReasonServiceTypeUnsupported,
+			expectedExported:  ServiceExportReasonNotExported,
+			expectNoConflicts: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			ctx := context.TODO()
+
+			k8sScheme := runtime.NewScheme()
+			clientgoscheme.AddToScheme(k8sScheme)
+			anv1alpha1.AddToScheme(k8sScheme)
+
+			svcExport := &anv1alpha1.ServiceExport{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "svc"}}
+			builder := testclient.NewClientBuilder().WithScheme(k8sScheme).
+				WithStatusSubresource(&anv1alpha1.ServiceExport{}).WithObjects(svcExport)
+			if tt.svc != nil {
+				builder.WithObjects(tt.svc)
+			}
+			k8sClient := builder.Build()
+
+			stack := core.NewDefaultStack(core.StackID{Namespace: "ns", Name: "svc"})
+			for _, port := range tt.ports {
+				tg, err := model.NewTargetGroup(stack, model.TargetGroupSpec{
+					VpcId:           "vpc-id",
+					Protocol:        "HTTP",
+					ProtocolVersion: "HTTP1",
+					IpAddressType:   "IPV4",
+					TargetGroupTagFields: model.TargetGroupTagFields{
+						K8SClusterName:      "cluster-a",
+						K8SSourceType:       model.SourceTypeSvcExport,
+						K8SServiceName:      "svc",
+						K8SServiceNamespace: "ns",
+						K8SServicePort:      port,
+						K8SProtocolVersion:  "HTTP1",
+					},
+				})
+				assert.NoError(t, err)
+				tg.Status = &model.TargetGroupStatus{Arn: "arn-" + port, Id: "tg-" + port}
+			}
+
+			mockCloud := aws2.NewMockCloud(c)
+			mockTagging := mocks.NewMockTagging(c)
+			mockCloud.EXPECT().Tagging().Return(mockTagging).AnyTimes()
+			if !tt.expectNoConflicts {
+				otherArns := []string{"arn-own"}
+				for arn := range tt.otherTags {
+					otherArns = append(otherArns, arn)
+				}
+				tags := map[string]mocks.Tags{"arn-own": tgTags("cluster-a", "80", "HTTP1")}
+				for arn, otherTags := range tt.otherTags {
+					tags[arn] = otherTags
+				}
+				mockTagging.EXPECT().FindResourcesByTags(gomock.Any(), mocks.ResourceTypeTargetGroup, gomock.Any()).
+					Return(otherArns, nil)
+				mockTagging.EXPECT().GetTagsForArns(gomock.Any(), gomock.Any()).Return(tags, nil)
+			}
+
+			r := &serviceExportReconciler{
+				log:    gwlog.FallbackLogger,
+				client: k8sClient,
+				cloud:  mockCloud,
+			}
+			assert.NoError(t, r.updateStatus(ctx, svcExport, stack, tt.deployErr))
+
+			updated := &anv1alpha1.ServiceExport{}
+			assert.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Namespace: "ns", Name: "svc"}, updated))
+
+			valid := findServiceExportCondition(updated, anv1alpha1.ServiceExportValid)
+			assert.Equal(t, tt.expectedValid, aws.StringValue(valid.Reason))
+			exported := findServiceExportCondition(updated, anv1alpha1.ServiceExportExported)
+			assert.Equal(t, tt.expectedExported, aws.StringValue(exported.Reason))
+			if tt.expectedArnInMsg != "" {
+				assert.Contains(t, aws.StringValue(exported.Message), tt.expectedArnInMsg)
+			}
+			conflict := findServiceExportCondition(updated, anv1alpha1.ServiceExportConflict)
+			if tt.expectNoConflicts {
+				assert.Nil(t, conflict)
+			} else {
+				assert.Equal(t, tt.expectedConflict, aws.StringValue(conflict.Reason))
+			}
+		})
+	}
+}
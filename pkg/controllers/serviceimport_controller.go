@@ -19,6 +19,7 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"time"
 
 	anv1alpha1 "github.com/aws/aws-application-networking-k8s/pkg/apis/applicationnetworking/v1alpha1"
 	"github.com/aws/aws-application-networking-k8s/pkg/utils/gwlog"
@@ -28,8 +29,13 @@ import (
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	"github.com/aws/aws-application-networking-k8s/pkg/aws"
+	"github.com/aws/aws-application-networking-k8s/pkg/deploy/lattice"
 	"github.com/aws/aws-application-networking-k8s/pkg/k8s"
+	lattice_runtime "github.com/aws/aws-application-networking-k8s/pkg/runtime"
 )
 
 type serviceImportReconciler struct {
@@ -38,14 +44,20 @@ type serviceImportReconciler struct {
 	Scheme           *runtime.Scheme
 	finalizerManager k8s.FinalizerManager
 	eventRecorder    record.EventRecorder
+	tgManager        lattice.TargetGroupManager
 }
 
 const (
 	serviceImportFinalizer = "serviceimport.k8s.aws/resource"
+
+	// serviceImportRefreshInterval is the period of the exporting cluster lookups, the exports of
+	// the other clusters cannot be watched
+	serviceImportRefreshInterval = 5 * time.Minute
 )
 
 func RegisterServiceImportController(
 	log gwlog.Logger,
+	cloud aws.Cloud,
 	mgr ctrl.Manager,
 	finalizerManager k8s.FinalizerManager,
 ) error {
@@ -59,10 +71,19 @@ func RegisterServiceImportController(
 		Scheme:           scheme,
 		finalizerManager: finalizerManager,
 		eventRecorder:    eventRecorder,
+		tgManager:        lattice.NewTargetGroupManager(log, cloud),
 	}
 
+	// the ServiceExports of this cluster export the ServiceImport of the same name
+	svcExportHandler := handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []reconcile.Request {
+		return []reconcile.Request{{NamespacedName: k8s.NamespacedName(obj)}}
+	})
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&anv1alpha1.ServiceImport{}).
+		Watches(&anv1alpha1.ServiceImport{}, lattice_runtime.EnqueuePriorityRequests(lattice_runtime.IsBeingDeleted)).
+		Watches(&anv1alpha1.ServiceExport{}, svcExportHandler).
+		WithOptions(controllerOptions("serviceimport")).
 		Complete(r)
 }
 
@@ -70,10 +91,10 @@ func RegisterServiceImportController(
 //+kubebuilder:rbac:groups=application-networking.k8s.aws,resources=serviceimports/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=application-networking.k8s.aws,resources=serviceimports/finalizers,verbs=update
 
-func (r *serviceImportReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *serviceImportReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reconcileErr error) {
 	ctx = gwlog.StartReconcileTrace(ctx, r.log, "serviceimport", req.Name, req.Namespace)
 	defer func() {
-		gwlog.EndReconcileTrace(ctx, r.log)
+		gwlog.EndReconcileTrace(ctx, r.log, reconcileErr)
 	}()
 
 	serviceImport := &anv1alpha1.ServiceImport{}
@@ -94,6 +115,11 @@ func (r *serviceImportReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		}
 		r.log.Info(ctx, "Adding/Updating")
 
-		return ctrl.Result{}, nil
+		if err := r.updateStatus(ctx, serviceImport); err != nil {
+			r.log.Infow(ctx, "reconcile error", "name", req.Name, "message", err.Error())
+			return lattice_runtime.HandleReconcileError(err)
+		}
+		return lattice_runtime.HandleReconcileError(
+			lattice_runtime.NewRequeueNeededAfter("refresh exporting clusters", serviceImportRefreshInterval))
 	}
 }
@@ -0,0 +1,255 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	anv1alpha1 "github.com/aws/aws-application-networking-k8s/pkg/apis/applicationnetworking/v1alpha1"
+	pkg_aws "github.com/aws/aws-application-networking-k8s/pkg/aws"
+	"github.com/aws/aws-application-networking-k8s/pkg/aws/services"
+	"github.com/aws/aws-application-networking-k8s/pkg/config"
+	model "github.com/aws/aws-application-networking-k8s/pkg/model/lattice"
+	"github.com/aws/aws-application-networking-k8s/pkg/utils/gwlog"
+)
+
+const (
+	// ServiceImportDiscoveredLabel marks the ServiceImports created by the discovery, the others are left untouched
+	ServiceImportDiscoveredLabel = "application-networking.k8s.aws/discovered"
+)
+
+// ServiceImportDiscovery periodically finds the target groups of the ServiceExports of all the clusters of the
+// account, and maintains a ServiceImport for every exported service in the namespaces of
+// SERVICE_IMPORT_DISCOVERY_NAMESPACES. ServiceImports created by users are never changed.
+type ServiceImportDiscovery struct {
+	log    gwlog.Logger
+	client client.Client
+	// reader lists the ServiceImports from the API server, the cache only has those of the watch label selector
+	reader     client.Reader
+	cloud      pkg_aws.Cloud
+	namespaces []string
+	interval   time.Duration
+	// labels of the discovered ServiceImports, matching the watch label selector
+	labels map[string]string
+}
+
+// discoveredExport is a service exported by one or more clusters
+type discoveredExport struct {
+	ports    []int32
+	clusters []string
+}
+
+func RegisterServiceImportDiscovery(log gwlog.Logger, cloud pkg_aws.Cloud, mgr ctrl.Manager) error {
+	watchLabels, err := config.WatchLabels()
+	if err != nil {
+		return fmt.Errorf("discovered ServiceImports would not be watched: %w", err)
+	}
+	svcImportLabels := maps.Clone(watchLabels)
+	svcImportLabels[ServiceImportDiscoveredLabel] = "true"
+	return mgr.Add(&ServiceImportDiscovery{
+		log:        log,
+		client:     mgr.GetClient(),
+		reader:     mgr.GetAPIReader(),
+		cloud:      cloud,
+		namespaces: config.ServiceImportDiscoveryNamespaces,
+		interval:   config.ServiceImportDiscoveryInterval,
+		labels:     svcImportLabels,
+	})
+}
+
+// NeedLeaderElection is true, a single replica creates the ServiceImports
+func (d *ServiceImportDiscovery) NeedLeaderElection() bool {
+	return true
+}
+
+// Start runs the discovery until the context is done
+func (d *ServiceImportDiscovery) Start(ctx context.Context) error {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+	for {
+		if err := d.discover(ctx); err != nil {
+			d.log.Warnf(ctx, "ServiceImport discovery failed, will retry in %s: %s", d.interval, err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d *ServiceImportDiscovery) discover(ctx context.Context) error {
+	// ServiceImports are deleted when their service is missing from the exports, which are only
+	// complete when every target group and its tags were listed without error
+	exports, err := d.findExports(ctx)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, namespace := range d.namespaces {
+		if err := d.syncNamespace(ctx, namespace, exports); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%d namespaces failed, first error: %w", len(errs), errs[0])
+	}
+	return nil
+}
+
+// findExports returns the services exported in the discovery namespaces, from the tags of their target groups
+func (d *ServiceImportDiscovery) findExports(ctx context.Context) (map[types.NamespacedName]*discoveredExport, error) {
+	arns, err := d.cloud.Tagging().FindResourcesByTags(ctx, services.ResourceTypeTargetGroup, services.Tags{
+		model.K8SSourceTypeKey: aws.String(string(model.SourceTypeSvcExport)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find ServiceExport target groups: %w", err)
+	}
+	if len(arns) == 0 {
+		return nil, nil
+	}
+	tgTags, err := d.cloud.Tagging().GetTagsForArns(ctx, arns)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tags of ServiceExport target groups: %w", err)
+	}
+	if len(tgTags) < len(arns) {
+		return nil, fmt.Errorf("found tags of %d of %d ServiceExport target groups", len(tgTags), len(arns))
+	}
+
+	exports := map[types.NamespacedName]*discoveredExport{}
+	for _, tags := range tgTags {
+		tagFields := model.TGTagFieldsFromTags(tags)
+		if !tagFields.IsSourceTypeServiceExport() || !slices.Contains(d.namespaces, tagFields.K8SServiceNamespace) {
+			continue
+		}
+		name := types.NamespacedName{Namespace: tagFields.K8SServiceNamespace, Name: tagFields.K8SServiceName}
+		export, ok := exports[name]
+		if !ok {
+			export = &discoveredExport{}
+			exports[name] = export
+		}
+		if tagFields.K8SClusterName != "" && !slices.Contains(export.clusters, tagFields.K8SClusterName) {
+			export.clusters = append(export.clusters, tagFields.K8SClusterName)
+		}
+		// target groups of earlier releases have no port
+		if port, err := strconv.ParseInt(tagFields.K8SServicePort, 10, 32); err == nil && !slices.Contains(export.ports, int32(port)) {
+			export.ports = append(export.ports, int32(port))
+		}
+	}
+	for _, export := range exports {
+		slices.Sort(export.ports)
+		slices.Sort(export.clusters)
+	}
+	return exports, nil
+}
+
+// syncNamespace creates and updates the ServiceImports of the exports of a namespace, and deletes
+// the discovered ServiceImports no cluster exports anymore
+func (d *ServiceImportDiscovery) syncNamespace(ctx context.Context, namespace string,
+	exports map[types.NamespacedName]*discoveredExport) error {
+
+	svcImports := &anv1alpha1.ServiceImportList{}
+	if err := d.reader.List(ctx, svcImports, client.InNamespace(namespace)); err != nil {
+		return fmt.Errorf("failed to list ServiceImports in %s: %w", namespace, err)
+	}
+	existing := map[string]*anv1alpha1.ServiceImport{}
+	for i := range svcImports.Items {
+		existing[svcImports.Items[i].Name] = &svcImports.Items[i]
+	}
+
+	for name, export := range exports {
+		if name.Namespace != namespace {
+			continue
+		}
+		svcImport, ok := existing[name.Name]
+		if !ok {
+			if err := d.create(ctx, name, export); err != nil {
+				return err
+			}
+			continue
+		}
+		if svcImport.Labels[ServiceImportDiscoveredLabel] != "true" {
+			d.log.Debugf(ctx, "ServiceImport %s was not created by the discovery, skipping", name)
+			continue
+		}
+		if err := d.update(ctx, svcImport, export); err != nil {
+			return err
+		}
+	}
+
+	for _, svcImport := range existing {
+		name := types.NamespacedName{Namespace: namespace, Name: svcImport.Name}
+		if _, ok := exports[name]; ok || svcImport.Labels[ServiceImportDiscoveredLabel] != "true" {
+			continue
+		}
+		if err := d.client.Delete(ctx, svcImport); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete ServiceImport %s: %w", name, err)
+		}
+		d.log.Infof(ctx, "Deleted ServiceImport %s, the service is not exported anymore", name)
+	}
+	return nil
+}
+
+func (d *ServiceImportDiscovery) create(ctx context.Context, name types.NamespacedName, export *discoveredExport) error {
+	svcImport := &anv1alpha1.ServiceImport{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: name.Namespace,
+			Name:      name.Name,
+			Labels:    maps.Clone(d.labels),
+		},
+		Spec: anv1alpha1.ServiceImportSpec{
+			Type:  anv1alpha1.ClusterSetIP,
+			Ports: export.servicePorts(),
+		},
+	}
+	if err := d.client.Create(ctx, svcImport); err != nil {
+		if apierrors.IsAlreadyExists(err) {
+			// created since the list, it is updated on the next discovery
+			return nil
+		}
+		return fmt.Errorf("failed to create ServiceImport %s: %w", name, err)
+	}
+	d.log.Infof(ctx, "Created ServiceImport %s for the service exported by %v", name, export.clusters)
+	return nil
+}
+
+func (d *ServiceImportDiscovery) update(ctx context.Context, svcImport *anv1alpha1.ServiceImport, export *discoveredExport) error {
+	ports := export.servicePorts()
+	portsChanged := !slices.EqualFunc(svcImport.Spec.Ports, ports, func(a, b anv1alpha1.ServicePort) bool { return a.Port == b.Port })
+	labelsChanged := false
+	for key, value := range d.labels {
+		if existing, ok := svcImport.Labels[key]; !ok || existing != value {
+			svcImport.Labels[key] = value
+			labelsChanged = true
+		}
+	}
+	if !portsChanged && !labelsChanged {
+		return nil
+	}
+	svcImport.Spec.Ports = ports
+	if err := d.client.Update(ctx, svcImport); err != nil {
+		return fmt.Errorf("failed to update ServiceImport %s/%s: %w", svcImport.Namespace, svcImport.Name, err)
+	}
+	d.log.Infof(ctx, "Updated ServiceImport %s/%s for the service exported by %v", svcImport.Namespace, svcImport.Name, export.clusters)
+	return nil
+}
+
+func (e *discoveredExport) servicePorts() []anv1alpha1.ServicePort {
+	ports := []anv1alpha1.ServicePort{}
+	for _, port := range e.ports {
+		ports = append(ports, anv1alpha1.ServicePort{Port: port, Protocol: corev1.ProtocolTCP})
+	}
+	return ports
+}
@@ -0,0 +1,165 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	anv1alpha1 "github.com/aws/aws-application-networking-k8s/pkg/apis/applicationnetworking/v1alpha1"
+	aws2 "github.com/aws/aws-application-networking-k8s/pkg/aws"
+	mocks "github.com/aws/aws-application-networking-k8s/pkg/aws/services"
+	model "github.com/aws/aws-application-networking-k8s/pkg/model/lattice"
+	"github.com/aws/aws-application-networking-k8s/pkg/utils/gwlog"
+)
+
+func TestServiceImportDiscovery(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+	ctx := context.TODO()
+
+	k8sScheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(k8sScheme)
+	anv1alpha1.AddToScheme(k8sScheme)
+
+	discovered := map[string]string{ServiceImportDiscoveredLabel: "true"}
+	k8sClient := testclient.NewClientBuilder().WithScheme(k8sScheme).WithStatusSubresource(&anv1alpha1.ServiceImport{}).WithObjects(
+		// discovered earlier, gets the new port and cluster
+		&anv1alpha1.ServiceImport{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "updated", Labels: discovered},
+			Spec: anv1alpha1.ServiceImportSpec{
+				Type:  anv1alpha1.ClusterSetIP,
+				Ports: []anv1alpha1.ServicePort{{Port: 80, Protocol: corev1.ProtocolTCP}},
+			},
+		},
+		// created by a user, left untouched
+		&anv1alpha1.ServiceImport{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "user"},
+			Spec: anv1alpha1.ServiceImportSpec{
+				Type:  anv1alpha1.ClusterSetIP,
+				Ports: []anv1alpha1.ServicePort{{Port: 8080, Protocol: corev1.ProtocolTCP}},
+			},
+		},
+		// not exported anymore
+		&anv1alpha1.ServiceImport{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "deleted", Labels: discovered},
+			Spec:       anv1alpha1.ServiceImportSpec{Type: anv1alpha1.ClusterSetIP},
+		},
+	).Build()
+
+	tgTags := func(namespace, name, cluster, port string) mocks.Tags {
+		return model.TagsFromTGTagFields(model.TargetGroupTagFields{
+			K8SClusterName:      cluster,
+			K8SSourceType:       model.SourceTypeSvcExport,
+			K8SServiceName:      name,
+			K8SServiceNamespace: namespace,
+			K8SServicePort:      port,
+		})
+	}
+	mockCloud := aws2.NewMockCloud(c)
+	mockTagging := mocks.NewMockTagging(c)
+	mockCloud.EXPECT().Tagging().Return(mockTagging).AnyTimes()
+	mockTagging.EXPECT().FindResourcesByTags(gomock.Any(), mocks.ResourceTypeTargetGroup, gomock.Any()).
+		Return([]string{"arn-1", "arn-2", "arn-3", "arn-4", "arn-5", "arn-6"}, nil)
+	mockTagging.EXPECT().GetTagsForArns(gomock.Any(), gomock.Any()).Return(map[string]mocks.Tags{
+		"arn-1": tgTags("ns", "created", "cluster-b", "8080"),
+		"arn-2": tgTags("ns", "created", "cluster-a", "80"),
+		"arn-3": tgTags("ns", "updated", "cluster-a", "80"),
+		"arn-4": tgTags("ns", "updated", "cluster-b", "443"),
+		"arn-5": tgTags("ns", "user", "cluster-a", "80"),
+		"arn-6": tgTags("other-ns", "ignored", "cluster-a", "80"),
+	}, nil)
+
+	// labelled to match the watch label selector
+	watched := map[string]string{ServiceImportDiscoveredLabel: "true", "tenant": "a"}
+	d := &ServiceImportDiscovery{
+		log:        gwlog.FallbackLogger,
+		client:     k8sClient,
+		reader:     k8sClient,
+		cloud:      mockCloud,
+		namespaces: []string{"ns"},
+		labels:     watched,
+	}
+	assert.NoError(t, d.discover(ctx))
+
+	created := &anv1alpha1.ServiceImport{}
+	assert.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Namespace: "ns", Name: "created"}, created))
+	assert.Equal(t, watched, created.Labels)
+	assert.Equal(t, anv1alpha1.ClusterSetIP, created.Spec.Type)
+	assert.Equal(t, []anv1alpha1.ServicePort{{Port: 80, Protocol: corev1.ProtocolTCP}, {Port: 8080, Protocol: corev1.ProtocolTCP}}, created.Spec.Ports)
+
+	updated := &anv1alpha1.ServiceImport{}
+	assert.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Namespace: "ns", Name: "updated"}, updated))
+	assert.Equal(t, []anv1alpha1.ServicePort{{Port: 80, Protocol: corev1.ProtocolTCP}, {Port: 443, Protocol: corev1.ProtocolTCP}}, updated.Spec.Ports)
+	assert.Equal(t, watched, updated.Labels)
+
+	user := &anv1alpha1.ServiceImport{}
+	assert.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Namespace: "ns", Name: "user"}, user))
+	assert.Equal(t, []anv1alpha1.ServicePort{{Port: 8080, Protocol: corev1.ProtocolTCP}}, user.Spec.Ports)
+
+	err := k8sClient.Get(ctx, types.NamespacedName{Namespace: "ns", Name: "deleted"}, &anv1alpha1.ServiceImport{})
+	assert.True(t, err != nil && client.IgnoreNotFound(err) == nil)
+
+	err = k8sClient.Get(ctx, types.NamespacedName{Namespace: "other-ns", Name: "ignored"}, &anv1alpha1.ServiceImport{})
+	assert.True(t, err != nil && client.IgnoreNotFound(err) == nil)
+}
+
+func TestServiceImportDiscovery_FailedScan(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+	ctx := context.TODO()
+
+	k8sScheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(k8sScheme)
+	anv1alpha1.AddToScheme(k8sScheme)
+
+	k8sClient := testclient.NewClientBuilder().WithScheme(k8sScheme).WithObjects(
+		&anv1alpha1.ServiceImport{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "kept", Labels: map[string]string{ServiceImportDiscoveredLabel: "true"}},
+			Spec:       anv1alpha1.ServiceImportSpec{Type: anv1alpha1.ClusterSetIP},
+		},
+	).Build()
+
+	mockCloud := aws2.NewMockCloud(c)
+	mockTagging := mocks.NewMockTagging(c)
+	mockCloud.EXPECT().Tagging().Return(mockTagging).AnyTimes()
+	gomock.InOrder(
+		mockTagging.EXPECT().FindResourcesByTags(gomock.Any(), mocks.ResourceTypeTargetGroup, gomock.Any()).
+			Return(nil, errors.New("throttled")),
+		mockTagging.EXPECT().FindResourcesByTags(gomock.Any(), mocks.ResourceTypeTargetGroup, gomock.Any()).
+			Return([]string{"arn-1", "arn-2"}, nil),
+	)
+	// tags of only one of the target groups
+	mockTagging.EXPECT().GetTagsForArns(gomock.Any(), gomock.Any()).Return(map[string]mocks.Tags{
+		"arn-1": model.TagsFromTGTagFields(model.TargetGroupTagFields{
+			K8SClusterName:      "cluster-a",
+			K8SSourceType:       model.SourceTypeSvcExport,
+			K8SServiceName:      "other",
+			K8SServiceNamespace: "ns",
+		}),
+	}, nil)
+
+	d := &ServiceImportDiscovery{
+		log:        gwlog.FallbackLogger,
+		client:     k8sClient,
+		reader:     k8sClient,
+		cloud:      mockCloud,
+		namespaces: []string{"ns"},
+	}
+	for i := 0; i < 2; i++ {
+		assert.Error(t, d.discover(ctx))
+		// nothing is deleted or created unless the scan completed
+		assert.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Namespace: "ns", Name: "kept"}, &anv1alpha1.ServiceImport{}))
+		err := k8sClient.Get(ctx, types.NamespacedName{Namespace: "ns", Name: "other"}, &anv1alpha1.ServiceImport{})
+		assert.True(t, err != nil && client.IgnoreNotFound(err) == nil)
+	}
+}
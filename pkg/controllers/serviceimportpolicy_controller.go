@@ -0,0 +1,76 @@
+package controllers
+
+import (
+	"context"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	anv1alpha1 "github.com/aws/aws-application-networking-k8s/pkg/apis/applicationnetworking/v1alpha1"
+	"github.com/aws/aws-application-networking-k8s/pkg/k8s"
+	policy "github.com/aws/aws-application-networking-k8s/pkg/k8s/policyhelper"
+	lattice_runtime "github.com/aws/aws-application-networking-k8s/pkg/runtime"
+	"github.com/aws/aws-application-networking-k8s/pkg/utils/gwlog"
+)
+
+type (
+	SIP = anv1alpha1.ServiceImportPolicy
+)
+
+type ServiceImportPolicyController struct {
+	log    gwlog.Logger
+	client client.Client
+	ph     *policy.PolicyHandler[*SIP]
+}
+
+func RegisterServiceImportPolicyController(log gwlog.Logger, mgr ctrl.Manager) error {
+	if ok, err := k8s.IsGVKSupported(mgr, anv1alpha1.GroupVersion.String(), anv1alpha1.ServiceImportPolicyKind); !ok {
+		if err != nil {
+			return err
+		}
+		log.Infof(context.TODO(), "ServiceImportPolicy CRD is not installed, skipping controller")
+		return nil
+	}
+
+	ph := policy.NewServiceImportPolicyHandler(log, mgr.GetClient())
+	controller := &ServiceImportPolicyController{
+		log:    log,
+		client: mgr.GetClient(),
+		ph:     ph,
+	}
+
+	b := ctrl.NewControllerManagedBy(mgr).
+		For(&SIP{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&SIP{}, lattice_runtime.EnqueuePriorityRequests(lattice_runtime.IsBeingDeleted)).
+		WithOptions(controllerOptions("serviceimportpolicy"))
+	ph.AddWatchers(b, &anv1alpha1.ServiceImport{})
+
+	return b.Complete(controller)
+}
+
+func (c *ServiceImportPolicyController) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reconcileErr error) {
+	ctx = gwlog.StartReconcileTrace(ctx, c.log, "serviceimportpolicy", req.Name, req.Namespace)
+	defer func() {
+		gwlog.EndReconcileTrace(ctx, c.log, reconcileErr)
+	}()
+
+	siPolicy := &SIP{}
+	err := c.client.Get(ctx, req.NamespacedName, siPolicy)
+	if err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	c.log.Infow(ctx, "reconcile service import policy", "req", req, "targetRef", siPolicy.Spec.TargetRef)
+
+	_, err = c.ph.ValidateAndUpdateCondition(ctx, siPolicy)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	c.log.Infow(ctx, "reconciled service import policy",
+		"req", req,
+		"targetRef", siPolicy.Spec.TargetRef,
+	)
+	return ctrl.Result{}, nil
+}
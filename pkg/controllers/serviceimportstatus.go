@@ -0,0 +1,180 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	anv1alpha1 "github.com/aws/aws-application-networking-k8s/pkg/apis/applicationnetworking/v1alpha1"
+	"github.com/aws/aws-application-networking-k8s/pkg/deploy/lattice"
+)
+
+const (
+	ServiceImportReasonExported         = "Exported"
+	ServiceImportReasonNoExporters      = "NoExporters"
+	ServiceImportReasonPortsValid       = "PortsValid"
+	ServiceImportReasonPortsNotExported = "PortsNotExported"
+	ServiceImportReasonShared           = "Shared"
+	ServiceImportReasonNotShared        = "NotShared"
+	ServiceImportReasonAccountAccess    = "AccountAccessFailed"
+)
+
+// updateStatus fills status.clusters with the clusters exporting the service, and spec.ports with the
+// exported ports when empty. The Exported and PortsValid conditions report missing exporters and ports,
+// the Shared condition the target groups of another account missing a resource share.
+func (r *serviceImportReconciler) updateStatus(ctx context.Context, svcImport *anv1alpha1.ServiceImport) error {
+	accountId := svcImport.AccountId()
+	clusters, err := r.tgManager.FindSvcExportClusters(ctx, svcImport.Namespace, svcImport.Name, accountId)
+	if err != nil {
+		accessErr := &lattice.AccountAccessError{}
+		if !errors.As(err, &accessErr) {
+			return fmt.Errorf("failed to find the clusters exporting %s/%s: %w", svcImport.Namespace, svcImport.Name, err)
+		}
+		// the status of the exports is unknown until the account can be accessed
+		r.log.Infof(ctx, "Failed to look up the exports of ServiceImport %s/%s: %s", svcImport.Namespace, svcImport.Name, err)
+		status := svcImport.Status.DeepCopy()
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+			Type:               anv1alpha1.ServiceImportShared,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: svcImport.Generation,
+			Reason:             ServiceImportReasonAccountAccess,
+			Message:            err.Error(),
+		})
+		return r.patchStatus(ctx, svcImport, status)
+	}
+
+	var exportedPorts []int32
+	for _, cluster := range clusters {
+		for _, port := range cluster.Ports {
+			if !slices.Contains(exportedPorts, port) {
+				exportedPorts = append(exportedPorts, port)
+			}
+		}
+	}
+	slices.Sort(exportedPorts)
+
+	if len(svcImport.Spec.Ports) == 0 && len(exportedPorts) > 0 {
+		for _, port := range exportedPorts {
+			svcImport.Spec.Ports = append(svcImport.Spec.Ports, anv1alpha1.ServicePort{Port: port, Protocol: corev1.ProtocolTCP})
+		}
+		if err := r.client.Update(ctx, svcImport); err != nil {
+			return fmt.Errorf("failed to update ports of ServiceImport %s/%s: %w", svcImport.Namespace, svcImport.Name, err)
+		}
+		r.log.Infof(ctx, "Set ports of ServiceImport %s/%s to the exported ports %v", svcImport.Namespace, svcImport.Name, exportedPorts)
+	}
+
+	status := svcImport.Status.DeepCopy()
+	status.Clusters = nil
+	var clusterNames []string
+	for _, cluster := range clusters {
+		status.Clusters = append(status.Clusters, anv1alpha1.ClusterStatus{Cluster: cluster.ClusterName, VpcId: cluster.VpcId})
+		clusterNames = append(clusterNames, cluster.ClusterName)
+	}
+
+	if len(clusters) == 0 {
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+			Type:               anv1alpha1.ServiceImportExported,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: svcImport.Generation,
+			Reason:             ServiceImportReasonNoExporters,
+			Message:            "No cluster exports the service",
+		})
+		meta.RemoveStatusCondition(&status.Conditions, anv1alpha1.ServiceImportPortsValid)
+	} else {
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+			Type:               anv1alpha1.ServiceImportExported,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: svcImport.Generation,
+			Reason:             ServiceImportReasonExported,
+			Message:            "Exported by clusters " + strings.Join(clusterNames, ", "),
+		})
+		meta.SetStatusCondition(&status.Conditions, portsCondition(svcImport, clusters, exportedPorts))
+	}
+
+	if accountId == "" {
+		meta.RemoveStatusCondition(&status.Conditions, anv1alpha1.ServiceImportShared)
+	} else {
+		meta.SetStatusCondition(&status.Conditions, sharedCondition(svcImport, accountId, clusters))
+	}
+	return r.patchStatus(ctx, svcImport, status)
+}
+
+func (r *serviceImportReconciler) patchStatus(ctx context.Context, svcImport *anv1alpha1.ServiceImport,
+	status *anv1alpha1.ServiceImportStatus) error {
+
+	if equality.Semantic.DeepEqual(&svcImport.Status, status) {
+		return nil
+	}
+	svcImport.Status = *status
+	if err := r.client.Status().Update(ctx, svcImport); err != nil {
+		return fmt.Errorf("failed to update status of ServiceImport %s/%s: %w", svcImport.Namespace, svcImport.Name, err)
+	}
+	return nil
+}
+
+// sharedCondition is False when a target group exported from the account of the annotation is not shared
+// with the account of the controller, routes cannot forward traffic to it
+func sharedCondition(svcImport *anv1alpha1.ServiceImport, accountId string,
+	clusters []lattice.SvcExportCluster) metav1.Condition {
+
+	var unshared []string
+	for _, cluster := range clusters {
+		unshared = append(unshared, cluster.UnsharedTargetGroups...)
+	}
+	if len(unshared) > 0 {
+		return metav1.Condition{
+			Type:               anv1alpha1.ServiceImportShared,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: svcImport.Generation,
+			Reason:             ServiceImportReasonNotShared,
+			Message: fmt.Sprintf("Target groups %s of account %s are not shared with this account, "+
+				"add them to a RAM resource share", strings.Join(unshared, ", "), accountId),
+		}
+	}
+	return metav1.Condition{
+		Type:               anv1alpha1.ServiceImportShared,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: svcImport.Generation,
+		Reason:             ServiceImportReasonShared,
+		Message:            fmt.Sprintf("Target groups of account %s are shared with this account", accountId),
+	}
+}
+
+// portsCondition is False when a port of the spec is not exported by any cluster. Clusters of earlier
+// releases do not tag their target groups with the port, their ports are not validated.
+func portsCondition(svcImport *anv1alpha1.ServiceImport, clusters []lattice.SvcExportCluster,
+	exportedPorts []int32) metav1.Condition {
+
+	condition := metav1.Condition{
+		Type:               anv1alpha1.ServiceImportPortsValid,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: svcImport.Generation,
+		Reason:             ServiceImportReasonPortsValid,
+		Message:            "All ports are exported",
+	}
+	if slices.ContainsFunc(clusters, func(c lattice.SvcExportCluster) bool { return len(c.Ports) == 0 }) {
+		condition.Message = "Some exporting clusters do not report their ports"
+		return condition
+	}
+
+	var missing []string
+	for _, port := range svcImport.Spec.Ports {
+		if !slices.Contains(exportedPorts, port.Port) {
+			missing = append(missing, strconv.Itoa(int(port.Port)))
+		}
+	}
+	if len(missing) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = ServiceImportReasonPortsNotExported
+		condition.Message = fmt.Sprintf("Ports %s are not exported by any cluster", strings.Join(missing, ", "))
+	}
+	return condition
+}
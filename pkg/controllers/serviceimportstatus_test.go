@@ -0,0 +1,166 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	anv1alpha1 "github.com/aws/aws-application-networking-k8s/pkg/apis/applicationnetworking/v1alpha1"
+	"github.com/aws/aws-application-networking-k8s/pkg/deploy/lattice"
+	"github.com/aws/aws-application-networking-k8s/pkg/k8s"
+	"github.com/aws/aws-application-networking-k8s/pkg/utils/gwlog"
+)
+
+func TestServiceImportUpdateStatus(t *testing.T) {
+	tests := []struct {
+		name               string
+		ports              []anv1alpha1.ServicePort
+		clusters           []lattice.SvcExportCluster
+		expectedPorts      []anv1alpha1.ServicePort
+		expectedClusters   []anv1alpha1.ClusterStatus
+		expectedExported   metav1.ConditionStatus
+		expectedPortsValid *metav1.ConditionStatus
+		accountId          string
+		findErr            error
+		expectedShared     string
+	}{
+		{
+			name:  "ports filled from the exports",
+			ports: nil,
+			clusters: []lattice.SvcExportCluster{
+				{ClusterName: "cluster-a", VpcId: "vpc-a", Ports: []int32{80}},
+				{ClusterName: "cluster-b", VpcId: "vpc-b", Ports: []int32{80, 443}},
+			},
+			expectedPorts: []anv1alpha1.ServicePort{{Port: 80, Protocol: corev1.ProtocolTCP}, {Port: 443, Protocol: corev1.ProtocolTCP}},
+			expectedClusters: []anv1alpha1.ClusterStatus{
+				{Cluster: "cluster-a", VpcId: "vpc-a"},
+				{Cluster: "cluster-b", VpcId: "vpc-b"},
+			},
+			expectedExported:   metav1.ConditionTrue,
+			expectedPortsValid: conditionStatusPtr(metav1.ConditionTrue),
+		},
+		{
+			name:               "port not exported",
+			ports:              []anv1alpha1.ServicePort{{Port: 8080}},
+			clusters:           []lattice.SvcExportCluster{{ClusterName: "cluster-a", VpcId: "vpc-a", Ports: []int32{80}}},
+			expectedPorts:      []anv1alpha1.ServicePort{{Port: 8080}},
+			expectedClusters:   []anv1alpha1.ClusterStatus{{Cluster: "cluster-a", VpcId: "vpc-a"}},
+			expectedExported:   metav1.ConditionTrue,
+			expectedPortsValid: conditionStatusPtr(metav1.ConditionFalse),
+		},
+		{
+			name:               "ports of earlier releases are not validated",
+			ports:              []anv1alpha1.ServicePort{{Port: 8080}},
+			clusters:           []lattice.SvcExportCluster{{ClusterName: "cluster-a", VpcId: "vpc-a"}},
+			expectedPorts:      []anv1alpha1.ServicePort{{Port: 8080}},
+			expectedClusters:   []anv1alpha1.ClusterStatus{{Cluster: "cluster-a", VpcId: "vpc-a"}},
+			expectedExported:   metav1.ConditionTrue,
+			expectedPortsValid: conditionStatusPtr(metav1.ConditionTrue),
+		},
+		{
+			name:             "no exporters",
+			ports:            []anv1alpha1.ServicePort{{Port: 80}},
+			expectedPorts:    []anv1alpha1.ServicePort{{Port: 80}},
+			expectedExported: metav1.ConditionFalse,
+		},
+		{
+			name:               "shared by another account",
+			ports:              []anv1alpha1.ServicePort{{Port: 80}},
+			accountId:          "111122223333",
+			clusters:           []lattice.SvcExportCluster{{ClusterName: "cluster-a", VpcId: "vpc-a", Ports: []int32{80}}},
+			expectedPorts:      []anv1alpha1.ServicePort{{Port: 80}},
+			expectedClusters:   []anv1alpha1.ClusterStatus{{Cluster: "cluster-a", VpcId: "vpc-a"}},
+			expectedExported:   metav1.ConditionTrue,
+			expectedPortsValid: conditionStatusPtr(metav1.ConditionTrue),
+			expectedShared:     ServiceImportReasonShared,
+		},
+		{
+			name:      "share missing",
+			ports:     []anv1alpha1.ServicePort{{Port: 80}},
+			accountId: "111122223333",
+			clusters: []lattice.SvcExportCluster{{ClusterName: "cluster-a", VpcId: "vpc-a", Ports: []int32{80},
+				UnsharedTargetGroups: []string{"arn-tg"}}},
+			expectedPorts:      []anv1alpha1.ServicePort{{Port: 80}},
+			expectedClusters:   []anv1alpha1.ClusterStatus{{Cluster: "cluster-a", VpcId: "vpc-a"}},
+			expectedExported:   metav1.ConditionTrue,
+			expectedPortsValid: conditionStatusPtr(metav1.ConditionTrue),
+			expectedShared:     ServiceImportReasonNotShared,
+		},
+		{
+			name:           "account role missing",
+			ports:          []anv1alpha1.ServicePort{{Port: 80}},
+			accountId:      "111122223333",
+			findErr:        &lattice.AccountAccessError{AccountId: "111122223333", Err: errors.New("no role")},
+			expectedPorts:  []anv1alpha1.ServicePort{{Port: 80}},
+			expectedShared: ServiceImportReasonAccountAccess,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			ctx := context.TODO()
+
+			k8sScheme := runtime.NewScheme()
+			clientgoscheme.AddToScheme(k8sScheme)
+			anv1alpha1.AddToScheme(k8sScheme)
+
+			svcImport := &anv1alpha1.ServiceImport{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "svc"},
+				Spec:       anv1alpha1.ServiceImportSpec{Type: anv1alpha1.ClusterSetIP, Ports: tt.ports},
+			}
+			if tt.accountId != "" {
+				svcImport.Annotations = map[string]string{anv1alpha1.ServiceImportAccountAnnotation: tt.accountId}
+			}
+			k8sClient := testclient.NewClientBuilder().WithScheme(k8sScheme).
+				WithStatusSubresource(&anv1alpha1.ServiceImport{}).WithObjects(svcImport).Build()
+
+			mockTGManager := lattice.NewMockTargetGroupManager(c)
+			mockTGManager.EXPECT().FindSvcExportClusters(ctx, "ns", "svc", tt.accountId).Return(tt.clusters, tt.findErr)
+
+			r := &serviceImportReconciler{
+				log:       gwlog.FallbackLogger,
+				client:    k8sClient,
+				tgManager: mockTGManager,
+			}
+			assert.NoError(t, r.updateStatus(ctx, svcImport))
+
+			updated := &anv1alpha1.ServiceImport{}
+			assert.NoError(t, k8sClient.Get(ctx, k8s.NamespacedName(svcImport), updated))
+			assert.Equal(t, tt.expectedPorts, updated.Spec.Ports)
+			assert.Equal(t, tt.expectedClusters, updated.Status.Clusters)
+			exported := meta.FindStatusCondition(updated.Status.Conditions, anv1alpha1.ServiceImportExported)
+			if tt.expectedExported == "" {
+				assert.Nil(t, exported)
+			} else {
+				assert.Equal(t, tt.expectedExported, exported.Status)
+			}
+			shared := meta.FindStatusCondition(updated.Status.Conditions, anv1alpha1.ServiceImportShared)
+			if tt.expectedShared == "" {
+				assert.Nil(t, shared)
+			} else {
+				assert.Equal(t, tt.expectedShared, shared.Reason)
+			}
+			portsValid := meta.FindStatusCondition(updated.Status.Conditions, anv1alpha1.ServiceImportPortsValid)
+			if tt.expectedPortsValid == nil {
+				assert.Nil(t, portsValid)
+			} else {
+				assert.Equal(t, *tt.expectedPortsValid, portsValid.Status)
+			}
+		})
+	}
+}
+
+func conditionStatusPtr(status metav1.ConditionStatus) *metav1.ConditionStatus {
+	return &status
+}
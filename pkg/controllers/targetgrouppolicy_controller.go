@@ -11,6 +11,7 @@ import (
 
 	anv1alpha1 "github.com/aws/aws-application-networking-k8s/pkg/apis/applicationnetworking/v1alpha1"
 	policy "github.com/aws/aws-application-networking-k8s/pkg/k8s/policyhelper"
+	lattice_runtime "github.com/aws/aws-application-networking-k8s/pkg/runtime"
 	"github.com/aws/aws-application-networking-k8s/pkg/utils/gwlog"
 )
 
@@ -33,17 +34,19 @@ func RegisterTargetGroupPolicyController(log gwlog.Logger, mgr ctrl.Manager) err
 	}
 
 	b := ctrl.NewControllerManagedBy(mgr).
-		For(&TGP{}, builder.WithPredicates(predicate.GenerationChangedPredicate{}))
+		For(&TGP{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&TGP{}, lattice_runtime.EnqueuePriorityRequests(lattice_runtime.IsBeingDeleted)).
+		WithOptions(controllerOptions("targetgrouppolicy"))
 	ph.AddWatchers(b, &corev1.Service{})
 	ph.AddWatchers(b, &anv1alpha1.ServiceExport{})
 
 	return b.Complete(controller)
 }
 
-func (c *TargetGroupPolicyController) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (c *TargetGroupPolicyController) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reconcileErr error) {
 	ctx = gwlog.StartReconcileTrace(ctx, c.log, "targetgrouppolicy", req.Name, req.Namespace)
 	defer func() {
-		gwlog.EndReconcileTrace(ctx, c.log)
+		gwlog.EndReconcileTrace(ctx, c.log, reconcileErr)
 	}()
 
 	tgPolicy := &TGP{}
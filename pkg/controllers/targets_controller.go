@@ -0,0 +1,356 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/service/vpclattice"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	anv1alpha1 "github.com/aws/aws-application-networking-k8s/pkg/apis/applicationnetworking/v1alpha1"
+	"github.com/aws/aws-application-networking-k8s/pkg/aws"
+	"github.com/aws/aws-application-networking-k8s/pkg/aws/services"
+	"github.com/aws/aws-application-networking-k8s/pkg/deploy/lattice"
+	"github.com/aws/aws-application-networking-k8s/pkg/gateway"
+	"github.com/aws/aws-application-networking-k8s/pkg/k8s"
+	"github.com/aws/aws-application-networking-k8s/pkg/model/core"
+	model "github.com/aws/aws-application-networking-k8s/pkg/model/lattice"
+	lattice_runtime "github.com/aws/aws-application-networking-k8s/pkg/runtime"
+	"github.com/aws/aws-application-networking-k8s/pkg/utils/gwlog"
+)
+
+// targetsReconciler keeps the targets of the Lattice target groups of a service in sync with its
+// EndpointSlices. It only touches targets, so endpoint changes do not redeploy the routes or
+// service exports the target groups belong to, and only the changed targets are registered or deregistered.
+type targetsReconciler struct {
+	log            gwlog.Logger
+	client         client.Client
+	cloud          aws.Cloud
+	tgIndex        *lattice.TargetGroupIndex
+	targetsManager lattice.TargetsManager
+	// latticeServiceStatusEnabled is true when the LatticeServiceStatus CRD is installed
+	latticeServiceStatusEnabled bool
+}
+
+func RegisterTargetsController(log gwlog.Logger, cloud aws.Cloud, mgr ctrl.Manager) error {
+	r := &targetsReconciler{
+		log:            log,
+		client:         mgr.GetClient(),
+		cloud:          cloud,
+		tgIndex:        lattice.NewTargetGroupIndex(log, cloud),
+		targetsManager: lattice.NewTargetsManager(log, cloud),
+	}
+	r.latticeServiceStatusEnabled, _ = k8s.IsGVKSupported(mgr, anv1alpha1.GroupVersion.String(), anv1alpha1.LatticeServiceStatusKind)
+	err := ctrl.NewControllerManagedBy(mgr).
+		Named("targets").
+		Watches(&discoveryv1.EndpointSlice{}, handler.EnqueueRequestsFromMapFunc(endpointSliceToService)).
+		WithOptions(controllerOptions("targets")).
+		Complete(r)
+	return err
+}
+
+func endpointSliceToService(_ context.Context, obj client.Object) []reconcile.Request {
+	svcName, ok := obj.GetLabels()[discoveryv1.LabelServiceName]
+	if !ok || svcName == "" {
+		return nil
+	}
+	return []reconcile.Request{{
+		NamespacedName: types.NamespacedName{Namespace: obj.GetNamespace(), Name: svcName},
+	}}
+}
+
+//+kubebuilder:rbac:groups=discovery.k8s.io,resources=endpointslices,verbs=get;list;watch
+
+func (r *targetsReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reconcileErr error) {
+	ctx = gwlog.StartReconcileTrace(ctx, r.log, "targets", req.Name, req.Namespace)
+	defer func() {
+		gwlog.EndReconcileTrace(ctx, r.log, reconcileErr)
+	}()
+
+	recErr := r.reconcile(ctx, req)
+	if recErr != nil {
+		r.log.Infow(ctx, "reconcile error", "name", req.Name, "message", recErr.Error())
+	}
+	return lattice_runtime.HandleReconcileError(recErr)
+}
+
+func (r *targetsReconciler) reconcile(ctx context.Context, req ctrl.Request) error {
+	svc := &corev1.Service{}
+	if err := r.client.Get(ctx, req.NamespacedName, svc); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if !svc.DeletionTimestamp.IsZero() {
+		// targets are deregistered along with the target groups by the route and service export controllers
+		return nil
+	}
+
+	tgs, err := r.tgIndex.Get(ctx, req.NamespacedName)
+	if err != nil {
+		return err
+	}
+	var errs error
+	for _, tg := range tgs {
+		if err := r.syncTargets(ctx, svc, tg); err != nil {
+			errs = errors.Join(errs, err)
+		}
+	}
+	if errs != nil {
+		// the target group may have been deleted or replaced since the last refresh
+		r.tgIndex.Invalidate()
+	}
+	return errs
+}
+
+// syncTargets builds the targets of a single target group the same way the owning route or service
+// export controller does, and registers the difference to the targets in Lattice.
+func (r *targetsReconciler) syncTargets(ctx context.Context, svc *corev1.Service, tg lattice.IndexedTargetGroup) error {
+	stack := core.NewDefaultStack(core.StackID(k8s.NamespacedName(svc)))
+	modelTg := &model.TargetGroup{
+		ResourceMeta: core.NewResourceMeta(stack, "AWS:VPCServiceNetwork::TargetGroup", tg.Status.Id),
+		Spec: model.TargetGroupSpec{
+			VpcId:                tg.VpcId,
+			Type:                 model.TargetGroupTypeIP,
+			TargetGroupTagFields: tg.TargetGroupTagFields,
+		},
+		Status: &model.TargetGroupStatus{
+			Name: tg.Status.Name,
+			Arn:  tg.Status.Arn,
+			Id:   tg.Status.Id,
+		},
+	}
+	if err := stack.AddResource(modelTg); err != nil {
+		return err
+	}
+
+	targetsBuilder := gateway.NewTargetsBuilder(r.log, r.client, stack)
+	if tg.IsSourceTypeServiceExport() {
+		svcExport := &anv1alpha1.ServiceExport{}
+		if err := r.client.Get(ctx, k8s.NamespacedName(svc), svcExport); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		if !svcExport.DeletionTimestamp.IsZero() {
+			return nil
+		}
+		if _, err := targetsBuilder.BuildForServiceExport(ctx, svcExport, tg.K8SServicePort, modelTg.ID()); err != nil {
+			return err
+		}
+	} else {
+		backendRef, err := r.findBackendRef(ctx, svc, tg)
+		if err != nil || backendRef == nil {
+			return err
+		}
+		if _, err := targetsBuilder.Build(ctx, svc, backendRef, modelTg.ID()); err != nil {
+			return err
+		}
+	}
+
+	synthesizer := lattice.NewTargetsSynthesizer(r.log, r.client, r.targetsManager, stack)
+	if err := synthesizer.Synthesize(ctx); err != nil {
+		return err
+	}
+	// refresh the target health conditions of the pods and of the service or service export,
+	// the route and service export controllers do not watch endpoints
+	if err := synthesizer.PostSynthesize(ctx); err != nil {
+		return err
+	}
+	if !tg.IsSourceTypeServiceExport() && r.latticeServiceStatusEnabled {
+		return r.updateLatticeServiceTargetHealth(ctx, stack, tg)
+	}
+	return nil
+}
+
+// updateLatticeServiceTargetHealth refreshes the target health of the target group in the
+// LatticeServiceStatus of its route. The rest of the status is written by the route controller.
+func (r *targetsReconciler) updateLatticeServiceTargetHealth(ctx context.Context, stack core.Stack, tg lattice.IndexedTargetGroup) error {
+	var resTargets []*model.Targets
+	if err := stack.ListResources(&resTargets); err != nil {
+		return err
+	}
+	if len(resTargets) == 0 || resTargets[0].Status == nil {
+		return nil
+	}
+	targetHealth := latticeTargetHealth(resTargets[0].Status)
+
+	var routeType core.RouteType
+	switch tg.K8SSourceType {
+	case model.SourceTypeGRPCRoute:
+		routeType = core.GrpcRouteType
+	case model.SourceTypeTLSRoute:
+		routeType = core.TlsRouteType
+	default:
+		routeType = core.HttpRouteType
+	}
+	lss := &anv1alpha1.LatticeServiceStatus{}
+	key := types.NamespacedName{Name: latticeServiceStatusName(tg.K8SRouteName, routeType), Namespace: tg.K8SRouteNamespace}
+	if err := r.client.Get(ctx, key, lss); err != nil {
+		// created by the route controller on its next deployment
+		return client.IgnoreNotFound(err)
+	}
+	for i := range lss.Status.TargetGroups {
+		tgStatus := &lss.Status.TargetGroups[i]
+		if tgStatus.Id != tg.Status.Id {
+			continue
+		}
+		if equality.Semantic.DeepEqual(tgStatus.TargetHealth, targetHealth) {
+			return nil
+		}
+		tgStatus.TargetHealth = targetHealth
+		now := metav1.Now()
+		lss.Status.LastUpdateTime = &now
+		if err := r.client.Status().Update(ctx, lss); err != nil {
+			return fmt.Errorf("failed to update LatticeServiceStatus %s due to %w", key, err)
+		}
+		return nil
+	}
+	return nil
+}
+
+// findBackendRef returns the backendRef of the target group route which points to the service and builds
+// the target group. Route target groups are not created per Service port, so backendRefs to different
+// ports of the service are told apart by the target group they build, e.g. their protocol, and an error is
+// returned when backendRefs to different ports build the same target group.
+// A target group replaced after a change of its protocol or protocol version is synced with the backendRef
+// building its replacement, as long as the rules still forward to it.
+// Returns nil when the route is gone or does not reference the service anymore, in which case
+// the route controller deletes the target group.
+func (r *targetsReconciler) findBackendRef(ctx context.Context, svc *corev1.Service, tg lattice.IndexedTargetGroup) (core.BackendRef, error) {
+	routeName := types.NamespacedName{Namespace: tg.K8SRouteNamespace, Name: tg.K8SRouteName}
+	var route core.Route
+	var err error
+	switch tg.K8SSourceType {
+	case model.SourceTypeHTTPRoute:
+		route, err = core.GetHTTPRoute(ctx, r.client, routeName)
+	case model.SourceTypeGRPCRoute:
+		route, err = core.GetGRPCRoute(ctx, r.client, routeName)
+	case model.SourceTypeTLSRoute:
+		route, err = core.GetTLSRoute(ctx, r.client, routeName)
+	default:
+		return nil, fmt.Errorf("unsupported source type %s for target group %s", tg.K8SSourceType, tg.Status.Id)
+	}
+	if err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+	if !route.DeletionTimestamp().IsZero() {
+		return nil, nil
+	}
+
+	// the backendRefs building the target group, or the target group which replaces this one, preferably
+	// with the same protocol
+	var matches, replacedBy, replacedBySameProtocol []core.BackendRef
+	var replacedByProtocol string
+	for _, rule := range route.Spec().Rules() {
+		for _, backendRef := range rule.BackendRefs() {
+			if backendRef.Kind() != nil && *backendRef.Kind() != "Service" {
+				continue
+			}
+			namespace := route.Namespace()
+			if backendRef.Namespace() != nil {
+				namespace = string(*backendRef.Namespace())
+			}
+			if namespace != svc.Namespace || string(backendRef.Name()) != svc.Name {
+				continue
+			}
+			spec, err := gateway.BuildBackendRefTargetGroupSpec(ctx, r.log, r.client, route, backendRef)
+			if err != nil {
+				return nil, err
+			}
+			if spec.Protocol == tg.Protocol && model.TagFieldsMatch(spec, tg.TargetGroupTagFields) {
+				matches = append(matches, backendRef)
+				continue
+			}
+			spec.K8SProtocolVersion = tg.K8SProtocolVersion
+			if !model.TagFieldsMatch(spec, tg.TargetGroupTagFields) {
+				continue
+			}
+			if spec.Protocol == tg.Protocol {
+				replacedBySameProtocol = append(replacedBySameProtocol, backendRef)
+			} else if len(replacedBy) == 0 || spec.Protocol == replacedByProtocol {
+				replacedBy = append(replacedBy, backendRef)
+				replacedByProtocol = spec.Protocol
+			}
+		}
+	}
+	if len(matches) > 0 {
+		return singlePortBackendRef(route, svc, tg, matches)
+	}
+	if len(replacedBySameProtocol) > 0 {
+		replacedBy = replacedBySameProtocol
+	}
+	if len(replacedBy) == 0 {
+		return nil, nil
+	}
+	backendRef, err := singlePortBackendRef(route, svc, tg, replacedBy)
+	if err != nil {
+		return nil, err
+	}
+	forwarded, err := r.isForwardedTo(ctx, tg)
+	if err != nil || !forwarded {
+		return nil, err
+	}
+	r.log.Debugf(ctx, "Syncing the targets of target group %s until its traffic shifts to its replacement", tg.Status.Id)
+	return backendRef, nil
+}
+
+// singlePortBackendRef returns the first of the backendRefs building a target group, or an error when they
+// point to different ports of the service, as the targets of one port would replace the ones of the other
+func singlePortBackendRef(route core.Route, svc *corev1.Service, tg lattice.IndexedTargetGroup,
+	backendRefs []core.BackendRef) (core.BackendRef, error) {
+
+	for _, backendRef := range backendRefs[1:] {
+		if !ptr.Equal(backendRef.Port(), backendRefs[0].Port()) {
+			return nil, fmt.Errorf("backendRefs of route %s/%s to ports %s and %s of service %s/%s build the same target group %s, "+
+				"set a different protocol for one of the ports with a TargetGroupPolicy",
+				route.Namespace(), route.Name(), backendRefPort(backendRefs[0]), backendRefPort(backendRef),
+				svc.Namespace, svc.Name, tg.Status.Id)
+		}
+	}
+	return backendRefs[0], nil
+}
+
+func backendRefPort(backendRef core.BackendRef) string {
+	if backendRef.Port() == nil {
+		return "<unset>"
+	}
+	return strconv.Itoa(int(*backendRef.Port()))
+}
+
+// isForwardedTo reads the target group again, the index does not tell whether the rules still forward to it
+func (r *targetsReconciler) isForwardedTo(ctx context.Context, tg lattice.IndexedTargetGroup) (bool, error) {
+	latticeTg, err := r.cloud.Lattice().GetTargetGroupWithContext(ctx, &vpclattice.GetTargetGroupInput{
+		TargetGroupIdentifier: &tg.Status.Id,
+	})
+	if err != nil {
+		if services.IsNotFoundError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return len(latticeTg.ServiceArns) > 0, nil
+}
@@ -0,0 +1,413 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/vpclattice"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gwv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gwv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	anv1alpha1 "github.com/aws/aws-application-networking-k8s/pkg/apis/applicationnetworking/v1alpha1"
+	aws2 "github.com/aws/aws-application-networking-k8s/pkg/aws"
+	mocks "github.com/aws/aws-application-networking-k8s/pkg/aws/services"
+	"github.com/aws/aws-application-networking-k8s/pkg/config"
+	"github.com/aws/aws-application-networking-k8s/pkg/deploy/lattice"
+	model "github.com/aws/aws-application-networking-k8s/pkg/model/lattice"
+	"github.com/aws/aws-application-networking-k8s/pkg/utils/gwlog"
+)
+
+func TestTargetsReconciler_RegistersEndpointDiff(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+	ctx := context.TODO()
+	config.VpcID = "vpc-1"
+	config.ClusterName = "cluster"
+
+	k8sScheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(k8sScheme)
+	gwv1.Install(k8sScheme)
+	discoveryv1.AddToScheme(k8sScheme)
+	anv1alpha1.AddToScheme(k8sScheme)
+
+	port := gwv1.PortNumber(80)
+	adminPort := gwv1.PortNumber(9090)
+	serviceKind := gwv1.Kind("Service")
+	k8sClient := testclient.NewClientBuilder().WithScheme(k8sScheme).WithObjects(
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "svc"},
+			Spec: corev1.ServiceSpec{
+				Ports:      []corev1.ServicePort{{Name: "http", Port: 80}, {Name: "admin", Port: 9090}},
+				IPFamilies: []corev1.IPFamily{corev1.IPv4Protocol},
+			},
+		},
+		&discoveryv1.EndpointSlice{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "ns",
+				Name:      "svc-abcde",
+				Labels:    map[string]string{discoveryv1.LabelServiceName: "svc"},
+			},
+			AddressType: discoveryv1.AddressTypeIPv4,
+			Ports: []discoveryv1.EndpointPort{
+				{Name: aws.String("http"), Port: aws.Int32(8080)},
+				{Name: aws.String("admin"), Port: aws.Int32(9090)},
+			},
+			Endpoints: []discoveryv1.Endpoint{
+				{Addresses: []string{"10.0.0.1"}},
+				{Addresses: []string{"10.0.0.2"}},
+			},
+		},
+		&gwv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "route"},
+			Spec: gwv1.HTTPRouteSpec{
+				// the admin port builds a TCP target group, only the http port builds the HTTP one
+				Rules: []gwv1.HTTPRouteRule{{
+					BackendRefs: []gwv1.HTTPBackendRef{{
+						BackendRef: gwv1.BackendRef{
+							BackendObjectReference: gwv1.BackendObjectReference{Kind: &serviceKind, Name: "svc", Port: &adminPort},
+						},
+					}},
+				}, {
+					BackendRefs: []gwv1.HTTPBackendRef{{
+						BackendRef: gwv1.BackendRef{
+							BackendObjectReference: gwv1.BackendObjectReference{Kind: &serviceKind, Name: "svc", Port: &port},
+						},
+					}},
+				}},
+			},
+		},
+		&anv1alpha1.LatticeServiceStatus{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "route-httproute"},
+			Status: anv1alpha1.LatticeServiceObservedStatus{
+				TargetGroups: []anv1alpha1.LatticeTargetGroupStatus{{Id: "tg-route"}},
+			},
+		},
+		&anv1alpha1.TargetGroupPolicy{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "tgp"},
+			Spec: anv1alpha1.TargetGroupPolicySpec{
+				TargetRef: &gwv1alpha2.NamespacedPolicyTargetReference{Kind: "Service", Name: "svc"},
+				PortOverrides: []anv1alpha1.TargetGroupPortOverride{{
+					Port:     &adminPort,
+					Protocol: aws.String(vpclattice.TargetGroupProtocolTcp),
+				}},
+			},
+		},
+		&anv1alpha1.ServiceExport{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "ns",
+				Name:        "svc",
+				Annotations: map[string]string{"application-networking.k8s.aws/port": "9090"},
+			},
+		},
+	).WithStatusSubresource(&corev1.Service{}, &anv1alpha1.ServiceExport{}, &anv1alpha1.LatticeServiceStatus{}).Build()
+
+	mockCloud := aws2.NewMockCloud(c)
+	mockLattice := mocks.NewMockLattice(c)
+	mockTagging := mocks.NewMockTagging(c)
+	mockCloud.EXPECT().Lattice().Return(mockLattice).AnyTimes()
+	mockCloud.EXPECT().Tagging().Return(mockTagging).AnyTimes()
+	mockCloud.EXPECT().Config().Return(aws2.CloudConfig{ClusterName: "cluster", VpcId: "vpc-1"}).AnyTimes()
+
+	tgTags := func(sourceType model.K8SSourceType, clusterName string) mocks.Tags {
+		return model.TagsFromTGTagFields(model.TargetGroupTagFields{
+			K8SClusterName:      clusterName,
+			K8SSourceType:       sourceType,
+			K8SServiceName:      "svc",
+			K8SServiceNamespace: "ns",
+			K8SRouteName:        "route",
+			K8SRouteNamespace:   "ns",
+			K8SProtocolVersion:  vpclattice.TargetGroupProtocolVersionHttp1,
+		})
+	}
+	mockLattice.EXPECT().ListTargetGroupsAsList(gomock.Any(), gomock.Any()).Return([]*vpclattice.TargetGroupSummary{
+		{Arn: aws.String("arn-route"), Id: aws.String("tg-route"), Protocol: aws.String(vpclattice.TargetGroupProtocolHttp), VpcIdentifier: aws.String("vpc-1"), Status: aws.String(vpclattice.TargetGroupStatusActive)},
+		{Arn: aws.String("arn-export"), Id: aws.String("tg-export"), VpcIdentifier: aws.String("vpc-1"), Status: aws.String(vpclattice.TargetGroupStatusActive)},
+		{Arn: aws.String("arn-other-cluster"), Id: aws.String("tg-other-cluster"), VpcIdentifier: aws.String("vpc-1"), Status: aws.String(vpclattice.TargetGroupStatusActive)},
+	}, nil).Times(1)
+	mockTagging.EXPECT().GetTagsForArns(gomock.Any(), gomock.Any()).Return(map[string]mocks.Tags{
+		"arn-route":         tgTags(model.SourceTypeHTTPRoute, "cluster"),
+		"arn-export":        tgTags(model.SourceTypeSvcExport, "cluster"),
+		"arn-other-cluster": tgTags(model.SourceTypeHTTPRoute, "other"),
+	}, nil).Times(1)
+
+	// route target group: 10.0.0.1 is registered, 10.0.0.3 is gone. Targets are listed again for their health.
+	mockLattice.EXPECT().ListTargetsAsList(gomock.Any(), &vpclattice.ListTargetsInput{TargetGroupIdentifier: aws.String("tg-route")}).
+		Return([]*vpclattice.TargetSummary{
+			{Id: aws.String("10.0.0.1"), Port: aws.Int64(8080), Status: aws.String(vpclattice.TargetStatusHealthy)},
+			{Id: aws.String("10.0.0.3"), Port: aws.Int64(8080), Status: aws.String(vpclattice.TargetStatusHealthy)},
+		}, nil).Times(4)
+	mockLattice.EXPECT().RegisterTargetsWithContext(gomock.Any(), &vpclattice.RegisterTargetsInput{
+		TargetGroupIdentifier: aws.String("tg-route"),
+		Targets:               []*vpclattice.Target{{Id: aws.String("10.0.0.2"), Port: aws.Int64(8080)}},
+	}).Return(&vpclattice.RegisterTargetsOutput{}, nil).Times(2)
+	mockLattice.EXPECT().DeregisterTargetsWithContext(gomock.Any(), &vpclattice.DeregisterTargetsInput{
+		TargetGroupIdentifier: aws.String("tg-route"),
+		Targets:               []*vpclattice.Target{{Id: aws.String("10.0.0.3"), Port: aws.Int64(8080)}},
+	}).Return(&vpclattice.DeregisterTargetsOutput{}, nil).Times(2)
+
+	// service export target group: all targets on the exported port are registered
+	mockLattice.EXPECT().ListTargetsAsList(gomock.Any(), &vpclattice.ListTargetsInput{TargetGroupIdentifier: aws.String("tg-export")}).
+		Return([]*vpclattice.TargetSummary{
+			{Id: aws.String("10.0.0.1"), Port: aws.Int64(9090), Status: aws.String(vpclattice.TargetStatusHealthy)},
+			{Id: aws.String("10.0.0.2"), Port: aws.Int64(9090), Status: aws.String(vpclattice.TargetStatusHealthy)},
+		}, nil).Times(4)
+
+	r := &targetsReconciler{
+		log:            gwlog.FallbackLogger,
+		client:         k8sClient,
+		cloud:          mockCloud,
+		tgIndex:        lattice.NewTargetGroupIndex(gwlog.FallbackLogger, mockCloud),
+		targetsManager: lattice.NewTargetsManager(gwlog.FallbackLogger, mockCloud),
+
+		latticeServiceStatusEnabled: true,
+	}
+
+	// the second reconcile is served from the index
+	for i := 0; i < 2; i++ {
+		result, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "svc"}})
+		assert.Nil(t, err)
+		assert.Zero(t, result)
+	}
+
+	// target health is refreshed without a deployment of the route or the service export
+	svc := &corev1.Service{}
+	assert.Nil(t, k8sClient.Get(ctx, types.NamespacedName{Namespace: "ns", Name: "svc"}, svc))
+	assert.NotNil(t, meta.FindStatusCondition(svc.Status.Conditions, lattice.ServiceTargetsHealthyConditionType))
+	svcExport := &anv1alpha1.ServiceExport{}
+	assert.Nil(t, k8sClient.Get(ctx, types.NamespacedName{Namespace: "ns", Name: "svc"}, svcExport))
+	assert.Len(t, svcExport.Status.Conditions, 1)
+	lss := &anv1alpha1.LatticeServiceStatus{}
+	assert.Nil(t, k8sClient.Get(ctx, types.NamespacedName{Namespace: "ns", Name: "route-httproute"}, lss))
+	assert.Equal(t, int32(2), lss.Status.TargetGroups[0].TargetHealth.Healthy)
+}
+
+func TestEndpointSliceToService(t *testing.T) {
+	epSlice := &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "svc-abcde",
+			Labels:    map[string]string{discoveryv1.LabelServiceName: "svc"},
+		},
+	}
+	assert.Equal(t, []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "svc"}}},
+		endpointSliceToService(context.TODO(), epSlice))
+
+	epSlice.Labels = nil
+	assert.Empty(t, endpointSliceToService(context.TODO(), epSlice))
+}
+
+func TestTargetsReconciler_SyncsReplacedTargetGroup(t *testing.T) {
+	tests := []struct {
+		name        string
+		serviceArns []*string
+	}{
+		{
+			name:        "replaced target group still forwarded to",
+			serviceArns: []*string{aws.String("svc-arn")},
+		},
+		{
+			name: "traffic shifted to the new target group",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			ctx := context.TODO()
+			config.VpcID = "vpc-1"
+			config.ClusterName = "cluster"
+
+			k8sScheme := runtime.NewScheme()
+			clientgoscheme.AddToScheme(k8sScheme)
+			gwv1.Install(k8sScheme)
+			discoveryv1.AddToScheme(k8sScheme)
+			anv1alpha1.AddToScheme(k8sScheme)
+
+			port := gwv1.PortNumber(80)
+			serviceKind := gwv1.Kind("Service")
+			k8sClient := testclient.NewClientBuilder().WithScheme(k8sScheme).WithObjects(
+				&corev1.Service{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "svc"},
+					Spec: corev1.ServiceSpec{
+						Ports:      []corev1.ServicePort{{Name: "http", Port: 80}},
+						IPFamilies: []corev1.IPFamily{corev1.IPv4Protocol},
+					},
+				},
+				&discoveryv1.EndpointSlice{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "ns",
+						Name:      "svc-abcde",
+						Labels:    map[string]string{discoveryv1.LabelServiceName: "svc"},
+					},
+					AddressType: discoveryv1.AddressTypeIPv4,
+					Ports:       []discoveryv1.EndpointPort{{Name: aws.String("http"), Port: aws.Int32(8080)}},
+					Endpoints:   []discoveryv1.Endpoint{{Addresses: []string{"10.0.0.1"}}},
+				},
+				&gwv1.HTTPRoute{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "route"},
+					Spec: gwv1.HTTPRouteSpec{
+						Rules: []gwv1.HTTPRouteRule{{
+							BackendRefs: []gwv1.HTTPBackendRef{{
+								BackendRef: gwv1.BackendRef{
+									BackendObjectReference: gwv1.BackendObjectReference{Kind: &serviceKind, Name: "svc", Port: &port},
+								},
+							}},
+						}},
+					},
+				},
+				// the protocol version changed from HTTP1 to HTTP2
+				&anv1alpha1.TargetGroupPolicy{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "tgp"},
+					Spec: anv1alpha1.TargetGroupPolicySpec{
+						TargetRef:       &gwv1alpha2.NamespacedPolicyTargetReference{Kind: "Service", Name: "svc"},
+						ProtocolVersion: aws.String(vpclattice.TargetGroupProtocolVersionHttp2),
+					},
+				},
+			).WithStatusSubresource(&corev1.Service{}).Build()
+
+			mockCloud := aws2.NewMockCloud(c)
+			mockLattice := mocks.NewMockLattice(c)
+			mockTagging := mocks.NewMockTagging(c)
+			mockCloud.EXPECT().Lattice().Return(mockLattice).AnyTimes()
+			mockCloud.EXPECT().Tagging().Return(mockTagging).AnyTimes()
+			mockCloud.EXPECT().Config().Return(aws2.CloudConfig{ClusterName: "cluster", VpcId: "vpc-1"}).AnyTimes()
+
+			tgTags := func(protocolVersion string) mocks.Tags {
+				return model.TagsFromTGTagFields(model.TargetGroupTagFields{
+					K8SClusterName:      "cluster",
+					K8SSourceType:       model.SourceTypeHTTPRoute,
+					K8SServiceName:      "svc",
+					K8SServiceNamespace: "ns",
+					K8SRouteName:        "route",
+					K8SRouteNamespace:   "ns",
+					K8SProtocolVersion:  protocolVersion,
+				})
+			}
+			mockLattice.EXPECT().ListTargetGroupsAsList(gomock.Any(), gomock.Any()).Return([]*vpclattice.TargetGroupSummary{
+				{Arn: aws.String("arn-new"), Id: aws.String("tg-new"), Protocol: aws.String(vpclattice.TargetGroupProtocolHttp), VpcIdentifier: aws.String("vpc-1"), Status: aws.String(vpclattice.TargetGroupStatusActive)},
+				{Arn: aws.String("arn-old"), Id: aws.String("tg-old"), Protocol: aws.String(vpclattice.TargetGroupProtocolHttp), VpcIdentifier: aws.String("vpc-1"), Status: aws.String(vpclattice.TargetGroupStatusActive)},
+			}, nil)
+			mockTagging.EXPECT().GetTagsForArns(gomock.Any(), gomock.Any()).Return(map[string]mocks.Tags{
+				"arn-new": tgTags(vpclattice.TargetGroupProtocolVersionHttp2),
+				"arn-old": tgTags(vpclattice.TargetGroupProtocolVersionHttp1),
+			}, nil)
+			mockLattice.EXPECT().GetTargetGroupWithContext(gomock.Any(), &vpclattice.GetTargetGroupInput{TargetGroupIdentifier: aws.String("tg-old")}).
+				Return(&vpclattice.GetTargetGroupOutput{Id: aws.String("tg-old"), ServiceArns: tt.serviceArns}, nil)
+
+			// the new target group gets the targets of the endpoints, and so does the replaced one until the traffic shift
+			tgIds := []string{"tg-new"}
+			if len(tt.serviceArns) > 0 {
+				tgIds = append(tgIds, "tg-old")
+			}
+			for _, tgId := range tgIds {
+				mockLattice.EXPECT().ListTargetsAsList(gomock.Any(), &vpclattice.ListTargetsInput{TargetGroupIdentifier: aws.String(tgId)}).
+					Return([]*vpclattice.TargetSummary{}, nil).Times(2)
+				mockLattice.EXPECT().RegisterTargetsWithContext(gomock.Any(), &vpclattice.RegisterTargetsInput{
+					TargetGroupIdentifier: aws.String(tgId),
+					Targets:               []*vpclattice.Target{{Id: aws.String("10.0.0.1"), Port: aws.Int64(8080)}},
+				}).Return(&vpclattice.RegisterTargetsOutput{}, nil)
+			}
+
+			r := &targetsReconciler{
+				log:            gwlog.FallbackLogger,
+				client:         k8sClient,
+				cloud:          mockCloud,
+				tgIndex:        lattice.NewTargetGroupIndex(gwlog.FallbackLogger, mockCloud),
+				targetsManager: lattice.NewTargetsManager(gwlog.FallbackLogger, mockCloud),
+			}
+			result, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "svc"}})
+			assert.Nil(t, err)
+			assert.Zero(t, result)
+		})
+	}
+}
+
+func TestTargetsReconciler_TwoPortsOfOneService(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+	ctx := context.TODO()
+	config.VpcID = "vpc-1"
+	config.ClusterName = "cluster"
+
+	k8sScheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(k8sScheme)
+	gwv1.Install(k8sScheme)
+	discoveryv1.AddToScheme(k8sScheme)
+	anv1alpha1.AddToScheme(k8sScheme)
+
+	// both ports build the same HTTP target group
+	port := gwv1.PortNumber(80)
+	otherPort := gwv1.PortNumber(8081)
+	serviceKind := gwv1.Kind("Service")
+	backendRef := func(port *gwv1.PortNumber) gwv1.HTTPBackendRef {
+		return gwv1.HTTPBackendRef{
+			BackendRef: gwv1.BackendRef{
+				BackendObjectReference: gwv1.BackendObjectReference{Kind: &serviceKind, Name: "svc", Port: port},
+			},
+		}
+	}
+	k8sClient := testclient.NewClientBuilder().WithScheme(k8sScheme).WithObjects(
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "svc"},
+			Spec: corev1.ServiceSpec{
+				Ports:      []corev1.ServicePort{{Name: "http", Port: 80}, {Name: "other", Port: 8081}},
+				IPFamilies: []corev1.IPFamily{corev1.IPv4Protocol},
+			},
+		},
+		&gwv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "route"},
+			Spec: gwv1.HTTPRouteSpec{
+				Rules: []gwv1.HTTPRouteRule{
+					{BackendRefs: []gwv1.HTTPBackendRef{backendRef(&port)}},
+					{BackendRefs: []gwv1.HTTPBackendRef{backendRef(&otherPort)}},
+				},
+			},
+		},
+	).WithStatusSubresource(&corev1.Service{}).Build()
+
+	mockCloud := aws2.NewMockCloud(c)
+	mockLattice := mocks.NewMockLattice(c)
+	mockTagging := mocks.NewMockTagging(c)
+	mockCloud.EXPECT().Lattice().Return(mockLattice).AnyTimes()
+	mockCloud.EXPECT().Tagging().Return(mockTagging).AnyTimes()
+	mockCloud.EXPECT().Config().Return(aws2.CloudConfig{ClusterName: "cluster", VpcId: "vpc-1"}).AnyTimes()
+
+	mockLattice.EXPECT().ListTargetGroupsAsList(gomock.Any(), gomock.Any()).Return([]*vpclattice.TargetGroupSummary{
+		{Arn: aws.String("arn-route"), Id: aws.String("tg-route"), Protocol: aws.String(vpclattice.TargetGroupProtocolHttp), VpcIdentifier: aws.String("vpc-1"), Status: aws.String(vpclattice.TargetGroupStatusActive)},
+	}, nil).AnyTimes()
+	mockTagging.EXPECT().GetTagsForArns(gomock.Any(), gomock.Any()).Return(map[string]mocks.Tags{
+		"arn-route": model.TagsFromTGTagFields(model.TargetGroupTagFields{
+			K8SClusterName:      "cluster",
+			K8SSourceType:       model.SourceTypeHTTPRoute,
+			K8SServiceName:      "svc",
+			K8SServiceNamespace: "ns",
+			K8SRouteName:        "route",
+			K8SRouteNamespace:   "ns",
+			K8SProtocolVersion:  vpclattice.TargetGroupProtocolVersionHttp1,
+		}),
+	}, nil).AnyTimes()
+
+	r := &targetsReconciler{
+		log:            gwlog.FallbackLogger,
+		client:         k8sClient,
+		cloud:          mockCloud,
+		tgIndex:        lattice.NewTargetGroupIndex(gwlog.FallbackLogger, mockCloud),
+		targetsManager: lattice.NewTargetsManager(gwlog.FallbackLogger, mockCloud),
+	}
+
+	// no targets are registered, as the ones of one port would replace the ones of the other
+	err := r.reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "svc"}})
+	assert.ErrorContains(t, err, "ports 80 and 8081 of service ns/svc build the same target group tg-route")
+}
@@ -3,6 +3,7 @@ package externaldns
 import (
 	"context"
 	"reflect"
+	"strings"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -21,6 +22,7 @@ import (
 
 type DnsEndpointManager interface {
 	Create(ctx context.Context, service *latticemodel.Service) error
+	Delete(ctx context.Context, service *latticemodel.Service) error
 }
 
 type defaultDnsEndpointManager struct {
@@ -36,10 +38,7 @@ func NewDnsEndpointManager(log gwlog.Logger, k8sClient client.Client) *defaultDn
 }
 
 func (s *defaultDnsEndpointManager) Create(ctx context.Context, service *latticemodel.Service) error {
-	namespacedName := types.NamespacedName{
-		Namespace: service.Spec.RouteNamespace,
-		Name:      service.Spec.RouteName + "-dns",
-	}
+	namespacedName := dnsEndpointName(service)
 	if service.Spec.CustomerDomainName == "" {
 		s.log.Debugf(ctx, "Skipping creation of %s: detected no custom domain", namespacedName)
 		return nil
@@ -124,3 +123,31 @@ func (s *defaultDnsEndpointManager) Create(ctx context.Context, service *lattice
 	}
 	return nil
 }
+
+// Delete removes the DNSEndpoint of a headless service, the DNSEndpoints of a route are otherwise
+// garbage collected with the route
+func (s *defaultDnsEndpointManager) Delete(ctx context.Context, service *latticemodel.Service) error {
+	namespacedName := dnsEndpointName(service)
+	ep := &endpoint.DNSEndpoint{}
+	if err := s.k8sClient.Get(ctx, namespacedName, ep); err != nil {
+		if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return nil
+		}
+		return err
+	}
+	s.log.Debugf(ctx, "Deleting DNSEndpoint %s", namespacedName)
+	return client.IgnoreNotFound(s.k8sClient.Delete(ctx, ep))
+}
+
+// dnsEndpointName is the route name suffixed with the cluster of a headless service
+func dnsEndpointName(service *latticemodel.Service) types.NamespacedName {
+	name := service.Spec.RouteName + "-dns"
+	if service.Spec.IsHeadless() {
+		cluster := strings.ToLower(strings.ReplaceAll(service.Spec.HeadlessCluster, "_", "-"))
+		name = service.Spec.RouteName + "-" + cluster + "-dns"
+	}
+	return types.NamespacedName{
+		Namespace: service.Spec.RouteNamespace,
+		Name:      name,
+	}
+}
@@ -290,3 +290,37 @@ func TestCreateDnsEndpoint(t *testing.T) {
 		})
 	}
 }
+
+func TestDeleteHeadlessDnsEndpoint(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	service := &model.Service{
+		Spec: model.ServiceSpec{
+			ServiceTagFields: model.ServiceTagFields{
+				RouteName:       "service",
+				RouteNamespace:  "default",
+				HeadlessCluster: "Cluster_A",
+			},
+		},
+	}
+	dnsName := types.NamespacedName{Namespace: "default", Name: "service-cluster-a-dns"}
+
+	t.Run("deletes the DNSEndpoint of the cluster", func(t *testing.T) {
+		mockClient := mock_client.NewMockClient(c)
+		mgr := NewDnsEndpointManager(gwlog.FallbackLogger, mockClient)
+		mockClient.EXPECT().Get(gomock.Any(), gomock.Eq(dnsName), gomock.Any()).Return(nil)
+		mockClient.EXPECT().Delete(gomock.Any(), gomock.Any()).Return(nil)
+
+		assert.Nil(t, mgr.Delete(context.Background(), service))
+	})
+
+	t.Run("skips a missing DNSEndpoint", func(t *testing.T) {
+		mockClient := mock_client.NewMockClient(c)
+		mgr := NewDnsEndpointManager(gwlog.FallbackLogger, mockClient)
+		mockClient.EXPECT().Get(gomock.Any(), gomock.Eq(dnsName), gomock.Any()).
+			Return(apierrors.NewNotFound(schema.GroupResource{}, dnsName.Name))
+
+		assert.Nil(t, mgr.Delete(context.Background(), service))
+	})
+}
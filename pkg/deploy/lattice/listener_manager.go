@@ -4,7 +4,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"reflect"
 
 	"github.com/aws/aws-application-networking-k8s/pkg/aws/services"
 	"github.com/aws/aws-application-networking-k8s/pkg/utils/gwlog"
@@ -236,7 +235,7 @@ func (d *defaultListenerManager) needToUpdateDefaultAction(
 		return false, err
 	}
 
-	return !reflect.DeepEqual(resp.DefaultAction, listenerDefaultActionFromStack), nil
+	return !ruleActionsEqual(resp.DefaultAction, listenerDefaultActionFromStack), nil
 }
 
 func (d *defaultListenerManager) findListenerByPort(
@@ -92,7 +92,8 @@ func (l *listenerSynthesizer) Synthesize(ctx context.Context) error {
 
 func (l *listenerSynthesizer) shouldDelete(listenerToFind *model.Listener, stackListeners []*model.Listener) bool {
 	for _, candidate := range stackListeners {
-		if candidate.Spec.Port == listenerToFind.Spec.Port && candidate.Spec.Protocol == listenerToFind.Spec.Protocol {
+		if candidate.Spec.StackServiceId == listenerToFind.Spec.StackServiceId &&
+			candidate.Spec.Port == listenerToFind.Spec.Port && candidate.Spec.Protocol == listenerToFind.Spec.Protocol {
 			// found a match, do not delete
 			return false
 		}
@@ -172,3 +172,55 @@ func Test_SynthesizeListener_CreatNewTLSPassthroughListener_DeleteStaleHTTPSList
 	err := ls.Synthesize(ctx)
 	assert.Nil(t, err)
 }
+
+func Test_SynthesizeListener_DeleteStaleListenerOfOtherService(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	ctx := context.TODO()
+	mockListenerMgr := NewMockListenerManager(c)
+	mockTargetGroupManager := NewMockTargetGroupManager(c)
+
+	stack := core.NewDefaultStack(core.StackID{Name: "foo", Namespace: "bar"})
+
+	routeSvc := &model.Service{
+		ResourceMeta: core.NewResourceMeta(stack, "AWS:VPCServiceNetwork::Service", "stack-svc-id"),
+		Status:       &model.ServiceStatus{Id: "svc-id"},
+	}
+	assert.NoError(t, stack.AddResource(routeSvc))
+	headlessSvc := &model.Service{
+		ResourceMeta: core.NewResourceMeta(stack, "AWS:VPCServiceNetwork::Service", "stack-headless-svc-id"),
+		Status:       &model.ServiceStatus{Id: "headless-svc-id"},
+	}
+	assert.NoError(t, stack.AddResource(headlessSvc))
+
+	l := &model.Listener{
+		ResourceMeta: core.NewResourceMeta(stack, "AWS:VPCServiceNetwork::Listener", "l-id"),
+		Spec: model.ListenerSpec{
+			StackServiceId: "stack-svc-id",
+			Protocol:       "HTTP",
+			Port:           80,
+			DefaultAction:  &model.DefaultAction{FixedResponseStatusCode: aws.Int64(404)},
+		},
+	}
+	assert.NoError(t, stack.AddResource(l))
+	mockListenerMgr.EXPECT().Upsert(ctx, l, routeSvc).Return(model.ListenerStatus{Id: "listener-id"}, nil)
+
+	// both services have a listener on port 80, only the headless one is stale
+	mockListenerMgr.EXPECT().List(ctx, "svc-id").Return([]*vpclattice.ListenerSummary{
+		{Id: aws.String("listener-id"), Protocol: aws.String("HTTP"), Port: aws.Int64(80)},
+	}, nil)
+	mockListenerMgr.EXPECT().List(ctx, "headless-svc-id").Return([]*vpclattice.ListenerSummary{
+		{Id: aws.String("to-delete-id"), Protocol: aws.String("HTTP"), Port: aws.Int64(80)},
+	}, nil)
+	mockListenerMgr.EXPECT().Delete(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, ml *model.Listener) error {
+			assert.Equal(t, "to-delete-id", ml.Status.Id)
+			assert.Equal(t, "headless-svc-id", ml.Status.ServiceId)
+			return nil
+		})
+
+	ls := NewListenerSynthesizer(gwlog.FallbackLogger, mockListenerMgr, mockTargetGroupManager, stack)
+	err := ls.Synthesize(ctx)
+	assert.Nil(t, err)
+}
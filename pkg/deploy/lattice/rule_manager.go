@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
 
@@ -215,7 +216,7 @@ func (r *defaultRuleManager) updateIfNeeded(
 	}
 
 	// we already validated Match, if Action is also the same then no updates required
-	updateNeeded := !reflect.DeepEqual(ruleToUpdate.Action, matchingRule.Action)
+	updateNeeded := !ruleActionsEqual(ruleToUpdate.Action, matchingRule.Action)
 	if !updateNeeded {
 		r.log.Debugf(ctx, "rule unchanged, no updates required")
 		return updatedRuleStatus, nil
@@ -380,3 +381,30 @@ func (r *defaultRuleManager) Delete(ctx context.Context, ruleId string, serviceI
 	r.log.Infof(ctx, "Success DeleteRule %s/%s/%s", serviceId, listenerId, ruleId)
 	return nil
 }
+
+// ruleActionsEqual compares rule actions with their target groups identified by id, target groups of other
+// accounts are forwarded to by ARN while Lattice may return their id
+func ruleActionsEqual(a, b *vpclattice.RuleAction) bool {
+	return reflect.DeepEqual(ruleActionWithTgIds(a), ruleActionWithTgIds(b))
+}
+
+func ruleActionWithTgIds(action *vpclattice.RuleAction) *vpclattice.RuleAction {
+	if action == nil || action.Forward == nil {
+		return action
+	}
+	normalized := *action
+	forward := *action.Forward
+	forward.TargetGroups = make([]*vpclattice.WeightedTargetGroup, len(action.Forward.TargetGroups))
+	for i, tg := range action.Forward.TargetGroups {
+		if tg == nil {
+			continue
+		}
+		withId := *tg
+		if identifier := aws.StringValue(tg.TargetGroupIdentifier); strings.HasPrefix(identifier, "arn:") {
+			withId.TargetGroupIdentifier = aws.String(identifier[strings.LastIndex(identifier, "/")+1:])
+		}
+		forward.TargetGroups[i] = &withId
+	}
+	normalized.Forward = &forward
+	return &normalized
+}
@@ -245,6 +245,52 @@ func Test_Create(t *testing.T) {
 		assert.Equal(t, "existing-arn", ruleStatus.Arn)
 	})
 
+	t.Run("test update - nothing to do for a target group of another account", func(t *testing.T) {
+		rOtherAccount := &model.Rule{
+			Spec: model.RuleSpec{
+				Priority: 1,
+				Method:   "POST",
+				Action: model.RuleAction{
+					TargetGroups: []*model.RuleTargetGroup{
+						{
+							LatticeTgId: "arn:aws:vpc-lattice:us-west-2:111122223333:targetgroup/tg-id",
+							Weight:      1,
+						},
+					},
+				},
+			},
+		}
+		mockLattice.EXPECT().GetRulesAsList(ctx, gomock.Any()).Return(
+			[]*vpclattice.GetRuleOutput{
+				{
+					Id:  aws.String("existing-id"),
+					Arn: aws.String("existing-arn"),
+					Match: &vpclattice.RuleMatch{
+						HttpMatch: &vpclattice.HttpMatch{
+							Method: aws.String("POST"),
+						},
+					},
+					Action: &vpclattice.RuleAction{
+						Forward: &vpclattice.ForwardAction{
+							TargetGroups: []*vpclattice.WeightedTargetGroup{
+								{
+									TargetGroupIdentifier: aws.String("tg-id"),
+									Weight:                aws.Int64(1),
+								},
+							},
+						},
+					},
+					Name:     aws.String("existing-name"),
+					Priority: aws.Int64(1),
+				},
+			}, nil) // <-- the ARN forwarded to is returned as an id, no update required
+
+		rm := NewRuleManager(gwlog.FallbackLogger, cloud)
+		ruleStatus, err := rm.Upsert(ctx, rOtherAccount, l, svc)
+		assert.Nil(t, err)
+		assert.Equal(t, "existing-arn", ruleStatus.Arn)
+	})
+
 	t.Run("test create - invalid backendRefs", func(t *testing.T) {
 		mockLattice.EXPECT().GetRulesAsList(ctx, gomock.Any()).Return(
 			[]*vpclattice.GetRuleOutput{}, nil).Times(2)
@@ -2,12 +2,16 @@ package lattice
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/aws/aws-application-networking-k8s/pkg/aws/services"
+	"github.com/aws/aws-application-networking-k8s/pkg/utils"
 	"github.com/aws/aws-application-networking-k8s/pkg/utils/gwlog"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/vpclattice"
 
 	pkg_aws "github.com/aws/aws-application-networking-k8s/pkg/aws"
@@ -34,6 +38,7 @@ type SnSvcAssocSummary = vpclattice.ServiceNetworkServiceAssociationSummary
 type ServiceManager interface {
 	Upsert(ctx context.Context, service *model.Service) (model.ServiceStatus, error)
 	Delete(ctx context.Context, service *model.Service) error
+	ListHeadless(ctx context.Context, service *model.Service) ([]*model.Service, error)
 }
 
 type defaultServiceManager struct {
@@ -59,7 +64,7 @@ func (m *defaultServiceManager) createServiceAndAssociate(ctx context.Context, s
 		aws.StringValue(createSvcResp.Name), aws.StringValue(createSvcResp.Id))
 
 	for _, snName := range svc.Spec.ServiceNetworkNames {
-		err = m.createAssociation(ctx, createSvcResp.Id, snName)
+		err = m.createAssociation(ctx, createSvcResp.Id, snName, svc.Spec.ServiceNetworkArns[snName])
 		if err != nil {
 			return ServiceInfo{}, err
 		}
@@ -68,20 +73,34 @@ func (m *defaultServiceManager) createServiceAndAssociate(ctx context.Context, s
 	return svcInfo, nil
 }
 
-func (m *defaultServiceManager) createAssociation(ctx context.Context, svcId *string, snName string) error {
+// associates the service to the service network, snArn is the ARN already resolved by the gateway controller,
+// the service network is looked up by name when it is empty or no longer exists
+func (m *defaultServiceManager) createAssociation(ctx context.Context, svcId *string, snName string, snArn string) error {
+	if snArn != "" {
+		err := m.createAssociationWithSn(ctx, svcId, aws.String(snArn))
+		var aerr awserr.Error
+		if err == nil || !errors.As(err, &aerr) || !services.IsNotFoundError(aerr) {
+			return err
+		}
+		m.log.Debugf(ctx, "Service network %s not found, looking up %s", snArn, snName)
+	}
+
 	snInfo, err := m.cloud.Lattice().FindServiceNetwork(ctx, snName)
 	if err != nil {
 		return err
 	}
+	return m.createAssociationWithSn(ctx, svcId, snInfo.SvcNetwork.Id)
+}
 
+func (m *defaultServiceManager) createAssociationWithSn(ctx context.Context, svcId *string, snIdentifier *string) error {
 	assocReq := &CreateSnSvcAssocReq{
 		ServiceIdentifier:        svcId,
-		ServiceNetworkIdentifier: snInfo.SvcNetwork.Id,
+		ServiceNetworkIdentifier: snIdentifier,
 		Tags:                     m.cloud.DefaultTags(),
 	}
 	assocResp, err := m.cloud.Lattice().CreateServiceNetworkServiceAssociationWithContext(ctx, assocReq)
 	if err != nil {
-		return fmt.Errorf("failed CreateServiceNetworkServiceAssociation %s %s due to %s",
+		return fmt.Errorf("failed CreateServiceNetworkServiceAssociation %s %s due to %w",
 			aws.StringValue(assocReq.ServiceNetworkIdentifier), aws.StringValue(assocReq.ServiceIdentifier), err)
 	}
 	m.log.Infof(ctx, "Success CreateServiceNetworkServiceAssociation %s %s",
@@ -98,7 +117,7 @@ func (m *defaultServiceManager) newCreateSvcReq(svc *Service) *CreateSvcReq {
 	svcName := svc.LatticeServiceName()
 	req := &vpclattice.CreateServiceInput{
 		Name: &svcName,
-		Tags: m.cloud.DefaultTagsMergedWith(svc.Spec.ToTags()),
+		Tags: m.cloud.DefaultTagsMergedWith(serviceTags(svc)),
 	}
 
 	if svc.Spec.CustomerDomainName != "" {
@@ -148,7 +167,7 @@ func (m *defaultServiceManager) checkAndUpdateTags(ctx context.Context, svc *Ser
 		// correct information and add tags
 		_, err = m.cloud.Lattice().TagResourceWithContext(ctx, &vpclattice.TagResourceInput{
 			ResourceArn: svcSum.Arn,
-			Tags:        svc.Spec.ToTags(),
+			Tags:        serviceTags(svc),
 		})
 		return err
 	case tagFields != svc.Spec.ServiceTagFields:
@@ -157,10 +176,25 @@ func (m *defaultServiceManager) checkAndUpdateTags(ctx context.Context, svc *Ser
 		// - two services with conflict edge case such as my-namespace/service & my/namespace-service
 		return services.NewConflictError("service", svc.Spec.RouteName+"/"+svc.Spec.RouteNamespace,
 			fmt.Sprintf("Found existing resource with conflicting service name: %s", *svcSum.Arn))
+	case svc.Spec.HasHeadlessServices && tagsResp.Tags[model.K8SHeadlessServicesKey] == nil:
+		_, err = m.cloud.Lattice().TagResourceWithContext(ctx, &vpclattice.TagResourceInput{
+			ResourceArn: svcSum.Arn,
+			Tags:        services.Tags{model.K8SHeadlessServicesKey: aws.String("true")},
+		})
+		return err
 	}
 	return nil
 }
 
+// serviceTags are the tags of the service, the headless services tag is never removed once added
+func serviceTags(svc *Service) services.Tags {
+	tags := svc.Spec.ToTags()
+	if svc.Spec.HasHeadlessServices {
+		tags[model.K8SHeadlessServicesKey] = aws.String("true")
+	}
+	return tags
+}
+
 func (m *defaultServiceManager) updateServiceAndAssociations(ctx context.Context, svc *Service, svcSum *SvcSummary) (ServiceInfo, error) {
 	if svc.Spec.CustomerCertARN != "" {
 		updReq := &UpdateSvcReq{
@@ -212,7 +246,7 @@ func (m *defaultServiceManager) updateAssociations(ctx context.Context, svc *Ser
 		return err
 	}
 	for _, snName := range toCreate {
-		err := m.createAssociation(ctx, svcSum.Id, snName)
+		err := m.createAssociation(ctx, svcSum.Id, snName, svc.Spec.ServiceNetworkArns[snName])
 		if err != nil {
 			return err
 		}
@@ -410,3 +444,65 @@ func (m *defaultServiceManager) Delete(ctx context.Context, svc *Service) error
 	}
 	return nil
 }
+
+// ListHeadless returns the headless services of the route of the service, built from their tags. Their
+// names share a prefix, the tags of the services with that prefix tell the route and cluster. Only the
+// routes with a Headless ServiceImport backend, or whose service is tagged for having had one, are listed.
+func (m *defaultServiceManager) ListHeadless(ctx context.Context, svc *Service) ([]*Service, error) {
+	if !svc.Spec.HasHeadlessServices {
+		hadHeadless, err := m.hadHeadlessServices(ctx, svc)
+		if err != nil || !hadHeadless {
+			return nil, err
+		}
+	}
+
+	svcSums, err := m.cloud.Lattice().ListServicesAsList(ctx, &vpclattice.ListServicesInput{})
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := utils.LatticeHeadlessServiceNamePrefix(svc.Spec.RouteName, svc.Spec.RouteNamespace)
+	var headlessSvcs []*Service
+	for _, svcSum := range svcSums {
+		if !strings.HasPrefix(aws.StringValue(svcSum.Name), prefix) {
+			continue
+		}
+		tagsResp, err := m.cloud.Lattice().ListTagsForResourceWithContext(ctx, &vpclattice.ListTagsForResourceInput{
+			ResourceArn: svcSum.Arn,
+		})
+		if err != nil {
+			return nil, err
+		}
+		tagFields := model.ServiceTagFieldsFromTags(tagsResp.Tags)
+		if !tagFields.IsHeadless() || tagFields.RouteName != svc.Spec.RouteName ||
+			tagFields.RouteNamespace != svc.Spec.RouteNamespace || tagFields.RouteType != svc.Spec.RouteType {
+			continue
+		}
+		headlessSvcs = append(headlessSvcs, &Service{
+			Spec: model.ServiceSpec{ServiceTagFields: tagFields},
+			Status: &ServiceInfo{
+				Arn: aws.StringValue(svcSum.Arn),
+				Id:  aws.StringValue(svcSum.Id),
+			},
+		})
+	}
+	return headlessSvcs, nil
+}
+
+// hadHeadlessServices returns whether the Lattice service of the route is tagged for headless services
+func (m *defaultServiceManager) hadHeadlessServices(ctx context.Context, svc *Service) (bool, error) {
+	svcSum, err := m.cloud.Lattice().FindService(ctx, svc.LatticeServiceName())
+	if err != nil {
+		if services.IsNotFoundError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	tagsResp, err := m.cloud.Lattice().ListTagsForResourceWithContext(ctx, &vpclattice.ListTagsForResourceInput{
+		ResourceArn: svcSum.Arn,
+	})
+	if err != nil {
+		return false, err
+	}
+	return tagsResp.Tags[model.K8SHeadlessServicesKey] != nil, nil
+}
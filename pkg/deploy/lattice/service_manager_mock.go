@@ -49,6 +49,21 @@ func (mr *MockServiceManagerMockRecorder) Delete(arg0, arg1 interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockServiceManager)(nil).Delete), arg0, arg1)
 }
 
+// ListHeadless mocks base method.
+func (m *MockServiceManager) ListHeadless(arg0 context.Context, arg1 *lattice.Service) ([]*lattice.Service, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListHeadless", arg0, arg1)
+	ret0, _ := ret[0].([]*lattice.Service)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListHeadless indicates an expected call of ListHeadless.
+func (mr *MockServiceManagerMockRecorder) ListHeadless(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListHeadless", reflect.TypeOf((*MockServiceManager)(nil).ListHeadless), arg0, arg1)
+}
+
 // Upsert mocks base method.
 func (m *MockServiceManager) Upsert(arg0 context.Context, arg1 *lattice.Service) (lattice.ServiceStatus, error) {
 	m.ctrl.T.Helper()
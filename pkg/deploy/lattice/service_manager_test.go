@@ -11,6 +11,7 @@ import (
 	model "github.com/aws/aws-application-networking-k8s/pkg/model/lattice"
 	"github.com/aws/aws-application-networking-k8s/pkg/utils/gwlog"
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/vpclattice"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -97,6 +98,66 @@ func TestServiceManagerInteg(t *testing.T) {
 		assert.Equal(t, "arn", status.Arn)
 	})
 
+	// Service network ARNs resolved by the gateway controller are used as they are, the service network is
+	// only looked up when the ARN no longer exists
+	t.Run("create associations with resolved service network arns", func(t *testing.T) {
+		svc := &Service{
+			Spec: model.ServiceSpec{
+				ServiceTagFields: model.ServiceTagFields{
+					RouteName:      "svc",
+					RouteNamespace: "ns",
+					RouteType:      core.HttpRouteType,
+				},
+				ServiceNetworkNames: []string{"sn", "sn-recreated"},
+				ServiceNetworkArns: map[string]string{
+					"sn":           "sn-arn",
+					"sn-recreated": "sn-recreated-old-arn",
+				},
+			},
+		}
+
+		mockLattice.EXPECT().
+			FindService(gomock.Any(), gomock.Any()).
+			Return(nil, mocks.NewNotFoundError("", ""))
+		mockLattice.EXPECT().
+			CreateServiceWithContext(gomock.Any(), gomock.Any()).
+			Return(&CreateSvcResp{
+				Arn:      aws.String("arn"),
+				DnsEntry: &vpclattice.DnsEntry{DomainName: aws.String("dns")},
+				Id:       aws.String("svc-id"),
+			}, nil)
+
+		var snIdentifiers []string
+		mockLattice.EXPECT().
+			CreateServiceNetworkServiceAssociationWithContext(gomock.Any(), gomock.Any()).
+			DoAndReturn(
+				func(_ context.Context, req *CreateSnSvcAssocReq, _ ...interface{}) (*CreateSnSvcAssocResp, error) {
+					snIdentifiers = append(snIdentifiers, *req.ServiceNetworkIdentifier)
+					if *req.ServiceNetworkIdentifier == "sn-recreated-old-arn" {
+						return nil, awserr.New(vpclattice.ErrCodeResourceNotFoundException, "", nil)
+					}
+					return &CreateSnSvcAssocResp{
+						Status: aws.String(vpclattice.ServiceNetworkServiceAssociationStatusActive),
+					}, nil
+				}).
+			Times(3)
+
+		mockLattice.EXPECT().
+			FindServiceNetwork(gomock.Any(), "sn-recreated").
+			Return(&mocks.ServiceNetworkInfo{
+				SvcNetwork: vpclattice.ServiceNetworkSummary{
+					Arn:  aws.String("sn-recreated-arn"),
+					Id:   aws.String("sn-recreated-id"),
+					Name: aws.String("sn-recreated"),
+				},
+			}, nil).
+			Times(1)
+
+		_, err := m.Upsert(ctx, svc)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"sn-arn", "sn-recreated-old-arn", "sn-recreated-id"}, snIdentifiers)
+	})
+
 	// Update is more complex than create, we need to apply diff for Sn-Svc associations
 	// This test covers creation/deletion for multiple SN's
 	// sn-keep - no changes
@@ -337,6 +398,117 @@ func TestServiceManagerInteg(t *testing.T) {
 		assert.Nil(t, err)
 	})
 
+	t.Run("list headless services of the route", func(t *testing.T) {
+		svc := &Service{
+			Spec: model.ServiceSpec{
+				ServiceTagFields: model.ServiceTagFields{
+					RouteName:      "kafka",
+					RouteNamespace: "ns",
+					RouteType:      core.TlsRouteType,
+				},
+				HasHeadlessServices: true,
+			},
+		}
+		headlessTags := func(routeName, cluster string) mocks.Tags {
+			tagFields := svc.Spec.ServiceTagFields
+			tagFields.RouteName = routeName
+			tagFields.HeadlessCluster = cluster
+			return tagFields.ToTags()
+		}
+
+		mockLattice.EXPECT().
+			ListServicesAsList(gomock.Any(), gomock.Any()).
+			Return([]*SvcSummary{
+				{Name: aws.String("kafka-ns"), Arn: aws.String("arn-route")},
+				{Name: aws.String("kafka-ns-cluster-a"), Arn: aws.String("arn-a"), Id: aws.String("id-a")},
+				{Name: aws.String("kafka-ns-cluster-b"), Arn: aws.String("arn-b"), Id: aws.String("id-b")},
+				{Name: aws.String("other-ns-cluster-a"), Arn: aws.String("arn-other")},
+			}, nil)
+		mockLattice.EXPECT().
+			ListTagsForResourceWithContext(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, req *vpclattice.ListTagsForResourceInput, _ ...interface{}) (*vpclattice.ListTagsForResourceOutput, error) {
+				switch aws.StringValue(req.ResourceArn) {
+				case "arn-a":
+					return &vpclattice.ListTagsForResourceOutput{Tags: headlessTags("kafka", "cluster-a")}, nil
+				case "arn-b":
+					// a route of another name sharing the truncated prefix
+					return &vpclattice.ListTagsForResourceOutput{Tags: headlessTags("kafka-other", "cluster-b")}, nil
+				}
+				return nil, errors.New("unexpected arn")
+			}).
+			Times(2)
+
+		headlessSvcs, err := m.ListHeadless(ctx, svc)
+		assert.Nil(t, err)
+		assert.Len(t, headlessSvcs, 1)
+		assert.Equal(t, "cluster-a", headlessSvcs[0].Spec.HeadlessCluster)
+		assert.Equal(t, "id-a", headlessSvcs[0].Status.Id)
+		assert.Equal(t, "kafka-ns-cluster-a", headlessSvcs[0].LatticeServiceName())
+	})
+
+	t.Run("headless services listed only for routes that had some", func(t *testing.T) {
+		svc := &Service{
+			Spec: model.ServiceSpec{
+				ServiceTagFields: model.ServiceTagFields{
+					RouteName:      "web",
+					RouteNamespace: "ns",
+					RouteType:      core.HttpRouteType,
+				},
+			},
+		}
+		mockLattice.EXPECT().
+			FindService(gomock.Any(), "web-ns").
+			Return(&vpclattice.ServiceSummary{Arn: aws.String("arn-web"), Name: aws.String("web-ns")}, nil).
+			Times(2)
+
+		// never had headless services
+		mockLattice.EXPECT().
+			ListTagsForResourceWithContext(gomock.Any(), gomock.Any()).
+			Return(&vpclattice.ListTagsForResourceOutput{Tags: svc.Spec.ToTags()}, nil)
+		headlessSvcs, err := m.ListHeadless(ctx, svc)
+		assert.Nil(t, err)
+		assert.Empty(t, headlessSvcs)
+
+		// the Headless ServiceImport backend was removed
+		tags := svc.Spec.ToTags()
+		tags[model.K8SHeadlessServicesKey] = aws.String("true")
+		mockLattice.EXPECT().
+			ListTagsForResourceWithContext(gomock.Any(), gomock.Any()).
+			Return(&vpclattice.ListTagsForResourceOutput{Tags: tags}, nil)
+		mockLattice.EXPECT().
+			ListServicesAsList(gomock.Any(), gomock.Any()).
+			Return([]*SvcSummary{{Name: aws.String("web-ns"), Arn: aws.String("arn-web")}}, nil)
+		headlessSvcs, err = m.ListHeadless(ctx, svc)
+		assert.Nil(t, err)
+		assert.Empty(t, headlessSvcs)
+	})
+
+	t.Run("tag the service of a route with headless services", func(t *testing.T) {
+		svc := &Service{
+			Spec: model.ServiceSpec{
+				ServiceTagFields: model.ServiceTagFields{
+					RouteName:      "kafka",
+					RouteNamespace: "ns",
+					RouteType:      core.TlsRouteType,
+				},
+				HasHeadlessServices: true,
+			},
+		}
+		mockLattice.EXPECT().
+			FindService(gomock.Any(), gomock.Any()).
+			Return(&vpclattice.ServiceSummary{Arn: aws.String("svc-arn"), Id: aws.String("svc-id")}, nil)
+		mockLattice.EXPECT().
+			ListTagsForResourceWithContext(gomock.Any(), gomock.Any()).
+			Return(&vpclattice.ListTagsForResourceOutput{Tags: cl.DefaultTagsMergedWith(svc.Spec.ToTags())}, nil)
+		mockLattice.EXPECT().TagResourceWithContext(gomock.Any(), gomock.Eq(&vpclattice.TagResourceInput{
+			ResourceArn: aws.String("svc-arn"),
+			Tags:        mocks.Tags{model.K8SHeadlessServicesKey: aws.String("true")},
+		})).Times(1)
+		mockLattice.EXPECT().ListServiceNetworkServiceAssociationsAsList(gomock.Any(), gomock.Any())
+
+		_, err := m.Upsert(ctx, svc)
+		assert.Nil(t, err)
+	})
 }
 
 func TestCreateSvcReq(t *testing.T) {
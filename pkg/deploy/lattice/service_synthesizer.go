@@ -4,8 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
-
-	"github.com/aws/aws-application-networking-k8s/pkg/utils"
+	"slices"
 
 	"github.com/aws/aws-application-networking-k8s/pkg/deploy/externaldns"
 	"github.com/aws/aws-application-networking-k8s/pkg/model/core"
@@ -38,9 +37,10 @@ func (s *serviceSynthesizer) Synthesize(ctx context.Context) error {
 	var resServices []*model.Service
 	s.stack.ListResources(&resServices)
 
-	var svcErr error
+	// before the route services, whose tags tell whether they had headless services, may be deleted
+	svcErr := s.deleteStaleHeadlessServices(ctx, resServices)
 	for _, resService := range resServices {
-		svcName := utils.LatticeServiceName(resService.Spec.RouteName, resService.Spec.RouteNamespace)
+		svcName := resService.LatticeServiceName()
 		s.log.Debugf(ctx, "Synthesizing service: %s", svcName)
 		if resService.IsDeleted {
 			err := s.serviceManager.Delete(ctx, resService)
@@ -66,7 +66,40 @@ func (s *serviceSynthesizer) Synthesize(ctx context.Context) error {
 			}
 		}
 	}
+	return svcErr
+}
 
+// deleteStaleHeadlessServices deletes the headless services of the route of clusters that no longer export
+// the Headless ServiceImport backend, or of a route without such a backend
+func (s *serviceSynthesizer) deleteStaleHeadlessServices(ctx context.Context, resServices []*model.Service) error {
+	var svcErr error
+	for _, resService := range resServices {
+		if resService.Spec.IsHeadless() {
+			continue
+		}
+		headlessSvcs, err := s.serviceManager.ListHeadless(ctx, resService)
+		if err != nil {
+			svcErr = errors.Join(svcErr,
+				fmt.Errorf("failed ServiceManager.ListHeadless %s due to %w", resService.LatticeServiceName(), err))
+			continue
+		}
+		for _, headlessSvc := range headlessSvcs {
+			svcName := headlessSvc.LatticeServiceName()
+			if slices.ContainsFunc(resServices, func(svc *model.Service) bool {
+				return svc.LatticeServiceName() == svcName
+			}) {
+				continue
+			}
+			s.log.Infof(ctx, "Deleting headless service %s of cluster %s", svcName, headlessSvc.Spec.HeadlessCluster)
+			if err := s.serviceManager.Delete(ctx, headlessSvc); err != nil {
+				svcErr = errors.Join(svcErr, fmt.Errorf("failed ServiceManager.Delete %s due to %w", svcName, err))
+				continue
+			}
+			if err := s.dnsEndpointManager.Delete(ctx, headlessSvc); err != nil {
+				svcErr = errors.Join(svcErr, fmt.Errorf("failed DnsEndpointManager.Delete %s due to %w", svcName, err))
+			}
+		}
+	}
 	return svcErr
 }
 
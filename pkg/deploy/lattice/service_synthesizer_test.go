@@ -179,6 +179,7 @@ func Test_SynthesizeService(t *testing.T) {
 			if !latticeService.IsDeleted && tt.mgrErr == nil {
 				mockDnsManager.EXPECT().Create(ctx, gomock.Any()).Return(tt.dnsErr)
 			}
+			mockSvcManager.EXPECT().ListHeadless(ctx, latticeService).Return(nil, nil)
 
 			synthesizer := NewServiceSynthesizer(gwlog.FallbackLogger, mockSvcManager, mockDnsManager, stack)
 
@@ -191,3 +192,35 @@ func Test_SynthesizeService(t *testing.T) {
 		})
 	}
 }
+
+func Test_SynthesizeService_DeleteStaleHeadlessServices(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+	ctx := context.TODO()
+
+	stack := core.NewDefaultStack(core.StackID{Namespace: "ns", Name: "kafka"})
+	mockSvcManager := NewMockServiceManager(c)
+	mockDnsManager := externaldns.NewMockDnsEndpointManager(c)
+
+	tagFields := model.ServiceTagFields{RouteName: "kafka", RouteNamespace: "ns", RouteType: core.TlsRouteType}
+	routeSvc, err := model.NewLatticeService(stack, model.ServiceSpec{ServiceTagFields: tagFields})
+	assert.Nil(t, err)
+	tagFields.HeadlessCluster = "cluster-a"
+	headlessSvc, err := model.NewLatticeService(stack, model.ServiceSpec{ServiceTagFields: tagFields})
+	assert.Nil(t, err)
+	tagFields.HeadlessCluster = "cluster-b"
+	staleSvc := &model.Service{Spec: model.ServiceSpec{ServiceTagFields: tagFields}}
+
+	mockSvcManager.EXPECT().Upsert(ctx, routeSvc).Return(model.ServiceStatus{Id: "svc-id"}, nil)
+	mockSvcManager.EXPECT().Upsert(ctx, headlessSvc).Return(model.ServiceStatus{Id: "svc-a-id"}, nil)
+	mockDnsManager.EXPECT().Create(ctx, gomock.Any()).Return(nil).Times(2)
+	mockSvcManager.EXPECT().ListHeadless(ctx, routeSvc).Return([]*model.Service{
+		{Spec: headlessSvc.Spec},
+		staleSvc,
+	}, nil)
+	mockSvcManager.EXPECT().Delete(ctx, staleSvc).Return(nil)
+	mockDnsManager.EXPECT().Delete(ctx, staleSvc).Return(nil)
+
+	synthesizer := NewServiceSynthesizer(gwlog.FallbackLogger, mockSvcManager, mockDnsManager, stack)
+	assert.Nil(t, synthesizer.Synthesize(ctx))
+}
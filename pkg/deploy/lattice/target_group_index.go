@@ -0,0 +1,120 @@
+package lattice
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/vpclattice"
+	"k8s.io/apimachinery/pkg/types"
+
+	pkg_aws "github.com/aws/aws-application-networking-k8s/pkg/aws"
+	model "github.com/aws/aws-application-networking-k8s/pkg/model/lattice"
+	"github.com/aws/aws-application-networking-k8s/pkg/utils/gwlog"
+)
+
+// target groups created or deleted by other controller instances are picked up within this interval
+const targetGroupIndexTTL = 60 * time.Second
+
+// targetGroupsVersion is bumped whenever this controller creates or deletes a target group,
+// so that the index does not miss target groups created after its last refresh.
+var targetGroupsVersion atomic.Uint64
+
+func targetGroupsChanged() {
+	targetGroupsVersion.Add(1)
+}
+
+// IndexedTargetGroup is a target group of this cluster, as seen at the last index refresh.
+type IndexedTargetGroup struct {
+	Status   model.TargetGroupStatus
+	VpcId    string
+	Protocol string
+	model.TargetGroupTagFields
+}
+
+// TargetGroupIndex maps Kubernetes services to the Lattice target groups created for them in this
+// cluster, using the target group tags. It is rebuilt from a single listing of all target groups,
+// so looking up the target groups of a service does not cost any API call in between refreshes.
+type TargetGroupIndex struct {
+	log       gwlog.Logger
+	cloud     pkg_aws.Cloud
+	tgManager TargetGroupManager
+	ttl       time.Duration
+
+	lock        sync.Mutex
+	byService   map[types.NamespacedName][]IndexedTargetGroup
+	refreshedAt time.Time
+	version     uint64
+}
+
+func NewTargetGroupIndex(log gwlog.Logger, cloud pkg_aws.Cloud) *TargetGroupIndex {
+	return &TargetGroupIndex{
+		log:       log,
+		cloud:     cloud,
+		tgManager: NewTargetGroupManager(log, cloud),
+		ttl:       targetGroupIndexTTL,
+	}
+}
+
+// Get returns the target groups of the given service, refreshing the index when it is stale.
+func (idx *TargetGroupIndex) Get(ctx context.Context, svc types.NamespacedName) ([]IndexedTargetGroup, error) {
+	idx.lock.Lock()
+	defer idx.lock.Unlock()
+
+	if idx.byService == nil || time.Since(idx.refreshedAt) > idx.ttl || idx.version != targetGroupsVersion.Load() {
+		if err := idx.refresh(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return idx.byService[svc], nil
+}
+
+// Invalidate forces a refresh on the next lookup, e.g. after a target group turned out to be deleted.
+func (idx *TargetGroupIndex) Invalidate() {
+	idx.lock.Lock()
+	defer idx.lock.Unlock()
+	idx.byService = nil
+}
+
+func (idx *TargetGroupIndex) refresh(ctx context.Context) error {
+	version := targetGroupsVersion.Load()
+	tgs, err := idx.tgManager.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	config := idx.cloud.Config()
+	byService := make(map[types.NamespacedName][]IndexedTargetGroup)
+	for _, tg := range tgs {
+		if tg.tags == nil || aws.StringValue(tg.tgSummary.VpcIdentifier) != config.VpcId {
+			continue
+		}
+		status := aws.StringValue(tg.tgSummary.Status)
+		if status == vpclattice.TargetGroupStatusDeleteInProgress || status == vpclattice.TargetGroupStatusDeleteFailed {
+			continue
+		}
+		tagFields := model.TGTagFieldsFromTags(tg.tags)
+		if tagFields.K8SClusterName != config.ClusterName || tagFields.K8SServiceName == "" {
+			continue
+		}
+		key := types.NamespacedName{Namespace: tagFields.K8SServiceNamespace, Name: tagFields.K8SServiceName}
+		byService[key] = append(byService[key], IndexedTargetGroup{
+			Status: model.TargetGroupStatus{
+				Name: aws.StringValue(tg.tgSummary.Name),
+				Arn:  aws.StringValue(tg.tgSummary.Arn),
+				Id:   aws.StringValue(tg.tgSummary.Id),
+			},
+			VpcId:                aws.StringValue(tg.tgSummary.VpcIdentifier),
+			Protocol:             aws.StringValue(tg.tgSummary.Protocol),
+			TargetGroupTagFields: tagFields,
+		})
+	}
+
+	idx.byService = byService
+	idx.refreshedAt = time.Now()
+	idx.version = version
+	idx.log.Debugf(ctx, "Refreshed target group index, %d services with target groups", len(byService))
+	return nil
+}
@@ -4,8 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/vpclattice"
 
 	"github.com/aws/aws-application-networking-k8s/pkg/aws/services"
@@ -24,10 +29,43 @@ import (
 type TargetGroupManager interface {
 	Upsert(ctx context.Context, modelTg *model.TargetGroup) (model.TargetGroupStatus, error)
 	Delete(ctx context.Context, modelTg *model.TargetGroup) error
+	Drain(ctx context.Context, modelTg *model.TargetGroup) (bool, error)
 	List(ctx context.Context) ([]tgListOutput, error)
 	IsTargetGroupMatch(ctx context.Context, modelTg *model.TargetGroup, latticeTg *vpclattice.TargetGroupSummary,
 		latticeTags *model.TargetGroupTagFields) (bool, error)
 	ResolveRuleTgIds(ctx context.Context, modelRuleAction *model.RuleAction, stack core.Stack) error
+	ResolveReplacedTargetGroup(ctx context.Context, modelTg *model.TargetGroup, stack core.Stack) error
+	FindSvcExportClusters(ctx context.Context, namespace string, name string, accountId string) ([]SvcExportCluster, error)
+}
+
+// SvcExportCluster is a cluster exporting a service, from the tags of its target groups
+type SvcExportCluster struct {
+	ClusterName string
+	VpcId       string
+	// Ports are the exported ports, empty for the target groups of earlier releases
+	Ports []int32
+	// UnsharedTargetGroups are the ARNs of the target groups of another account not shared with the account
+	// of the controller
+	UnsharedTargetGroups []string
+}
+
+// ErrTargetGroupNotShared is returned for the target groups of another account not shared with the account
+// of the controller through a RAM resource share
+var ErrTargetGroupNotShared = errors.New("target group is not shared with the account")
+
+// AccountAccessError is returned when the target groups of another account cannot be listed, without a role
+// for the account or when the role cannot be assumed
+type AccountAccessError struct {
+	AccountId string
+	Err       error
+}
+
+func (e *AccountAccessError) Error() string {
+	return fmt.Sprintf("failed to list the target groups of account %s: %s", e.AccountId, e.Err)
+}
+
+func (e *AccountAccessError) Unwrap() error {
+	return e.Err
 }
 
 type defaultTargetGroupManager struct {
@@ -52,11 +90,85 @@ func (s *defaultTargetGroupManager) Upsert(
 		return model.TargetGroupStatus{}, err
 	}
 
+	var status model.TargetGroupStatus
 	if latticeTgSummary == nil {
-		return s.create(ctx, modelTg)
+		status, err = s.create(ctx, modelTg)
 	} else {
-		return s.update(ctx, modelTg, latticeTgSummary)
+		status, err = s.update(ctx, modelTg, latticeTgSummary)
+	}
+	if err != nil {
+		return model.TargetGroupStatus{}, err
 	}
+	return status, nil
+}
+
+// ResolveReplacedTargetGroup looks for the target group of the route backendRef with other immutable fields the
+// service still forwards to, once every target group of the stack is upserted. Rules keep forwarding to it until
+// the new target group has healthy targets, so that no request fails while the targets of the new target group
+// are registered and checked.
+func (s *defaultTargetGroupManager) ResolveReplacedTargetGroup(ctx context.Context, modelTg *model.TargetGroup,
+	stack core.Stack) error {
+
+	// ServiceExport target groups are picked by the importing clusters, which do not know about the
+	// replacement, the previous target group is only kept until they stop forwarding to it
+	if modelTg.IsDeleted || modelTg.Status == nil || !modelTg.Spec.IsSourceTypeRoute() {
+		return nil
+	}
+	status := modelTg.Status
+	status.ReplacedId = ""
+	status.ReplacementReady = false
+
+	// e.g. a route sending the same service to target groups of other protocol versions, through the
+	// per-port overrides of its TargetGroupPolicy, builds all of them, none replaces the other
+	var stackTgs []*model.TargetGroup
+	if err := stack.ListResources(&stackTgs); err != nil {
+		return err
+	}
+	builtIds := map[string]bool{}
+	for _, stackTg := range stackTgs {
+		if !stackTg.IsDeleted && stackTg.Status != nil {
+			builtIds[stackTg.Status.Id] = true
+		}
+	}
+
+	// the protocol version is the only immutable field in the tags
+	tags := model.TagsFromTGTagFields(modelTg.Spec.TargetGroupTagFields)
+	delete(tags, model.K8SProtocolVersionKey)
+	arns, err := s.cloud.Tagging().FindResourcesByTags(ctx, services.ResourceTypeTargetGroup, tags)
+	if err != nil {
+		return err
+	}
+	for _, arn := range arns {
+		if arn == status.Arn {
+			continue
+		}
+		latticeTg, err := s.cloud.Lattice().GetTargetGroupWithContext(ctx, &vpclattice.GetTargetGroupInput{
+			TargetGroupIdentifier: aws.String(arn),
+		})
+		if err != nil {
+			if services.IsNotFoundError(err) {
+				continue
+			}
+			return err
+		}
+		if builtIds[aws.StringValue(latticeTg.Id)] {
+			continue
+		}
+		if aws.StringValue(latticeTg.Status) != vpclattice.TargetGroupStatusActive || len(latticeTg.ServiceArns) == 0 ||
+			aws.StringValue(latticeTg.Config.VpcIdentifier) != modelTg.Spec.VpcId {
+			continue
+		}
+
+		status.ReplacedId = aws.StringValue(latticeTg.Id)
+		status.ReplacementReady, err = s.hasHealthyTargets(ctx, status.Id)
+		if err != nil {
+			return err
+		}
+		s.log.Infof(ctx, "Target group %s replaces %s, healthy targets: %t", status.Id, status.ReplacedId,
+			status.ReplacementReady)
+		return nil
+	}
+	return nil
 }
 
 func (s *defaultTargetGroupManager) create(ctx context.Context, modelTg *model.TargetGroup) (model.TargetGroupStatus, error) {
@@ -93,6 +205,9 @@ func (s *defaultTargetGroupManager) create(ctx context.Context, modelTg *model.T
 	createInput.Tags[model.K8SServiceNamespaceKey] = &modelTg.Spec.K8SServiceNamespace
 	createInput.Tags[model.K8SSourceTypeKey] = aws.String(string(modelTg.Spec.K8SSourceType))
 	createInput.Tags[model.K8SProtocolVersionKey] = &modelTg.Spec.ProtocolVersion
+	if modelTg.Spec.K8SServicePort != "" {
+		createInput.Tags[model.K8SServicePortKey] = &modelTg.Spec.K8SServicePort
+	}
 
 	if modelTg.Spec.IsSourceTypeRoute() {
 		createInput.Tags[model.K8SRouteNameKey] = &modelTg.Spec.K8SRouteName
@@ -106,6 +221,7 @@ func (s *defaultTargetGroupManager) create(ctx context.Context, modelTg *model.T
 			fmt.Errorf("Failed CreateTargetGroup %s due to %s", latticeTgName, err)
 	}
 	s.log.Infof(ctx, "Success CreateTargetGroup %s", latticeTgName)
+	targetGroupsChanged()
 
 	latticeTgStatus := aws.StringValue(resp.Status)
 	if latticeTgStatus != vpclattice.TargetGroupStatusActive &&
@@ -176,6 +292,62 @@ func (s *defaultTargetGroupManager) Delete(ctx context.Context, modelTg *model.T
 	lattice := s.cloud.Lattice()
 
 	// de-register all targets first
+	_, drainCount, err := s.deregisterAllTargets(ctx, modelTg)
+	if err != nil {
+		if services.IsLatticeAPINotFoundErr(err) {
+			s.log.Debugf(ctx, "Target group %s was already deleted", modelTg.Status.Id)
+			deleteTargetsMetric(modelTg.Status.Id)
+			return nil
+		}
+		return err
+	}
+	if drainCount > 0 {
+		// no point in trying to deregister may as well wait
+		return fmt.Errorf("cannot deregister targets for %s as %d targets are DRAINING", modelTg.Status.Id, drainCount)
+	}
+
+	deleteTGInput := vpclattice.DeleteTargetGroupInput{
+		TargetGroupIdentifier: &modelTg.Status.Id,
+	}
+	_, err = lattice.DeleteTargetGroupWithContext(ctx, &deleteTGInput)
+	if err != nil {
+		if services.IsLatticeAPINotFoundErr(err) {
+			s.log.Infof(ctx, "Target group %s was already deleted", modelTg.Status.Id)
+			deleteTargetsMetric(modelTg.Status.Id)
+			return nil
+		} else {
+			return fmt.Errorf("failed DeleteTargetGroup %s due to %s", modelTg.Status.Id, err)
+		}
+	}
+
+	s.log.Infof(ctx, "Success DeleteTargetGroup %s", modelTg.Status.Id)
+	deleteTargetsMetric(modelTg.Status.Id)
+	targetGroupsChanged()
+	return nil
+}
+
+// Drain deregisters the targets of an unused target group, so that the requests in flight complete before its
+// deletion. Returns true once no target is left draining.
+func (s *defaultTargetGroupManager) Drain(ctx context.Context, modelTg *model.TargetGroup) (bool, error) {
+	deregistered, drainCount, err := s.deregisterAllTargets(ctx, modelTg)
+	if err != nil {
+		if services.IsLatticeAPINotFoundErr(err) {
+			// nothing left to drain, deleting it is a no-op
+			return true, nil
+		}
+		return false, err
+	}
+	if deregistered > 0 || drainCount > 0 {
+		s.log.Debugf(ctx, "Waiting for %d targets of target group %s to drain", deregistered+drainCount, modelTg.Status.Id)
+		return false, nil
+	}
+	return true, nil
+}
+
+// deregisterAllTargets deregisters the targets which are not draining yet, and returns their number along with
+// the number of targets already draining. Deregistration is skipped while targets are draining.
+func (s *defaultTargetGroupManager) deregisterAllTargets(ctx context.Context, modelTg *model.TargetGroup) (int, int, error) {
+	lattice := s.cloud.Lattice()
 	listTargetsInput := vpclattice.ListTargetsInput{
 		TargetGroupIdentifier: &modelTg.Status.Id,
 	}
@@ -183,10 +355,9 @@ func (s *defaultTargetGroupManager) Delete(ctx context.Context, modelTg *model.T
 	listResp, err := lattice.ListTargetsAsList(ctx, &listTargetsInput)
 	if err != nil {
 		if services.IsLatticeAPINotFoundErr(err) {
-			s.log.Debugf(ctx, "Target group %s was already deleted", modelTg.Status.Id)
-			return nil
+			return 0, 0, err
 		}
-		return fmt.Errorf("failed ListTargets %s due to %s", modelTg.Status.Id, err)
+		return 0, 0, fmt.Errorf("failed ListTargets %s due to %s", modelTg.Status.Id, err)
 	}
 
 	var targetsToDeregister []*vpclattice.Target
@@ -202,49 +373,28 @@ func (s *defaultTargetGroupManager) Delete(ctx context.Context, modelTg *model.T
 		}
 	}
 
-	if drainCount > 0 {
-		// no point in trying to deregister may as well wait
-		return fmt.Errorf("cannot deregister targets for %s as %d targets are DRAINING", modelTg.Status.Id, drainCount)
+	if drainCount > 0 || len(targetsToDeregister) == 0 {
+		return 0, drainCount, nil
 	}
 
-	if len(targetsToDeregister) > 0 {
-		var deregisterTargetsError error
-		chunks := utils.Chunks(targetsToDeregister, maxTargetsPerLatticeTargetsApiCall)
-		for i, targets := range chunks {
-			deregisterInput := vpclattice.DeregisterTargetsInput{
-				TargetGroupIdentifier: &modelTg.Status.Id,
-				Targets:               targets,
-			}
-			deregisterResponse, err := lattice.DeregisterTargetsWithContext(ctx, &deregisterInput)
-			if err != nil {
-				deregisterTargetsError = errors.Join(deregisterTargetsError, fmt.Errorf("failed to deregister targets from VPC Lattice Target Group %s due to %s", modelTg.Status.Id, err))
-			}
-			if len(deregisterResponse.Unsuccessful) > 0 {
-				deregisterTargetsError = errors.Join(deregisterTargetsError, fmt.Errorf("failed to deregister targets from VPC Lattice Target Group %s for chunk %d/%d, unsuccessful targets %v",
-					modelTg.Status.Id, i+1, len(chunks), deregisterResponse.Unsuccessful))
-			}
-			s.log.Debugf(ctx, "Successfully deregistered targets from VPC Lattice Target Group %s for chunk %d/%d", modelTg.Status.Id, i+1, len(chunks))
+	var deregisterTargetsError error
+	chunks := utils.Chunks(targetsToDeregister, maxTargetsPerLatticeTargetsApiCall)
+	for i, targets := range chunks {
+		deregisterInput := vpclattice.DeregisterTargetsInput{
+			TargetGroupIdentifier: &modelTg.Status.Id,
+			Targets:               targets,
 		}
-		if deregisterTargetsError != nil {
-			return deregisterTargetsError
+		deregisterResponse, err := lattice.DeregisterTargetsWithContext(ctx, &deregisterInput)
+		if err != nil {
+			deregisterTargetsError = errors.Join(deregisterTargetsError, fmt.Errorf("failed to deregister targets from VPC Lattice Target Group %s due to %s", modelTg.Status.Id, err))
 		}
-	}
-
-	deleteTGInput := vpclattice.DeleteTargetGroupInput{
-		TargetGroupIdentifier: &modelTg.Status.Id,
-	}
-	_, err = lattice.DeleteTargetGroupWithContext(ctx, &deleteTGInput)
-	if err != nil {
-		if services.IsLatticeAPINotFoundErr(err) {
-			s.log.Infof(ctx, "Target group %s was already deleted", modelTg.Status.Id)
-			return nil
-		} else {
-			return fmt.Errorf("failed DeleteTargetGroup %s due to %s", modelTg.Status.Id, err)
+		if len(deregisterResponse.Unsuccessful) > 0 {
+			deregisterTargetsError = errors.Join(deregisterTargetsError, fmt.Errorf("failed to deregister targets from VPC Lattice Target Group %s for chunk %d/%d, unsuccessful targets %v",
+				modelTg.Status.Id, i+1, len(chunks), deregisterResponse.Unsuccessful))
 		}
+		s.log.Debugf(ctx, "Successfully deregistered targets from VPC Lattice Target Group %s for chunk %d/%d", modelTg.Status.Id, i+1, len(chunks))
 	}
-
-	s.log.Infof(ctx, "Success DeleteTargetGroup %s", modelTg.Status.Id)
-	return nil
+	return len(targetsToDeregister), 0, deregisterTargetsError
 }
 
 type tgListOutput struct {
@@ -254,7 +404,11 @@ type tgListOutput struct {
 
 // Retrieve all TGs in the account, including tags. If individual tags fetch fails, tags will be nil for that tg
 func (s *defaultTargetGroupManager) List(ctx context.Context) ([]tgListOutput, error) {
-	lattice := s.cloud.Lattice()
+	return listTargetGroups(ctx, s.cloud)
+}
+
+func listTargetGroups(ctx context.Context, cloud pkg_aws.Cloud) ([]tgListOutput, error) {
+	lattice := cloud.Lattice()
 	var tgList []tgListOutput
 	targetGroupListInput := vpclattice.ListTargetGroupsInput{}
 	resp, err := lattice.ListTargetGroupsAsList(ctx, &targetGroupListInput)
@@ -267,7 +421,7 @@ func (s *defaultTargetGroupManager) List(ctx context.Context) ([]tgListOutput, e
 	tgArns := utils.SliceMap(resp, func(tg *vpclattice.TargetGroupSummary) string {
 		return aws.StringValue(tg.Arn)
 	})
-	tgArnToTagsMap, err := s.cloud.Tagging().GetTagsForArns(ctx, tgArns)
+	tgArnToTagsMap, err := cloud.Tagging().GetTagsForArns(ctx, tgArns)
 
 	if err != nil {
 		return nil, err
@@ -437,30 +591,159 @@ func (s *defaultTargetGroupManager) fillDefaultHealthCheckConfig(hc *vpclattice.
 	}
 }
 
+// findSvcExportTG returns the target group of the exported port of the backendRef, or of the lowest exported
+// port when the backendRef has no port. Target groups without port, exported by earlier releases, are used
+// when no target group has a port.
 func (s *defaultTargetGroupManager) findSvcExportTG(ctx context.Context, svcImportTg model.SvcImportTargetGroup) (string, error) {
-	tgs, err := s.List(ctx)
+	tgs, err := s.listForAccount(ctx, svcImportTg.AccountId)
 	if err != nil {
 		return "", err
 	}
+	var legacyTg, portTg *vpclattice.TargetGroupSummary
+	lowestPort := math.MaxInt
 	for _, tg := range tgs {
 		tgTags := model.TGTagFieldsFromTags(tg.tags)
 		svcMatch := tgTags.IsSourceTypeServiceExport() && (tgTags.K8SServiceName == svcImportTg.K8SServiceName) &&
 			(tgTags.K8SServiceNamespace == svcImportTg.K8SServiceNamespace)
 		clusterMatch := (svcImportTg.K8SClusterName == "") || (tgTags.K8SClusterName == svcImportTg.K8SClusterName)
 		vpcMatch := (svcImportTg.VpcId == "") || (svcImportTg.VpcId == aws.StringValue(tg.tgSummary.VpcIdentifier))
-		if svcMatch && clusterMatch && vpcMatch {
-			return *tg.tgSummary.Id, nil
+		if !svcMatch || !clusterMatch || !vpcMatch {
+			continue
 		}
+		if tgTags.K8SServicePort == "" {
+			if legacyTg == nil {
+				legacyTg = tg.tgSummary
+			}
+			continue
+		}
+		if svcImportTg.K8SServicePort != "" {
+			if tgTags.K8SServicePort == svcImportTg.K8SServicePort {
+				return s.svcExportTGId(ctx, svcImportTg, tg.tgSummary)
+			}
+			continue
+		}
+		if port, err := strconv.Atoi(tgTags.K8SServicePort); err == nil && port < lowestPort {
+			lowestPort = port
+			portTg = tg.tgSummary
+		}
+	}
+	if portTg != nil {
+		return s.svcExportTGId(ctx, svcImportTg, portTg)
+	}
+	if legacyTg != nil {
+		return s.svcExportTGId(ctx, svcImportTg, legacyTg)
+	}
+	if svcImportTg.K8SServicePort != "" {
+		return "", fmt.Errorf("target group for port %s of service import could not be found", svcImportTg.K8SServicePort)
 	}
 	return "", errors.New("target group for service import could not be found")
 }
 
+// svcExportTGId returns the id of a target group of the account, and the ARN of a target group of another
+// account once checked it is shared with the account
+func (s *defaultTargetGroupManager) svcExportTGId(ctx context.Context, svcImportTg model.SvcImportTargetGroup,
+	tg *vpclattice.TargetGroupSummary) (string, error) {
+
+	if !s.isOtherAccount(svcImportTg.AccountId) {
+		return aws.StringValue(tg.Id), nil
+	}
+	shared, err := s.isShared(ctx, aws.StringValue(tg.Arn))
+	if err != nil {
+		return "", err
+	}
+	if !shared {
+		return "", fmt.Errorf("%w: %s", ErrTargetGroupNotShared, aws.StringValue(tg.Arn))
+	}
+	return aws.StringValue(tg.Arn), nil
+}
+
+func (s *defaultTargetGroupManager) isOtherAccount(accountId string) bool {
+	return accountId != "" && accountId != s.cloud.Config().AccountId
+}
+
+// listForAccount lists the target groups of another account through its role, or of the account of the
+// controller when empty
+func (s *defaultTargetGroupManager) listForAccount(ctx context.Context, accountId string) ([]tgListOutput, error) {
+	if !s.isOtherAccount(accountId) {
+		return s.List(ctx)
+	}
+	cloud, err := s.cloud.ForAccount(accountId)
+	if err != nil {
+		return nil, &AccountAccessError{AccountId: accountId, Err: err}
+	}
+	tgs, err := listTargetGroups(ctx, cloud)
+	if err != nil {
+		return nil, &AccountAccessError{AccountId: accountId, Err: err}
+	}
+	return tgs, nil
+}
+
+// isShared returns whether the target group of another account is visible from the account of the
+// controller, through a RAM resource share
+func (s *defaultTargetGroupManager) isShared(ctx context.Context, arn string) (bool, error) {
+	_, err := s.cloud.Lattice().GetTargetGroupWithContext(ctx, &vpclattice.GetTargetGroupInput{
+		TargetGroupIdentifier: &arn,
+	})
+	if err == nil {
+		return true, nil
+	}
+	if aerr, ok := err.(awserr.Error); ok && aerr.Code() == vpclattice.ErrCodeAccessDeniedException {
+		return false, nil
+	}
+	if services.IsNotFoundError(err) {
+		return false, nil
+	}
+	return false, err
+}
+
+// FindSvcExportClusters returns the clusters exporting the service, sorted by cluster name. The clusters
+// of another account are looked up through its role.
+func (s *defaultTargetGroupManager) FindSvcExportClusters(ctx context.Context, namespace string, name string,
+	accountId string) ([]SvcExportCluster, error) {
+
+	tgs, err := s.listForAccount(ctx, accountId)
+	if err != nil {
+		return nil, err
+	}
+	var clusters []SvcExportCluster
+	for _, tg := range tgs {
+		tgTags := model.TGTagFieldsFromTags(tg.tags)
+		if !tgTags.IsSourceTypeServiceExport() || tgTags.K8SServiceName != name || tgTags.K8SServiceNamespace != namespace {
+			continue
+		}
+		i := slices.IndexFunc(clusters, func(c SvcExportCluster) bool { return c.ClusterName == tgTags.K8SClusterName })
+		if i < 0 {
+			clusters = append(clusters, SvcExportCluster{
+				ClusterName: tgTags.K8SClusterName,
+				VpcId:       aws.StringValue(tg.tgSummary.VpcIdentifier),
+			})
+			i = len(clusters) - 1
+		}
+		if port, err := strconv.ParseInt(tgTags.K8SServicePort, 10, 32); err == nil && !slices.Contains(clusters[i].Ports, int32(port)) {
+			clusters[i].Ports = append(clusters[i].Ports, int32(port))
+			slices.Sort(clusters[i].Ports)
+		}
+		if s.isOtherAccount(accountId) {
+			shared, err := s.isShared(ctx, aws.StringValue(tg.tgSummary.Arn))
+			if err != nil {
+				return nil, err
+			}
+			if !shared {
+				clusters[i].UnsharedTargetGroups = append(clusters[i].UnsharedTargetGroups, aws.StringValue(tg.tgSummary.Arn))
+			}
+		}
+	}
+	slices.SortFunc(clusters, func(a, b SvcExportCluster) int { return strings.Compare(a.ClusterName, b.ClusterName) })
+	return clusters, nil
+}
+
 // ResolveRuleTgIds populates all target group ids in the rule's actions
 func (s *defaultTargetGroupManager) ResolveRuleTgIds(ctx context.Context, ruleAction *model.RuleAction, stack core.Stack) error {
 	if len(ruleAction.TargetGroups) == 0 {
 		s.log.Debugf(ctx, "no target groups to resolve for rule")
 		return nil
 	}
+	var replaced []*model.RuleTargetGroup
 	for i, ruleActionTg := range ruleAction.TargetGroups {
 		if ruleActionTg.StackTargetGroupId == "" && ruleActionTg.SvcImportTG == nil && ruleActionTg.LatticeTgId == "" {
 			return errors.New("rule TG is missing a required target group identifier")
@@ -485,18 +768,120 @@ func (s *defaultTargetGroupManager) ResolveRuleTgIds(ctx context.Context, ruleAc
 				return errors.New("stack target group is missing Status field")
 			}
 			ruleActionTg.LatticeTgId = stackTg.Status.Id
+			if stackTg.Status.ReplacedId != "" && !stackTg.Status.ReplacementReady {
+				// the replaced target group keeps the traffic, the new one is forwarded to without weight
+				// so that Lattice checks the health of its targets
+				s.log.Debugf(ctx, "Keeping the traffic of TG %d on replaced target group %s", i, stackTg.Status.ReplacedId)
+				replaced = append(replaced, &model.RuleTargetGroup{
+					LatticeTgId: stackTg.Status.ReplacedId,
+					Weight:      ruleActionTg.Weight,
+				})
+				ruleActionTg.Weight = 0
+			}
 		}
 		if ruleActionTg.SvcImportTG != nil {
-			s.log.Debugf(ctx, "Getting target group for service import %s %s (%s, %s)",
+			s.log.Debugf(ctx, "Getting target group for service import %s %s (%s, %s, port %s)",
 				ruleActionTg.SvcImportTG.K8SServiceName, ruleActionTg.SvcImportTG.K8SServiceNamespace,
-				ruleActionTg.SvcImportTG.K8SClusterName, ruleActionTg.SvcImportTG.VpcId)
+				ruleActionTg.SvcImportTG.K8SClusterName, ruleActionTg.SvcImportTG.VpcId,
+				ruleActionTg.SvcImportTG.K8SServicePort)
 			tgId, err := s.findSvcExportTG(ctx, *ruleActionTg.SvcImportTG)
 
 			if err != nil {
-				return err
+				if ruleActionTg.ClusterGroup == "" {
+					return err
+				}
+				// checked with the other clusters of the group below
+				s.log.Infof(ctx, "Skipping cluster %s of service import %s/%s: %s", ruleActionTg.SvcImportTG.K8SClusterName,
+					ruleActionTg.SvcImportTG.K8SServiceNamespace, ruleActionTg.SvcImportTG.K8SServiceName, err)
+				ruleActionTg.LatticeTgId = model.InvalidBackendRefTgId
+				continue
 			}
 			ruleActionTg.LatticeTgId = tgId
 		}
 	}
+	for _, replacedTg := range replaced {
+		i := slices.IndexFunc(ruleAction.TargetGroups, func(tg *model.RuleTargetGroup) bool {
+			return tg.LatticeTgId == replacedTg.LatticeTgId
+		})
+		if i >= 0 {
+			ruleAction.TargetGroups[i].Weight += replacedTg.Weight
+			continue
+		}
+		ruleAction.TargetGroups = append(ruleAction.TargetGroups, replacedTg)
+	}
+	return s.resolveClusterGroups(ctx, ruleAction)
+}
+
+// resolveClusterGroups checks that every cluster group has a target group, and gives the weight of the
+// failover groups to their first target group with healthy targets, or to their first target group when
+// none has healthy targets
+func (s *defaultTargetGroupManager) resolveClusterGroups(ctx context.Context, ruleAction *model.RuleAction) error {
+	groups := map[string][]*model.RuleTargetGroup{}
+	var groupNames []string
+	for _, ruleActionTg := range ruleAction.TargetGroups {
+		if ruleActionTg.ClusterGroup == "" {
+			continue
+		}
+		if _, ok := groups[ruleActionTg.ClusterGroup]; !ok {
+			groupNames = append(groupNames, ruleActionTg.ClusterGroup)
+		}
+		groups[ruleActionTg.ClusterGroup] = append(groups[ruleActionTg.ClusterGroup], ruleActionTg)
+	}
+
+	for _, name := range groupNames {
+		var resolved []*model.RuleTargetGroup
+		for _, ruleActionTg := range groups[name] {
+			if ruleActionTg.LatticeTgId != model.InvalidBackendRefTgId {
+				resolved = append(resolved, ruleActionTg)
+			}
+		}
+		if len(resolved) == 0 {
+			svcImportTg := groups[name][0].SvcImportTG
+			return fmt.Errorf("target group for service import %s/%s could not be found in any cluster",
+				svcImportTg.K8SServiceNamespace, svcImportTg.K8SServiceName)
+		}
+		if !resolved[0].Failover {
+			continue
+		}
+
+		active := resolved[0]
+		for _, ruleActionTg := range resolved {
+			// e.g. access denied or throttled on the target group of another account, the cluster is
+			// considered unhealthy so that the traffic still fails over to the other clusters
+			healthy, err := s.hasHealthyTargets(ctx, ruleActionTg.LatticeTgId)
+			if err != nil {
+				s.log.Warnf(ctx, "Considering cluster %s of %s unhealthy: %s", ruleActionTg.SvcImportTG.K8SClusterName, name, err)
+				continue
+			}
+			if healthy {
+				active = ruleActionTg
+				break
+			}
+		}
+		weight := active.Weight
+		for _, ruleActionTg := range groups[name] {
+			ruleActionTg.Weight = 0
+		}
+		active.Weight = weight
+		s.log.Debugf(ctx, "Sending the traffic of %s to cluster %s", name, active.SvcImportTG.K8SClusterName)
+	}
 	return nil
 }
+
+// hasHealthyTargets returns whether a target group has targets able to receive traffic, the targets of
+// target groups without health checks are unavailable rather than healthy
+func (s *defaultTargetGroupManager) hasHealthyTargets(ctx context.Context, tgId string) (bool, error) {
+	targets, err := s.cloud.Lattice().ListTargetsAsList(ctx, &vpclattice.ListTargetsInput{
+		TargetGroupIdentifier: aws.String(tgId),
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to list targets of target group %s: %w", tgId, err)
+	}
+	for _, target := range targets {
+		status := aws.StringValue(target.Status)
+		if status == vpclattice.TargetStatusHealthy || status == vpclattice.TargetStatusUnavailable {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -51,6 +51,36 @@ func (mr *MockTargetGroupManagerMockRecorder) Delete(arg0, arg1 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTargetGroupManager)(nil).Delete), arg0, arg1)
 }
 
+// Drain mocks base method.
+func (m *MockTargetGroupManager) Drain(arg0 context.Context, arg1 *lattice0.TargetGroup) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Drain", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Drain indicates an expected call of Drain.
+func (mr *MockTargetGroupManagerMockRecorder) Drain(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Drain", reflect.TypeOf((*MockTargetGroupManager)(nil).Drain), arg0, arg1)
+}
+
+// FindSvcExportClusters mocks base method.
+func (m *MockTargetGroupManager) FindSvcExportClusters(arg0 context.Context, arg1, arg2, arg3 string) ([]SvcExportCluster, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindSvcExportClusters", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]SvcExportCluster)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindSvcExportClusters indicates an expected call of FindSvcExportClusters.
+func (mr *MockTargetGroupManagerMockRecorder) FindSvcExportClusters(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindSvcExportClusters", reflect.TypeOf((*MockTargetGroupManager)(nil).FindSvcExportClusters), arg0, arg1, arg2, arg3)
+}
+
 // IsTargetGroupMatch mocks base method.
 func (m *MockTargetGroupManager) IsTargetGroupMatch(arg0 context.Context, arg1 *lattice0.TargetGroup, arg2 *vpclattice.TargetGroupSummary, arg3 *lattice0.TargetGroupTagFields) (bool, error) {
 	m.ctrl.T.Helper()
@@ -81,6 +111,20 @@ func (mr *MockTargetGroupManagerMockRecorder) List(arg0 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockTargetGroupManager)(nil).List), arg0)
 }
 
+// ResolveReplacedTargetGroup mocks base method.
+func (m *MockTargetGroupManager) ResolveReplacedTargetGroup(arg0 context.Context, arg1 *lattice0.TargetGroup, arg2 core.Stack) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveReplacedTargetGroup", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResolveReplacedTargetGroup indicates an expected call of ResolveReplacedTargetGroup.
+func (mr *MockTargetGroupManagerMockRecorder) ResolveReplacedTargetGroup(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveReplacedTargetGroup", reflect.TypeOf((*MockTargetGroupManager)(nil).ResolveReplacedTargetGroup), arg0, arg1, arg2)
+}
+
 // ResolveRuleTgIds mocks base method.
 func (m *MockTargetGroupManager) ResolveRuleTgIds(arg0 context.Context, arg1 *lattice0.RuleAction, arg2 core.Stack) error {
 	m.ctrl.T.Helper()
@@ -5,9 +5,11 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/vpclattice"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -309,6 +311,85 @@ func Test_CreateTargetGroup_TGActive_HealthCheckSame(t *testing.T) {
 	assert.Equal(t, "id", resp.Id)
 }
 
+// target group created before DEFAULT_TAGS changed, found with the tags it was created with
+func Test_CreateTargetGroup_DefaultTagsChanged(t *testing.T) {
+	ctx := context.TODO()
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	mockLattice := mocks.NewMockLattice(c)
+	mockTagging := mocks.NewMockTagging(c)
+	cloud := pkg_aws.NewDefaultCloudWithTagging(mockLattice, mockTagging, TestCloudConfig)
+	defer config.ApplyLive(config.DEFAULT_TAGS, "")
+
+	tgSpec := model.TargetGroupSpec{
+		Port:            80,
+		Protocol:        vpclattice.TargetGroupProtocolHttp,
+		ProtocolVersion: vpclattice.TargetGroupProtocolVersionHttp1,
+		Type:            model.TargetGroupTypeIP,
+	}
+	tgSpec.VpcId = "vpc-id"
+	tgSpec.K8SClusterName = "cluster-name"
+	tgSpec.K8SSourceType = model.SourceTypeHTTPRoute
+	tgSpec.K8SServiceName = "svc"
+	tgSpec.K8SServiceNamespace = "ns"
+	tgSpec.K8SRouteName = "route"
+	tgSpec.K8SRouteNamespace = "ns"
+	tgSpec.K8SProtocolVersion = vpclattice.TargetGroupProtocolVersionHttp1
+
+	// the tagging API finds the resources with all the tags of the filter
+	var createdTags mocks.Tags
+	mockTagging.EXPECT().FindResourcesByTags(ctx, mocks.ResourceTypeTargetGroup, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ mocks.ResourceType, tags mocks.Tags) ([]string, error) {
+			if createdTags == nil {
+				return nil, nil
+			}
+			for key, value := range tags {
+				if aws.StringValue(createdTags[key]) != aws.StringValue(value) {
+					return nil, nil
+				}
+			}
+			return []string{"tg-arn"}, nil
+		}).Times(3)
+	mockLattice.EXPECT().CreateTargetGroupWithContext(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, input *vpclattice.CreateTargetGroupInput, _ ...interface{}) (*vpclattice.CreateTargetGroupOutput, error) {
+			createdTags = input.Tags
+			return &vpclattice.CreateTargetGroupOutput{
+				Arn:    aws.String("tg-arn"),
+				Id:     aws.String("tg-id"),
+				Name:   aws.String("tg-name"),
+				Status: aws.String(vpclattice.TargetGroupStatusActive),
+			}, nil
+		})
+	mockLattice.EXPECT().GetTargetGroupWithContext(ctx, gomock.Any()).Return(&vpclattice.GetTargetGroupOutput{
+		Arn:    aws.String("tg-arn"),
+		Id:     aws.String("tg-id"),
+		Name:   aws.String("tg-name"),
+		Status: aws.String(vpclattice.TargetGroupStatusActive),
+		Type:   aws.String(vpclattice.TargetGroupTypeIp),
+		Config: &vpclattice.TargetGroupConfig{
+			Port:            aws.Int64(80),
+			Protocol:        aws.String(vpclattice.TargetGroupProtocolHttp),
+			ProtocolVersion: aws.String(vpclattice.TargetGroupProtocolVersionHttp1),
+			VpcIdentifier:   aws.String("vpc-id"),
+		},
+	}, nil).Times(2)
+	mockLattice.EXPECT().UpdateTargetGroupWithContext(ctx, gomock.Any()).Return(nil, nil).Times(2)
+
+	tgManager := NewTargetGroupManager(gwlog.FallbackLogger, cloud)
+	resp, err := tgManager.Upsert(ctx, &model.TargetGroup{Spec: tgSpec})
+	assert.Nil(t, err)
+	assert.Equal(t, "tg-id", resp.Id)
+	assert.NotContains(t, createdTags, "team")
+
+	for _, defaultTags := range []string{"team=networking", "team=platform"} {
+		assert.Nil(t, config.ApplyLive(config.DEFAULT_TAGS, defaultTags))
+		resp, err = tgManager.Upsert(ctx, &model.TargetGroup{Spec: tgSpec})
+		assert.Nil(t, err)
+		assert.Equal(t, "tg-id", resp.Id)
+	}
+}
+
 // target group status is create-in-progress before creation, return Retry
 func Test_CreateTargetGroup_ExistingTG_Status_Retry(t *testing.T) {
 	c := gomock.NewController(t)
@@ -773,6 +854,61 @@ func Test_DeleteTG_DeRegisterTargets_DeleteTargetGroupFailed(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
+func Test_DrainTG(t *testing.T) {
+	target := func(status string) *vpclattice.TargetSummary {
+		return &vpclattice.TargetSummary{Id: aws.String("10.0.0.1"), Port: aws.Int64(8080), Status: aws.String(status)}
+	}
+	tests := []struct {
+		name             string
+		targets          []*vpclattice.TargetSummary
+		listErr          error
+		expectDeregister bool
+		expectedDrained  bool
+	}{
+		{
+			name:             "targets are deregistered",
+			targets:          []*vpclattice.TargetSummary{target(vpclattice.TargetStatusHealthy)},
+			expectDeregister: true,
+		},
+		{
+			name:    "targets are draining",
+			targets: []*vpclattice.TargetSummary{target(vpclattice.TargetStatusDraining)},
+		},
+		{
+			name:            "no targets left",
+			expectedDrained: true,
+		},
+		{
+			name:            "target group already deleted",
+			listErr:         &vpclattice.ResourceNotFoundException{},
+			expectedDrained: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			ctx := context.TODO()
+			mockLattice := mocks.NewMockLattice(c)
+			cloud := pkg_aws.NewDefaultCloudWithTagging(mockLattice, mocks.NewMockTagging(c), TestCloudConfig)
+
+			mockLattice.EXPECT().ListTargetsAsList(ctx, gomock.Any()).Return(tt.targets, tt.listErr)
+			if tt.expectDeregister {
+				mockLattice.EXPECT().DeregisterTargetsWithContext(ctx, &vpclattice.DeregisterTargetsInput{
+					TargetGroupIdentifier: aws.String("tg-id"),
+					Targets:               []*vpclattice.Target{{Id: aws.String("10.0.0.1"), Port: aws.Int64(8080)}},
+				}).Return(&vpclattice.DeregisterTargetsOutput{}, nil)
+			}
+			mockLattice.EXPECT().DeleteTargetGroupWithContext(ctx, gomock.Any()).Times(0)
+
+			tgManager := NewTargetGroupManager(gwlog.FallbackLogger, cloud)
+			drained, err := tgManager.Drain(ctx, &model.TargetGroup{Status: &model.TargetGroupStatus{Id: "tg-id"}})
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expectedDrained, drained)
+		})
+	}
+}
+
 func Test_ListTG_TGsExist(t *testing.T) {
 	arn := "123456789"
 	id := "123456789"
@@ -1153,3 +1289,501 @@ func Test_ResolveRuleTgIds(t *testing.T) {
 	assert.Equal(t, "tg-id", stackRule.Spec.Action.TargetGroups[1].LatticeTgId)
 	assert.Equal(t, model.InvalidBackendRefTgId, stackRule.Spec.Action.TargetGroups[2].LatticeTgId)
 }
+
+func Test_ResolveRuleTgIds_ReplacedTargetGroup(t *testing.T) {
+	tests := []struct {
+		name            string
+		ready           bool
+		expectedWeights map[string]int64
+	}{
+		{
+			name:            "traffic kept on the replaced target group",
+			expectedWeights: map[string]int64{"tg-id": 0, "old-tg-id": 80, "other-tg-id": 20},
+		},
+		{
+			name:            "traffic shifted to the new target group",
+			ready:           true,
+			expectedWeights: map[string]int64{"tg-id": 80, "other-tg-id": 20},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			ctx := context.TODO()
+
+			stack := core.NewDefaultStack(core.StackID{Name: "foo", Namespace: "bar"})
+			stackTg := &model.TargetGroup{
+				ResourceMeta: core.NewResourceMeta(stack, "AWS:VPCServiceNetwork::TargetGroup", "stack-tg-id"),
+				Status: &model.TargetGroupStatus{
+					Id:               "tg-id",
+					ReplacedId:       "old-tg-id",
+					ReplacementReady: tt.ready,
+				},
+			}
+			assert.NoError(t, stack.AddResource(stackTg))
+			ruleAction := &model.RuleAction{
+				TargetGroups: []*model.RuleTargetGroup{
+					{StackTargetGroupId: "stack-tg-id", Weight: 80},
+					{LatticeTgId: "other-tg-id", Weight: 20},
+				},
+			}
+
+			s := NewTargetGroupManager(gwlog.FallbackLogger, pkg_aws.NewMockCloud(c))
+			assert.NoError(t, s.ResolveRuleTgIds(ctx, ruleAction, stack))
+
+			weights := map[string]int64{}
+			for _, ruleTg := range ruleAction.TargetGroups {
+				weights[ruleTg.LatticeTgId] = ruleTg.Weight
+			}
+			assert.Equal(t, tt.expectedWeights, weights)
+		})
+	}
+}
+
+func Test_CreateTargetGroup_ReplacedTargetGroup(t *testing.T) {
+	tests := []struct {
+		name          string
+		targetStatus  string
+		expectedReady bool
+	}{
+		{
+			name:         "new target group without healthy targets",
+			targetStatus: vpclattice.TargetStatusInitial,
+		},
+		{
+			name:          "new target group with healthy targets",
+			targetStatus:  vpclattice.TargetStatusHealthy,
+			expectedReady: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			ctx := context.TODO()
+
+			config.VpcID = "vpc-id"
+			config.ClusterName = "cluster-name"
+			mockLattice := mocks.NewMockLattice(c)
+			mockTagging := mocks.NewMockTagging(c)
+			cloud := pkg_aws.NewDefaultCloudWithTagging(mockLattice, mockTagging, TestCloudConfig)
+
+			tgSpec := model.TargetGroupSpec{
+				Port:            int32(8080),
+				Protocol:        vpclattice.TargetGroupProtocolHttp,
+				ProtocolVersion: vpclattice.TargetGroupProtocolVersionGrpc,
+			}
+			tgSpec.VpcId = config.VpcID
+			tgSpec.K8SClusterName = config.ClusterName
+			tgSpec.K8SSourceType = model.SourceTypeGRPCRoute
+			tgSpec.K8SServiceName = "backend-svc1"
+			tgSpec.K8SServiceNamespace = "default"
+			tgSpec.K8SRouteName = "grpcroute1"
+			tgSpec.K8SRouteNamespace = "default"
+			tgSpec.Type = model.TargetGroupTypeIP
+
+			mockTagging.EXPECT().FindResourcesByTags(ctx, gomock.Any(), gomock.Any()).Return(nil, nil)
+			mockLattice.EXPECT().CreateTargetGroupWithContext(ctx, gomock.Any()).Return(
+				&vpclattice.CreateTargetGroupOutput{
+					Arn:    aws.String("tg-arn-2"),
+					Id:     aws.String("tg-id-2"),
+					Name:   aws.String("tg-name-2"),
+					Status: aws.String(vpclattice.TargetGroupStatusActive),
+				}, nil)
+
+			// the target group of the previous protocol version is found without the protocol version tag
+			mockTagging.EXPECT().FindResourcesByTags(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, resourceType mocks.ResourceType, tags map[string]*string) ([]string, error) {
+					assert.NotContains(t, tags, model.K8SProtocolVersionKey)
+					return []string{"tg-arn-1", "tg-arn-2"}, nil
+				})
+			mockLattice.EXPECT().GetTargetGroupWithContext(ctx, gomock.Any()).Return(
+				&vpclattice.GetTargetGroupOutput{
+					Arn:         aws.String("tg-arn-1"),
+					Id:          aws.String("tg-id-1"),
+					Status:      aws.String(vpclattice.TargetGroupStatusActive),
+					ServiceArns: []*string{aws.String("svc-arn")},
+					Config: &vpclattice.TargetGroupConfig{
+						VpcIdentifier: aws.String(config.VpcID),
+					},
+				}, nil)
+			mockLattice.EXPECT().ListTargetsAsList(ctx, gomock.Any()).DoAndReturn(
+				func(ctx context.Context, input *vpclattice.ListTargetsInput) ([]*vpclattice.TargetSummary, error) {
+					assert.Equal(t, "tg-id-2", aws.StringValue(input.TargetGroupIdentifier))
+					return []*vpclattice.TargetSummary{{Status: aws.String(tt.targetStatus)}}, nil
+				})
+
+			stack := core.NewDefaultStack(core.StackID{Name: "foo", Namespace: "bar"})
+			modelTg := &model.TargetGroup{
+				ResourceMeta: core.NewResourceMeta(stack, "AWS:VPCServiceNetwork::TargetGroup", "stack-tg-id"),
+				Spec:         tgSpec,
+			}
+			assert.NoError(t, stack.AddResource(modelTg))
+
+			tgManager := NewTargetGroupManager(gwlog.FallbackLogger, cloud)
+			resp, err := tgManager.Upsert(ctx, modelTg)
+			assert.Nil(t, err)
+			modelTg.Status = &resp
+			err = tgManager.ResolveReplacedTargetGroup(ctx, modelTg, stack)
+
+			assert.Nil(t, err)
+			assert.Equal(t, "tg-id-2", modelTg.Status.Id)
+			assert.Equal(t, "tg-id-1", modelTg.Status.ReplacedId)
+			assert.Equal(t, tt.expectedReady, modelTg.Status.ReplacementReady)
+		})
+	}
+}
+
+func Test_ResolveReplacedTargetGroup_OtherProtocolVersionOfTheStack(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+	ctx := context.TODO()
+
+	config.VpcID = "vpc-id"
+	config.ClusterName = "cluster-name"
+	mockLattice := mocks.NewMockLattice(c)
+	mockTagging := mocks.NewMockTagging(c)
+	cloud := pkg_aws.NewDefaultCloudWithTagging(mockLattice, mockTagging, TestCloudConfig)
+
+	// the same service is sent to target groups of two protocol versions by the route
+	stack := core.NewDefaultStack(core.StackID{Name: "foo", Namespace: "bar"})
+	tgSpec := func(protocolVersion string) model.TargetGroupSpec {
+		spec := model.TargetGroupSpec{
+			Port:            80,
+			Protocol:        vpclattice.TargetGroupProtocolHttp,
+			ProtocolVersion: protocolVersion,
+			Type:            model.TargetGroupTypeIP,
+		}
+		spec.VpcId = config.VpcID
+		spec.K8SClusterName = config.ClusterName
+		spec.K8SSourceType = model.SourceTypeHTTPRoute
+		spec.K8SServiceName = "backend-svc1"
+		spec.K8SServiceNamespace = "default"
+		spec.K8SRouteName = "route1"
+		spec.K8SRouteNamespace = "default"
+		return spec
+	}
+	http1Tg := &model.TargetGroup{
+		ResourceMeta: core.NewResourceMeta(stack, "AWS:VPCServiceNetwork::TargetGroup", "http1-tg"),
+		Spec:         tgSpec(vpclattice.TargetGroupProtocolVersionHttp1),
+		Status:       &model.TargetGroupStatus{Arn: "tg-arn-1", Id: "tg-id-1"},
+	}
+	http2Tg := &model.TargetGroup{
+		ResourceMeta: core.NewResourceMeta(stack, "AWS:VPCServiceNetwork::TargetGroup", "http2-tg"),
+		Spec:         tgSpec(vpclattice.TargetGroupProtocolVersionHttp2),
+		Status:       &model.TargetGroupStatus{Arn: "tg-arn-2", Id: "tg-id-2"},
+	}
+	assert.NoError(t, stack.AddResource(http1Tg))
+	assert.NoError(t, stack.AddResource(http2Tg))
+
+	mockTagging.EXPECT().FindResourcesByTags(ctx, gomock.Any(), gomock.Any()).Return(
+		[]string{"tg-arn-1", "tg-arn-2"}, nil).Times(2)
+	mockLattice.EXPECT().GetTargetGroupWithContext(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, input *vpclattice.GetTargetGroupInput, arg3 ...interface{}) (*vpclattice.GetTargetGroupOutput, error) {
+			id := strings.Replace(aws.StringValue(input.TargetGroupIdentifier), "arn", "id", 1)
+			return &vpclattice.GetTargetGroupOutput{
+				Arn:         input.TargetGroupIdentifier,
+				Id:          aws.String(id),
+				Status:      aws.String(vpclattice.TargetGroupStatusActive),
+				ServiceArns: []*string{aws.String("svc-arn")},
+				Config: &vpclattice.TargetGroupConfig{
+					VpcIdentifier: aws.String(config.VpcID),
+				},
+			}, nil
+		}).Times(2)
+
+	tgManager := NewTargetGroupManager(gwlog.FallbackLogger, cloud)
+	assert.NoError(t, tgManager.ResolveReplacedTargetGroup(ctx, http1Tg, stack))
+	assert.NoError(t, tgManager.ResolveReplacedTargetGroup(ctx, http2Tg, stack))
+
+	assert.Empty(t, http1Tg.Status.ReplacedId)
+	assert.Empty(t, http2Tg.Status.ReplacedId)
+}
+
+func Test_ResolveRuleTgIds_ServiceExportPorts(t *testing.T) {
+	config.VpcID = "vpc-id"
+	config.ClusterName = "cluster-name"
+
+	c := gomock.NewController(t)
+	defer c.Finish()
+	ctx := context.TODO()
+	mockLattice := mocks.NewMockLattice(c)
+	mockTagging := mocks.NewMockTagging(c)
+	mockCloud := pkg_aws.NewMockCloud(c)
+	mockCloud.EXPECT().Lattice().Return(mockLattice).AnyTimes()
+	mockCloud.EXPECT().Tagging().Return(mockTagging).AnyTimes()
+
+	svcExportTags := func(port string) map[string]*string {
+		tags := map[string]*string{
+			model.K8SServiceNameKey:      aws.String("svc-name"),
+			model.K8SServiceNamespaceKey: aws.String("ns"),
+			model.K8SClusterNameKey:      aws.String("cluster-name"),
+			model.K8SSourceTypeKey:       aws.String(string(model.SourceTypeSvcExport)),
+		}
+		if port != "" {
+			tags[model.K8SServicePortKey] = aws.String(port)
+		}
+		return tags
+	}
+	mockTagging.EXPECT().GetTagsForArns(ctx, gomock.Any()).Return(
+		map[string]map[string]*string{
+			"legacy-tg-arn": svcExportTags(""),
+			"tg-9090-arn":   svcExportTags("9090"),
+			"tg-8080-arn":   svcExportTags("8080"),
+		}, nil).AnyTimes()
+	mockLattice.EXPECT().ListTargetGroupsAsList(ctx, gomock.Any()).Return(
+		[]*vpclattice.TargetGroupSummary{
+			{Arn: aws.String("legacy-tg-arn"), VpcIdentifier: aws.String("vpc-id"), Id: aws.String("legacy-tg-id")},
+			{Arn: aws.String("tg-9090-arn"), VpcIdentifier: aws.String("vpc-id"), Id: aws.String("tg-9090-id")},
+			{Arn: aws.String("tg-8080-arn"), VpcIdentifier: aws.String("vpc-id"), Id: aws.String("tg-8080-id")},
+		}, nil).AnyTimes()
+
+	s := NewTargetGroupManager(gwlog.FallbackLogger, mockCloud)
+	stack := core.NewDefaultStack(core.StackID{Name: "foo", Namespace: "bar"})
+	resolve := func(port string) (string, error) {
+		action := &model.RuleAction{TargetGroups: []*model.RuleTargetGroup{{
+			SvcImportTG: &model.SvcImportTargetGroup{
+				K8SServiceName:      "svc-name",
+				K8SServiceNamespace: "ns",
+				K8SServicePort:      port,
+			},
+		}}}
+		err := s.ResolveRuleTgIds(ctx, action, stack)
+		return action.TargetGroups[0].LatticeTgId, err
+	}
+
+	tgId, err := resolve("9090")
+	assert.NoError(t, err)
+	assert.Equal(t, "tg-9090-id", tgId)
+
+	// the lowest exported port without backendRef port
+	tgId, err = resolve("")
+	assert.NoError(t, err)
+	assert.Equal(t, "tg-8080-id", tgId)
+
+	// the target group of earlier releases when the port has no target group
+	tgId, err = resolve("7070")
+	assert.NoError(t, err)
+	assert.Equal(t, "legacy-tg-id", tgId)
+}
+
+func Test_ResolveRuleTgIds_ServiceImportPolicyClusters(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+	ctx := context.TODO()
+	mockLattice := mocks.NewMockLattice(c)
+	mockTagging := mocks.NewMockTagging(c)
+	mockCloud := pkg_aws.NewMockCloud(c)
+	mockCloud.EXPECT().Lattice().Return(mockLattice).AnyTimes()
+	mockCloud.EXPECT().Tagging().Return(mockTagging).AnyTimes()
+
+	svcExportTags := func(cluster string) map[string]*string {
+		return map[string]*string{
+			model.K8SServiceNameKey:      aws.String("svc-name"),
+			model.K8SServiceNamespaceKey: aws.String("ns"),
+			model.K8SClusterNameKey:      aws.String(cluster),
+			model.K8SSourceTypeKey:       aws.String(string(model.SourceTypeSvcExport)),
+			model.K8SServicePortKey:      aws.String("80"),
+		}
+	}
+	mockTagging.EXPECT().GetTagsForArns(ctx, gomock.Any()).Return(
+		map[string]map[string]*string{
+			"tg-a-arn": svcExportTags("cluster-a"),
+			"tg-b-arn": svcExportTags("cluster-b"),
+		}, nil).AnyTimes()
+	mockLattice.EXPECT().ListTargetGroupsAsList(ctx, gomock.Any()).Return(
+		[]*vpclattice.TargetGroupSummary{
+			{Arn: aws.String("tg-a-arn"), VpcIdentifier: aws.String("vpc-a"), Id: aws.String("tg-a-id")},
+			{Arn: aws.String("tg-b-arn"), VpcIdentifier: aws.String("vpc-b"), Id: aws.String("tg-b-id")},
+		}, nil).AnyTimes()
+	healthy := map[string]string{}
+	listTargetsErr := map[string]error{}
+	mockLattice.EXPECT().ListTargetsAsList(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, input *vpclattice.ListTargetsInput) ([]*vpclattice.TargetSummary, error) {
+			if err := listTargetsErr[*input.TargetGroupIdentifier]; err != nil {
+				return nil, err
+			}
+			return []*vpclattice.TargetSummary{
+				{Id: aws.String("10.0.0.1"), Status: aws.String(healthy[*input.TargetGroupIdentifier])},
+			}, nil
+		}).AnyTimes()
+
+	s := NewTargetGroupManager(gwlog.FallbackLogger, mockCloud)
+	stack := core.NewDefaultStack(core.StackID{Name: "foo", Namespace: "bar"})
+	resolve := func(failover bool, clusters ...string) ([]*model.RuleTargetGroup, error) {
+		action := &model.RuleAction{}
+		for _, cluster := range clusters {
+			action.TargetGroups = append(action.TargetGroups, &model.RuleTargetGroup{
+				SvcImportTG: &model.SvcImportTargetGroup{
+					K8SClusterName:      cluster,
+					K8SServiceName:      "svc-name",
+					K8SServiceNamespace: "ns",
+				},
+				Weight:       10,
+				ClusterGroup: "ns/svc-name-0",
+				Failover:     failover,
+			})
+		}
+		err := s.ResolveRuleTgIds(ctx, action, stack)
+		return action.TargetGroups, err
+	}
+
+	// a cluster without target group is skipped
+	tgs, err := resolve(false, "cluster-a", "cluster-c")
+	assert.NoError(t, err)
+	assert.Equal(t, "tg-a-id", tgs[0].LatticeTgId)
+	assert.Equal(t, model.InvalidBackendRefTgId, tgs[1].LatticeTgId)
+
+	_, err = resolve(false, "cluster-c")
+	assert.Error(t, err)
+
+	// the primary gets the traffic while it has healthy targets
+	healthy["tg-a-id"] = vpclattice.TargetStatusHealthy
+	healthy["tg-b-id"] = vpclattice.TargetStatusHealthy
+	tgs, err = resolve(true, "cluster-a", "cluster-b")
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{10, 0}, []int64{tgs[0].Weight, tgs[1].Weight})
+
+	healthy["tg-a-id"] = vpclattice.TargetStatusUnhealthy
+	tgs, err = resolve(true, "cluster-a", "cluster-b")
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{0, 10}, []int64{tgs[0].Weight, tgs[1].Weight})
+
+	// the primary keeps the traffic when no cluster has healthy targets
+	healthy["tg-b-id"] = vpclattice.TargetStatusDraining
+	tgs, err = resolve(true, "cluster-a", "cluster-b")
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{10, 0}, []int64{tgs[0].Weight, tgs[1].Weight})
+
+	// a cluster whose targets cannot be listed is unhealthy, the others still get the traffic
+	healthy["tg-a-id"] = vpclattice.TargetStatusHealthy
+	healthy["tg-b-id"] = vpclattice.TargetStatusHealthy
+	listTargetsErr["tg-a-id"] = awserr.New(vpclattice.ErrCodeAccessDeniedException, "denied", nil)
+	tgs, err = resolve(true, "cluster-a", "cluster-b")
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{0, 10}, []int64{tgs[0].Weight, tgs[1].Weight})
+
+	listTargetsErr["tg-b-id"] = awserr.New(vpclattice.ErrCodeThrottlingException, "throttled", nil)
+	tgs, err = resolve(true, "cluster-a", "cluster-b")
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{10, 0}, []int64{tgs[0].Weight, tgs[1].Weight})
+}
+
+func Test_FindSvcExportClusters(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+	ctx := context.TODO()
+	mockLattice := mocks.NewMockLattice(c)
+	mockTagging := mocks.NewMockTagging(c)
+	mockCloud := pkg_aws.NewMockCloud(c)
+	mockCloud.EXPECT().Lattice().Return(mockLattice).AnyTimes()
+	mockCloud.EXPECT().Tagging().Return(mockTagging).AnyTimes()
+
+	svcExportTags := func(name, cluster, port string) map[string]*string {
+		return model.TagsFromTGTagFields(model.TargetGroupTagFields{
+			K8SServiceName:      name,
+			K8SServiceNamespace: "ns",
+			K8SClusterName:      cluster,
+			K8SSourceType:       model.SourceTypeSvcExport,
+			K8SServicePort:      port,
+		})
+	}
+	mockTagging.EXPECT().GetTagsForArns(ctx, gomock.Any()).Return(
+		map[string]map[string]*string{
+			"tg-b-443": svcExportTags("svc-name", "cluster-b", "443"),
+			"tg-a-80":  svcExportTags("svc-name", "cluster-a", "80"),
+			"tg-b-80":  svcExportTags("svc-name", "cluster-b", "80"),
+			"tg-c":     svcExportTags("svc-name", "cluster-c", ""),
+			"tg-other": svcExportTags("other", "cluster-a", "80"),
+		}, nil)
+	mockLattice.EXPECT().ListTargetGroupsAsList(ctx, gomock.Any()).Return(
+		[]*vpclattice.TargetGroupSummary{
+			{Arn: aws.String("tg-b-443"), VpcIdentifier: aws.String("vpc-b")},
+			{Arn: aws.String("tg-a-80"), VpcIdentifier: aws.String("vpc-a")},
+			{Arn: aws.String("tg-b-80"), VpcIdentifier: aws.String("vpc-b")},
+			{Arn: aws.String("tg-c"), VpcIdentifier: aws.String("vpc-c")},
+			{Arn: aws.String("tg-other"), VpcIdentifier: aws.String("vpc-a")},
+		}, nil)
+
+	s := NewTargetGroupManager(gwlog.FallbackLogger, mockCloud)
+	clusters, err := s.FindSvcExportClusters(ctx, "ns", "svc-name", "")
+	assert.NoError(t, err)
+	assert.Equal(t, []SvcExportCluster{
+		{ClusterName: "cluster-a", VpcId: "vpc-a", Ports: []int32{80}},
+		{ClusterName: "cluster-b", VpcId: "vpc-b", Ports: []int32{80, 443}},
+		{ClusterName: "cluster-c", VpcId: "vpc-c"},
+	}, clusters)
+}
+
+func Test_FindSvcExportClusters_OtherAccount(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+	ctx := context.TODO()
+	mockLattice := mocks.NewMockLattice(c)
+	mockCloud := pkg_aws.NewMockCloud(c)
+	mockCloud.EXPECT().Lattice().Return(mockLattice).AnyTimes()
+	mockCloud.EXPECT().Config().Return(TestCloudConfig).AnyTimes()
+
+	otherLattice := mocks.NewMockLattice(c)
+	otherTagging := mocks.NewMockTagging(c)
+	otherCloud := pkg_aws.NewMockCloud(c)
+	otherCloud.EXPECT().Lattice().Return(otherLattice).AnyTimes()
+	otherCloud.EXPECT().Tagging().Return(otherTagging).AnyTimes()
+	mockCloud.EXPECT().ForAccount("other-account").Return(otherCloud, nil).AnyTimes()
+	mockCloud.EXPECT().ForAccount("unknown-account").Return(nil, pkg_aws.ErrNoAccountRole).AnyTimes()
+
+	svcExportTags := func(cluster string) map[string]*string {
+		return model.TagsFromTGTagFields(model.TargetGroupTagFields{
+			K8SServiceName:      "svc-name",
+			K8SServiceNamespace: "ns",
+			K8SClusterName:      cluster,
+			K8SSourceType:       model.SourceTypeSvcExport,
+			K8SServicePort:      "80",
+		})
+	}
+	otherTagging.EXPECT().GetTagsForArns(ctx, gomock.Any()).Return(
+		map[string]map[string]*string{
+			"arn-a": svcExportTags("cluster-a"),
+			"arn-b": svcExportTags("cluster-b"),
+		}, nil).AnyTimes()
+	otherLattice.EXPECT().ListTargetGroupsAsList(ctx, gomock.Any()).Return(
+		[]*vpclattice.TargetGroupSummary{
+			{Arn: aws.String("arn-a"), Id: aws.String("tg-a"), VpcIdentifier: aws.String("vpc-a")},
+			{Arn: aws.String("arn-b"), Id: aws.String("tg-b"), VpcIdentifier: aws.String("vpc-b")},
+		}, nil).AnyTimes()
+
+	// only arn-a is shared with the account of the controller
+	mockLattice.EXPECT().GetTargetGroupWithContext(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, input *vpclattice.GetTargetGroupInput, opts ...interface{}) (*vpclattice.GetTargetGroupOutput, error) {
+			if aws.StringValue(input.TargetGroupIdentifier) == "arn-a" {
+				return &vpclattice.GetTargetGroupOutput{Arn: input.TargetGroupIdentifier}, nil
+			}
+			return nil, awserr.New(vpclattice.ErrCodeAccessDeniedException, "denied", nil)
+		}).AnyTimes()
+
+	s := NewTargetGroupManager(gwlog.FallbackLogger, mockCloud)
+	clusters, err := s.FindSvcExportClusters(ctx, "ns", "svc-name", "other-account")
+	assert.NoError(t, err)
+	assert.Equal(t, []SvcExportCluster{
+		{ClusterName: "cluster-a", VpcId: "vpc-a", Ports: []int32{80}},
+		{ClusterName: "cluster-b", VpcId: "vpc-b", Ports: []int32{80}, UnsharedTargetGroups: []string{"arn-b"}},
+	}, clusters)
+
+	// the shared target group is referenced by ARN
+	tgId, err := s.findSvcExportTG(ctx, model.SvcImportTargetGroup{
+		K8SClusterName: "cluster-a", K8SServiceName: "svc-name", K8SServiceNamespace: "ns", AccountId: "other-account",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "arn-a", tgId)
+
+	_, err = s.findSvcExportTG(ctx, model.SvcImportTargetGroup{
+		K8SClusterName: "cluster-b", K8SServiceName: "svc-name", K8SServiceNamespace: "ns", AccountId: "other-account",
+	})
+	assert.ErrorIs(t, err, ErrTargetGroupNotShared)
+
+	_, err = s.FindSvcExportClusters(ctx, "ns", "svc-name", "unknown-account")
+	accessErr := &AccountAccessError{}
+	assert.ErrorAs(t, err, &accessErr)
+	assert.ErrorIs(t, err, pkg_aws.ErrNoAccountRole)
+}
@@ -4,24 +4,43 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/vpclattice"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	anv1alpha1 "github.com/aws/aws-application-networking-k8s/pkg/apis/applicationnetworking/v1alpha1"
 	"github.com/aws/aws-application-networking-k8s/pkg/gateway"
+	policy "github.com/aws/aws-application-networking-k8s/pkg/k8s/policyhelper"
+	"github.com/aws/aws-application-networking-k8s/pkg/utils"
 	"github.com/aws/aws-application-networking-k8s/pkg/utils/gwlog"
 
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	pkg_aws "github.com/aws/aws-application-networking-k8s/pkg/aws"
+	"github.com/aws/aws-application-networking-k8s/pkg/aws/services"
 	"github.com/aws/aws-application-networking-k8s/pkg/config"
 	"github.com/aws/aws-application-networking-k8s/pkg/model/core"
 	model "github.com/aws/aws-application-networking-k8s/pkg/model/lattice"
 )
 
+// unused target groups are kept for this period after their creation, so that the deployment which created
+// them has the time to update the rules forwarding to them, even when it runs on another replica
+const targetGroupGcGracePeriod = 5 * time.Minute
+
+const (
+	TargetGroupReplacementReasonWaiting   = "WaitingForHealthyTargets"
+	TargetGroupReplacementReasonShifted   = "TrafficShifted"
+	TargetGroupReplacementReasonCompleted = "ReplacementCompleted"
+)
+
 // helpful for testing/mocking
 func NewTargetGroupSynthesizer(
 	log gwlog.Logger,
@@ -80,6 +99,23 @@ func (t *TargetGroupSynthesizer) SynthesizeCreate(ctx context.Context) error {
 		} else {
 			t.log.Debugf(ctx, "Failed TargetGroupManager.Upsert %s due to %s", prefix, err)
 			returnErr = true
+			continue
+		}
+	}
+
+	if returnErr {
+		return fmt.Errorf("error during target group synthesis, will retry")
+	}
+
+	// replacements are resolved once the ids of all the target groups of the stack are known
+	for _, resTargetGroup := range resTargetGroups {
+		if resTargetGroup.IsDeleted {
+			continue
+		}
+		prefix := model.TgNamePrefix(resTargetGroup.Spec)
+		if err := t.targetGroupManager.ResolveReplacedTargetGroup(ctx, resTargetGroup, t.stack); err != nil {
+			t.log.Debugf(ctx, "Failed TargetGroupManager.ResolveReplacedTargetGroup %s due to %s", prefix, err)
+			returnErr = true
 		}
 	}
 
@@ -116,6 +152,94 @@ func (t *TargetGroupSynthesizer) SynthesizeDelete(ctx context.Context) error {
 	return nil
 }
 
+// syncReplacementCondition records the replacements of the target groups of the stack's route forwarding to a
+// Service on its TargetGroupPolicy, once the rules are deployed, and sets the TargetGroupsReplaced condition
+// from the replacements of all the routes forwarding to the Service
+func (t *TargetGroupSynthesizer) syncReplacementCondition(ctx context.Context, key types.NamespacedName,
+	tgs []*model.TargetGroup) error {
+
+	svc := &corev1.Service{}
+	if err := t.client.Get(ctx, key, svc); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	tgp, err := policy.NewTargetGroupPolicyHandler(t.log, t.client).ObjResolvedPolicy(ctx, svc)
+	if err != nil || tgp == nil {
+		return err
+	}
+
+	spec := tgs[0].Spec
+	route := fmt.Sprintf("%s/%s/%s", spec.K8SSourceType, spec.K8SRouteNamespace, spec.K8SRouteName)
+	var replacements, previous []anv1alpha1.TargetGroupReplacement
+	for _, r := range tgp.Status.Replacements {
+		if r.Route == route {
+			previous = append(previous, r)
+		} else {
+			replacements = append(replacements, r)
+		}
+	}
+	var current []anv1alpha1.TargetGroupReplacement
+	for _, tg := range tgs {
+		if tg.Status.ReplacedId == "" {
+			continue
+		}
+		current = append(current, anv1alpha1.TargetGroupReplacement{
+			Route:      route,
+			Id:         tg.Status.Id,
+			ReplacedId: tg.Status.ReplacedId,
+			Ready:      tg.Status.ReplacementReady,
+		})
+	}
+	replacements = append(replacements, current...)
+
+	// the replacements of the route completed once their new target groups alone receive its traffic
+	completed := len(current) == 0 && len(previous) > 0 && slices.ContainsFunc(previous,
+		func(r anv1alpha1.TargetGroupReplacement) bool {
+			return slices.ContainsFunc(tgs, func(tg *model.TargetGroup) bool {
+				return tg.Status.Id == r.Id && tg.Status.ReplacedId == ""
+			})
+		})
+
+	existing := meta.FindStatusCondition(tgp.Status.Conditions, anv1alpha1.TargetGroupPolicyReplaced)
+	condition := metav1.Condition{
+		Type:               anv1alpha1.TargetGroupPolicyReplaced,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: tgp.Generation,
+	}
+	ready := !slices.ContainsFunc(replacements, func(r anv1alpha1.TargetGroupReplacement) bool { return !r.Ready })
+	descriptions := utils.SliceMap(replacements, func(r anv1alpha1.TargetGroupReplacement) string {
+		return fmt.Sprintf("%s replaces %s", r.Id, r.ReplacedId)
+	})
+	switch {
+	case len(replacements) > 0 && !ready:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = TargetGroupReplacementReasonWaiting
+		condition.Message = fmt.Sprintf("Waiting for healthy targets before shifting traffic: %s",
+			strings.Join(descriptions, ", "))
+	case len(replacements) > 0:
+		condition.Reason = TargetGroupReplacementReasonShifted
+		condition.Message = fmt.Sprintf("Traffic shifted: %s, the replaced target groups are deleted once drained",
+			strings.Join(descriptions, ", "))
+	case completed:
+		condition.Reason = TargetGroupReplacementReasonCompleted
+		condition.Message = "Target groups replaced, the replaced target groups no longer receive traffic"
+	case len(previous) > 0:
+		// the route no longer forwards to the new target groups, there is no replacement left to report
+		meta.RemoveStatusCondition(&tgp.Status.Conditions, anv1alpha1.TargetGroupPolicyReplaced)
+		tgp.Status.Replacements = nil
+		return t.client.Status().Update(ctx, tgp)
+	default:
+		return nil
+	}
+	if slices.Equal(tgp.Status.Replacements, replacements) && existing != nil &&
+		existing.Status == condition.Status && existing.Reason == condition.Reason &&
+		existing.Message == condition.Message && existing.ObservedGeneration == condition.ObservedGeneration {
+		return nil
+	}
+	tgp.Status.Replacements = replacements
+	meta.SetStatusCondition(&tgp.Status.Conditions, condition)
+	return t.client.Status().Update(ctx, tgp)
+}
+
 // result of deletion attempt, if err is nil target group was deleted
 type DeleteUnusedResult struct {
 	Arn string
@@ -132,9 +256,17 @@ func (t *TargetGroupSynthesizer) SynthesizeUnusedDelete(ctx context.Context) ([]
 		return nil, err
 	}
 
-	results := make([]DeleteUnusedResult, len(tgsToDelete))
+	var results []DeleteUnusedResult
+	for _, tg := range tgsToDelete {
+		unused, err := t.isStillUnused(ctx, tg)
+		if err != nil {
+			results = append(results, DeleteUnusedResult{Arn: aws.StringValue(tg.tgSummary.Arn), Err: err})
+			continue
+		}
+		if !unused {
+			continue
+		}
 
-	for i, tg := range tgsToDelete {
 		modelStatus := model.TargetGroupStatus{
 			Name: aws.StringValue(tg.tgSummary.Name),
 			Arn:  aws.StringValue(tg.tgSummary.Arn),
@@ -145,16 +277,51 @@ func (t *TargetGroupSynthesizer) SynthesizeUnusedDelete(ctx context.Context) ([]
 			IsDeleted: true,
 		}
 
-		err := t.targetGroupManager.Delete(ctx, &modelTg)
-		results[i] = DeleteUnusedResult{
+		// e.g. a target group replaced by another one just stopped receiving traffic, the requests
+		// in flight complete on its draining targets, it is deleted by a later cycle once they are gone
+		drained, err := t.targetGroupManager.Drain(ctx, &modelTg)
+		if err != nil || !drained {
+			if err != nil {
+				results = append(results, DeleteUnusedResult{Arn: modelTg.Status.Arn, Err: err})
+			}
+			continue
+		}
+
+		err = t.targetGroupManager.Delete(ctx, &modelTg)
+		results = append(results, DeleteUnusedResult{
 			Arn: modelTg.Status.Arn,
 			Err: err,
-		}
+		})
 	}
 
 	return results, nil
 }
 
+// isStillUnused reads the target group again right before its deletion. The GC runs on the leader only,
+// while routes are reconciled by several sharded replicas, so a target group listed as unused may have been
+// taken into use since, or just created by a deployment which did not update its rules yet.
+func (t *TargetGroupSynthesizer) isStillUnused(ctx context.Context, tg tgListOutput) (bool, error) {
+	latticeTg, err := t.cloud.Lattice().GetTargetGroupWithContext(ctx, &vpclattice.GetTargetGroupInput{
+		TargetGroupIdentifier: tg.tgSummary.Id,
+	})
+	if err != nil {
+		if services.IsNotFoundError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	if len(latticeTg.ServiceArns) > 0 {
+		t.log.Debugf(ctx, "TargetGroup %s is referenced by lattice service since it was listed", aws.StringValue(latticeTg.Arn))
+		return false, nil
+	}
+	if time.Since(aws.TimeValue(latticeTg.CreatedAt)) < targetGroupGcGracePeriod {
+		t.log.Debugf(ctx, "TargetGroup %s was created less than %s ago, not deleting it yet",
+			aws.StringValue(latticeTg.Arn), targetGroupGcGracePeriod)
+		return false, nil
+	}
+	return true, nil
+}
+
 func (t *TargetGroupSynthesizer) calculateTargetGroupsToDelete(ctx context.Context) ([]tgListOutput, error) {
 	latticeTgs, err := t.targetGroupManager.List(ctx)
 	if err != nil {
@@ -235,14 +402,27 @@ func (t *TargetGroupSynthesizer) shouldDeleteSvcExportTg(
 	// now we get to the tricky business of seeing if our unused target group actually matches
 	// the current state of the service and service export - the most correct way to do this is to
 	// reconstruct the target group spec from the service export itself, then compare fields
-	modelTg, err := t.svcExportTgBuilder.BuildTargetGroup(ctx, svcExport)
+	modelTgs, err := t.svcExportTgBuilder.BuildTargetGroups(ctx, svcExport)
 	if err != nil {
 		t.log.Infof(ctx, "Received error building svc export target group model %s", err)
 		return false
 	}
 
-	// the main identifiers are validated, just need to check the other essentials.
-	// protocolVersion is not in TG summary so we are bringing it from tags.
+	// the main identifiers are validated, just need to find the target group of the same port and
+	// check the other essentials. protocolVersion is not in TG summary so we are bringing it from tags.
+	var modelTg *model.TargetGroup
+	for _, tg := range modelTgs {
+		if tg.Spec.K8SServicePort == tagFields.K8SServicePort {
+			modelTg = tg
+			break
+		}
+	}
+	if modelTg == nil {
+		t.log.Infof(ctx, "Will delete TargetGroup %s (%s) - service port %q is not exported",
+			*latticeTg.tgSummary.Arn, *latticeTg.tgSummary.Name, tagFields.K8SServicePort)
+		return true
+	}
+
 	if int64(modelTg.Spec.Port) != aws.Int64Value(latticeTg.tgSummary.Port) ||
 		modelTg.Spec.Protocol != aws.StringValue(latticeTg.tgSummary.Protocol) ||
 		modelTg.Spec.ProtocolVersion != tagFields.K8SProtocolVersion ||
@@ -381,7 +561,26 @@ func (t *TargetGroupSynthesizer) hasExpectedTags(latticeTg tgListOutput, tagFiel
 	return true
 }
 
+// PostSynthesize reports the replacements of route target groups on the TargetGroupPolicy of their Service,
+// after the rules shifted the traffic. Reporting is informational, failures do not fail the deployment.
 func (t *TargetGroupSynthesizer) PostSynthesize(ctx context.Context) error {
-	// nothing to do here
+	var resTargetGroups []*model.TargetGroup
+	if err := t.stack.ListResources(&resTargetGroups); err != nil {
+		return err
+	}
+
+	svcTgs := map[types.NamespacedName][]*model.TargetGroup{}
+	for _, tg := range resTargetGroups {
+		if tg.IsDeleted || tg.Status == nil || !tg.Spec.IsSourceTypeRoute() {
+			continue
+		}
+		key := types.NamespacedName{Namespace: tg.Spec.K8SServiceNamespace, Name: tg.Spec.K8SServiceName}
+		svcTgs[key] = append(svcTgs[key], tg)
+	}
+	for key, tgs := range svcTgs {
+		if err := t.syncReplacementCondition(ctx, key, tgs); err != nil {
+			t.log.Infof(ctx, "Failed to update the replacement condition of the policy of service %s due to %s", key, err)
+		}
+	}
 	return nil
 }
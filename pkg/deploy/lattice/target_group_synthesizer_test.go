@@ -8,12 +8,21 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/vpclattice"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gwv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 
 	mock_client "github.com/aws/aws-application-networking-k8s/mocks/controller-runtime/client"
+	anv1alpha1 "github.com/aws/aws-application-networking-k8s/pkg/apis/applicationnetworking/v1alpha1"
+	pkg_aws "github.com/aws/aws-application-networking-k8s/pkg/aws"
+	"github.com/aws/aws-application-networking-k8s/pkg/aws/services"
 	"github.com/aws/aws-application-networking-k8s/pkg/config"
 	"github.com/aws/aws-application-networking-k8s/pkg/gateway"
 	"github.com/aws/aws-application-networking-k8s/pkg/model/core"
@@ -46,6 +55,7 @@ func Test_Synthesize(t *testing.T) {
 
 	mockTGManager.EXPECT().Delete(ctx, tgToDelete).Return(nil)
 	mockTGManager.EXPECT().Upsert(ctx, tgToCreate).Return(model.TargetGroupStatus{Name: "create-name"}, nil)
+	mockTGManager.EXPECT().ResolveReplacedTargetGroup(ctx, tgToCreate, stack).Return(nil)
 
 	synthesizer := NewTargetGroupSynthesizer(gwlog.FallbackLogger, nil, nil, mockTGManager, nil, nil, stack)
 
@@ -242,7 +252,7 @@ func Test_DoNotDeleteCases(t *testing.T) {
 	svcExportModelTg := baseModelTg
 	svcExportModelTg.Spec.TargetGroupTagFields.K8SSourceType = model.SourceTypeSvcExport
 
-	mockSvcExportTgBuilder.EXPECT().BuildTargetGroup(ctx, gomock.Any()).Return(&svcExportModelTg, nil)
+	mockSvcExportTgBuilder.EXPECT().BuildTargetGroups(ctx, gomock.Any()).Return([]*model.TargetGroup{&svcExportModelTg}, nil)
 
 	stack := core.NewDefaultStack(core.StackID{Name: "foo", Namespace: "bar"})
 	svcModelTg := baseModelTg
@@ -294,10 +304,11 @@ func Test_DeleteServiceExport_DeleteCases(t *testing.T) {
 					Reason: metav1.StatusReasonNotFound,
 				},
 			})
+		mockTGManager.EXPECT().Drain(ctx, gomock.Any()).Return(true, nil)
 		mockTGManager.EXPECT().Delete(ctx, gomock.Any()).Return(nil)
 
 		synthesizer := NewTargetGroupSynthesizer(
-			gwlog.FallbackLogger, nil, mockClient, mockTGManager, mockSvcExportTgBuilder, nil, nil)
+			gwlog.FallbackLogger, unusedTgCloud(c), mockClient, mockTGManager, mockSvcExportTgBuilder, nil, nil)
 
 		_, err := synthesizer.SynthesizeUnusedDelete(ctx)
 		assert.Nil(t, err)
@@ -316,10 +327,11 @@ func Test_DeleteServiceExport_DeleteCases(t *testing.T) {
 				return nil
 			},
 		)
+		mockTGManager.EXPECT().Drain(ctx, gomock.Any()).Return(true, nil)
 		mockTGManager.EXPECT().Delete(ctx, gomock.Any()).Return(nil)
 
 		synthesizer := NewTargetGroupSynthesizer(
-			gwlog.FallbackLogger, nil, mockClient, mockTGManager, mockSvcExportTgBuilder, nil, nil)
+			gwlog.FallbackLogger, unusedTgCloud(c), mockClient, mockTGManager, mockSvcExportTgBuilder, nil, nil)
 
 		_, err := synthesizer.SynthesizeUnusedDelete(ctx)
 		assert.Nil(t, err)
@@ -351,13 +363,14 @@ func Test_DeleteServiceExport_DeleteCases(t *testing.T) {
 			},
 		)
 
-		mockSvcExportTgBuilder.EXPECT().BuildTargetGroup(ctx, gomock.Any()).Return(&modelTg, nil)
+		mockSvcExportTgBuilder.EXPECT().BuildTargetGroups(ctx, gomock.Any()).Return([]*model.TargetGroup{&modelTg}, nil)
 
 		mockTGManager.EXPECT().List(ctx).Return(deleteTgs, nil)
+		mockTGManager.EXPECT().Drain(ctx, gomock.Any()).Return(true, nil)
 		mockTGManager.EXPECT().Delete(ctx, gomock.Any()).Return(nil)
 
 		synthesizer := NewTargetGroupSynthesizer(
-			gwlog.FallbackLogger, nil, mockClient, mockTGManager, mockSvcExportTgBuilder, nil, nil)
+			gwlog.FallbackLogger, unusedTgCloud(c), mockClient, mockTGManager, mockSvcExportTgBuilder, nil, nil)
 
 		_, err := synthesizer.SynthesizeUnusedDelete(ctx)
 		assert.Nil(t, err)
@@ -397,10 +410,11 @@ func Test_DeleteRoute_DeleteCases(t *testing.T) {
 					Reason: metav1.StatusReasonNotFound,
 				},
 			})
+		mockTGManager.EXPECT().Drain(ctx, gomock.Any()).Return(true, nil)
 		mockTGManager.EXPECT().Delete(ctx, gomock.Any()).Return(nil)
 
 		synthesizer := NewTargetGroupSynthesizer(
-			gwlog.FallbackLogger, nil, mockClient, mockTGManager, nil, mockSvcBuilder, nil)
+			gwlog.FallbackLogger, unusedTgCloud(c), mockClient, mockTGManager, nil, mockSvcBuilder, nil)
 
 		_, err := synthesizer.SynthesizeUnusedDelete(ctx)
 		assert.Nil(t, err)
@@ -419,10 +433,11 @@ func Test_DeleteRoute_DeleteCases(t *testing.T) {
 				return nil
 			},
 		)
+		mockTGManager.EXPECT().Drain(ctx, gomock.Any()).Return(true, nil)
 		mockTGManager.EXPECT().Delete(ctx, gomock.Any()).Return(nil)
 
 		synthesizer := NewTargetGroupSynthesizer(
-			gwlog.FallbackLogger, nil, mockClient, mockTGManager, nil, mockSvcBuilder, nil)
+			gwlog.FallbackLogger, unusedTgCloud(c), mockClient, mockTGManager, nil, mockSvcBuilder, nil)
 
 		_, err := synthesizer.SynthesizeUnusedDelete(ctx)
 		assert.Nil(t, err)
@@ -469,14 +484,185 @@ func Test_DeleteRoute_DeleteCases(t *testing.T) {
 		// this is actually what decides if the tgs are a match or not
 		mockTGManager.EXPECT().IsTargetGroupMatch(ctx, gomock.Any(), gomock.Any(), gomock.Any()).
 			Return(false, nil)
+		mockTGManager.EXPECT().Drain(ctx, gomock.Any()).Return(true, nil)
 		mockTGManager.EXPECT().Delete(ctx, gomock.Any()).Return(nil)
 
 		synthesizer := NewTargetGroupSynthesizer(
-			gwlog.FallbackLogger, nil, mockClient, mockTGManager, nil, mockSvcBuilder, nil)
+			gwlog.FallbackLogger, unusedTgCloud(c), mockClient, mockTGManager, nil, mockSvcBuilder, nil)
 
 		_, err := synthesizer.SynthesizeUnusedDelete(ctx)
 		assert.Nil(t, err)
 	})
 }
 
+// unusedTgCloud returns the target groups as unused and created long ago when they are read before deletion
+func unusedTgCloud(c *gomock.Controller) pkg_aws.Cloud {
+	mockCloud := pkg_aws.NewMockCloud(c)
+	mockLattice := services.NewMockLattice(c)
+	mockCloud.EXPECT().Lattice().Return(mockLattice).AnyTimes()
+	mockLattice.EXPECT().GetTargetGroupWithContext(gomock.Any(), gomock.Any()).Return(&vpclattice.GetTargetGroupOutput{
+		Arn:       aws.String("tg-arn"),
+		CreatedAt: aws.Time(time.Now().Add(-time.Hour)),
+	}, nil).AnyTimes()
+	return mockCloud
+}
+
+func Test_DeleteUnused_RecheckedBeforeDelete(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+	ctx := context.TODO()
+
+	config.VpcID = "vpc-id"
+	config.ClusterName = "cluster-name"
+
+	tgSvcExport := copy(getBaseTg())
+	tgSvcExport.tags[model.K8SSourceTypeKey] = aws.String(string(model.SourceTypeSvcExport))
+
+	tests := []struct {
+		name      string
+		latticeTg *vpclattice.GetTargetGroupOutput
+	}{
+		{
+			name: "taken into use by another replica",
+			latticeTg: &vpclattice.GetTargetGroupOutput{
+				CreatedAt:   aws.Time(time.Now().Add(-time.Hour)),
+				ServiceArns: []*string{aws.String("svc-arn")},
+			},
+		},
+		{
+			name:      "just created",
+			latticeTg: &vpclattice.GetTargetGroupOutput{CreatedAt: aws.Time(time.Now())},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockTGManager := NewMockTargetGroupManager(c)
+			mockClient := mock_client.NewMockClient(c)
+			mockCloud := pkg_aws.NewMockCloud(c)
+			mockLattice := services.NewMockLattice(c)
+			mockCloud.EXPECT().Lattice().Return(mockLattice).AnyTimes()
+
+			mockTGManager.EXPECT().List(ctx).Return([]tgListOutput{tgSvcExport}, nil)
+			mockClient.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(
+				&apierrors.StatusError{ErrStatus: metav1.Status{Code: http.StatusNotFound, Reason: metav1.StatusReasonNotFound}})
+			mockLattice.EXPECT().GetTargetGroupWithContext(ctx, &vpclattice.GetTargetGroupInput{
+				TargetGroupIdentifier: aws.String("tg-id"),
+			}).Return(tt.latticeTg, nil)
+			mockTGManager.EXPECT().Delete(ctx, gomock.Any()).Times(0)
+
+			synthesizer := NewTargetGroupSynthesizer(
+				gwlog.FallbackLogger, mockCloud, mockClient, mockTGManager, nil, nil, nil)
+			results, err := synthesizer.SynthesizeUnusedDelete(ctx)
+			assert.Nil(t, err)
+			assert.Empty(t, results)
+		})
+	}
+}
+
+func Test_DeleteUnused_DrainedBeforeDelete(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+	ctx := context.TODO()
+
+	config.VpcID = "vpc-id"
+	config.ClusterName = "cluster-name"
+
+	tgSvcExport := copy(getBaseTg())
+	tgSvcExport.tags[model.K8SSourceTypeKey] = aws.String(string(model.SourceTypeSvcExport))
+
+	mockTGManager := NewMockTargetGroupManager(c)
+	mockClient := mock_client.NewMockClient(c)
+	mockTGManager.EXPECT().List(ctx).Return([]tgListOutput{tgSvcExport}, nil)
+	mockClient.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(
+		&apierrors.StatusError{ErrStatus: metav1.Status{Code: http.StatusNotFound, Reason: metav1.StatusReasonNotFound}})
+
+	// targets are still draining, the target group is deleted by a later cycle
+	mockTGManager.EXPECT().Drain(ctx, gomock.Any()).Return(false, nil)
+	mockTGManager.EXPECT().Delete(ctx, gomock.Any()).Times(0)
+
+	synthesizer := NewTargetGroupSynthesizer(
+		gwlog.FallbackLogger, unusedTgCloud(c), mockClient, mockTGManager, nil, nil, nil)
+	results, err := synthesizer.SynthesizeUnusedDelete(ctx)
+	assert.Nil(t, err)
+	assert.Empty(t, results)
+}
+
 // TODO: Error cases should not delete
+
+func Test_PostSynthesize_ReplacementCondition(t *testing.T) {
+	ctx := context.TODO()
+	k8sScheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(k8sScheme)
+	anv1alpha1.AddToScheme(k8sScheme)
+	tgp := &anv1alpha1.TargetGroupPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "tgp", Namespace: "default"},
+		Spec: anv1alpha1.TargetGroupPolicySpec{
+			TargetRef: &gwv1alpha2.NamespacedPolicyTargetReference{Kind: "Service", Name: "svc"},
+		},
+	}
+	k8sClient := testclient.NewClientBuilder().WithScheme(k8sScheme).WithObjects(
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "svc", Namespace: "default"}},
+		tgp,
+	).WithStatusSubresource(tgp).Build()
+
+	deploy := func(route string, status model.TargetGroupStatus) *metav1.Condition {
+		stack := core.NewDefaultStack(core.StackID{Name: route, Namespace: "default"})
+		tg := &model.TargetGroup{
+			ResourceMeta: core.NewResourceMeta(stack, "AWS:VPCServiceNetwork::TargetGroup", "tg"),
+			Status:       &status,
+		}
+		tg.Spec.K8SSourceType = model.SourceTypeHTTPRoute
+		tg.Spec.K8SRouteName = route
+		tg.Spec.K8SRouteNamespace = "default"
+		tg.Spec.K8SServiceName = "svc"
+		tg.Spec.K8SServiceNamespace = "default"
+		assert.NoError(t, stack.AddResource(tg))
+
+		synthesizer := NewTargetGroupSynthesizer(gwlog.FallbackLogger, nil, k8sClient, nil, nil, nil, stack)
+		assert.NoError(t, synthesizer.PostSynthesize(ctx))
+
+		assert.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(tgp), tgp))
+		return meta.FindStatusCondition(tgp.Status.Conditions, anv1alpha1.TargetGroupPolicyReplaced)
+	}
+
+	// a route forwarding to the service without replacement leaves the condition alone
+	assert.Nil(t, deploy("route-a", model.TargetGroupStatus{Id: "tg-id-2"}))
+
+	cnd := deploy("route-a", model.TargetGroupStatus{Id: "tg-id-2", ReplacedId: "tg-id-1"})
+	assert.Equal(t, metav1.ConditionFalse, cnd.Status)
+	assert.Equal(t, TargetGroupReplacementReasonWaiting, cnd.Reason)
+
+	// the replacements of other routes are kept
+	cnd = deploy("route-b", model.TargetGroupStatus{Id: "tg-id-4", ReplacedId: "tg-id-3", ReplacementReady: true})
+	assert.Equal(t, TargetGroupReplacementReasonWaiting, cnd.Reason)
+	assert.Equal(t, []anv1alpha1.TargetGroupReplacement{
+		{Route: "HTTPRoute/default/route-a", Id: "tg-id-2", ReplacedId: "tg-id-1"},
+		{Route: "HTTPRoute/default/route-b", Id: "tg-id-4", ReplacedId: "tg-id-3", Ready: true},
+	}, tgp.Status.Replacements)
+
+	cnd = deploy("route-a", model.TargetGroupStatus{Id: "tg-id-2", ReplacedId: "tg-id-1", ReplacementReady: true})
+	assert.Equal(t, metav1.ConditionTrue, cnd.Status)
+	assert.Equal(t, TargetGroupReplacementReasonShifted, cnd.Reason)
+
+	// route-b no longer forwards to its new target group, ids are compared exactly
+	cnd = deploy("route-b", model.TargetGroupStatus{Id: "tg-id-40"})
+	assert.Equal(t, TargetGroupReplacementReasonShifted, cnd.Reason)
+	assert.Equal(t, []anv1alpha1.TargetGroupReplacement{
+		{Route: "HTTPRoute/default/route-a", Id: "tg-id-2", ReplacedId: "tg-id-1", Ready: true},
+	}, tgp.Status.Replacements)
+
+	cnd = deploy("route-a", model.TargetGroupStatus{Id: "tg-id-2"})
+	assert.Equal(t, metav1.ConditionTrue, cnd.Status)
+	assert.Equal(t, TargetGroupReplacementReasonCompleted, cnd.Reason)
+	assert.NotContains(t, cnd.Message, "tg-id-1")
+	assert.Empty(t, tgp.Status.Replacements)
+
+	cnd = deploy("route-a", model.TargetGroupStatus{Id: "tg-id-2"})
+	assert.Equal(t, TargetGroupReplacementReasonCompleted, cnd.Reason)
+
+	// a replacement that did not complete is no longer reported
+	cnd = deploy("route-b", model.TargetGroupStatus{Id: "tg-id-6", ReplacedId: "tg-id-5"})
+	assert.Equal(t, TargetGroupReplacementReasonWaiting, cnd.Reason)
+	assert.Nil(t, deploy("route-b", model.TargetGroupStatus{Id: "tg-id-60"}))
+	assert.Empty(t, tgp.Status.Replacements)
+}
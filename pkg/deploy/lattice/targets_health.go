@@ -0,0 +1,348 @@
+package lattice
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/vpclattice"
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	anv1alpha1 "github.com/aws/aws-application-networking-k8s/pkg/apis/applicationnetworking/v1alpha1"
+	model "github.com/aws/aws-application-networking-k8s/pkg/model/lattice"
+	"github.com/aws/aws-application-networking-k8s/pkg/utils"
+)
+
+const (
+	// Pod condition reporting the Lattice health of the pod's targets. Unlike the readiness gate
+	// condition it is set on every pod backing a target group, and keeps being updated after the pod is ready.
+	LatticeTargetHealthConditionType corev1.PodConditionType = "application-networking.k8s.aws/lattice-target-health"
+
+	// Service condition reporting the Lattice health of the service's targets
+	ServiceTargetsHealthyConditionType = "application-networking.k8s.aws/TargetsHealthy"
+
+	TargetsHealthReasonNoTargets = "NoTargets"
+)
+
+var latticeTargets = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Subsystem: "lattice",
+	Name:      "targets",
+	Help:      "Number of targets registered to a VPC Lattice target group, by target status",
+}, []string{"target_group", "namespace", "service", "status"})
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(latticeTargets)
+}
+
+// serviceHealthKey identifies the Service, or the ServiceExport, a target group reports its health on
+type serviceHealthKey struct {
+	types.NamespacedName
+	export bool
+}
+
+// serviceTargetsHealth holds the last target status counts of every target group, grouped by the Service
+// or ServiceExport they belong to, so that a single TargetsHealthy condition covers all of their target groups
+type serviceTargetsHealth struct {
+	lock      sync.Mutex
+	byService map[serviceHealthKey]map[string]model.TargetsStatus
+}
+
+var targetsHealth = &serviceTargetsHealth{byService: map[serviceHealthKey]map[string]model.TargetsStatus{}}
+
+func healthKeyOf(tg *model.TargetGroup) serviceHealthKey {
+	return serviceHealthKey{
+		NamespacedName: types.NamespacedName{Namespace: tg.Spec.K8SServiceNamespace, Name: tg.Spec.K8SServiceName},
+		export:         tg.Spec.K8SSourceType == model.SourceTypeSvcExport,
+	}
+}
+
+func (h *serviceTargetsHealth) record(key serviceHealthKey, tgId string, status *model.TargetsStatus) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if h.byService[key] == nil {
+		h.byService[key] = map[string]model.TargetsStatus{}
+	}
+	h.byService[key][tgId] = *status
+}
+
+func (h *serviceTargetsHealth) delete(tgId string) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	for key, tgs := range h.byService {
+		delete(tgs, tgId)
+		if len(tgs) == 0 {
+			delete(h.byService, key)
+		}
+	}
+}
+
+// sum returns the target group ids of a Service or ServiceExport, in order, and their summed target counts
+func (h *serviceTargetsHealth) sum(key serviceHealthKey) ([]string, *model.TargetsStatus) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	total := &model.TargetsStatus{}
+	var tgIds []string
+	for tgId, status := range h.byService[key] {
+		tgIds = append(tgIds, tgId)
+		total.Healthy += status.Healthy
+		total.Unhealthy += status.Unhealthy
+		total.Initial += status.Initial
+		total.Unavailable += status.Unavailable
+		total.Unused += status.Unused
+		total.Draining += status.Draining
+		for code, count := range status.ReasonCodes {
+			if total.ReasonCodes == nil {
+				total.ReasonCodes = map[string]int32{}
+			}
+			total.ReasonCodes[code] += count
+		}
+	}
+	sort.Strings(tgIds)
+	return tgIds, total
+}
+
+func recordTargetsMetric(tg *model.TargetGroup, status *model.TargetsStatus) {
+	if tg.Status == nil || tg.Status.Id == "" {
+		return
+	}
+	if tg.Spec.K8SServiceName != "" {
+		targetsHealth.record(healthKeyOf(tg), tg.Status.Id, status)
+	}
+	counts := map[string]int32{
+		vpclattice.TargetStatusHealthy:     status.Healthy,
+		vpclattice.TargetStatusUnhealthy:   status.Unhealthy,
+		vpclattice.TargetStatusInitial:     status.Initial,
+		vpclattice.TargetStatusUnavailable: status.Unavailable,
+		vpclattice.TargetStatusUnused:      status.Unused,
+		vpclattice.TargetStatusDraining:    status.Draining,
+	}
+	for targetStatus, count := range counts {
+		latticeTargets.WithLabelValues(tg.Status.Id, tg.Spec.K8SServiceNamespace, tg.Spec.K8SServiceName, targetStatus).
+			Set(float64(count))
+	}
+}
+
+func deleteTargetsMetric(tgId string) {
+	latticeTargets.DeletePartialMatch(prometheus.Labels{"target_group": tgId})
+	targetsHealth.delete(tgId)
+}
+
+// ReadinessGateCondition maps the Lattice status of a pod's target to the readiness gate condition.
+// Returns true when the status is still pending and should be checked again.
+func ReadinessGateCondition(latticeTarget *vpclattice.TargetSummary) (corev1.PodCondition, bool) {
+	cond := corev1.PodCondition{
+		Type:   LatticeReadinessGateConditionType,
+		Status: corev1.ConditionFalse,
+	}
+	if latticeTarget == nil {
+		cond.Reason = ReadinessReasonTargetNotFound
+		return cond, true
+	}
+	requeue := false
+	switch status := aws.StringValue(latticeTarget.Status); status {
+	case vpclattice.TargetStatusHealthy:
+		cond.Status = corev1.ConditionTrue
+		cond.Reason = ReadinessReasonHealthy
+	case vpclattice.TargetStatusUnavailable:
+		// Lattice HC not turned on. Readiness is designed to work only with HC but do not block deployment on this case.
+		cond.Status = corev1.ConditionTrue
+		cond.Reason = ReadinessReasonHealthCheckUnavailable
+	case vpclattice.TargetStatusUnused:
+		// Target group is not referenced by any rule, e.g. a ServiceExport TG which is not imported anywhere.
+		// In this case we do not have to evaluate them as Healthy, but we also do not have to requeue.
+		cond.Reason = ReadinessReasonUnused
+	case vpclattice.TargetStatusInitial:
+		requeue = true
+		cond.Reason = ReadinessReasonInitial
+	default:
+		requeue = true
+		cond.Reason = ReadinessReasonUnhealthy
+		cond.Message = fmt.Sprintf("Target health check status: %s", status)
+	}
+	return cond, requeue
+}
+
+// targetsHealthCondition summarizes the target status counts of target groups.
+// Status is True when all used targets are healthy, Unknown while health checks are pending or disabled.
+func targetsHealthCondition(tgIds []string, status *model.TargetsStatus) (metav1.ConditionStatus, string, string) {
+	conditionStatus, reason := metav1.ConditionTrue, ReadinessReasonHealthy
+	switch {
+	case status.Unhealthy > 0 || status.Draining > 0:
+		conditionStatus, reason = metav1.ConditionFalse, ReadinessReasonUnhealthy
+	case status.Initial > 0:
+		conditionStatus, reason = metav1.ConditionUnknown, ReadinessReasonInitial
+	case status.Unavailable > 0:
+		conditionStatus, reason = metav1.ConditionUnknown, ReadinessReasonHealthCheckUnavailable
+	case status.Healthy == 0 && status.Unused == 0:
+		conditionStatus, reason = metav1.ConditionFalse, TargetsHealthReasonNoTargets
+	case status.Healthy == 0:
+		conditionStatus, reason = metav1.ConditionUnknown, ReadinessReasonUnused
+	}
+
+	prefix := "Target group"
+	if len(tgIds) > 1 {
+		prefix = "Target groups"
+	}
+	message := fmt.Sprintf("%s %s: %d healthy, %d unhealthy, %d initial, %d unavailable, %d unused, %d draining",
+		prefix, strings.Join(tgIds, ", "), status.Healthy, status.Unhealthy, status.Initial, status.Unavailable, status.Unused, status.Draining)
+	if len(status.ReasonCodes) > 0 {
+		var codes []string
+		for code, count := range status.ReasonCodes {
+			codes = append(codes, fmt.Sprintf("%s=%d", code, count))
+		}
+		sort.Strings(codes)
+		message += ". Reason codes: " + strings.Join(codes, ", ")
+	}
+	return conditionStatus, reason, message
+}
+
+// syncServiceHealth sets the TargetsHealthy condition on the Service or ServiceExport backing the target group.
+// The condition sums up the last known targets of all the target groups of the Service or ServiceExport, the
+// target groups of every route using the Service, or of every exported port.
+func (t *targetsSynthesizer) syncServiceHealth(ctx context.Context, tg *model.TargetGroup) error {
+	if tg.Spec.K8SServiceName == "" || tg.Status == nil {
+		return nil
+	}
+	healthKey := healthKeyOf(tg)
+	key := healthKey.NamespacedName
+	tgIds, status := targetsHealth.sum(healthKey)
+	if len(tgIds) == 0 {
+		return nil
+	}
+	conditionStatus, reason, message := targetsHealthCondition(tgIds, status)
+
+	if tg.Spec.K8SSourceType == model.SourceTypeSvcExport {
+		svcExport := &anv1alpha1.ServiceExport{}
+		if err := t.client.Get(ctx, key, svcExport); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		if !SetServiceExportCondition(svcExport, anv1alpha1.ServiceExportCondition{
+			Type:    anv1alpha1.ServiceExportTargetsHealthy,
+			Status:  corev1.ConditionStatus(conditionStatus),
+			Reason:  aws.String(reason),
+			Message: aws.String(message),
+		}) {
+			return nil
+		}
+		return t.client.Status().Update(ctx, svcExport)
+	}
+
+	svc := &corev1.Service{}
+	if err := t.client.Get(ctx, key, svc); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	existing := meta.FindStatusCondition(svc.Status.Conditions, ServiceTargetsHealthyConditionType)
+	if existing != nil && existing.Status == conditionStatus && existing.Reason == reason && existing.Message == message {
+		return nil
+	}
+	meta.SetStatusCondition(&svc.Status.Conditions, metav1.Condition{
+		Type:    ServiceTargetsHealthyConditionType,
+		Status:  conditionStatus,
+		Reason:  reason,
+		Message: message,
+	})
+	return t.client.Status().Update(ctx, svc)
+}
+
+// SetServiceExportCondition returns false when the condition is already up-to-date
+func SetServiceExportCondition(svcExport *anv1alpha1.ServiceExport, newCondition anv1alpha1.ServiceExportCondition) bool {
+	now := metav1.Now()
+	for i := range svcExport.Status.Conditions {
+		existing := &svcExport.Status.Conditions[i]
+		if existing.Type != newCondition.Type {
+			continue
+		}
+		if existing.Status == newCondition.Status &&
+			aws.StringValue(existing.Reason) == aws.StringValue(newCondition.Reason) &&
+			aws.StringValue(existing.Message) == aws.StringValue(newCondition.Message) {
+			return false
+		}
+		if existing.Status != newCondition.Status {
+			existing.LastTransitionTime = &now
+		}
+		existing.Status = newCondition.Status
+		existing.Reason = newCondition.Reason
+		existing.Message = newCondition.Message
+		return true
+	}
+	newCondition.LastTransitionTime = &now
+	svcExport.Status.Conditions = append(svcExport.Status.Conditions, newCondition)
+	return true
+}
+
+// syncPodHealth sets the target health condition on every pod backing the target group
+func (t *targetsSynthesizer) syncPodHealth(ctx context.Context, tg *model.TargetGroup, modelTargets []model.Target, latticeTargets []*vpclattice.TargetSummary) error {
+	latticeTargetMap := make(map[model.Target]*vpclattice.TargetSummary)
+	for _, latticeTarget := range latticeTargets {
+		ipPort := model.Target{
+			TargetIP: aws.StringValue(latticeTarget.Id),
+			Port:     aws.Int64Value(latticeTarget.Port),
+		}
+		latticeTargetMap[ipPort] = latticeTarget
+	}
+
+	tgId := ""
+	if tg.Status != nil {
+		tgId = tg.Status.Id
+	}
+	for _, target := range modelTargets {
+		if target.TargetRef.Name == "" {
+			continue
+		}
+		latticeTarget, ok := latticeTargetMap[model.Target{TargetIP: target.TargetIP, Port: target.Port}]
+		if !ok {
+			continue
+		}
+
+		newCond := corev1.PodCondition{
+			Type:   LatticeTargetHealthConditionType,
+			Status: corev1.ConditionFalse,
+		}
+		status := aws.StringValue(latticeTarget.Status)
+		switch status {
+		case vpclattice.TargetStatusHealthy:
+			newCond.Status = corev1.ConditionTrue
+			newCond.Reason = ReadinessReasonHealthy
+		case vpclattice.TargetStatusUnavailable:
+			newCond.Status = corev1.ConditionUnknown
+			newCond.Reason = ReadinessReasonHealthCheckUnavailable
+		case vpclattice.TargetStatusInitial:
+			newCond.Status = corev1.ConditionUnknown
+			newCond.Reason = ReadinessReasonInitial
+		case vpclattice.TargetStatusUnused:
+			newCond.Reason = ReadinessReasonUnused
+		default:
+			newCond.Reason = ReadinessReasonUnhealthy
+		}
+		newCond.Message = fmt.Sprintf("Target %s:%d in target group %s is %s", target.TargetIP, target.Port, tgId, status)
+		if reasonCode := aws.StringValue(latticeTarget.ReasonCode); reasonCode != "" {
+			newCond.Message += fmt.Sprintf(" (%s)", reasonCode)
+		}
+
+		pod := &corev1.Pod{}
+		if err := t.client.Get(ctx, target.TargetRef, pod); err != nil {
+			if client.IgnoreNotFound(err) != nil {
+				return err
+			}
+			continue
+		}
+		existing := utils.FindPodStatusCondition(pod.Status.Conditions, LatticeTargetHealthConditionType)
+		if existing != nil && existing.Status == newCond.Status && existing.Reason == newCond.Reason && existing.Message == newCond.Message {
+			continue
+		}
+		utils.SetPodStatusCondition(&pod.Status.Conditions, newCond)
+		if err := t.client.Status().Update(ctx, pod); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -66,12 +66,43 @@ func (s *defaultTargetsManager) Update(ctx context.Context, modelTargets *model.
 		return err
 	}
 	staleTargets := s.findStaleTargets(modelTargets, latticeTargets)
+	newTargets := s.findNewTargets(modelTargets, latticeTargets)
 
 	err1 := s.deregisterTargets(ctx, modelTg, staleTargets)
-	err2 := s.registerTargets(ctx, modelTg, modelTargets.Spec.TargetList)
+	err2 := s.registerTargets(ctx, modelTg, newTargets)
 	return errors.Join(err1, err2)
 }
 
+// findNewTargets returns the model targets which are not registered yet, or are draining
+// and need to be registered again.
+func (s *defaultTargetsManager) findNewTargets(
+	modelTargets *model.Targets,
+	listTargetsOutput []*vpclattice.TargetSummary) []model.Target {
+
+	registeredSet := utils.NewSet[model.Target]()
+	for _, target := range listTargetsOutput {
+		if aws.StringValue(target.Status) == vpclattice.TargetStatusDraining {
+			continue
+		}
+		registeredSet.Put(model.Target{
+			TargetIP: aws.StringValue(target.Id),
+			Port:     aws.Int64Value(target.Port),
+		})
+	}
+
+	newTargets := make([]model.Target, 0)
+	for _, target := range modelTargets.Spec.TargetList {
+		ipPort := model.Target{
+			TargetIP: target.TargetIP,
+			Port:     target.Port,
+		}
+		if !registeredSet.Contains(ipPort) {
+			newTargets = append(newTargets, target)
+		}
+	}
+	return newTargets
+}
+
 func (s *defaultTargetsManager) findStaleTargets(
 	modelTargets *model.Targets,
 	listTargetsOutput []*vpclattice.TargetSummary) []model.Target {
@@ -243,8 +243,8 @@ func TestTargetsManager(t *testing.T) {
 
 		registerInput := &vpclattice.RegisterTargetsInput{
 			TargetGroupIdentifier: aws.String("tg-id"),
+			// mt2 is already registered, only the new target is registered
 			Targets: []*vpclattice.Target{
-				{Id: aws.String(mt2.TargetIP), Port: aws.Int64(mt2.Port)},
 				{Id: aws.String(mt3.TargetIP), Port: aws.Int64(mt3.Port)},
 			},
 		}
@@ -260,6 +260,54 @@ func TestTargetsManager(t *testing.T) {
 
 	})
 
+	t.Run("registered targets are not registered again, draining targets are", func(t *testing.T) {
+		mt1 := model.Target{
+			TargetIP: "192.0.2.10",
+			Port:     int64(8080),
+			Ready:    true,
+		}
+		mt2 := model.Target{
+			TargetIP: "192.0.2.20",
+			Port:     int64(8080),
+			Ready:    true,
+		}
+
+		existingTargets := []*vpclattice.TargetSummary{
+			{
+				Id:     aws.String(mt1.TargetIP),
+				Port:   aws.Int64(mt1.Port),
+				Status: aws.String(vpclattice.TargetStatusHealthy),
+			},
+			{
+				Id:     aws.String(mt2.TargetIP),
+				Port:   aws.Int64(mt2.Port),
+				Status: aws.String(vpclattice.TargetStatusDraining),
+			},
+		}
+
+		newTargets := model.Targets{
+			Spec: model.TargetsSpec{
+				StackTargetGroupId: "tg-stack-id",
+				TargetList:         []model.Target{mt1, mt2},
+			},
+		}
+
+		registerInput := &vpclattice.RegisterTargetsInput{
+			TargetGroupIdentifier: aws.String("tg-id"),
+			Targets: []*vpclattice.Target{
+				{Id: aws.String(mt2.TargetIP), Port: aws.Int64(mt2.Port)},
+			},
+		}
+
+		mockLattice.EXPECT().ListTargetsAsList(ctx, gomock.Any()).Return(existingTargets, nil)
+		mockLattice.EXPECT().RegisterTargetsWithContext(ctx, registerInput).Return(registerTargetsOutput, nil)
+
+		targetsManager := NewTargetsManager(gwlog.FallbackLogger, mockCloud)
+		err := targetsManager.Update(ctx, &newTargets, &modelTg)
+
+		assert.Nil(t, err)
+	})
+
 	t.Run("port difference handled correctly", func(t *testing.T) {
 		existingTarget := &vpclattice.TargetSummary{
 			Id:   aws.String(targets.TargetIP),
@@ -2,6 +2,7 @@ package lattice
 
 import (
 	"context"
+	anv1alpha1 "github.com/aws/aws-application-networking-k8s/pkg/apis/applicationnetworking/v1alpha1"
 	"github.com/aws/aws-application-networking-k8s/pkg/model/core"
 	model "github.com/aws/aws-application-networking-k8s/pkg/model/lattice"
 	"github.com/aws/aws-application-networking-k8s/pkg/utils"
@@ -9,10 +10,14 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/vpclattice"
 	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"testing"
 )
@@ -69,198 +74,214 @@ func Test_SynthesizeTargets(t *testing.T) {
 	assert.Nil(t, err)
 }
 
-func Test_PostSynthesize_Conditions(t *testing.T) {
+func Test_PostSynthesize_TargetHealth(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+	ctx := context.TODO()
 
-	newPod := func(namespace, name string, hasGate bool, ready bool) *corev1.Pod {
-		var readinessGates []corev1.PodReadinessGate
-		if hasGate {
-			readinessGates = append(readinessGates, corev1.PodReadinessGate{
-				ConditionType: LatticeReadinessGateConditionType,
-			})
-		}
-		condition := corev1.PodCondition{
-			Type:   LatticeReadinessGateConditionType,
-			Status: corev1.ConditionFalse,
-			Reason: ReadinessReasonUnhealthy,
-		}
-		if ready {
-			condition = corev1.PodCondition{
-				Type:   LatticeReadinessGateConditionType,
-				Status: corev1.ConditionTrue,
-				Reason: ReadinessReasonHealthy,
-			}
-		}
+	mockTargetsManager := NewMockTargetsManager(c)
+	stack := core.NewDefaultStack(core.StackID{Name: "foo", Namespace: "bar"})
 
-		return &corev1.Pod{
-			TypeMeta: metav1.TypeMeta{
-				Kind: "Pod",
-			},
-			ObjectMeta: metav1.ObjectMeta{
-				Namespace: namespace,
-				Name:      name,
-			},
-			Spec: corev1.PodSpec{
-				ReadinessGates: readinessGates,
-			},
-			Status: corev1.PodStatus{
-				Conditions: []corev1.PodCondition{condition},
+	modelTg := model.TargetGroup{
+		ResourceMeta: core.NewResourceMeta(stack, "AWS:VPCServiceNetwork::TargetGroup", "tg-stack-id"),
+		Spec: model.TargetGroupSpec{
+			TargetGroupTagFields: model.TargetGroupTagFields{
+				K8SSourceType:       model.SourceTypeHTTPRoute,
+				K8SServiceName:      "svc",
+				K8SServiceNamespace: "ns",
 			},
-		}
-	}
-	newLatticeTarget := func(ip string, port int64, status string) *vpclattice.TargetSummary {
-		return &vpclattice.TargetSummary{
-			Id:     aws.String(ip),
-			Port:   aws.Int64(port),
-			Status: aws.String(status),
-		}
-	}
-
-	target := model.Target{
-		TargetIP:  "10.10.1.1",
-		Port:      8675,
-		Ready:     false,
-		TargetRef: types.NamespacedName{Namespace: "ns", Name: "pod1"},
-	}
-
-	tests := []struct {
-		name           string
-		model          model.Target
-		lattice        *vpclattice.TargetSummary
-		pod            *corev1.Pod
-		expectedStatus corev1.ConditionStatus
-		expectedReason string
-		requeue        bool
-	}{
-		{
-			name:           "Healthy targets make pod ready",
-			model:          target,
-			lattice:        newLatticeTarget("10.10.1.1", 8675, vpclattice.TargetStatusHealthy),
-			pod:            newPod("ns", "pod1", true, false),
-			expectedStatus: corev1.ConditionTrue,
-			expectedReason: ReadinessReasonHealthy,
-			requeue:        false,
-		},
-		{
-			name:           "Unavailable targets make pod ready",
-			model:          target,
-			lattice:        newLatticeTarget("10.10.1.1", 8675, vpclattice.TargetStatusUnavailable),
-			pod:            newPod("ns", "pod1", true, false),
-			expectedStatus: corev1.ConditionTrue,
-			expectedReason: ReadinessReasonHealthCheckUnavailable,
-			requeue:        false,
 		},
-		{
-			name:           "Initial targets do not make pod ready",
-			model:          target,
-			lattice:        newLatticeTarget("10.10.1.1", 8675, vpclattice.TargetStatusInitial),
-			pod:            newPod("ns", "pod1", true, false),
-			expectedStatus: corev1.ConditionFalse,
-			expectedReason: ReadinessReasonInitial,
-			requeue:        true,
-		},
-		{
-			name:           "Unhealthy targets do not make pod ready",
-			model:          target,
-			lattice:        newLatticeTarget("10.10.1.1", 8675, vpclattice.TargetStatusUnhealthy),
-			pod:            newPod("ns", "pod1", true, false),
-			expectedStatus: corev1.ConditionFalse,
-			expectedReason: ReadinessReasonUnhealthy,
-			requeue:        true,
-		},
-		{
-			name:           "Draining(unhealthy) targets do not make pod ready",
-			model:          target,
-			lattice:        newLatticeTarget("10.10.1.1", 8675, vpclattice.TargetStatusDraining),
-			pod:            newPod("ns", "pod1", true, false),
-			expectedStatus: corev1.ConditionFalse,
-			expectedReason: ReadinessReasonUnhealthy,
-			requeue:        true,
-		},
-		{
-			name:           "Requeues if target not found",
-			model:          target,
-			lattice:        newLatticeTarget("dummy", 8675, vpclattice.TargetStatusHealthy),
-			pod:            newPod("ns", "pod1", true, false),
-			expectedStatus: corev1.ConditionFalse,
-			expectedReason: ReadinessReasonTargetNotFound,
-			requeue:        true,
+		Status: &model.TargetGroupStatus{
+			Name: "tg-name",
+			Arn:  "tg-arn",
+			Id:   "tg-id",
 		},
-		{
-			name:           "Pod without gate does not change condition",
-			model:          target,
-			lattice:        newLatticeTarget("10.10.1.1", 8675, vpclattice.TargetStatusHealthy),
-			pod:            newPod("ns", "pod1", false, false),
-			expectedStatus: corev1.ConditionFalse,
-			expectedReason: ReadinessReasonUnhealthy,
-			requeue:        false,
+	}
+	assert.NoError(t, stack.AddResource(&modelTg))
+
+	targets, _ := model.NewTargets(stack, model.TargetsSpec{
+		StackTargetGroupId: modelTg.ID(),
+		TargetList: []model.Target{
+			{TargetIP: "10.10.1.1", Port: 80, Ready: true, TargetRef: types.NamespacedName{Namespace: "ns", Name: "pod1"}},
+			{TargetIP: "10.10.1.2", Port: 80, Ready: true, TargetRef: types.NamespacedName{Namespace: "ns", Name: "pod2"}},
 		},
-		{
-			name:           "Ready pods keep condition (even if target is unhealthy)",
-			model:          target,
-			lattice:        newLatticeTarget("10.10.1.1", 8675, vpclattice.TargetStatusUnhealthy),
-			pod:            newPod("ns", "pod1", true, true),
-			expectedStatus: corev1.ConditionTrue,
-			expectedReason: ReadinessReasonHealthy,
-			requeue:        false,
+	})
+
+	mockTargetsManager.EXPECT().List(ctx, gomock.Any()).Return([]*vpclattice.TargetSummary{
+		{Id: aws.String("10.10.1.1"), Port: aws.Int64(80), Status: aws.String(vpclattice.TargetStatusHealthy)},
+		{Id: aws.String("10.10.1.2"), Port: aws.Int64(80), Status: aws.String(vpclattice.TargetStatusUnhealthy),
+			ReasonCode: aws.String("HealthCheckFailed")},
+	}, nil)
+
+	k8sClient := testclient.NewClientBuilder().Build()
+	assert.NoError(t, k8sClient.Create(ctx, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "pod1"}}))
+	assert.NoError(t, k8sClient.Create(ctx, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "pod2"}}))
+	assert.NoError(t, k8sClient.Create(ctx, &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "svc"}}))
+
+	synthesizer := NewTargetsSynthesizer(gwlog.FallbackLogger, k8sClient, mockTargetsManager, stack)
+	assert.Nil(t, synthesizer.PostSynthesize(ctx))
+
+	assert.Equal(t, &model.TargetsStatus{Healthy: 1, Unhealthy: 1, ReasonCodes: map[string]int32{"HealthCheckFailed": 1}}, targets.Status)
+
+	pod := &corev1.Pod{}
+	k8sClient.Get(ctx, types.NamespacedName{Namespace: "ns", Name: "pod1"}, pod)
+	cond := utils.FindPodStatusCondition(pod.Status.Conditions, LatticeTargetHealthConditionType)
+	assert.Equal(t, corev1.ConditionTrue, cond.Status)
+	assert.Equal(t, ReadinessReasonHealthy, cond.Reason)
+
+	k8sClient.Get(ctx, types.NamespacedName{Namespace: "ns", Name: "pod2"}, pod)
+	cond = utils.FindPodStatusCondition(pod.Status.Conditions, LatticeTargetHealthConditionType)
+	assert.Equal(t, corev1.ConditionFalse, cond.Status)
+	assert.Equal(t, ReadinessReasonUnhealthy, cond.Reason)
+	assert.Contains(t, cond.Message, "HealthCheckFailed")
+
+	svc := &corev1.Service{}
+	k8sClient.Get(ctx, types.NamespacedName{Namespace: "ns", Name: "svc"}, svc)
+	svcCond := meta.FindStatusCondition(svc.Status.Conditions, ServiceTargetsHealthyConditionType)
+	assert.Equal(t, metav1.ConditionFalse, svcCond.Status)
+	assert.Equal(t, ReadinessReasonUnhealthy, svcCond.Reason)
+	assert.Equal(t, "Target group tg-id: 1 healthy, 1 unhealthy, 0 initial, 0 unavailable, 0 unused, 0 draining. Reason codes: HealthCheckFailed=1", svcCond.Message)
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(latticeTargets.WithLabelValues("tg-id", "ns", "svc", vpclattice.TargetStatusUnhealthy)))
+	deleteTargetsMetric("tg-id")
+	assert.Equal(t, 0, testutil.CollectAndCount(latticeTargets))
+}
+
+func Test_PostSynthesize_TargetHealth_ServiceExport(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+	ctx := context.TODO()
+
+	mockTargetsManager := NewMockTargetsManager(c)
+	stack := core.NewDefaultStack(core.StackID{Name: "foo", Namespace: "bar"})
+
+	modelTg := model.TargetGroup{
+		ResourceMeta: core.NewResourceMeta(stack, "AWS:VPCServiceNetwork::TargetGroup", "tg-stack-id"),
+		Spec: model.TargetGroupSpec{
+			TargetGroupTagFields: model.TargetGroupTagFields{
+				K8SSourceType:       model.SourceTypeSvcExport,
+				K8SServiceName:      "svc",
+				K8SServiceNamespace: "ns",
+			},
 		},
-		{
-			name:           "Unused pods keep condition",
-			model:          target,
-			lattice:        newLatticeTarget("10.10.1.1", 8675, vpclattice.TargetStatusUnused),
-			pod:            newPod("ns", "pod1", true, false),
-			expectedStatus: corev1.ConditionFalse,
-			expectedReason: ReadinessReasonUnused,
-			requeue:        false,
+		Status: &model.TargetGroupStatus{
+			Name: "tg-name",
+			Arn:  "tg-arn",
+			Id:   "tg-id",
 		},
 	}
+	assert.NoError(t, stack.AddResource(&modelTg))
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			c := gomock.NewController(t)
-			defer c.Finish()
-			ctx := context.TODO()
+	_, err := model.NewTargets(stack, model.TargetsSpec{
+		StackTargetGroupId: modelTg.ID(),
+		TargetList: []model.Target{
+			{TargetIP: "10.10.1.1", Port: 80, Ready: true},
+		},
+	})
+	assert.NoError(t, err)
 
-			mockTargetsManager := NewMockTargetsManager(c)
+	mockTargetsManager.EXPECT().List(ctx, gomock.Any()).Return([]*vpclattice.TargetSummary{
+		{Id: aws.String("10.10.1.1"), Port: aws.Int64(80), Status: aws.String(vpclattice.TargetStatusHealthy)},
+	}, nil)
 
-			stack := core.NewDefaultStack(core.StackID{Name: "foo", Namespace: "bar"})
+	k8sScheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(k8sScheme)
+	anv1alpha1.AddToScheme(k8sScheme)
+	k8sClient := testclient.NewClientBuilder().WithScheme(k8sScheme).
+		WithStatusSubresource(&anv1alpha1.ServiceExport{}).
+		WithObjects(&anv1alpha1.ServiceExport{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "svc"}}).
+		Build()
 
-			modelTg := model.TargetGroup{
-				ResourceMeta: core.NewResourceMeta(stack, "AWS:VPCServiceNetwork::TargetGroup", "tg-stack-id"),
-				Status: &model.TargetGroupStatus{
-					Name: "tg-name",
-					Arn:  "tg-arn",
-					Id:   "tg-id",
-				},
-			}
-			assert.NoError(t, stack.AddResource(&modelTg))
+	synthesizer := NewTargetsSynthesizer(gwlog.FallbackLogger, k8sClient, mockTargetsManager, stack)
+	assert.Nil(t, synthesizer.PostSynthesize(ctx))
 
-			targetsSpec := model.TargetsSpec{
-				StackTargetGroupId: modelTg.ID(),
-				TargetList:         []model.Target{tt.model},
-			}
-			model.NewTargets(stack, targetsSpec)
+	svcExport := &anv1alpha1.ServiceExport{}
+	assert.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Namespace: "ns", Name: "svc"}, svcExport))
+	assert.Len(t, svcExport.Status.Conditions, 1)
+	cond := svcExport.Status.Conditions[0]
+	assert.Equal(t, anv1alpha1.ServiceExportTargetsHealthy, cond.Type)
+	assert.Equal(t, corev1.ConditionTrue, cond.Status)
+	assert.Equal(t, ReadinessReasonHealthy, aws.StringValue(cond.Reason))
+	assert.NotNil(t, cond.LastTransitionTime)
+	deleteTargetsMetric("tg-id")
+}
 
-			mockTargetsManager.EXPECT().List(ctx, gomock.Any()).Return([]*vpclattice.TargetSummary{tt.lattice}, nil)
+func Test_PostSynthesize_TargetHealth_AllTargetGroupsOfService(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+	ctx := context.TODO()
 
-			k8sClient := testclient.NewClientBuilder().Build()
-			assert.NoError(t, k8sClient.Create(ctx, tt.pod))
+	k8sClient := testclient.NewClientBuilder().Build()
+	assert.NoError(t, k8sClient.Create(ctx, &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "svc"}}))
+
+	// two routes use the service, each deploys its own target group
+	deployRoute := func(route string, tgId string, targetStatus string) {
+		mockTargetsManager := NewMockTargetsManager(c)
+		stack := core.NewDefaultStack(core.StackID{Name: route, Namespace: "ns"})
+		modelTg := model.TargetGroup{
+			ResourceMeta: core.NewResourceMeta(stack, "AWS:VPCServiceNetwork::TargetGroup", "tg-stack-id"),
+			Spec: model.TargetGroupSpec{
+				TargetGroupTagFields: model.TargetGroupTagFields{
+					K8SSourceType:       model.SourceTypeHTTPRoute,
+					K8SServiceName:      "svc",
+					K8SServiceNamespace: "ns",
+					K8SRouteName:        route,
+					K8SRouteNamespace:   "ns",
+				},
+			},
+			Status: &model.TargetGroupStatus{Id: tgId},
+		}
+		assert.NoError(t, stack.AddResource(&modelTg))
+		_, err := model.NewTargets(stack, model.TargetsSpec{
+			StackTargetGroupId: modelTg.ID(),
+			TargetList:         []model.Target{{TargetIP: "10.10.1.1", Port: 80, Ready: true}},
+		})
+		assert.NoError(t, err)
+		mockTargetsManager.EXPECT().List(ctx, gomock.Any()).Return([]*vpclattice.TargetSummary{
+			{Id: aws.String("10.10.1.1"), Port: aws.Int64(80), Status: aws.String(targetStatus)},
+		}, nil)
+		synthesizer := NewTargetsSynthesizer(gwlog.FallbackLogger, k8sClient, mockTargetsManager, stack)
+		assert.Nil(t, synthesizer.PostSynthesize(ctx))
+	}
+	defer deleteTargetsMetric("tg-a")
+	defer deleteTargetsMetric("tg-b")
 
-			synthesizer := NewTargetsSynthesizer(gwlog.FallbackLogger, k8sClient, mockTargetsManager, stack)
-			err := synthesizer.PostSynthesize(ctx)
+	deployRoute("route-a", "tg-a", vpclattice.TargetStatusUnhealthy)
+	deployRoute("route-b", "tg-b", vpclattice.TargetStatusHealthy)
 
-			if tt.requeue {
-				assert.ErrorAs(t, err, &RetryErr)
-			} else {
-				assert.Nil(t, err)
-			}
+	// the healthy target group of route-b does not hide the unhealthy one of route-a
+	svc := &corev1.Service{}
+	assert.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Namespace: "ns", Name: "svc"}, svc))
+	svcCond := meta.FindStatusCondition(svc.Status.Conditions, ServiceTargetsHealthyConditionType)
+	assert.Equal(t, metav1.ConditionFalse, svcCond.Status)
+	assert.Equal(t, ReadinessReasonUnhealthy, svcCond.Reason)
+	assert.Equal(t, "Target groups tg-a, tg-b: 1 healthy, 1 unhealthy, 0 initial, 0 unavailable, 0 unused, 0 draining", svcCond.Message)
 
-			pod := &corev1.Pod{}
-			k8sClient.Get(ctx, types.NamespacedName{Namespace: "ns", Name: "pod1"}, pod)
-			cond := utils.FindPodStatusCondition(pod.Status.Conditions, LatticeReadinessGateConditionType)
-			assert.NotNil(t, cond)
+	// once the target group of route-a is deleted, only route-b counts
+	deleteTargetsMetric("tg-a")
+	deployRoute("route-b", "tg-b", vpclattice.TargetStatusHealthy)
+	assert.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Namespace: "ns", Name: "svc"}, svc))
+	svcCond = meta.FindStatusCondition(svc.Status.Conditions, ServiceTargetsHealthyConditionType)
+	assert.Equal(t, metav1.ConditionTrue, svcCond.Status)
+	assert.Equal(t, "Target group tg-b: 1 healthy, 0 unhealthy, 0 initial, 0 unavailable, 0 unused, 0 draining", svcCond.Message)
+}
 
-			assert.Equal(t, tt.expectedReason, cond.Reason)
-			assert.Equal(t, tt.expectedStatus, cond.Status)
-		})
+func Test_TargetsHealthCondition(t *testing.T) {
+	tests := []struct {
+		status         model.TargetsStatus
+		expectedStatus metav1.ConditionStatus
+		expectedReason string
+	}{
+		{model.TargetsStatus{Healthy: 2}, metav1.ConditionTrue, ReadinessReasonHealthy},
+		{model.TargetsStatus{Healthy: 2, Draining: 1}, metav1.ConditionFalse, ReadinessReasonUnhealthy},
+		{model.TargetsStatus{Healthy: 1, Initial: 1}, metav1.ConditionUnknown, ReadinessReasonInitial},
+		{model.TargetsStatus{Unavailable: 1}, metav1.ConditionUnknown, ReadinessReasonHealthCheckUnavailable},
+		{model.TargetsStatus{Unused: 1}, metav1.ConditionUnknown, ReadinessReasonUnused},
+		{model.TargetsStatus{}, metav1.ConditionFalse, TargetsHealthReasonNoTargets},
+	}
+	for _, tt := range tests {
+		status, reason, _ := targetsHealthCondition([]string{"tg-id"}, &tt.status)
+		assert.Equal(t, tt.expectedStatus, status)
+		assert.Equal(t, tt.expectedReason, reason)
 	}
 }
@@ -3,6 +3,7 @@ package deploy
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -10,15 +11,13 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	pkg_aws "github.com/aws/aws-application-networking-k8s/pkg/aws"
+	"github.com/aws/aws-application-networking-k8s/pkg/config"
 	"github.com/aws/aws-application-networking-k8s/pkg/deploy/externaldns"
 	"github.com/aws/aws-application-networking-k8s/pkg/deploy/lattice"
 	"github.com/aws/aws-application-networking-k8s/pkg/gateway"
 	"github.com/aws/aws-application-networking-k8s/pkg/model/core"
 	"github.com/aws/aws-application-networking-k8s/pkg/utils/gwlog"
-)
-
-const (
-	TG_GC_IVL = time.Second * 30
+	"github.com/aws/aws-application-networking-k8s/pkg/utils/tracing"
 )
 
 type StackDeployer interface {
@@ -33,12 +32,12 @@ type ResourceSynthesizer interface {
 // Deploy a resource stack
 func deploy(ctx context.Context, stack core.Stack, synthesizers []ResourceSynthesizer) error {
 	for _, synthesizer := range synthesizers {
-		if err := synthesizer.Synthesize(ctx); err != nil {
+		if err := traced(ctx, "synthesize "+synthesizerName(synthesizer), synthesizer.Synthesize); err != nil {
 			return err
 		}
 	}
 	for i := len(synthesizers) - 1; i >= 0; i-- {
-		if err := synthesizers[i].PostSynthesize(ctx); err != nil {
+		if err := traced(ctx, "post-synthesize "+synthesizerName(synthesizers[i]), synthesizers[i].PostSynthesize); err != nil {
 			return err
 		}
 	}
@@ -46,6 +45,22 @@ func deploy(ctx context.Context, stack core.Stack, synthesizers []ResourceSynthe
 	return nil
 }
 
+// runs a single deployment step in its own span
+func traced(ctx context.Context, name string, fn func(context.Context) error) error {
+	ctx, span := tracing.Start(ctx, name)
+	err := fn(ctx)
+	tracing.End(span, err)
+	return err
+}
+
+func synthesizerName(synthesizer ResourceSynthesizer) string {
+	t := reflect.TypeOf(synthesizer)
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Name()
+}
+
 type latticeServiceStackDeployer struct {
 	log                   gwlog.Logger
 	cloud                 pkg_aws.Cloud
@@ -85,10 +100,9 @@ func NewLatticeServiceStackDeploy(
 			log:     log.Named("tg-gc"),
 			ctx:     context.TODO(),
 			isDone:  atomic.Bool{},
-			ivl:     TG_GC_IVL,
+			ivl:     config.TargetGroupGcInterval,
 			cycleFn: tgGcFn,
 		}
-		tgGc.start()
 	})
 
 	return &latticeServiceStackDeployer{
@@ -130,11 +144,12 @@ func NewTgGcFn(tgSynth *lattice.TargetGroupSynthesizer) TgGcCycleFn {
 }
 
 type TgGc struct {
-	lock    sync.RWMutex
-	log     gwlog.Logger
-	ctx     context.Context
-	isDone  atomic.Bool
-	ivl     time.Duration
+	lock   sync.RWMutex
+	log    gwlog.Logger
+	ctx    context.Context
+	isDone atomic.Bool
+	// ivl returns the interval between cycles, it can change while the GC runs
+	ivl     func() time.Duration
 	cycleFn TgGcCycleFn
 }
 
@@ -147,8 +162,29 @@ type TgGcResult struct {
 	duration time.Duration
 }
 
+// TargetGroupGc returns the target group GC, once a lattice service stack deployer is created
+func TargetGroupGc() *TgGc {
+	return tgGc
+}
+
+// Start runs the GC until the context is done
+func (gc *TgGc) Start(ctx context.Context) error {
+	gc.ctx = ctx
+	gc.start()
+	<-ctx.Done()
+	return nil
+}
+
+// NeedLeaderElection is true, so that a single replica deletes target groups even when
+// routes are reconciled by several sharded replicas. The lock only holds off the deployments of
+// the leader, target groups are read again right before their deletion to guard against the others.
+func (gc *TgGc) NeedLeaderElection() bool {
+	return true
+}
+
 func (gc *TgGc) start() {
-	ticker := time.NewTicker(gc.ivl)
+	ivl := gc.ivl()
+	ticker := time.NewTicker(ivl)
 	go func() {
 		for {
 			select {
@@ -158,6 +194,11 @@ func (gc *TgGc) start() {
 				return
 			case <-ticker.C:
 				gc.cycle()
+				if newIvl := gc.ivl(); newIvl != ivl {
+					gc.log.Infof(context.TODO(), "GC interval changed to %s", newIvl)
+					ivl = newIvl
+					ticker.Reset(ivl)
+				}
 			}
 		}
 	}()
@@ -195,37 +236,42 @@ func (d *latticeServiceStackDeployer) Deploy(ctx context.Context, stack core.Sta
 	tgGc.lock.RLock()
 
 	//Handle targetGroups creation request
-	if err := targetGroupSynthesizer.SynthesizeCreate(ctx); err != nil {
+	if err := traced(ctx, "synthesize TargetGroups", targetGroupSynthesizer.SynthesizeCreate); err != nil {
 		return fmt.Errorf("error during tg synthesis %w", err)
 	}
 
 	//Handle targets "reconciliation" request (register intend-to-be-registered targets and deregister intend-to-be-registered targets)
-	if err := targetsSynthesizer.Synthesize(ctx); err != nil {
+	if err := traced(ctx, "synthesize Targets", targetsSynthesizer.Synthesize); err != nil {
 		return fmt.Errorf("error during target synthesis %w", err)
 	}
 
 	// Handle latticeService "reconciliation" request
-	if err := serviceSynthesizer.Synthesize(ctx); err != nil {
+	if err := traced(ctx, "synthesize Service", serviceSynthesizer.Synthesize); err != nil {
 		return fmt.Errorf("error during service synthesis %w", err)
 	}
 
 	//Handle latticeService listeners "reconciliation" request
-	if err := listenerSynthesizer.Synthesize(ctx); err != nil {
+	if err := traced(ctx, "synthesize Listeners", listenerSynthesizer.Synthesize); err != nil {
 		return fmt.Errorf("error during listener synthesis %w", err)
 	}
 
 	//Handle latticeService listener's rules "reconciliation" request
-	if err := ruleSynthesizer.Synthesize(ctx); err != nil {
+	if err := traced(ctx, "synthesize Rules", ruleSynthesizer.Synthesize); err != nil {
 		return fmt.Errorf("error during rule synthesis %w", err)
 	}
 
 	// Handle pod status update for targets.
-	if err := targetsSynthesizer.PostSynthesize(ctx); err != nil {
+	if err := traced(ctx, "post-synthesize Targets", targetsSynthesizer.PostSynthesize); err != nil {
 		return fmt.Errorf("error during target post synthesis %w", err)
 	}
 
+	// Report target group replacements once the rules forward to the new target groups
+	if err := traced(ctx, "post-synthesize TargetGroups", targetGroupSynthesizer.PostSynthesize); err != nil {
+		return fmt.Errorf("error during tg post synthesis %w", err)
+	}
+
 	//Handle targetGroup deletion request
-	if err := targetGroupSynthesizer.SynthesizeDelete(ctx); err != nil {
+	if err := traced(ctx, "delete TargetGroups", targetGroupSynthesizer.SynthesizeDelete); err != nil {
 		return fmt.Errorf("error during tg delete synthesis %w", err)
 	}
 
@@ -62,7 +62,7 @@ func TestTgGc(t *testing.T) {
 			tgGc := &TgGc{
 				log:     gwlog.FallbackLogger,
 				ctx:     ctx,
-				ivl:     ivl,
+				ivl:     func() time.Duration { return ivl },
 				cycleFn: f,
 			}
 			tgGc.start()
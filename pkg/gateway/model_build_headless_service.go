@@ -0,0 +1,159 @@
+package gateway
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/vpclattice"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	gwv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	anv1alpha1 "github.com/aws/aws-application-networking-k8s/pkg/apis/applicationnetworking/v1alpha1"
+	"github.com/aws/aws-application-networking-k8s/pkg/config"
+	"github.com/aws/aws-application-networking-k8s/pkg/model/core"
+	model "github.com/aws/aws-application-networking-k8s/pkg/model/lattice"
+)
+
+// buildHeadlessServices adds a Lattice service per cluster exporting the Headless ServiceImport backend of
+// the route, so clients can address the endpoints of a given cluster. The services have the listeners of
+// the route service and forward all their traffic to the target group of their cluster. When the route has
+// a hostname, the custom domain name of a service is the hostname prefixed with the cluster name.
+func (t *latticeServiceModelBuildTask) buildHeadlessServices(ctx context.Context, routeSvc *model.Service) error {
+	svcImport, port, err := t.findHeadlessServiceImport(ctx)
+	if err != nil || svcImport == nil {
+		return err
+	}
+	routeSvc.Spec.HasHeadlessServices = true
+
+	var routeListeners []*model.Listener
+	if err := t.stack.ListResources(&routeListeners); err != nil {
+		return err
+	}
+
+	for _, cluster := range svcImport.Status.Clusters {
+		spec := routeSvc.Spec
+		spec.HeadlessCluster = cluster.Cluster
+		spec.HasHeadlessServices = false
+		if spec.CustomerDomainName != "" {
+			spec.CustomerDomainName = headlessDomainName(cluster.Cluster, spec.CustomerDomainName)
+		}
+		svc, err := model.NewLatticeService(t.stack, spec)
+		if err != nil {
+			return err
+		}
+		svc.IsDeleted = routeSvc.IsDeleted
+		t.log.Debugf(ctx, "Added headless service %s for cluster %s to the stack (ID %s)",
+			svc.LatticeServiceName(), cluster.Cluster, svc.ID())
+		if svc.IsDeleted {
+			continue
+		}
+
+		svcImportTg := model.SvcImportTargetGroup{
+			K8SClusterName:      cluster.Cluster,
+			K8SServiceName:      svcImport.Name,
+			K8SServiceNamespace: svcImport.Namespace,
+			VpcId:               cluster.VpcId,
+			K8SServicePort:      port,
+			AccountId:           svcImport.AccountId(),
+		}
+		for _, routeListener := range routeListeners {
+			if routeListener.Spec.StackServiceId != routeSvc.ID() {
+				continue
+			}
+			if err := t.buildHeadlessListener(ctx, svc, routeListener, svcImportTg); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// buildHeadlessListener copies a listener of the route service to the headless service, forwarding to the
+// target group of the cluster with its default action for TLS_PASSTHROUGH, or with a rule matching all requests
+func (t *latticeServiceModelBuildTask) buildHeadlessListener(ctx context.Context, svc *model.Service,
+	routeListener *model.Listener, svcImportTg model.SvcImportTargetGroup) error {
+
+	forward := model.RuleAction{
+		TargetGroups: []*model.RuleTargetGroup{{SvcImportTG: &svcImportTg, Weight: 1}},
+	}
+	spec := model.ListenerSpec{
+		StackServiceId:    svc.ID(),
+		K8SRouteName:      routeListener.Spec.K8SRouteName,
+		K8SRouteNamespace: routeListener.Spec.K8SRouteNamespace,
+		Port:              routeListener.Spec.Port,
+		Protocol:          routeListener.Spec.Protocol,
+	}
+	if spec.Protocol == vpclattice.ListenerProtocolTlsPassthrough {
+		spec.DefaultAction = &model.DefaultAction{Forward: &forward}
+	} else {
+		spec.DefaultAction = &model.DefaultAction{
+			FixedResponseStatusCode: aws.Int64(model.DefaultActionFixedResponseStatusCode),
+		}
+	}
+	listener, err := model.NewListener(t.stack, spec)
+	if err != nil {
+		return err
+	}
+	t.log.Debugf(ctx, "Added listener %d to headless service %s (ID %s)",
+		spec.Port, svc.LatticeServiceName(), listener.ID())
+	if spec.DefaultAction.Forward != nil {
+		return nil
+	}
+
+	ruleSpec := model.RuleSpec{
+		StackListenerId: listener.ID(),
+		PathMatchValue:  "/",
+		PathMatchPrefix: true,
+		Priority:        1,
+		Action:          forward,
+	}
+	if _, ok := t.route.(*core.GRPCRoute); ok {
+		ruleSpec.Method = string(gwv1.HTTPMethodPost)
+	}
+	_, err = model.NewRule(t.stack, ruleSpec)
+	return err
+}
+
+// findHeadlessServiceImport returns the first Headless ServiceImport backend of the route and the port of
+// the backendRef, nil when there is none
+func (t *latticeServiceModelBuildTask) findHeadlessServiceImport(ctx context.Context) (*anv1alpha1.ServiceImport, string, error) {
+	for _, rule := range t.route.Spec().Rules() {
+		for _, backendRef := range rule.BackendRefs() {
+			if backendRef.Kind() == nil || string(*backendRef.Kind()) != "ServiceImport" {
+				continue
+			}
+			namespace := t.route.Namespace()
+			if backendRef.Namespace() != nil {
+				namespace = string(*backendRef.Namespace())
+			}
+			if !config.IsNamespaceWatched(namespace) {
+				continue
+			}
+			svcImport := &anv1alpha1.ServiceImport{}
+			svcImportName := types.NamespacedName{Namespace: namespace, Name: string(backendRef.Name())}
+			if err := t.client.Get(ctx, svcImportName, svcImport); err != nil {
+				if apierrors.IsNotFound(err) {
+					continue
+				}
+				return nil, "", err
+			}
+			if svcImport.Spec.Type != anv1alpha1.Headless {
+				continue
+			}
+			port := ""
+			if backendRef.Port() != nil && *backendRef.Port() != 0 {
+				port = strconv.Itoa(int(*backendRef.Port()))
+			}
+			return svcImport, port, nil
+		}
+	}
+	return nil, "", nil
+}
+
+// headlessDomainName prefixes the domain name with the cluster name as a DNS label
+func headlessDomainName(cluster string, domainName string) string {
+	return strings.ToLower(strings.ReplaceAll(cluster, "_", "-")) + "." + domainName
+}
@@ -0,0 +1,159 @@
+package gateway
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/vpclattice"
+	"github.com/stretchr/testify/assert"
+	apimachineryv1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gwv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	anv1alpha1 "github.com/aws/aws-application-networking-k8s/pkg/apis/applicationnetworking/v1alpha1"
+	"github.com/aws/aws-application-networking-k8s/pkg/k8s"
+	"github.com/aws/aws-application-networking-k8s/pkg/model/core"
+	model "github.com/aws/aws-application-networking-k8s/pkg/model/lattice"
+	"github.com/aws/aws-application-networking-k8s/pkg/utils/gwlog"
+)
+
+func Test_BuildHeadlessServices(t *testing.T) {
+	var serviceImportKind gwv1.Kind = "ServiceImport"
+
+	tests := []struct {
+		name             string
+		importType       anv1alpha1.ServiceImportType
+		deleted          bool
+		expectedServices []string
+		expectedDomains  []string
+	}{
+		{
+			name:             "service per cluster of a headless import",
+			importType:       anv1alpha1.Headless,
+			expectedServices: []string{"kafka-default-cluster-a", "kafka-default-cluster-b"},
+			expectedDomains:  []string{"cluster-a.kafka.example.com", "cluster-b.kafka.example.com"},
+		},
+		{
+			name:             "deleted route",
+			importType:       anv1alpha1.Headless,
+			deleted:          true,
+			expectedServices: []string{"kafka-default-cluster-a", "kafka-default-cluster-b"},
+			expectedDomains:  []string{"cluster-a.kafka.example.com", "cluster-b.kafka.example.com"},
+		},
+		{
+			name:       "no service for a ClusterSetIP import",
+			importType: anv1alpha1.ClusterSetIP,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.TODO()
+
+			k8sSchema := runtime.NewScheme()
+			anv1alpha1.AddToScheme(k8sSchema)
+			clientgoscheme.AddToScheme(k8sSchema)
+			k8sClient := testclient.NewClientBuilder().WithScheme(k8sSchema).WithObjects(
+				&anv1alpha1.ServiceImport{
+					ObjectMeta: apimachineryv1.ObjectMeta{Name: "kafka", Namespace: "default"},
+					Spec:       anv1alpha1.ServiceImportSpec{Type: tt.importType},
+					Status: anv1alpha1.ServiceImportStatus{
+						Clusters: []anv1alpha1.ClusterStatus{
+							{Cluster: "cluster-a", VpcId: "vpc-a"},
+							{Cluster: "Cluster_B", VpcId: "vpc-b"},
+						},
+					},
+				},
+			).Build()
+
+			route := core.NewHTTPRoute(gwv1.HTTPRoute{
+				ObjectMeta: apimachineryv1.ObjectMeta{Name: "kafka", Namespace: "default"},
+				Spec: gwv1.HTTPRouteSpec{
+					Hostnames: []gwv1.Hostname{"kafka.example.com"},
+					Rules: []gwv1.HTTPRouteRule{{
+						BackendRefs: []gwv1.HTTPBackendRef{{
+							BackendRef: gwv1.BackendRef{
+								BackendObjectReference: gwv1.BackendObjectReference{
+									Name: "kafka",
+									Kind: &serviceImportKind,
+									Port: ptr.To(gwv1.PortNumber(9092)),
+								},
+							},
+						}},
+					}},
+				},
+			})
+			stack := core.NewDefaultStack(core.StackID(k8s.NamespacedName(route.K8sObject())))
+			routeSvc, err := model.NewLatticeService(stack, model.ServiceSpec{
+				ServiceTagFields: model.ServiceTagFields{
+					RouteName:      "kafka",
+					RouteNamespace: "default",
+					RouteType:      core.HttpRouteType,
+				},
+				ServiceNetworkNames: []string{"sn"},
+				CustomerDomainName:  "kafka.example.com",
+			})
+			assert.NoError(t, err)
+			routeSvc.IsDeleted = tt.deleted
+			if !tt.deleted {
+				_, err = model.NewListener(stack, model.ListenerSpec{
+					StackServiceId: routeSvc.ID(),
+					Port:           80,
+					Protocol:       vpclattice.ListenerProtocolHttp,
+					DefaultAction:  &model.DefaultAction{FixedResponseStatusCode: aws.Int64(404)},
+				})
+				assert.NoError(t, err)
+			}
+
+			task := &latticeServiceModelBuildTask{
+				log:    gwlog.FallbackLogger,
+				route:  route,
+				stack:  stack,
+				client: k8sClient,
+			}
+			assert.NoError(t, task.buildHeadlessServices(ctx, routeSvc))
+			assert.Equal(t, tt.importType == anv1alpha1.Headless, routeSvc.Spec.HasHeadlessServices)
+
+			var services []*model.Service
+			assert.NoError(t, stack.ListResources(&services))
+			var serviceNames, domains []string
+			for _, svc := range services {
+				if !svc.Spec.IsHeadless() {
+					continue
+				}
+				serviceNames = append(serviceNames, svc.LatticeServiceName())
+				domains = append(domains, svc.Spec.CustomerDomainName)
+				assert.Equal(t, tt.deleted, svc.IsDeleted)
+				assert.Equal(t, []string{"sn"}, svc.Spec.ServiceNetworkNames)
+				assert.False(t, svc.Spec.HasHeadlessServices)
+			}
+			assert.ElementsMatch(t, tt.expectedServices, serviceNames)
+			assert.ElementsMatch(t, tt.expectedDomains, domains)
+
+			var listeners []*model.Listener
+			assert.NoError(t, stack.ListResources(&listeners))
+			var rules []*model.Rule
+			assert.NoError(t, stack.ListResources(&rules))
+			if tt.deleted || tt.importType != anv1alpha1.Headless {
+				assert.Len(t, rules, 0)
+				return
+			}
+			assert.Len(t, listeners, 3)
+			assert.Len(t, rules, 2)
+			var clusters []string
+			for _, rule := range rules {
+				assert.Equal(t, "/", rule.Spec.PathMatchValue)
+				assert.True(t, rule.Spec.PathMatchPrefix)
+				assert.Len(t, rule.Spec.Action.TargetGroups, 1)
+				svcImportTg := rule.Spec.Action.TargetGroups[0].SvcImportTG
+				assert.Equal(t, "kafka", svcImportTg.K8SServiceName)
+				assert.Equal(t, "9092", svcImportTg.K8SServicePort)
+				clusters = append(clusters, svcImportTg.K8SClusterName+"/"+svcImportTg.VpcId)
+			}
+			assert.ElementsMatch(t, []string{"cluster-a/vpc-a", "Cluster_B/vpc-b"}, clusters)
+		})
+	}
+}
@@ -3,9 +3,12 @@ package gateway
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/aws/aws-sdk-go/service/vpclattice"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/aws/aws-application-networking-k8s/pkg/utils/gwlog"
@@ -99,6 +102,11 @@ func (t *latticeServiceModelBuildTask) buildModel(ctx context.Context) error {
 		}
 	}
 
+	err = t.buildHeadlessServices(ctx, modelSvc)
+	if err != nil {
+		return fmt.Errorf("failed to build headless services due to %w", err)
+	}
+
 	return nil
 }
 
@@ -146,9 +154,16 @@ func (t *latticeServiceModelBuildTask) buildLatticeService(ctx context.Context)
 			continue
 		}
 		spec.ServiceNetworkNames = append(spec.ServiceNetworkNames, string(parentRef.Name))
+		if arn := programmedServiceNetworkArn(gw); arn != "" {
+			if spec.ServiceNetworkArns == nil {
+				spec.ServiceNetworkArns = map[string]string{}
+			}
+			spec.ServiceNetworkArns[string(parentRef.Name)] = arn
+		}
 	}
 	if config.ServiceNetworkOverrideMode {
 		spec.ServiceNetworkNames = []string{config.DefaultServiceNetwork}
+		spec.ServiceNetworkArns = nil
 	}
 
 	if len(t.route.Spec().Hostnames()) > 0 {
@@ -225,3 +240,16 @@ type latticeServiceModelBuildTask struct {
 	stack       core.Stack
 	brTgBuilder BackendRefTargetGroupModelBuilder
 }
+
+// programmedServiceNetworkArn returns the service network ARN the gateway controller recorded in the Programmed
+// condition of the gateway, so that route deploys do not look the service network up again on every replica
+func programmedServiceNetworkArn(gw *gwv1.Gateway) string {
+	cond := meta.FindStatusCondition(gw.Status.Conditions, string(gwv1.GatewayConditionProgrammed))
+	if cond == nil || cond.Status != metav1.ConditionTrue || cond.ObservedGeneration != gw.Generation {
+		return ""
+	}
+	if !strings.HasPrefix(cond.Message, model.ServiceNetworkArnMessagePrefix) {
+		return ""
+	}
+	return strings.TrimPrefix(cond.Message, model.ServiceNetworkArnMessagePrefix)
+}
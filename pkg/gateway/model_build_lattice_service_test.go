@@ -564,6 +564,90 @@ func Test_LatticeServiceModelBuild(t *testing.T) {
 				ServiceNetworkNames: []string{"gateway1"},
 			},
 		},
+		{
+			name:          "Service network ARNs from programmed gateways",
+			wantIsDeleted: false,
+			wantErrIsNil:  true,
+			gwClass: gwv1.GatewayClass{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "gwClass1",
+				},
+				Spec: gwv1.GatewayClassSpec{
+					ControllerName: config.LatticeGatewayControllerName,
+				},
+			},
+			gw: []gwv1.Gateway{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:       "gateway1",
+						Namespace:  "default",
+						Generation: 2,
+					},
+					Spec: gwv1.GatewaySpec{
+						GatewayClassName: "gwClass1",
+					},
+					Status: gwv1.GatewayStatus{
+						Conditions: []metav1.Condition{
+							{
+								Type:               string(gwv1.GatewayConditionProgrammed),
+								Status:             metav1.ConditionTrue,
+								ObservedGeneration: 2,
+								Message:            model.ServiceNetworkArnMessagePrefix + "sn-arn-1",
+							},
+						},
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:       "gateway2",
+						Namespace:  "default",
+						Generation: 2,
+					},
+					Spec: gwv1.GatewaySpec{
+						GatewayClassName: "gwClass1",
+					},
+					Status: gwv1.GatewayStatus{
+						Conditions: []metav1.Condition{
+							{
+								Type:               string(gwv1.GatewayConditionProgrammed),
+								Status:             metav1.ConditionTrue,
+								ObservedGeneration: 1,
+								Message:            model.ServiceNetworkArnMessagePrefix + "sn-arn-2",
+							},
+						},
+					},
+				},
+			},
+			route: core.NewHTTPRoute(gwv1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "service1",
+					Namespace: "default",
+				},
+				Spec: gwv1.HTTPRouteSpec{
+					CommonRouteSpec: gwv1.CommonRouteSpec{
+						ParentRefs: []gwv1.ParentReference{
+							{
+								Name:      "gateway1",
+								Namespace: namespacePtr("default"),
+							},
+							{
+								Name:      "gateway2",
+								Namespace: namespacePtr("default"),
+							},
+						},
+					},
+				},
+			}),
+			expected: model.ServiceSpec{
+				ServiceTagFields: model.ServiceTagFields{
+					RouteName:      "service1",
+					RouteNamespace: "default",
+					RouteType:      core.HttpRouteType,
+				},
+				ServiceNetworkNames: []string{"gateway1", "gateway2"},
+				ServiceNetworkArns:  map[string]string{"gateway1": "sn-arn-1"},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -605,6 +689,7 @@ func Test_LatticeServiceModelBuild(t *testing.T) {
 			assert.Equal(t, tt.expected.CustomerDomainName, svc.Spec.CustomerDomainName)
 			assert.Equal(t, tt.expected.RouteType, svc.Spec.RouteType)
 			assert.Equal(t, tt.expected.ServiceNetworkNames, svc.Spec.ServiceNetworkNames)
+			assert.Equal(t, tt.expected.ServiceNetworkArns, svc.Spec.ServiceNetworkArns)
 		})
 	}
 }
@@ -133,8 +133,8 @@ func (t *latticeServiceModelBuildTask) getListenerDefaultAction(ctx context.Cont
 		}, nil
 	}
 
-	if len(t.route.Spec().Rules()) != 1 {
-		return nil, fmt.Errorf("only support exactly 1 rule for TLSRoute %s/%s, but got %d", t.route.Namespace(), t.route.Name(), len(t.route.Spec().Rules()))
+	if err := t.validateTlsPassthroughRules(); err != nil {
+		return nil, err
 	}
 	modelRouteRule := t.route.Spec().Rules()[0]
 	ruleTgList, err := t.getTargetGroupsForRuleAction(ctx, modelRouteRule)
@@ -148,3 +148,10 @@ func (t *latticeServiceModelBuildTask) getListenerDefaultAction(ctx context.Cont
 		},
 	}, nil
 }
+
+func (t *latticeServiceModelBuildTask) validateTlsPassthroughRules() error {
+	if len(t.route.Spec().Rules()) != 1 {
+		return fmt.Errorf("only support exactly 1 rule for TLSRoute %s/%s, but got %d", t.route.Namespace(), t.route.Name(), len(t.route.Spec().Rules()))
+	}
+	return nil
+}
@@ -4,11 +4,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/types"
 
 	anv1alpha1 "github.com/aws/aws-application-networking-k8s/pkg/apis/applicationnetworking/v1alpha1"
+	"github.com/aws/aws-application-networking-k8s/pkg/config"
+	policy "github.com/aws/aws-application-networking-k8s/pkg/k8s/policyhelper"
 	"github.com/aws/aws-application-networking-k8s/pkg/model/core"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -26,6 +30,7 @@ const (
 	LATTICE_UNSUPPORTED_HEADER_MATCH_TYPE   = "LATTICE_UNSUPPORTED_HEADER_MATCH_TYPE"
 	LATTICE_UNSUPPORTED_PATH_MATCH_TYPE     = "LATTICE_UNSUPPORTED_PATH_MATCH_TYPE"
 	LATTICE_MAX_HEADER_MATCHES              = 5
+	LATTICE_MAX_TARGET_GROUP_WEIGHT         = 999
 )
 
 func (t *latticeServiceModelBuildTask) buildRules(ctx context.Context, stackListenerId string) error {
@@ -38,38 +43,8 @@ func (t *latticeServiceModelBuildTask) buildRules(ctx context.Context, stackList
 			Priority:        int64(i + 1),
 		}
 
-		if len(rule.Matches()) > 1 {
-			// only support 1 match today
-			return errors.New(LATTICE_NO_SUPPORT_FOR_MULTIPLE_MATCHES)
-		} else if len(rule.Matches()) > 0 {
-			t.log.Debugf(ctx, "Processing rule match")
-			match := rule.Matches()[0]
-
-			switch m := match.(type) {
-			case *core.HTTPRouteMatch:
-				if err := t.updateRuleSpecForHttpRoute(m, &ruleSpec); err != nil {
-					return err
-				}
-			case *core.GRPCRouteMatch:
-				if err := t.updateRuleSpecForGrpcRoute(m, &ruleSpec); err != nil {
-					return err
-				}
-			default:
-				return fmt.Errorf("unsupported rule match: %T", m)
-			}
-
-			if err := t.updateRuleSpecWithHeaderMatches(match, &ruleSpec); err != nil {
-				return err
-			}
-		} else {
-
-			// Match every traffic on no matches
-			ruleSpec.PathMatchValue = "/"
-			ruleSpec.PathMatchPrefix = true
-			if _, ok := rule.(*core.GRPCRouteRule); ok {
-				ruleSpec.Method = string(gwv1.HTTPMethodPost)
-			}
-
+		if err := t.updateRuleSpecWithMatches(ctx, rule, &ruleSpec); err != nil {
+			return err
 		}
 
 		ruleTgList, err := t.getTargetGroupsForRuleAction(ctx, rule)
@@ -97,6 +72,44 @@ func (t *latticeServiceModelBuildTask) buildRules(ctx context.Context, stackList
 	return nil
 }
 
+// updateRuleSpecWithMatches sets the match of the rule spec, failing on the matches Lattice does not support
+func (t *latticeServiceModelBuildTask) updateRuleSpecWithMatches(ctx context.Context, rule core.RouteRule, ruleSpec *model.RuleSpec) error {
+	if len(rule.Matches()) > 1 {
+		// only support 1 match today
+		return errors.New(LATTICE_NO_SUPPORT_FOR_MULTIPLE_MATCHES)
+	} else if len(rule.Matches()) > 0 {
+		t.log.Debugf(ctx, "Processing rule match")
+		match := rule.Matches()[0]
+
+		switch m := match.(type) {
+		case *core.HTTPRouteMatch:
+			if err := t.updateRuleSpecForHttpRoute(m, ruleSpec); err != nil {
+				return err
+			}
+		case *core.GRPCRouteMatch:
+			if err := t.updateRuleSpecForGrpcRoute(m, ruleSpec); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unsupported rule match: %T", m)
+		}
+
+		if err := t.updateRuleSpecWithHeaderMatches(match, ruleSpec); err != nil {
+			return err
+		}
+	} else {
+
+		// Match every traffic on no matches
+		ruleSpec.PathMatchValue = "/"
+		ruleSpec.PathMatchPrefix = true
+		if _, ok := rule.(*core.GRPCRouteRule); ok {
+			ruleSpec.Method = string(gwv1.HTTPMethodPost)
+		}
+
+	}
+	return nil
+}
+
 func (t *latticeServiceModelBuildTask) updateRuleSpecForHttpRoute(m *core.HTTPRouteMatch, ruleSpec *model.RuleSpec) error {
 	hasPath := m.Path() != nil
 	hasType := hasPath && m.Path().Type != nil
@@ -210,7 +223,7 @@ func (t *latticeServiceModelBuildTask) updateRuleSpecWithHeaderMatches(match cor
 func (t *latticeServiceModelBuildTask) getTargetGroupsForRuleAction(ctx context.Context, rule core.RouteRule) ([]*model.RuleTargetGroup, error) {
 	var tgList []*model.RuleTargetGroup
 
-	for _, backendRef := range rule.BackendRefs() {
+	for i, backendRef := range rule.BackendRefs() {
 		ruleTG := model.RuleTargetGroup{
 			Weight: 1, // default value according to spec
 		}
@@ -225,6 +238,15 @@ func (t *latticeServiceModelBuildTask) getTargetGroupsForRuleAction(ctx context.
 
 		t.log.Debugf(ctx, "Processing %s backendRef %s-%s", string(*backendRef.Kind()), backendRef.Name(), namespace)
 
+		if !config.IsNamespaceWatched(namespace) {
+			// the backend is not in the cache, the route has a ResolvedRefs condition for it
+			t.log.Infof(ctx, "BackendRef %s of route %s is in namespace %s, which is not watched",
+				backendRef.Name(), t.route.Name(), namespace)
+			ruleTG.StackTargetGroupId = model.InvalidBackendRefTgId
+			tgList = append(tgList, &ruleTG)
+			continue
+		}
+
 		if string(*backendRef.Kind()) == "ServiceImport" {
 			// there needs to be a pre-existing target group, we fetch all the fields
 			// needed to identify it
@@ -232,6 +254,9 @@ func (t *latticeServiceModelBuildTask) getTargetGroupsForRuleAction(ctx context.
 				K8SServiceNamespace: namespace,
 				K8SServiceName:      string(backendRef.Name()),
 			}
+			if backendRef.Port() != nil && *backendRef.Port() != 0 {
+				svcImportTg.K8SServicePort = strconv.Itoa(int(*backendRef.Port()))
+			}
 
 			// if there's a matching top-level service import, we can get additional fields
 			svcImportName := types.NamespacedName{
@@ -253,7 +278,20 @@ func (t *latticeServiceModelBuildTask) getTargetGroupsForRuleAction(ctx context.
 			if ok {
 				svcImportTg.K8SClusterName = eksCluster
 			}
+			svcImportTg.AccountId = svcImport.AccountId()
 			ruleTG.SvcImportTG = &svcImportTg
+
+			if svcImport.Name != "" {
+				sip, err := policy.NewServiceImportPolicyHandler(t.log, t.client).ObjResolvedPolicy(ctx, svcImport)
+				if err != nil && !meta.IsNoMatchError(err) {
+					return nil, err
+				}
+				if sip != nil {
+					clusterGroup := fmt.Sprintf("%s/%s-%d", namespace, backendRef.Name(), i)
+					tgList = append(tgList, clusterRuleTargetGroups(ruleTG, sip, clusterGroup)...)
+					continue
+				}
+			}
 		}
 
 		if string(*backendRef.Kind()) == "Service" {
@@ -275,5 +313,46 @@ func (t *latticeServiceModelBuildTask) getTargetGroupsForRuleAction(ctx context.
 		tgList = append(tgList, &ruleTG)
 	}
 
+	normalizeRuleWeights(tgList)
 	return tgList, nil
 }
+
+// normalizeRuleWeights scales the weights of the target groups of a rule down to the Lattice maximum,
+// keeping their proportions. Cluster weights multiply backendRef weights, so the product can exceed it.
+func normalizeRuleWeights(tgList []*model.RuleTargetGroup) {
+	var maxWeight int64
+	for _, ruleTG := range tgList {
+		maxWeight = max(maxWeight, ruleTG.Weight)
+	}
+	if maxWeight <= LATTICE_MAX_TARGET_GROUP_WEIGHT {
+		return
+	}
+	for _, ruleTG := range tgList {
+		if ruleTG.Weight == 0 {
+			continue
+		}
+		// rounded, a target group with traffic keeps some of it
+		ruleTG.Weight = max(1, (ruleTG.Weight*LATTICE_MAX_TARGET_GROUP_WEIGHT+maxWeight/2)/maxWeight)
+	}
+}
+
+// clusterRuleTargetGroups fans the target group of a ServiceImport backendRef out into one target group
+// per cluster of its ServiceImportPolicy. The cluster annotation of the ServiceImport is ignored.
+func clusterRuleTargetGroups(ruleTG model.RuleTargetGroup, sip *anv1alpha1.ServiceImportPolicy, clusterGroup string) []*model.RuleTargetGroup {
+	var tgList []*model.RuleTargetGroup
+	for _, cluster := range sip.Spec.Clusters {
+		svcImportTg := *ruleTG.SvcImportTG
+		svcImportTg.K8SClusterName = cluster.Name
+
+		clusterTG := ruleTG
+		clusterTG.SvcImportTG = &svcImportTg
+		clusterTG.ClusterGroup = clusterGroup
+		if sip.IsFailover() {
+			clusterTG.Failover = true
+		} else if cluster.Weight != nil {
+			clusterTG.Weight = ruleTG.Weight * *cluster.Weight
+		}
+		tgList = append(tgList, &clusterTG)
+	}
+	return tgList
+}
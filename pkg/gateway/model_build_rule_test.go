@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	anv1alpha1 "github.com/aws/aws-application-networking-k8s/pkg/apis/applicationnetworking/v1alpha1"
+	"github.com/aws/aws-application-networking-k8s/pkg/config"
 	"github.com/aws/aws-application-networking-k8s/pkg/k8s"
 	"github.com/aws/aws-application-networking-k8s/pkg/model/core"
 	model "github.com/aws/aws-application-networking-k8s/pkg/model/lattice"
@@ -22,6 +23,7 @@ import (
 	"k8s.io/utils/ptr"
 	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
 	gwv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gwv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 )
 
 type dummyTgBuilder struct {
@@ -1553,3 +1555,203 @@ func validateEqual(t *testing.T, expectedRules []model.RuleSpec, actualRules []*
 		}
 	}
 }
+
+func Test_RuleModelBuild_ServiceImportPolicy(t *testing.T) {
+	var serviceImportKind gwv1.Kind = "ServiceImport"
+	var httpSectionName gwv1.SectionName = "http"
+
+	tests := []struct {
+		name     string
+		mode     anv1alpha1.ServiceImportRoutingMode
+		expected []*model.RuleTargetGroup
+	}{
+		{
+			name: "weighted",
+			mode: anv1alpha1.ServiceImportRoutingModeWeighted,
+			expected: []*model.RuleTargetGroup{
+				{
+					SvcImportTG: &model.SvcImportTargetGroup{
+						K8SClusterName: "cluster-a", K8SServiceName: "svc", K8SServiceNamespace: "default",
+					},
+					Weight:       6,
+					ClusterGroup: "default/svc-0",
+				},
+				{
+					SvcImportTG: &model.SvcImportTargetGroup{
+						K8SClusterName: "cluster-b", K8SServiceName: "svc", K8SServiceNamespace: "default",
+					},
+					Weight:       2,
+					ClusterGroup: "default/svc-0",
+				},
+			},
+		},
+		{
+			name: "failover",
+			mode: anv1alpha1.ServiceImportRoutingModeFailover,
+			expected: []*model.RuleTargetGroup{
+				{
+					SvcImportTG: &model.SvcImportTargetGroup{
+						K8SClusterName: "cluster-a", K8SServiceName: "svc", K8SServiceNamespace: "default",
+					},
+					Weight:       2,
+					ClusterGroup: "default/svc-0",
+					Failover:     true,
+				},
+				{
+					SvcImportTG: &model.SvcImportTargetGroup{
+						K8SClusterName: "cluster-b", K8SServiceName: "svc", K8SServiceNamespace: "default",
+					},
+					Weight:       2,
+					ClusterGroup: "default/svc-0",
+					Failover:     true,
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.TODO()
+
+			k8sSchema := runtime.NewScheme()
+			anv1alpha1.AddToScheme(k8sSchema)
+			clientgoscheme.AddToScheme(k8sSchema)
+			k8sClient := testclient.NewClientBuilder().WithScheme(k8sSchema).WithObjects(
+				&anv1alpha1.ServiceImport{
+					ObjectMeta: apimachineryv1.ObjectMeta{
+						Name:        "svc",
+						Namespace:   "default",
+						Annotations: map[string]string{"application-networking.k8s.aws/aws-eks-cluster-name": "ignored"},
+					},
+				},
+				&anv1alpha1.ServiceImportPolicy{
+					ObjectMeta: apimachineryv1.ObjectMeta{Name: "policy", Namespace: "default"},
+					Spec: anv1alpha1.ServiceImportPolicySpec{
+						TargetRef: &gwv1alpha2.NamespacedPolicyTargetReference{
+							Group: anv1alpha1.GroupName,
+							Kind:  "ServiceImport",
+							Name:  "svc",
+						},
+						Mode: &tt.mode,
+						Clusters: []anv1alpha1.ServiceImportClusterTarget{
+							{Name: "cluster-a", Weight: aws.Int64(3)},
+							{Name: "cluster-b"},
+						},
+					},
+				},
+			).Build()
+
+			route := core.NewHTTPRoute(gwv1.HTTPRoute{
+				ObjectMeta: apimachineryv1.ObjectMeta{Name: "service1", Namespace: "default"},
+				Spec: gwv1.HTTPRouteSpec{
+					CommonRouteSpec: gwv1.CommonRouteSpec{
+						ParentRefs: []gwv1.ParentReference{{Name: "gw1", SectionName: &httpSectionName}},
+					},
+					Rules: []gwv1.HTTPRouteRule{{
+						BackendRefs: []gwv1.HTTPBackendRef{{
+							BackendRef: gwv1.BackendRef{
+								BackendObjectReference: gwv1.BackendObjectReference{Name: "svc", Kind: &serviceImportKind},
+								Weight:                 ptr.To(int32(2)),
+							},
+						}},
+					}},
+				},
+			})
+			stack := core.NewDefaultStack(core.StackID(k8s.NamespacedName(route.K8sObject())))
+			task := &latticeServiceModelBuildTask{
+				log:         gwlog.FallbackLogger,
+				route:       route,
+				stack:       stack,
+				client:      k8sClient,
+				brTgBuilder: &dummyTgBuilder{},
+			}
+			assert.NoError(t, task.buildRules(ctx, "listener-id"))
+
+			var resRules []*model.Rule
+			stack.ListResources(&resRules)
+			assert.Len(t, resRules, 1)
+			assert.Equal(t, tt.expected, resRules[0].Spec.Action.TargetGroups)
+		})
+	}
+}
+
+func Test_RuleModelBuild_UnwatchedBackendNamespace(t *testing.T) {
+	ctx := context.TODO()
+	config.WatchNamespaces = []string{"default"}
+	defer func() { config.WatchNamespaces = nil }()
+
+	var serviceKind gwv1.Kind = "Service"
+	var httpSectionName gwv1.SectionName = "http"
+	otherNamespace := gwv1.Namespace("other")
+	k8sSchema := runtime.NewScheme()
+	clientgoscheme.AddToScheme(k8sSchema)
+	k8sClient := testclient.NewClientBuilder().WithScheme(k8sSchema).Build()
+
+	route := core.NewHTTPRoute(gwv1.HTTPRoute{
+		ObjectMeta: apimachineryv1.ObjectMeta{Name: "service1", Namespace: "default"},
+		Spec: gwv1.HTTPRouteSpec{
+			CommonRouteSpec: gwv1.CommonRouteSpec{
+				ParentRefs: []gwv1.ParentReference{{Name: "gw1", SectionName: &httpSectionName}},
+			},
+			Rules: []gwv1.HTTPRouteRule{{
+				BackendRefs: []gwv1.HTTPBackendRef{{
+					BackendRef: gwv1.BackendRef{
+						BackendObjectReference: gwv1.BackendObjectReference{
+							Name: "svc", Namespace: &otherNamespace, Kind: &serviceKind,
+						},
+					},
+				}},
+			}},
+		},
+	})
+	stack := core.NewDefaultStack(core.StackID(k8s.NamespacedName(route.K8sObject())))
+	task := &latticeServiceModelBuildTask{
+		log:         gwlog.FallbackLogger,
+		route:       route,
+		stack:       stack,
+		client:      k8sClient,
+		brTgBuilder: &dummyTgBuilder{},
+	}
+	assert.NoError(t, task.buildRules(ctx, "listener-id"))
+
+	var resRules []*model.Rule
+	stack.ListResources(&resRules)
+	assert.Len(t, resRules, 1)
+	assert.Equal(t, []*model.RuleTargetGroup{{StackTargetGroupId: model.InvalidBackendRefTgId, Weight: 1}},
+		resRules[0].Spec.Action.TargetGroups)
+}
+
+func Test_normalizeRuleWeights(t *testing.T) {
+	tests := []struct {
+		name     string
+		weights  []int64
+		expected []int64
+	}{
+		{
+			name:     "within the maximum",
+			weights:  []int64{999, 1, 0},
+			expected: []int64{999, 1, 0},
+		},
+		{
+			name:     "backendRef weight multiplied by cluster weights",
+			weights:  []int64{500 * 999, 500 * 3, 100, 0},
+			expected: []int64{999, 3, 1, 0},
+		},
+		{
+			name:     "proportions kept",
+			weights:  []int64{2000, 1000, 0},
+			expected: []int64{999, 500, 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var tgList []*model.RuleTargetGroup
+			for _, weight := range tt.weights {
+				tgList = append(tgList, &model.RuleTargetGroup{Weight: weight})
+			}
+			normalizeRuleWeights(tgList)
+			for i, ruleTG := range tgList {
+				assert.Equal(t, tt.expected[i], ruleTG.Weight)
+			}
+		})
+	}
+}
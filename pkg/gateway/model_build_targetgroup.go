@@ -4,6 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/aws/aws-sdk-go/service/vpclattice"
 	corev1 "k8s.io/api/core/v1"
@@ -40,7 +43,7 @@ type SvcExportTargetGroupModelBuilder interface {
 	Build(ctx context.Context, svcExport *anv1alpha1.ServiceExport) (core.Stack, error)
 
 	// used for reconciliation of existing target groups against a service export object
-	BuildTargetGroup(ctx context.Context, svcExport *anv1alpha1.ServiceExport) (*model.TargetGroup, error)
+	BuildTargetGroups(ctx context.Context, svcExport *anv1alpha1.ServiceExport) ([]*model.TargetGroup, error)
 }
 
 type SvcExportTargetGroupBuilder struct {
@@ -87,7 +90,7 @@ func (b *SvcExportTargetGroupBuilder) Build(
 	return task.stack, nil
 }
 
-func (b *SvcExportTargetGroupBuilder) BuildTargetGroup(ctx context.Context, svcExport *anv1alpha1.ServiceExport) (*model.TargetGroup, error) {
+func (b *SvcExportTargetGroupBuilder) BuildTargetGroups(ctx context.Context, svcExport *anv1alpha1.ServiceExport) ([]*model.TargetGroup, error) {
 	stack := core.NewDefaultStack(core.StackID(k8s.NamespacedName(svcExport)))
 
 	task := &svcExportTargetGroupModelBuildTask{
@@ -98,18 +101,21 @@ func (b *SvcExportTargetGroupBuilder) BuildTargetGroup(ctx context.Context, svcE
 		tgp:           policy.NewTargetGroupPolicyHandler(b.log, b.client),
 	}
 
-	return task.buildTargetGroup(ctx)
+	return task.buildTargetGroups(ctx)
 }
 
 func (t *svcExportTargetGroupModelBuildTask) run(ctx context.Context) error {
-	tg, err := t.buildTargetGroup(ctx)
+	tgs, err := t.buildTargetGroups(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to build target group for service export %s-%s due to %w",
 			t.serviceExport.Name, t.serviceExport.Namespace, err)
 	}
 
-	if !tg.IsDeleted {
-		err = t.buildTargets(ctx, tg.ID())
+	for _, tg := range tgs {
+		if tg.IsDeleted {
+			continue
+		}
+		err = t.buildTargets(ctx, tg)
 		if err != nil {
 			t.log.Debugf(ctx, "Failed to build targets for service export %s-%s due to %s",
 				t.serviceExport.Name, t.serviceExport.Namespace, err)
@@ -120,16 +126,18 @@ func (t *svcExportTargetGroupModelBuildTask) run(ctx context.Context) error {
 	return nil
 }
 
-func (t *svcExportTargetGroupModelBuildTask) buildTargets(ctx context.Context, stackTgId string) error {
+func (t *svcExportTargetGroupModelBuildTask) buildTargets(ctx context.Context, tg *model.TargetGroup) error {
 	targetsBuilder := NewTargetsBuilder(t.log, t.client, t.stack)
-	_, err := targetsBuilder.BuildForServiceExport(ctx, t.serviceExport, stackTgId)
+	_, err := targetsBuilder.BuildForServiceExport(ctx, t.serviceExport, tg.Spec.K8SServicePort, tg.ID())
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (t *svcExportTargetGroupModelBuildTask) buildTargetGroup(ctx context.Context) (*model.TargetGroup, error) {
+// buildTargetGroups builds one target group per exported port: the ports of the port annotation,
+// or all the TCP ports of the Service without annotation
+func (t *svcExportTargetGroupModelBuildTask) buildTargetGroups(ctx context.Context) ([]*model.TargetGroup, error) {
 	svc := &corev1.Service{}
 	noSvcFoundAndDeleting := false
 	if err := t.client.Get(ctx, k8s.NamespacedName(t.serviceExport), svc); err != nil {
@@ -158,33 +166,104 @@ func (t *svcExportTargetGroupModelBuildTask) buildTargetGroup(ctx context.Contex
 		return nil, err
 	}
 
-	protocol, protocolVersion, healthCheckConfig, err := parseTargetGroupConfig(tgp)
-	if err != nil {
-		return nil, err
+	var ports []int32
+	if noSvcFoundAndDeleting {
+		// without Service only the annotated ports are known, the GC deletes the other target groups
+		ports = parseExportedPorts(ctx, t.log, t.serviceExport)
+	} else {
+		ports = exportedServicePorts(ctx, t.log, t.serviceExport, svc)
 	}
 
-	spec := model.TargetGroupSpec{
-		Type:              model.TargetGroupTypeIP,
-		Port:              80,
-		Protocol:          protocol,
-		ProtocolVersion:   protocolVersion,
-		IpAddressType:     ipAddressType,
-		HealthCheckConfig: healthCheckConfig,
+	var stackTGs []*model.TargetGroup
+	for _, port := range ports {
+		svcPort := &corev1.ServicePort{Port: port}
+		if !noSvcFoundAndDeleting {
+			svcPort = findServicePort(svc, port)
+		}
+		protocol, protocolVersion, healthCheckConfig, err := parseTargetGroupConfig(tgp, svcPort)
+		if err != nil {
+			return nil, err
+		}
+
+		spec := model.TargetGroupSpec{
+			Type:              model.TargetGroupTypeIP,
+			Port:              port,
+			Protocol:          protocol,
+			ProtocolVersion:   protocolVersion,
+			IpAddressType:     ipAddressType,
+			HealthCheckConfig: healthCheckConfig,
+		}
+		spec.VpcId = config.VpcID
+		spec.K8SSourceType = model.SourceTypeSvcExport
+		spec.K8SClusterName = config.ClusterName
+		spec.K8SServiceName = t.serviceExport.Name
+		spec.K8SServiceNamespace = t.serviceExport.Namespace
+		spec.K8SProtocolVersion = protocolVersion
+		spec.K8SServicePort = strconv.Itoa(int(port))
+
+		stackTG, err := model.NewTargetGroup(t.stack, spec)
+		if err != nil {
+			return nil, err
+		}
+
+		stackTG.IsDeleted = !t.serviceExport.DeletionTimestamp.IsZero()
+		stackTGs = append(stackTGs, stackTG)
 	}
-	spec.VpcId = config.VpcID
-	spec.K8SSourceType = model.SourceTypeSvcExport
-	spec.K8SClusterName = config.ClusterName
-	spec.K8SServiceName = t.serviceExport.Name
-	spec.K8SServiceNamespace = t.serviceExport.Namespace
-	spec.K8SProtocolVersion = protocolVersion
+	return stackTGs, nil
+}
 
-	stackTG, err := model.NewTargetGroup(t.stack, spec)
-	if err != nil {
-		return nil, err
+// exportedServicePorts returns the Service ports of the port annotation, all the TCP ports of the Service without annotation
+func exportedServicePorts(ctx context.Context, log gwlog.Logger, svcExport *anv1alpha1.ServiceExport, svc *corev1.Service) []int32 {
+	annotated := parseExportedPorts(ctx, log, svcExport)
+	var ports []int32
+	for _, port := range svc.Spec.Ports {
+		if port.Protocol != "" && port.Protocol != corev1.ProtocolTCP {
+			continue
+		}
+		if len(annotated) > 0 && !slices.Contains(annotated, port.Port) {
+			continue
+		}
+		ports = append(ports, port.Port)
 	}
+	for _, port := range annotated {
+		if !slices.Contains(ports, port) {
+			log.Infof(ctx, "Port %d of service export %s-%s is not a TCP port of the service, skipping",
+				port, svcExport.Name, svcExport.Namespace)
+		}
+	}
+	return ports
+}
 
-	stackTG.IsDeleted = !t.serviceExport.DeletionTimestamp.IsZero()
-	return stackTG, nil
+// findServicePort returns the port of the Service with the number, or a port with the number only when the
+// Service does not have it
+func findServicePort(svc *corev1.Service, port int32) *corev1.ServicePort {
+	for i := range svc.Spec.Ports {
+		if svc.Spec.Ports[i].Port == port {
+			return &svc.Spec.Ports[i]
+		}
+	}
+	return &corev1.ServicePort{Port: port}
+}
+
+// parseExportedPorts returns the ports of the port annotation of a ServiceExport
+func parseExportedPorts(ctx context.Context, log gwlog.Logger, svcExport *anv1alpha1.ServiceExport) []int32 {
+	var ports []int32
+	for _, portAnnotation := range strings.Split(svcExport.Annotations[portAnnotationsKey], ",") {
+		portAnnotation = strings.TrimSpace(portAnnotation)
+		if portAnnotation == "" {
+			continue
+		}
+		port, err := strconv.ParseInt(portAnnotation, 10, 32)
+		if err != nil {
+			log.Infof(ctx, "failed to read Annotations/Port: %s due to %s",
+				svcExport.Annotations[portAnnotationsKey], err)
+			continue
+		}
+		if !slices.Contains(ports, int32(port)) {
+			ports = append(ports, int32(port))
+		}
+	}
+	return ports
 }
 
 type BackendRefTargetGroupModelBuilder interface {
@@ -239,6 +318,20 @@ func (b *BackendRefTargetGroupBuilder) Build(
 	return task.stack, stackTg, nil
 }
 
+// BuildBackendRefTargetGroupSpec returns the spec of the target group the route builds for the backendRef,
+// without building its targets
+func BuildBackendRefTargetGroupSpec(ctx context.Context, log gwlog.Logger, client client.Client, route core.Route,
+	backendRef core.BackendRef) (model.TargetGroupSpec, error) {
+	task := backendRefTargetGroupModelBuildTask{
+		log:        log,
+		client:     client,
+		route:      route,
+		backendRef: backendRef,
+		tgp:        policy.NewTargetGroupPolicyHandler(log, client),
+	}
+	return task.buildTargetGroupSpec(ctx)
+}
+
 func (t *backendRefTargetGroupModelBuildTask) buildTargetGroup(ctx context.Context) (*model.TargetGroup, error) {
 	if string(*t.backendRef.Kind()) == "ServiceImport" {
 		return nil, errors.New("not supported for ServiceImport BackendRef")
@@ -316,7 +409,11 @@ func (t *backendRefTargetGroupModelBuildTask) buildTargetGroupSpec(ctx context.C
 		return model.TargetGroupSpec{}, err
 	}
 
-	protocol, protocolVersion, healthCheckConfig, err := parseTargetGroupConfig(tgp)
+	var svcPort *corev1.ServicePort
+	if t.backendRef.Port() != nil {
+		svcPort = findServicePort(svc, int32(*t.backendRef.Port()))
+	}
+	protocol, protocolVersion, healthCheckConfig, err := parseTargetGroupConfig(tgp, svcPort)
 	if err != nil {
 		return model.TargetGroupSpec{}, err
 	}
@@ -371,33 +468,106 @@ func getBackendRefNsName(route core.Route, backendRef core.BackendRef) types.Nam
 	return backendRefNsName
 }
 
-func parseTargetGroupConfig(tgp *anv1alpha1.TargetGroupPolicy) (
+// parseTargetGroupConfig returns the configuration of the policy for the target group of the Service port,
+// with the settings of the first port override matching it. svcPort is nil when the port is unknown.
+func parseTargetGroupConfig(tgp *anv1alpha1.TargetGroupPolicy, svcPort *corev1.ServicePort) (
 	protocol string, protocolVersion string, healthCheckConfig *vpclattice.HealthCheckConfig, err error) {
 	protocol = "HTTP"
 	protocolVersion = vpclattice.TargetGroupProtocolVersionHttp1
 	if tgp == nil {
 		return protocol, protocolVersion, nil, nil
 	}
-	if tgp.Spec.Protocol != nil && *tgp.Spec.Protocol == vpclattice.TargetGroupProtocolTcp {
-		if tgp.Spec.ProtocolVersion != nil {
+	spec := tgp.Spec
+	if override := findPortOverride(tgp, svcPort); override != nil {
+		if override.Protocol != nil {
+			spec.Protocol = override.Protocol
+			spec.ProtocolVersion = nil
+		}
+		if override.ProtocolVersion != nil {
+			spec.ProtocolVersion = override.ProtocolVersion
+		}
+		spec.HealthCheck = mergeHealthCheck(spec.HealthCheck, override.HealthCheck)
+	}
+	if spec.Protocol != nil && *spec.Protocol == vpclattice.TargetGroupProtocolTcp {
+		if spec.ProtocolVersion != nil {
 			return "", "", nil, fmt.Errorf("protocolVersion is not supported for TCP protocol TargetGroupPolicy")
 		}
 		protocolVersion = ""
 	}
 	// Override protocol if specified in the TargetGroupPolicy
-	if tgp.Spec.Protocol != nil {
-		protocol = *tgp.Spec.Protocol
+	if spec.Protocol != nil {
+		protocol = *spec.Protocol
 	}
 	// Override protocolVersion if specified in the TargetGroupPolicy for non-TCP protocol
-	if tgp.Spec.ProtocolVersion != nil && protocol != vpclattice.TargetGroupProtocolTcp {
-		protocolVersion = *tgp.Spec.ProtocolVersion
+	if spec.ProtocolVersion != nil && protocol != vpclattice.TargetGroupProtocolTcp {
+		protocolVersion = *spec.ProtocolVersion
 	}
-	healthCheckConfig = parseHealthCheckConfig(tgp)
+	healthCheckConfig = parseHealthCheckConfig(spec.HealthCheck)
 	return protocol, protocolVersion, healthCheckConfig, nil
 }
 
-func parseHealthCheckConfig(tgp *anv1alpha1.TargetGroupPolicy) *vpclattice.HealthCheckConfig {
-	hc := tgp.Spec.HealthCheck
+// findPortOverride returns the first port override of the policy selecting the Service port by name or number
+func findPortOverride(tgp *anv1alpha1.TargetGroupPolicy, svcPort *corev1.ServicePort) *anv1alpha1.TargetGroupPortOverride {
+	if svcPort == nil {
+		return nil
+	}
+	for i, override := range tgp.Spec.PortOverrides {
+		if override.SectionName != nil {
+			if svcPort.Name != "" && string(*override.SectionName) == svcPort.Name {
+				return &tgp.Spec.PortOverrides[i]
+			}
+			continue
+		}
+		if override.Port != nil && int32(*override.Port) == svcPort.Port {
+			return &tgp.Spec.PortOverrides[i]
+		}
+	}
+	return nil
+}
+
+// mergeHealthCheck returns the health check of the policy with the fields set by the port override
+func mergeHealthCheck(base *anv1alpha1.HealthCheckConfig, override *anv1alpha1.HealthCheckConfig) *anv1alpha1.HealthCheckConfig {
+	if override == nil {
+		return base
+	}
+	if base == nil {
+		return override
+	}
+	hc := *base
+	if override.Enabled != nil {
+		hc.Enabled = override.Enabled
+	}
+	if override.IntervalSeconds != nil {
+		hc.IntervalSeconds = override.IntervalSeconds
+	}
+	if override.TimeoutSeconds != nil {
+		hc.TimeoutSeconds = override.TimeoutSeconds
+	}
+	if override.HealthyThresholdCount != nil {
+		hc.HealthyThresholdCount = override.HealthyThresholdCount
+	}
+	if override.UnhealthyThresholdCount != nil {
+		hc.UnhealthyThresholdCount = override.UnhealthyThresholdCount
+	}
+	if override.StatusMatch != nil {
+		hc.StatusMatch = override.StatusMatch
+	}
+	if override.Path != nil {
+		hc.Path = override.Path
+	}
+	if override.Port != nil {
+		hc.Port = override.Port
+	}
+	if override.Protocol != nil {
+		hc.Protocol = override.Protocol
+	}
+	if override.ProtocolVersion != nil {
+		hc.ProtocolVersion = override.ProtocolVersion
+	}
+	return &hc
+}
+
+func parseHealthCheckConfig(hc *anv1alpha1.HealthCheckConfig) *vpclattice.HealthCheckConfig {
 	if hc == nil {
 		return nil
 	}
@@ -3,10 +3,12 @@ package gateway
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
 	"testing"
 
 	gwv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gwv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 
 	mock_client "github.com/aws/aws-application-networking-k8s/mocks/controller-runtime/client"
 	"github.com/aws/aws-application-networking-k8s/pkg/config"
@@ -15,6 +17,7 @@ import (
 
 	"github.com/aws/aws-application-networking-k8s/pkg/utils/gwlog"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/vpclattice"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -22,6 +25,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
 	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	anv1alpha1 "github.com/aws/aws-application-networking-k8s/pkg/apis/applicationnetworking/v1alpha1"
@@ -41,6 +45,7 @@ func Test_TGModelByServiceExportBuild(t *testing.T) {
 		wantErrIsNil        bool
 		wantIsDeleted       bool
 		wantIPv6TargetGroup bool
+		wantPorts           []int32
 	}{
 		{
 			name: "Adding ServiceExport where service object exist",
@@ -97,6 +102,7 @@ func Test_TGModelByServiceExportBuild(t *testing.T) {
 					Namespace:         "ns1",
 					Finalizers:        []string{"gateway.k8s.aws/resources"},
 					DeletionTimestamp: &now,
+					Annotations:       map[string]string{portAnnotationsKey: "80"},
 				},
 			},
 			wantIsDeleted: true,
@@ -203,6 +209,58 @@ func Test_TGModelByServiceExportBuild(t *testing.T) {
 			wantErrIsNil:  false,
 			wantIsDeleted: false,
 		},
+		{
+			name: "Adding ServiceExport of a multi-port service, one target group per exported port",
+			svcExport: &anv1alpha1.ServiceExport{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "export7",
+					Namespace:   "ns1",
+					Annotations: map[string]string{portAnnotationsKey: "8080, 9090"},
+				},
+			},
+			svc: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "export7",
+					Namespace: "ns1",
+				},
+				Spec: corev1.ServiceSpec{
+					IPFamilies: []corev1.IPFamily{corev1.IPv4Protocol},
+					Ports: []corev1.ServicePort{
+						{Name: "grpc", Port: 8080},
+						{Name: "metrics", Port: 9090},
+						{Name: "admin", Port: 9901},
+						{Name: "dns", Port: 53, Protocol: corev1.ProtocolUDP},
+					},
+				},
+			},
+			wantErrIsNil: true,
+			wantPorts:    []int32{8080, 9090},
+		},
+		{
+			name: "Adding ServiceExport without port annotation exports all the TCP ports",
+			svcExport: &anv1alpha1.ServiceExport{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "export8",
+					Namespace: "ns1",
+				},
+			},
+			svc: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "export8",
+					Namespace: "ns1",
+				},
+				Spec: corev1.ServiceSpec{
+					IPFamilies: []corev1.IPFamily{corev1.IPv4Protocol},
+					Ports: []corev1.ServicePort{
+						{Name: "grpc", Port: 8080, Protocol: corev1.ProtocolTCP},
+						{Name: "dns", Port: 53, Protocol: corev1.ProtocolUDP},
+						{Name: "metrics", Port: 9090},
+					},
+				},
+			},
+			wantErrIsNil: true,
+			wantPorts:    []int32{8080, 9090},
+		},
 	}
 
 	for _, tt := range tests {
@@ -237,6 +295,19 @@ func Test_TGModelByServiceExportBuild(t *testing.T) {
 			var resTargetGroups []*model.TargetGroup
 			err = stack.ListResources(&resTargetGroups)
 			assert.Nil(t, err)
+			if tt.wantPorts != nil {
+				var ports []int32
+				for _, tg := range resTargetGroups {
+					assert.Equal(t, strconv.Itoa(int(tg.Spec.Port)), tg.Spec.K8SServicePort)
+					ports = append(ports, tg.Spec.Port)
+				}
+				assert.ElementsMatch(t, tt.wantPorts, ports)
+
+				var resTargets []*model.Targets
+				assert.Nil(t, stack.ListResources(&resTargets))
+				assert.Equal(t, len(tt.wantPorts), len(resTargets))
+				return
+			}
 			assert.Equal(t, 1, len(resTargetGroups))
 
 			stackTg := resTargetGroups[0]
@@ -700,3 +771,88 @@ func Test_buildTargetGroupIpAddressType(t *testing.T) {
 		})
 	}
 }
+
+func Test_parseTargetGroupConfig_PortOverrides(t *testing.T) {
+	http1 := anv1alpha1.HealthCheckProtocolVersionHTTP1
+	tgp := &anv1alpha1.TargetGroupPolicy{
+		Spec: anv1alpha1.TargetGroupPolicySpec{
+			ProtocolVersion: aws.String(vpclattice.TargetGroupProtocolVersionGrpc),
+			HealthCheck: &anv1alpha1.HealthCheckConfig{
+				Path:            aws.String("/grpc.health.v1.Health/Check"),
+				IntervalSeconds: aws.Int64(10),
+			},
+			PortOverrides: []anv1alpha1.TargetGroupPortOverride{
+				{
+					SectionName:     ptr.To(gwv1alpha2.SectionName("admin")),
+					ProtocolVersion: aws.String(vpclattice.TargetGroupProtocolVersionHttp1),
+					HealthCheck: &anv1alpha1.HealthCheckConfig{
+						Path:            aws.String("/healthz"),
+						ProtocolVersion: &http1,
+					},
+				},
+				{
+					Port:     ptr.To(gwv1alpha2.PortNumber(9092)),
+					Protocol: aws.String(vpclattice.TargetGroupProtocolTcp),
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name                string
+		svcPort             *corev1.ServicePort
+		wantProtocol        string
+		wantProtocolVersion string
+		wantHealthCheck     *vpclattice.HealthCheckConfig
+	}{
+		{
+			name:                "unknown port uses the policy",
+			wantProtocol:        vpclattice.TargetGroupProtocolHttp,
+			wantProtocolVersion: vpclattice.TargetGroupProtocolVersionGrpc,
+			wantHealthCheck: &vpclattice.HealthCheckConfig{
+				Path:                       aws.String("/grpc.health.v1.Health/Check"),
+				HealthCheckIntervalSeconds: aws.Int64(10),
+			},
+		},
+		{
+			name:                "port without override uses the policy",
+			svcPort:             &corev1.ServicePort{Name: "api", Port: 8080},
+			wantProtocol:        vpclattice.TargetGroupProtocolHttp,
+			wantProtocolVersion: vpclattice.TargetGroupProtocolVersionGrpc,
+			wantHealthCheck: &vpclattice.HealthCheckConfig{
+				Path:                       aws.String("/grpc.health.v1.Health/Check"),
+				HealthCheckIntervalSeconds: aws.Int64(10),
+			},
+		},
+		{
+			name:                "override by sectionName merges the health check",
+			svcPort:             &corev1.ServicePort{Name: "admin", Port: 9000},
+			wantProtocol:        vpclattice.TargetGroupProtocolHttp,
+			wantProtocolVersion: vpclattice.TargetGroupProtocolVersionHttp1,
+			wantHealthCheck: &vpclattice.HealthCheckConfig{
+				Path:                       aws.String("/healthz"),
+				HealthCheckIntervalSeconds: aws.Int64(10),
+				ProtocolVersion:            aws.String("HTTP1"),
+			},
+		},
+		{
+			name:                "override by port drops the policy protocol version",
+			svcPort:             &corev1.ServicePort{Name: "kafka", Port: 9092},
+			wantProtocol:        vpclattice.TargetGroupProtocolTcp,
+			wantProtocolVersion: "",
+			wantHealthCheck: &vpclattice.HealthCheckConfig{
+				Path:                       aws.String("/grpc.health.v1.Health/Check"),
+				HealthCheckIntervalSeconds: aws.Int64(10),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			protocol, protocolVersion, healthCheck, err := parseTargetGroupConfig(tgp, tt.svcPort)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantProtocol, protocol)
+			assert.Equal(t, tt.wantProtocolVersion, protocolVersion)
+			assert.Equal(t, tt.wantHealthCheck, healthCheck)
+		})
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"errors"
 	"strconv"
-	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -26,7 +25,7 @@ const (
 
 type LatticeTargetsBuilder interface {
 	Build(ctx context.Context, service *corev1.Service, backendRef core.BackendRef, stackTgId string) (core.Stack, error)
-	BuildForServiceExport(ctx context.Context, serviceExport *anv1alpha1.ServiceExport, stackTgId string) (core.Stack, error)
+	BuildForServiceExport(ctx context.Context, serviceExport *anv1alpha1.ServiceExport, servicePort string, stackTgId string) (core.Stack, error)
 }
 
 type LatticeTargetsModelBuilder struct {
@@ -49,17 +48,19 @@ func NewTargetsBuilder(
 
 func (b *LatticeTargetsModelBuilder) Build(ctx context.Context, service *corev1.Service,
 	backendRef core.BackendRef, stackTgId string) (core.Stack, error) {
-	return b.build(ctx, nil, service, backendRef, b.stack, stackTgId)
+	return b.build(ctx, nil, "", service, backendRef, b.stack, stackTgId)
 }
 
+// BuildForServiceExport builds the targets of the target group of an exported Service port. Target groups
+// without port, from earlier releases, get the targets of all the ports of the port annotation.
 func (b *LatticeTargetsModelBuilder) BuildForServiceExport(ctx context.Context,
-	serviceExport *anv1alpha1.ServiceExport, stackTgId string) (core.Stack, error) {
+	serviceExport *anv1alpha1.ServiceExport, servicePort string, stackTgId string) (core.Stack, error) {
 
-	return b.build(ctx, serviceExport, nil, nil, b.stack, stackTgId)
+	return b.build(ctx, serviceExport, servicePort, nil, nil, b.stack, stackTgId)
 }
 
 func (b *LatticeTargetsModelBuilder) build(ctx context.Context,
-	serviceExport *anv1alpha1.ServiceExport,
+	serviceExport *anv1alpha1.ServiceExport, servicePort string,
 	service *corev1.Service, backendRef core.BackendRef,
 	stack core.Stack, stackTgId string,
 ) (core.Stack, error) {
@@ -102,6 +103,7 @@ func (b *LatticeTargetsModelBuilder) build(ctx context.Context,
 		log:           b.log,
 		client:        b.client,
 		serviceExport: serviceExport,
+		servicePort:   servicePort,
 		service:       service,
 		backendRef:    backendRef,
 		stack:         stack,
@@ -211,19 +213,16 @@ func (t *latticeTargetsModelBuildTask) getDefinedPorts() map[int32]struct{} {
 	definedPorts := make(map[int32]struct{})
 
 	isServiceExport := t.serviceExport != nil
-	if isServiceExport {
-		portsAnnotations := strings.Split(t.serviceExport.ObjectMeta.Annotations[portAnnotationsKey], ",")
-
-		for _, portAnnotation := range portsAnnotations {
-			if portAnnotation != "" {
-				definedPort, err := strconv.ParseInt(portAnnotation, 10, 32)
-				if err != nil {
-					t.log.Infof(context.TODO(), "failed to read Annotations/Port: %s due to %s",
-						t.serviceExport.ObjectMeta.Annotations[portAnnotationsKey], err)
-				} else {
-					definedPorts[int32(definedPort)] = struct{}{}
-				}
-			}
+	if isServiceExport && t.servicePort != "" {
+		definedPort, err := strconv.ParseInt(t.servicePort, 10, 32)
+		if err != nil {
+			t.log.Infof(context.TODO(), "failed to read the service port %s of the target group due to %s", t.servicePort, err)
+		} else {
+			definedPorts[int32(definedPort)] = struct{}{}
+		}
+	} else if isServiceExport {
+		for _, definedPort := range parseExportedPorts(context.TODO(), t.log, t.serviceExport) {
+			definedPorts[definedPort] = struct{}{}
 		}
 	} else if t.backendRef.Port() != nil {
 		backendRefPort := int32(*t.backendRef.Port())
@@ -238,6 +237,7 @@ type latticeTargetsModelBuildTask struct {
 	log           gwlog.Logger
 	client        client.Client
 	serviceExport *anv1alpha1.ServiceExport
+	servicePort   string
 	service       *corev1.Service
 	backendRef    core.BackendRef
 	stack         core.Stack
@@ -471,7 +471,7 @@ func Test_Targets(t *testing.T) {
 
 			var err error
 			if tt.refByServiceExport {
-				_, err = builder.BuildForServiceExport(ctx, &tt.serviceExport, "tg-id")
+				_, err = builder.BuildForServiceExport(ctx, &tt.serviceExport, "", "tg-id")
 			} else {
 				_, err = builder.Build(ctx, &tt.svc, &corebr, "tg-id")
 			}
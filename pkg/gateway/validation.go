@@ -0,0 +1,132 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/vpclattice"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	anv1alpha1 "github.com/aws/aws-application-networking-k8s/pkg/apis/applicationnetworking/v1alpha1"
+	"github.com/aws/aws-application-networking-k8s/pkg/model/core"
+	model "github.com/aws/aws-application-networking-k8s/pkg/model/lattice"
+	"github.com/aws/aws-application-networking-k8s/pkg/utils/gwlog"
+)
+
+// a comma separated list of HTTP codes or ranges, e.g. "200", "200,202" or "200-299"
+var statusMatchRegexp = regexp.MustCompile(`^\d{3}(-\d{3})?(,\d{3}(-\d{3})?)*$`)
+
+// ValidateRoute returns the first reason the model builder would reject the route, without building any model.
+func ValidateRoute(ctx context.Context, log gwlog.Logger, route core.Route) error {
+	t := &latticeServiceModelBuildTask{
+		log:   log,
+		route: route,
+	}
+	if _, ok := route.(*core.TLSRoute); ok {
+		return t.validateTlsPassthroughRules()
+	}
+	for i, rule := range route.Spec().Rules() {
+		if err := t.updateRuleSpecWithMatches(ctx, rule, &model.RuleSpec{}); err != nil {
+			return fmt.Errorf("rule %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// ValidateBackendRefsIpFamilies rejects backend Services with dual stack ip addresses. Missing Services are ignored.
+func ValidateBackendRefsIpFamilies(ctx context.Context, client client.Client, route core.Route) error {
+	rules := route.Spec().Rules()
+
+	for _, rule := range rules {
+		backendRefs := rule.BackendRefs()
+
+		for _, backendRef := range backendRefs {
+			// For now we skip checking service import
+			if *backendRef.Kind() == "ServiceImport" {
+				continue
+			}
+
+			svc, err := GetServiceForBackendRef(ctx, client, route, backendRef)
+			if err != nil {
+				// Ignore error since Service might not be created yet
+				continue
+			}
+
+			if len(svc.Spec.IPFamilies) > 1 {
+				return errors.New("Invalid IpFamilies, Lattice Target Group doesn't support dual stack ip addresses")
+			}
+		}
+	}
+
+	return nil
+}
+
+// ValidateTargetGroupPolicy checks the values the CRD schema cannot, so that they fail before reaching Lattice.
+func ValidateTargetGroupPolicy(tgp *anv1alpha1.TargetGroupPolicy) error {
+	if err := validateTargetGroupProtocol(tgp.Spec.Protocol, tgp.Spec.ProtocolVersion); err != nil {
+		return err
+	}
+	if _, _, _, err := parseTargetGroupConfig(tgp, nil); err != nil {
+		return err
+	}
+	if err := validateHealthCheck(tgp.Spec.HealthCheck); err != nil {
+		return err
+	}
+
+	for i, override := range tgp.Spec.PortOverrides {
+		if (override.SectionName == nil) == (override.Port == nil) {
+			return fmt.Errorf("portOverrides[%d]: exactly one of sectionName and port must be set", i)
+		}
+		if err := validateTargetGroupProtocol(override.Protocol, override.ProtocolVersion); err != nil {
+			return fmt.Errorf("portOverrides[%d]: %w", i, err)
+		}
+		if err := validateHealthCheck(override.HealthCheck); err != nil {
+			return fmt.Errorf("portOverrides[%d]: %w", i, err)
+		}
+		// the override applies to a port of that name or number, check the configuration it resolves to
+		svcPort := &corev1.ServicePort{}
+		if override.SectionName != nil {
+			svcPort.Name = string(*override.SectionName)
+		} else {
+			svcPort.Port = int32(*override.Port)
+		}
+		if _, _, _, err := parseTargetGroupConfig(tgp, svcPort); err != nil {
+			return fmt.Errorf("portOverrides[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func validateTargetGroupProtocol(protocol *string, protocolVersion *string) error {
+	if p := protocol; p != nil && !slices.Contains(vpclattice.TargetGroupProtocol_Values(), *p) {
+		return fmt.Errorf("unsupported protocol %s, expected one of %s", *p, strings.Join(vpclattice.TargetGroupProtocol_Values(), ", "))
+	}
+	if v := protocolVersion; v != nil && !slices.Contains(vpclattice.TargetGroupProtocolVersion_Values(), *v) {
+		return fmt.Errorf("unsupported protocolVersion %s, expected one of %s", *v, strings.Join(vpclattice.TargetGroupProtocolVersion_Values(), ", "))
+	}
+	return nil
+}
+
+func validateHealthCheck(hc *anv1alpha1.HealthCheckConfig) error {
+	if hc == nil {
+		return nil
+	}
+	if hc.Protocol != nil && *hc.Protocol != anv1alpha1.HealthCheckProtocolHTTP && *hc.Protocol != anv1alpha1.HealthCheckProtocolHTTPS {
+		return fmt.Errorf("unsupported healthCheck protocol %s, expected one of HTTP, HTTPS", *hc.Protocol)
+	}
+	if hc.ProtocolVersion != nil && *hc.ProtocolVersion != anv1alpha1.HealthCheckProtocolVersionHTTP1 && *hc.ProtocolVersion != anv1alpha1.HealthCheckProtocolVersionHTTP2 {
+		return fmt.Errorf("unsupported healthCheck protocolVersion %s, expected one of HTTP1, HTTP2", *hc.ProtocolVersion)
+	}
+	if hc.StatusMatch != nil && !statusMatchRegexp.MatchString(*hc.StatusMatch) {
+		return fmt.Errorf("invalid healthCheck statusMatch %s, expected HTTP codes or ranges such as 200, 200,202 or 200-299", *hc.StatusMatch)
+	}
+	if hc.Path != nil && !strings.HasPrefix(*hc.Path, "/") {
+		return fmt.Errorf("invalid healthCheck path %s, expected an absolute path", *hc.Path)
+	}
+	return nil
+}
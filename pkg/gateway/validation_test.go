@@ -0,0 +1,266 @@
+package gateway
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	apimachineryv1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gwv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gwv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	anv1alpha1 "github.com/aws/aws-application-networking-k8s/pkg/apis/applicationnetworking/v1alpha1"
+	"github.com/aws/aws-application-networking-k8s/pkg/model/core"
+	"github.com/aws/aws-application-networking-k8s/pkg/utils/gwlog"
+)
+
+func Test_ValidateRoute(t *testing.T) {
+	exact := gwv1.HeaderMatchExact
+	headerMatches := func(n int) []gwv1.HTTPHeaderMatch {
+		var matches []gwv1.HTTPHeaderMatch
+		for i := 0; i < n; i++ {
+			matches = append(matches, gwv1.HTTPHeaderMatch{Type: &exact, Name: gwv1.HTTPHeaderName(string(rune('a' + i))), Value: "v"})
+		}
+		return matches
+	}
+	regexPath := gwv1.PathMatchRegularExpression
+
+	tests := []struct {
+		name    string
+		route   core.Route
+		wantErr bool
+	}{
+		{
+			name: "http route without matches",
+			route: core.NewHTTPRoute(gwv1.HTTPRoute{
+				Spec: gwv1.HTTPRouteSpec{Rules: []gwv1.HTTPRouteRule{{}}},
+			}),
+		},
+		{
+			name: "http route with supported header matches",
+			route: core.NewHTTPRoute(gwv1.HTTPRoute{
+				Spec: gwv1.HTTPRouteSpec{Rules: []gwv1.HTTPRouteRule{{
+					Matches: []gwv1.HTTPRouteMatch{{Headers: headerMatches(LATTICE_MAX_HEADER_MATCHES)}},
+				}}},
+			}),
+		},
+		{
+			name: "http route with too many header matches",
+			route: core.NewHTTPRoute(gwv1.HTTPRoute{
+				Spec: gwv1.HTTPRouteSpec{Rules: []gwv1.HTTPRouteRule{{
+					Matches: []gwv1.HTTPRouteMatch{{Headers: headerMatches(LATTICE_MAX_HEADER_MATCHES + 1)}},
+				}}},
+			}),
+			wantErr: true,
+		},
+		{
+			name: "http route with multiple matches",
+			route: core.NewHTTPRoute(gwv1.HTTPRoute{
+				Spec: gwv1.HTTPRouteSpec{Rules: []gwv1.HTTPRouteRule{{
+					Matches: []gwv1.HTTPRouteMatch{{}, {}},
+				}}},
+			}),
+			wantErr: true,
+		},
+		{
+			name: "http route with unsupported path type",
+			route: core.NewHTTPRoute(gwv1.HTTPRoute{
+				Spec: gwv1.HTTPRouteSpec{Rules: []gwv1.HTTPRouteRule{{
+					Matches: []gwv1.HTTPRouteMatch{{Path: &gwv1.HTTPPathMatch{Type: &regexPath, Value: aws.String("/.*")}}},
+				}}},
+			}),
+			wantErr: true,
+		},
+		{
+			name: "grpc route without matches",
+			route: core.NewGRPCRoute(gwv1.GRPCRoute{
+				Spec: gwv1.GRPCRouteSpec{Rules: []gwv1.GRPCRouteRule{{}}},
+			}),
+		},
+		{
+			name: "tls route with one rule",
+			route: core.NewTLSRoute(gwv1alpha2.TLSRoute{
+				Spec: gwv1alpha2.TLSRouteSpec{Rules: []gwv1alpha2.TLSRouteRule{{}}},
+			}),
+		},
+		{
+			name: "tls route with two rules",
+			route: core.NewTLSRoute(gwv1alpha2.TLSRoute{
+				Spec: gwv1alpha2.TLSRouteSpec{Rules: []gwv1alpha2.TLSRouteRule{{}, {}}},
+			}),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateRoute(context.TODO(), gwlog.FallbackLogger, tt.route)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_ValidateBackendRefsIpFamilies(t *testing.T) {
+	k8sScheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(k8sScheme)
+	k8sClient := testclient.NewClientBuilder().WithScheme(k8sScheme).WithObjects(
+		&corev1.Service{
+			ObjectMeta: apimachineryv1.ObjectMeta{Name: "single-stack", Namespace: "ns"},
+			Spec:       corev1.ServiceSpec{IPFamilies: []corev1.IPFamily{corev1.IPv4Protocol}},
+		},
+		&corev1.Service{
+			ObjectMeta: apimachineryv1.ObjectMeta{Name: "dual-stack", Namespace: "ns"},
+			Spec:       corev1.ServiceSpec{IPFamilies: []corev1.IPFamily{corev1.IPv4Protocol, corev1.IPv6Protocol}},
+		},
+	).Build()
+
+	kind := gwv1.Kind("Service")
+	route := func(backends ...string) core.Route {
+		var refs []gwv1.HTTPBackendRef
+		for _, name := range backends {
+			refs = append(refs, gwv1.HTTPBackendRef{BackendRef: gwv1.BackendRef{
+				BackendObjectReference: gwv1.BackendObjectReference{Kind: &kind, Name: gwv1.ObjectName(name)},
+			}})
+		}
+		return core.NewHTTPRoute(gwv1.HTTPRoute{
+			ObjectMeta: apimachineryv1.ObjectMeta{Name: "route", Namespace: "ns"},
+			Spec:       gwv1.HTTPRouteSpec{Rules: []gwv1.HTTPRouteRule{{BackendRefs: refs}}},
+		})
+	}
+
+	ctx := context.TODO()
+	assert.NoError(t, ValidateBackendRefsIpFamilies(ctx, k8sClient, route("single-stack", "missing")))
+	assert.Error(t, ValidateBackendRefsIpFamilies(ctx, k8sClient, route("single-stack", "dual-stack")))
+}
+
+func Test_ValidateTargetGroupPolicy(t *testing.T) {
+	httpProtocol := anv1alpha1.HealthCheckProtocolHTTP
+	tcpProtocol := anv1alpha1.HealthCheckProtocol("TCP")
+
+	tests := []struct {
+		name    string
+		spec    anv1alpha1.TargetGroupPolicySpec
+		wantErr bool
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "valid",
+			spec: anv1alpha1.TargetGroupPolicySpec{
+				Protocol:        aws.String("HTTPS"),
+				ProtocolVersion: aws.String("HTTP2"),
+				HealthCheck: &anv1alpha1.HealthCheckConfig{
+					StatusMatch: aws.String("200-299,301"),
+					Path:        aws.String("/health"),
+					Protocol:    &httpProtocol,
+				},
+			},
+		},
+		{
+			name:    "unsupported protocol",
+			spec:    anv1alpha1.TargetGroupPolicySpec{Protocol: aws.String("UDP")},
+			wantErr: true,
+		},
+		{
+			name:    "unsupported protocol version",
+			spec:    anv1alpha1.TargetGroupPolicySpec{ProtocolVersion: aws.String("HTTP3")},
+			wantErr: true,
+		},
+		{
+			name:    "protocol version with tcp",
+			spec:    anv1alpha1.TargetGroupPolicySpec{Protocol: aws.String("TCP"), ProtocolVersion: aws.String("HTTP1")},
+			wantErr: true,
+		},
+		{
+			name:    "unsupported health check protocol",
+			spec:    anv1alpha1.TargetGroupPolicySpec{HealthCheck: &anv1alpha1.HealthCheckConfig{Protocol: &tcpProtocol}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid status match",
+			spec:    anv1alpha1.TargetGroupPolicySpec{HealthCheck: &anv1alpha1.HealthCheckConfig{StatusMatch: aws.String("2xx")}},
+			wantErr: true,
+		},
+		{
+			name:    "relative health check path",
+			spec:    anv1alpha1.TargetGroupPolicySpec{HealthCheck: &anv1alpha1.HealthCheckConfig{Path: aws.String("health")}},
+			wantErr: true,
+		},
+		{
+			name: "valid port overrides",
+			spec: anv1alpha1.TargetGroupPolicySpec{
+				ProtocolVersion: aws.String("HTTP2"),
+				PortOverrides: []anv1alpha1.TargetGroupPortOverride{
+					{SectionName: ptr.To(gwv1alpha2.SectionName("admin")), ProtocolVersion: aws.String("HTTP1")},
+					{Port: ptr.To(gwv1alpha2.PortNumber(9000)), Protocol: aws.String("TCP")},
+				},
+			},
+		},
+		{
+			name: "port override without port",
+			spec: anv1alpha1.TargetGroupPolicySpec{
+				PortOverrides: []anv1alpha1.TargetGroupPortOverride{{Protocol: aws.String("HTTPS")}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "port override with sectionName and port",
+			spec: anv1alpha1.TargetGroupPolicySpec{
+				PortOverrides: []anv1alpha1.TargetGroupPortOverride{{
+					SectionName: ptr.To(gwv1alpha2.SectionName("admin")),
+					Port:        ptr.To(gwv1alpha2.PortNumber(9000)),
+				}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "port override with unsupported protocol",
+			spec: anv1alpha1.TargetGroupPolicySpec{
+				PortOverrides: []anv1alpha1.TargetGroupPortOverride{
+					{Port: ptr.To(gwv1alpha2.PortNumber(9000)), Protocol: aws.String("UDP")},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "port override with protocol version on tcp policy",
+			spec: anv1alpha1.TargetGroupPolicySpec{
+				Protocol: aws.String("TCP"),
+				PortOverrides: []anv1alpha1.TargetGroupPortOverride{
+					{Port: ptr.To(gwv1alpha2.PortNumber(9000)), ProtocolVersion: aws.String("HTTP2")},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "port override with invalid health check",
+			spec: anv1alpha1.TargetGroupPolicySpec{
+				PortOverrides: []anv1alpha1.TargetGroupPortOverride{{
+					Port:        ptr.To(gwv1alpha2.PortNumber(9000)),
+					HealthCheck: &anv1alpha1.HealthCheckConfig{Path: aws.String("health")},
+				}},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateTargetGroupPolicy(&anv1alpha1.TargetGroupPolicy{Spec: tt.spec})
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
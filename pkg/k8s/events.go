@@ -19,6 +19,8 @@ const (
 	RouteEventReasonFailedDeployModel  = "FailedDeployModel"
 	RouteEventReasonRetryReconcile     = "Retry-Reconcile"
 
+	RouteEventReasonLatticeServiceStatusConflict = "LatticeServiceStatusConflict"
+
 	// Service events
 	ServiceEventReasonFailedAddFinalizer = "FailedAddFinalizer"
 	ServiceEventReasonFailedBuildModel   = "FailedBuildModel"
@@ -26,6 +26,8 @@ func ObjToGroupKind(obj client.Object) GroupKind {
 		return GroupKind{gwv1alpha2.GroupName, "TCPRoute"}
 	case *anv1alpha1.ServiceExport:
 		return GroupKind{anv1alpha1.GroupName, "ServiceExport"}
+	case *anv1alpha1.ServiceImport:
+		return GroupKind{anv1alpha1.GroupName, "ServiceImport"}
 	case *corev1.Service:
 		return GroupKind{corev1.GroupName, "Service"}
 	default:
@@ -54,6 +56,8 @@ func GroupKindToObj(gk GroupKind) (client.Object, bool) {
 		return &corev1.Service{}, true
 	case GroupKind{anv1alpha1.GroupName, "ServiceExport"}:
 		return &anv1alpha1.ServiceExport{}, true
+	case GroupKind{anv1alpha1.GroupName, "ServiceImport"}:
+		return &anv1alpha1.ServiceImport{}, true
 	default:
 		return nil, false
 	}
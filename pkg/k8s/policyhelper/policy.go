@@ -20,6 +20,8 @@ import (
 	gwv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 
 	anv1alpha1 "github.com/aws/aws-application-networking-k8s/pkg/apis/applicationnetworking/v1alpha1"
+	"github.com/aws/aws-application-networking-k8s/pkg/aws/authpolicy"
+	"github.com/aws/aws-application-networking-k8s/pkg/k8s"
 	"github.com/aws/aws-application-networking-k8s/pkg/utils"
 	"github.com/aws/aws-application-networking-k8s/pkg/utils/gwlog"
 )
@@ -28,6 +30,7 @@ var (
 	ErrGroupKind         = errors.New("group/kind error")
 	ErrTargetRefNotFound = errors.New("targetRef not found")
 	ErrTargetRefConflict = errors.New("targetRef has conflict")
+	ErrInvalidSpec       = errors.New("invalid policy")
 )
 
 type (
@@ -58,6 +61,8 @@ type (
 	IAPL = anv1alpha1.IAMAuthPolicyList
 	VAP  = anv1alpha1.VpcAssociationPolicy
 	VAPL = anv1alpha1.VpcAssociationPolicyList
+	SIP  = anv1alpha1.ServiceImportPolicy
+	SIPL = anv1alpha1.ServiceImportPolicyList
 )
 
 func NewVpcAssociationPolicyHandler(log gwlog.Logger, c k8sclient.Client) *PolicyHandler[*VAP] {
@@ -84,7 +89,47 @@ func NewIAMAuthPolicyHandler(log gwlog.Logger, c k8sclient.Client) *PolicyHandle
 		Client:         c,
 		TargetRefKinds: NewGroupKindSet(&gwv1.Gateway{}, &gwv1.HTTPRoute{}, &gwv1.GRPCRoute{}),
 	}
-	return NewPolicyHandler[IAP, IAPL](phcfg)
+	ph := NewPolicyHandler[IAP, IAPL](phcfg)
+	ph.validateSpec = func(policy *IAP) error {
+		_, err := authpolicy.Parse(policy.Spec.Policy)
+		return err
+	}
+	return ph
+}
+
+func NewServiceImportPolicyHandler(log gwlog.Logger, c k8sclient.Client) *PolicyHandler[*SIP] {
+	phcfg := PolicyHandlerConfig{
+		Log:            log,
+		Client:         c,
+		TargetRefKinds: NewGroupKindSet(&anv1alpha1.ServiceImport{}),
+	}
+	ph := NewPolicyHandler[SIP, SIPL](phcfg)
+	ph.validateSpec = validateServiceImportPolicy
+	return ph
+}
+
+// validateServiceImportPolicy checks the clusters are listed once, and that some cluster gets traffic
+func validateServiceImportPolicy(policy *SIP) error {
+	var clusters []string
+	var totalWeight int64
+	for _, cluster := range policy.Spec.Clusters {
+		if slices.Contains(clusters, cluster.Name) {
+			return fmt.Errorf("cluster %s is listed more than once", cluster.Name)
+		}
+		clusters = append(clusters, cluster.Name)
+		if cluster.Weight == nil {
+			totalWeight++
+		} else {
+			totalWeight += *cluster.Weight
+		}
+	}
+	if len(clusters) == 0 {
+		return errors.New("at least one cluster is required")
+	}
+	if !policy.IsFailover() && totalWeight == 0 {
+		return errors.New("at least one cluster must have a weight above zero")
+	}
+	return nil
 }
 
 // Policy with PolicyTargetReference
@@ -114,6 +159,8 @@ type PolicyHandler[P Policy] struct {
 	log    gwlog.Logger
 	kinds  *GroupKindSet
 	client PolicyClient[P]
+	// optional validation of the policy content, independent of its targetRef
+	validateSpec func(P) error
 }
 
 type PolicyHandlerConfig struct {
@@ -287,7 +334,10 @@ func (h *PolicyHandler[P]) targetRefMatch(obj k8sclient.Object, tr *gwv1alpha2.N
 
 // Validate Policy and update Accepted status condition.
 func (h *PolicyHandler[P]) ValidateAndUpdateCondition(ctx context.Context, policy P) (ConditionReason, error) {
-	validationErr := h.ValidateTargetRef(ctx, policy)
+	validationErr := h.ValidateSpec(policy)
+	if validationErr == nil {
+		validationErr = h.ValidateTargetRef(ctx, policy)
+	}
 	reason := errToReason(validationErr)
 	msg := ""
 	if validationErr != nil {
@@ -304,10 +354,8 @@ func (h *PolicyHandler[P]) ValidateTargetRef(ctx context.Context, policy P) erro
 	tr := policy.GetTargetRef()
 
 	// invalid
-	trGk := TargetRefGroupKind(tr)
-	if !h.kinds.Contains(trGk) {
-		return fmt.Errorf("%w: not supported GroupKind=%s/%s",
-			ErrGroupKind, tr.Group, tr.Kind)
+	if err := h.ValidateTargetRefKind(policy); err != nil {
+		return err
 	}
 
 	// not found
@@ -337,11 +385,33 @@ func (h *PolicyHandler[P]) ValidateTargetRef(ctx context.Context, policy P) erro
 	return nil
 }
 
+// ValidateSpec checks the content of the policy, for the policy types that validate it
+func (h *PolicyHandler[P]) ValidateSpec(policy P) error {
+	if h.validateSpec == nil {
+		return nil
+	}
+	if err := h.validateSpec(policy); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidSpec, err)
+	}
+	return nil
+}
+
+// ValidateTargetRefKind only checks the GroupKind of the targetRef, which does not depend on other resources
+func (h *PolicyHandler[P]) ValidateTargetRefKind(policy P) error {
+	tr := policy.GetTargetRef()
+	trGk := TargetRefGroupKind(tr)
+	if !h.kinds.Contains(trGk) {
+		return fmt.Errorf("%w: not supported GroupKind=%s/%s",
+			ErrGroupKind, tr.Group, tr.Kind)
+	}
+	return nil
+}
+
 func errToReason(err error) ConditionReason {
 	switch {
 	case err == nil:
 		return ReasonAccepted
-	case errors.Is(err, ErrGroupKind):
+	case errors.Is(err, ErrGroupKind), errors.Is(err, ErrInvalidSpec):
 		return ReasonInvalid
 	case errors.Is(err, ErrTargetRefNotFound):
 		return ReasonTargetNotFound
@@ -388,3 +458,26 @@ func (h *PolicyHandler[P]) conflictResolutionSort(policies []P) {
 		}
 	})
 }
+
+// ValidateAccessLogPolicyTargetRef checks the targetRef of an AccessLogPolicy, which must be a Gateway API
+// Gateway, HTTPRoute or GRPCRoute in the namespace of the policy
+func ValidateAccessLogPolicyTargetRef(alp *anv1alpha1.AccessLogPolicy) error {
+	tr := alp.Spec.TargetRef
+	if tr.Group != gwv1.GroupName {
+		return fmt.Errorf("The targetRef's Group must be \"%s\" but was \"%s\"",
+			gwv1.GroupName, tr.Group)
+	}
+
+	validKinds := []string{"Gateway", "HTTPRoute", "GRPCRoute"}
+	if !slices.Contains(validKinds, string(tr.Kind)) {
+		return fmt.Errorf("The targetRef's Kind must be \"Gateway\", \"HTTPRoute\", or \"GRPCRoute\""+
+			" but was \"%s\"", tr.Kind)
+	}
+
+	targetRefNamespace := k8s.NamespaceOrDefault(tr.Namespace)
+	if targetRefNamespace != alp.Namespace {
+		return fmt.Errorf("The targetRef's namespace, \"%s\", does not match the Access Log Policy's"+
+			" namespace, \"%s\"", targetRefNamespace, alp.Namespace)
+	}
+	return nil
+}
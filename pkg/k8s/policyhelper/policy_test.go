@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	anv1alpha1 "github.com/aws/aws-application-networking-k8s/pkg/apis/applicationnetworking/v1alpha1"
+	"github.com/aws/aws-application-networking-k8s/pkg/utils/gwlog"
 	"github.com/stretchr/testify/assert"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	gwv1 "sigs.k8s.io/gateway-api/apis/v1"
@@ -35,3 +36,42 @@ func TestGroupKindSet(t *testing.T) {
 	assert.True(t, gks.Contains(GroupKind{gwv1.GroupName, "HTTPRoute"}))
 	assert.True(t, gks.Contains(GroupKind{gwv1.GroupName, "GRPCRoute"}))
 }
+
+func TestPolicyHandler_ValidateSpec(t *testing.T) {
+	ph := NewIAMAuthPolicyHandler(gwlog.FallbackLogger, nil)
+
+	policy := &anv1alpha1.IAMAuthPolicy{Spec: anv1alpha1.IAMAuthPolicySpec{
+		Policy: `{"Statement": [{"Effect": "Allow", "Principal": "*", "Action": "vpc-lattice-svcs:Invoke"}]}`,
+	}}
+	assert.NoError(t, ph.ValidateSpec(policy))
+
+	policy.Spec.Policy = `{"Statement": [{"Effect": "Allow", "Principal": "*", "Action": "vpc-lattice:Invoke"}]}`
+	err := ph.ValidateSpec(policy)
+	assert.ErrorIs(t, err, ErrInvalidSpec)
+	assert.ErrorContains(t, err, "line 1, column 64")
+	assert.Equal(t, ReasonInvalid, errToReason(err))
+
+	// policies without content validation
+	assert.NoError(t, NewVpcAssociationPolicyHandler(gwlog.FallbackLogger, nil).ValidateSpec(&anv1alpha1.VpcAssociationPolicy{}))
+}
+
+func TestServiceImportPolicyHandler_ValidateSpec(t *testing.T) {
+	ph := NewServiceImportPolicyHandler(gwlog.FallbackLogger, nil)
+	weight := func(w int64) *int64 { return &w }
+	failover := anv1alpha1.ServiceImportRoutingModeFailover
+
+	policy := &anv1alpha1.ServiceImportPolicy{Spec: anv1alpha1.ServiceImportPolicySpec{
+		Clusters: []anv1alpha1.ServiceImportClusterTarget{{Name: "cluster-a", Weight: weight(3)}, {Name: "cluster-b"}},
+	}}
+	assert.NoError(t, ph.ValidateSpec(policy))
+
+	policy.Spec.Clusters = []anv1alpha1.ServiceImportClusterTarget{{Name: "cluster-a"}, {Name: "cluster-a"}}
+	assert.ErrorIs(t, ph.ValidateSpec(policy), ErrInvalidSpec)
+
+	policy.Spec.Clusters = []anv1alpha1.ServiceImportClusterTarget{{Name: "cluster-a", Weight: weight(0)}, {Name: "cluster-b", Weight: weight(0)}}
+	assert.ErrorIs(t, ph.ValidateSpec(policy), ErrInvalidSpec)
+
+	// weights are ignored in the failover mode
+	policy.Spec.Mode = &failover
+	assert.NoError(t, ph.ValidateSpec(policy))
+}
@@ -41,6 +41,12 @@ type RuleTargetGroup struct {
 	SvcImportTG        *SvcImportTargetGroup `json:"svcimporttg"`
 	LatticeTgId        string                `json:"latticetgid"`
 	Weight             int64                 `json:"weight"`
+	// ClusterGroup groups the per cluster target groups of a ServiceImport backendRef with a ServiceImportPolicy.
+	// A cluster without target group is skipped, as long as another cluster of the group has one.
+	ClusterGroup string `json:"clustergroup,omitempty"`
+	// Failover gives the weight of the cluster group to its first target group with healthy targets, the
+	// other target groups of the group get no traffic
+	Failover bool `json:"failover,omitempty"`
 }
 
 type SvcImportTargetGroup struct {
@@ -48,6 +54,11 @@ type SvcImportTargetGroup struct {
 	K8SServiceName      string `json:"k8sservicename"`
 	K8SServiceNamespace string `json:"k8sservicenamespace"`
 	VpcId               string `json:"vpcid"`
+	// K8SServicePort is the port of the backendRef, selecting the target group of that exported port
+	K8SServicePort string `json:"k8sserviceport"`
+	// AccountId is the account exporting the target group when not the account of the controller, its
+	// target group must be shared with the account of the controller
+	AccountId string `json:"accountid,omitempty"`
 }
 
 type RuleStatus struct {
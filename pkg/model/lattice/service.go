@@ -16,8 +16,14 @@ type Service struct {
 type ServiceSpec struct {
 	ServiceTagFields
 	ServiceNetworkNames []string `json:"servicenetworkhnames"`
-	CustomerDomainName  string   `json:"customerdomainname"`
-	CustomerCertARN     string   `json:"customercertarn"`
+	// ServiceNetworkArns are the ARNs of the service networks already resolved by the gateway controller,
+	// keyed by service network name
+	ServiceNetworkArns map[string]string `json:"servicenetworkarns,omitempty"`
+	CustomerDomainName string            `json:"customerdomainname"`
+	CustomerCertARN    string            `json:"customercertarn"`
+	// HasHeadlessServices is set on the service of a route with a Headless ServiceImport backend. The Lattice
+	// service keeps a tag of it, so the headless services are looked for only on routes that had some.
+	HasHeadlessServices bool `json:"hasheadlessservices,omitempty"`
 }
 
 type ServiceStatus struct {
@@ -30,23 +36,36 @@ type ServiceTagFields struct {
 	RouteName      string
 	RouteNamespace string
 	RouteType      core.RouteType
+	// HeadlessCluster is the exporting cluster of a Headless ServiceImport backend the service is dedicated to,
+	// empty for the service of the route
+	HeadlessCluster string
 }
 
 func ServiceTagFieldsFromTags(tags map[string]*string) ServiceTagFields {
 	return ServiceTagFields{
-		RouteName:      getMapValue(tags, K8SRouteNameKey),
-		RouteNamespace: getMapValue(tags, K8SRouteNamespaceKey),
-		RouteType:      core.RouteType(getMapValue(tags, K8SRouteTypeKey)),
+		RouteName:       getMapValue(tags, K8SRouteNameKey),
+		RouteNamespace:  getMapValue(tags, K8SRouteNamespaceKey),
+		RouteType:       core.RouteType(getMapValue(tags, K8SRouteTypeKey)),
+		HeadlessCluster: getMapValue(tags, K8SHeadlessClusterKey),
 	}
 }
 
 func (t *ServiceTagFields) ToTags() services.Tags {
 	rt := string(t.RouteType)
-	return services.Tags{
+	tags := services.Tags{
 		K8SRouteNameKey:      &t.RouteName,
 		K8SRouteNamespaceKey: &t.RouteNamespace,
 		K8SRouteTypeKey:      &rt,
 	}
+	if t.HeadlessCluster != "" {
+		tags[K8SHeadlessClusterKey] = &t.HeadlessCluster
+	}
+	return tags
+}
+
+// IsHeadless returns whether the service exposes a single cluster of a Headless ServiceImport
+func (t *ServiceTagFields) IsHeadless() bool {
+	return t.HeadlessCluster != ""
 }
 
 func NewLatticeService(stack core.Stack, spec ServiceSpec) (*Service, error) {
@@ -71,5 +90,8 @@ func (s *Service) LatticeServiceName() string {
 }
 
 func (s *ServiceSpec) LatticeServiceName() string {
+	if s.IsHeadless() {
+		return utils.LatticeHeadlessServiceName(s.RouteName, s.RouteNamespace, s.HeadlessCluster)
+	}
 	return utils.LatticeServiceName(s.RouteName, s.RouteNamespace)
 }
@@ -7,6 +7,9 @@ import (
 const (
 	K8SServiceNetworkOwnedByVPC = "K8SServiceNetworkOwnedByVPC"
 	K8SServiceOwnedByVPC        = "K8SServiceOwnedByVPC"
+
+	// ServiceNetworkArnMessagePrefix prefixes the service network ARN in the Programmed condition of a Gateway
+	ServiceNetworkArnMessagePrefix = "aws-service-network-arn: "
 )
 
 type ServiceNetwork struct {
@@ -19,9 +19,13 @@ const (
 	K8SRouteNamespaceKey   = aws.TagBase + "RouteNamespace"
 	K8SSourceTypeKey       = aws.TagBase + "SourceTypeKey"
 	K8SProtocolVersionKey  = aws.TagBase + "ProtocolVersion"
+	K8SServicePortKey      = aws.TagBase + "ServicePort"
 
 	// Service specific tags
-	K8SRouteTypeKey = aws.TagBase + "RouteType"
+	K8SRouteTypeKey       = aws.TagBase + "RouteType"
+	K8SHeadlessClusterKey = aws.TagBase + "HeadlessCluster"
+	// K8SHeadlessServicesKey marks the service of a route that has or had headless services
+	K8SHeadlessServicesKey = aws.TagBase + "HeadlessServices"
 
 	MaxNamespaceLength = 55
 	MaxNameLength      = 55
@@ -53,12 +57,21 @@ type TargetGroupTagFields struct {
 	K8SRouteName        string        `json:"k8sroutename"`
 	K8SRouteNamespace   string        `json:"k8sroutenamespace"`
 	K8SProtocolVersion  string        `json:"k8sprotocolversion"`
+	// K8SServicePort is the exported Service port of a ServiceExport target group, empty for the target
+	// groups of route backendRefs and for the single target group of exports of earlier releases
+	K8SServicePort string `json:"k8sserviceport"`
 }
 
 type TargetGroupStatus struct {
 	Name string `json:"name"`
 	Arn  string `json:"arn"`
 	Id   string `json:"id"`
+	// ReplacedId is the target group of previous immutable fields the service still forwards to, which this
+	// target group replaces
+	ReplacedId string `json:"replacedid,omitempty"`
+	// ReplacementReady is true once the targets of this target group are healthy, so that the traffic of the
+	// replaced target group shifts to it
+	ReplacementReady bool `json:"replacementready,omitempty"`
 }
 
 type TargetGroupType string
@@ -84,6 +97,7 @@ func TGTagFieldsFromTags(tags map[string]*string) TargetGroupTagFields {
 		K8SRouteName:        getMapValue(tags, K8SRouteNameKey),
 		K8SRouteNamespace:   getMapValue(tags, K8SRouteNamespaceKey),
 		K8SProtocolVersion:  getMapValue(tags, K8SProtocolVersionKey),
+		K8SServicePort:      getMapValue(tags, K8SServicePortKey),
 	}
 }
 
@@ -101,6 +115,9 @@ func TagsFromTGTagFields(tagFields TargetGroupTagFields) map[string]*string {
 		tags[K8SRouteNameKey] = &tagFields.K8SRouteName
 		tags[K8SRouteNamespaceKey] = &tagFields.K8SRouteNamespace
 	}
+	if tagFields.K8SServicePort != "" {
+		tags[K8SServicePortKey] = &tagFields.K8SServicePort
+	}
 	return tags
 }
 
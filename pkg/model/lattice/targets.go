@@ -1,13 +1,17 @@
 package lattice
 
 import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/vpclattice"
+
 	"github.com/aws/aws-application-networking-k8s/pkg/model/core"
 	"k8s.io/apimachinery/pkg/types"
 )
 
 type Targets struct {
 	core.ResourceMeta `json:"-"`
-	Spec              TargetsSpec `json:"spec"`
+	Spec              TargetsSpec    `json:"spec"`
+	Status            *TargetsStatus `json:"status,omitempty"`
 }
 
 // unlike target groups, which can reference a service export, targets
@@ -25,6 +29,45 @@ type Target struct {
 	TargetRef types.NamespacedName
 }
 
+// number of registered targets per Lattice target status, as observed at post synthesis
+type TargetsStatus struct {
+	Healthy     int32 `json:"healthy"`
+	Unhealthy   int32 `json:"unhealthy"`
+	Initial     int32 `json:"initial"`
+	Unavailable int32 `json:"unavailable"`
+	Unused      int32 `json:"unused"`
+	Draining    int32 `json:"draining"`
+	// number of targets per health check reason code, for targets which are not healthy
+	ReasonCodes map[string]int32 `json:"reasoncodes,omitempty"`
+}
+
+func NewTargetsStatus(latticeTargets []*vpclattice.TargetSummary) *TargetsStatus {
+	status := &TargetsStatus{}
+	for _, target := range latticeTargets {
+		switch aws.StringValue(target.Status) {
+		case vpclattice.TargetStatusHealthy:
+			status.Healthy++
+		case vpclattice.TargetStatusUnhealthy:
+			status.Unhealthy++
+		case vpclattice.TargetStatusInitial:
+			status.Initial++
+		case vpclattice.TargetStatusUnavailable:
+			status.Unavailable++
+		case vpclattice.TargetStatusUnused:
+			status.Unused++
+		case vpclattice.TargetStatusDraining:
+			status.Draining++
+		}
+		if reasonCode := aws.StringValue(target.ReasonCode); reasonCode != "" {
+			if status.ReasonCodes == nil {
+				status.ReasonCodes = make(map[string]int32)
+			}
+			status.ReasonCodes[reasonCode]++
+		}
+	}
+	return status
+}
+
 func NewTargets(stack core.Stack, spec TargetsSpec) (*Targets, error) {
 	id, err := core.IdFromHash(spec)
 	if err != nil {
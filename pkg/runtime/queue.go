@@ -0,0 +1,244 @@
+package runtime
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+type Queue = workqueue.TypedRateLimitingInterface[reconcile.Request]
+
+// PriorityQueue is a rate limited workqueue handing out the requests added with priority
+// before the others, so that deletes are not stuck behind bulk updates and resyncs.
+// It keeps the semantics of the default controller queue: a request is queued at most once,
+// and is not reconciled concurrently.
+type PriorityQueue struct {
+	Queue
+	order *priorityOrder
+	limit *ConcurrencyLimit
+}
+
+// NewPriorityQueue can be used as the NewQueue option of controllers
+func NewPriorityQueue(name string, rateLimiter workqueue.TypedRateLimiter[reconcile.Request]) Queue {
+	return newPriorityQueue(name, rateLimiter, nil)
+}
+
+// NewPriorityQueueWithLimit returns a NewQueue option for controllers whose concurrency can change
+// while they run. The controller must run as many workers as the highest limit it should support,
+// the workers above the limit wait for a slot before taking requests from the queue.
+func NewPriorityQueueWithLimit(limit *ConcurrencyLimit) func(string, workqueue.TypedRateLimiter[reconcile.Request]) Queue {
+	return func(name string, rateLimiter workqueue.TypedRateLimiter[reconcile.Request]) Queue {
+		return newPriorityQueue(name, rateLimiter, limit)
+	}
+}
+
+func newPriorityQueue(name string, rateLimiter workqueue.TypedRateLimiter[reconcile.Request], limit *ConcurrencyLimit) Queue {
+	order := &priorityOrder{marked: map[reconcile.Request]bool{}}
+	queue := workqueue.NewTypedWithConfig(workqueue.TypedQueueConfig[reconcile.Request]{
+		Name:  name,
+		Queue: order,
+	})
+	delayingQueue := workqueue.NewTypedDelayingQueueWithConfig(workqueue.TypedDelayingQueueConfig[reconcile.Request]{
+		Name:  name,
+		Queue: queue,
+	})
+	return &PriorityQueue{
+		Queue: workqueue.NewTypedRateLimitingQueueWithConfig(rateLimiter, workqueue.TypedRateLimitingQueueConfig[reconcile.Request]{
+			Name:          name,
+			DelayingQueue: delayingQueue,
+		}),
+		order: order,
+		limit: limit,
+	}
+}
+
+func (q *PriorityQueue) Get() (reconcile.Request, bool) {
+	if q.limit != nil && !q.limit.acquire(q.ShuttingDown) {
+		return reconcile.Request{}, true
+	}
+	item, shutdown := q.Queue.Get()
+	if shutdown && q.limit != nil {
+		q.limit.release()
+	}
+	return item, shutdown
+}
+
+func (q *PriorityQueue) Done(item reconcile.Request) {
+	q.Queue.Done(item)
+	if q.limit != nil {
+		q.limit.release()
+	}
+}
+
+func (q *PriorityQueue) ShutDown() {
+	q.Queue.ShutDown()
+	if q.limit != nil {
+		q.limit.wakeUp()
+	}
+}
+
+func (q *PriorityQueue) ShutDownWithDrain() {
+	if q.limit != nil {
+		defer q.limit.wakeUp()
+	}
+	q.Queue.ShutDownWithDrain()
+}
+
+// AddWithPriority queues a request ahead of the requests added without priority. A request
+// already queued is moved ahead, a request being reconciled is queued ahead once done.
+func (q *PriorityQueue) AddWithPriority(item reconcile.Request) {
+	q.order.mark(item)
+	q.Add(item)
+}
+
+// priorityOrder is the storage of the queue, a FIFO of priority requests served before
+// a FIFO of the other requests. The workqueue calls Touch, Push, Len and Pop under its lock,
+// marks are added concurrently by event handlers.
+type priorityOrder struct {
+	lock   sync.Mutex
+	marked map[reconcile.Request]bool
+	high   []reconcile.Request
+	normal []reconcile.Request
+}
+
+func (o *priorityOrder) mark(item reconcile.Request) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	o.marked[item] = true
+}
+
+func (o *priorityOrder) takeMark(item reconcile.Request) bool {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	marked := o.marked[item]
+	delete(o.marked, item)
+	return marked
+}
+
+func (o *priorityOrder) Touch(item reconcile.Request) {
+	if !o.takeMark(item) {
+		return
+	}
+	if i := slices.Index(o.normal, item); i >= 0 {
+		o.normal = slices.Delete(o.normal, i, i+1)
+		o.high = append(o.high, item)
+	}
+}
+
+func (o *priorityOrder) Push(item reconcile.Request) {
+	if o.takeMark(item) {
+		o.high = append(o.high, item)
+	} else {
+		o.normal = append(o.normal, item)
+	}
+}
+
+func (o *priorityOrder) Len() int {
+	return len(o.high) + len(o.normal)
+}
+
+func (o *priorityOrder) Pop() reconcile.Request {
+	var item reconcile.Request
+	if len(o.high) > 0 {
+		item, o.high = o.high[0], o.high[1:]
+	} else {
+		item, o.normal = o.normal[0], o.normal[1:]
+	}
+	return item
+}
+
+// ConcurrencyLimit is the number of requests of a queue reconciled at the same time
+type ConcurrencyLimit struct {
+	lock  sync.Mutex
+	cond  *sync.Cond
+	limit int
+	inUse int
+}
+
+func NewConcurrencyLimit(limit int) *ConcurrencyLimit {
+	l := &ConcurrencyLimit{limit: limit}
+	l.cond = sync.NewCond(&l.lock)
+	return l
+}
+
+// Set changes the limit, requests being reconciled above a lower limit complete normally
+func (l *ConcurrencyLimit) Set(limit int) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.limit = limit
+	l.cond.Broadcast()
+}
+
+func (l *ConcurrencyLimit) Get() int {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return l.limit
+}
+
+// acquire waits for a free slot, it returns false when the queue shuts down first
+func (l *ConcurrencyLimit) acquire(shuttingDown func() bool) bool {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	for l.inUse >= l.limit && !shuttingDown() {
+		l.cond.Wait()
+	}
+	if shuttingDown() {
+		return false
+	}
+	l.inUse++
+	return true
+}
+
+func (l *ConcurrencyLimit) release() {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.inUse--
+	l.cond.Signal()
+}
+
+func (l *ConcurrencyLimit) wakeUp() {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.cond.Broadcast()
+}
+
+// IsBeingDeleted returns true for objects with a deletion timestamp, waiting on finalizers
+func IsBeingDeleted(obj client.Object) bool {
+	return !obj.GetDeletionTimestamp().IsZero()
+}
+
+// EnqueuePriorityRequests is an event handler queuing objects with priority, on deletes and when
+// isPriority is true for them. It is meant to be added as an extra watch of the reconciled type,
+// next to the handler of For(): the request is then moved ahead when it is already queued.
+func EnqueuePriorityRequests(isPriority func(obj client.Object) bool) handler.EventHandler {
+	return handler.Funcs{
+		CreateFunc: func(_ context.Context, e event.CreateEvent, q Queue) {
+			if isPriority(e.Object) {
+				addWithPriority(q, e.Object)
+			}
+		},
+		UpdateFunc: func(_ context.Context, e event.UpdateEvent, q Queue) {
+			if isPriority(e.ObjectNew) {
+				addWithPriority(q, e.ObjectNew)
+			}
+		},
+		DeleteFunc: func(_ context.Context, e event.DeleteEvent, q Queue) {
+			addWithPriority(q, e.Object)
+		},
+	}
+}
+
+func addWithPriority(q Queue, obj client.Object) {
+	item := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(obj)}
+	if pq, ok := q.(*PriorityQueue); ok {
+		pq.AddWithPriority(item)
+	} else {
+		q.Add(item)
+	}
+}
@@ -0,0 +1,150 @@
+package runtime
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func req(name string) reconcile.Request {
+	return reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: name}}
+}
+
+func newTestQueue() *PriorityQueue {
+	return NewPriorityQueue("", workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]()).(*PriorityQueue)
+}
+
+func getAll(t *testing.T, q Queue) []string {
+	var names []string
+	for q.Len() > 0 {
+		item, shutdown := q.Get()
+		assert.False(t, shutdown)
+		names = append(names, item.Name)
+		q.Done(item)
+	}
+	return names
+}
+
+func TestPriorityQueue(t *testing.T) {
+	t.Run("priority requests first, in order", func(t *testing.T) {
+		q := newTestQueue()
+		defer q.ShutDown()
+		q.Add(req("a"))
+		q.AddWithPriority(req("b"))
+		q.Add(req("c"))
+		q.AddWithPriority(req("d"))
+		assert.Equal(t, []string{"b", "d", "a", "c"}, getAll(t, q))
+	})
+
+	t.Run("queued request moved ahead", func(t *testing.T) {
+		q := newTestQueue()
+		defer q.ShutDown()
+		q.Add(req("a"))
+		q.Add(req("b"))
+		q.AddWithPriority(req("b"))
+		q.Add(req("a"))
+		assert.Equal(t, 2, q.Len())
+		assert.Equal(t, []string{"b", "a"}, getAll(t, q))
+	})
+
+	t.Run("request being reconciled queued ahead once done", func(t *testing.T) {
+		q := newTestQueue()
+		defer q.ShutDown()
+		q.Add(req("a"))
+		item, _ := q.Get()
+		q.Add(req("b"))
+		q.AddWithPriority(req("a"))
+		assert.Equal(t, 1, q.Len())
+		q.Done(item)
+		assert.Equal(t, []string{"a", "b"}, getAll(t, q))
+	})
+
+	t.Run("delayed requests", func(t *testing.T) {
+		q := newTestQueue()
+		defer q.ShutDown()
+		q.AddAfter(req("a"), 10*time.Millisecond)
+		q.AddRateLimited(req("b"))
+		assert.Eventually(t, func() bool { return q.Len() == 2 }, time.Second, time.Millisecond)
+		assert.Equal(t, 1, q.NumRequeues(req("b")))
+	})
+}
+
+func TestEnqueuePriorityRequests(t *testing.T) {
+	ctx := context.Background()
+	q := newTestQueue()
+	defer q.ShutDown()
+	h := EnqueuePriorityRequests(IsBeingDeleted)
+
+	pod := func(name string, deleted bool) client.Object {
+		p := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name}}
+		if deleted {
+			p.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+		}
+		return p
+	}
+
+	q.Add(req("a"))
+	h.Create(ctx, event.CreateEvent{Object: pod("b", false)}, q)
+	h.Update(ctx, event.UpdateEvent{ObjectOld: pod("c", false), ObjectNew: pod("c", true)}, q)
+	q.Add(req("d"))
+	h.Delete(ctx, event.DeleteEvent{Object: pod("e", false)}, q)
+	assert.Equal(t, []string{"c", "e", "a", "d"}, getAll(t, q))
+}
+
+func TestConcurrencyLimit(t *testing.T) {
+	limit := NewConcurrencyLimit(1)
+	q := NewPriorityQueueWithLimit(limit)("", workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+	q.Add(req("a"))
+	q.Add(req("b"))
+
+	first, _ := q.Get()
+	got := make(chan string)
+	go func() {
+		item, shutdown := q.Get()
+		if !shutdown {
+			got <- item.Name
+		}
+	}()
+
+	select {
+	case <-got:
+		t.Fatal("second request taken above the limit")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	limit.Set(2)
+	select {
+	case name := <-got:
+		assert.Equal(t, "b", name)
+	case <-time.After(time.Second):
+		t.Fatal("second request not taken after the limit was raised")
+	}
+	q.Done(first)
+
+	t.Run("waiting workers stop on shutdown", func(t *testing.T) {
+		limit := NewConcurrencyLimit(0)
+		q := NewPriorityQueueWithLimit(limit)("", workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+		q.Add(req("a"))
+		done := make(chan bool)
+		go func() {
+			_, shutdown := q.Get()
+			done <- shutdown
+		}()
+		q.ShutDown()
+		select {
+		case shutdown := <-done:
+			assert.True(t, shutdown)
+		case <-time.After(time.Second):
+			t.Fatal("worker still waiting after shutdown")
+		}
+	})
+}
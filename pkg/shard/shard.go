@@ -0,0 +1,393 @@
+package shard
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/aws/aws-application-networking-k8s/pkg/utils/gwlog"
+)
+
+const (
+	// GroupLabel is set on all the leases of a group of replicas sharing the shards
+	GroupLabel = "application-networking.k8s.aws/shard-group"
+	// ShardLabel is set on shard leases, to the index of the shard
+	ShardLabel = "application-networking.k8s.aws/shard"
+	// MemberLabel is set on the leases replicas renew to announce themselves
+	MemberLabel = "application-networking.k8s.aws/shard-member"
+
+	DefaultGroup         = "amazon-vpc-lattice"
+	DefaultLeaseDuration = 30 * time.Second
+	DefaultRenewInterval = 10 * time.Second
+
+	// member leases of replicas that did not stop cleanly are deleted after this many lease durations
+	memberGcLeaseDurations = 10
+)
+
+type Config struct {
+	// Shards is the number of shards keys are split into
+	Shards int
+	// Namespace holds the leases, usually the namespace of the controller
+	Namespace string
+	// Identity of this replica, unique across the replicas and a valid DNS label
+	Identity string
+	// Group prefixes the lease names, replicas of the same group share the shards
+	Group string
+	// LeaseDuration is how long a shard stays owned without renewal
+	LeaseDuration time.Duration
+	// RenewInterval is the period of lease renewals and shard rebalancing
+	RenewInterval time.Duration
+}
+
+// Manager assigns shards to controller replicas using one Lease per shard.
+// Every replica also renews a member lease, so that the shards are spread evenly:
+// a replica holds at most ceil(shards / replicas) shards, releasing the extra ones when
+// new replicas join and acquiring the ones of replicas that stop renewing their leases.
+type Manager struct {
+	log    gwlog.Logger
+	client client.Client
+	cfg    Config
+	now    func() time.Time
+
+	lock sync.RWMutex
+	// owned shards, and until when this replica may assume it still holds them
+	owned map[int]time.Time
+	hooks []func(ctx context.Context, shard int)
+}
+
+func NewManager(log gwlog.Logger, client client.Client, cfg Config) *Manager {
+	if cfg.Group == "" {
+		cfg.Group = DefaultGroup
+	}
+	if cfg.LeaseDuration == 0 {
+		cfg.LeaseDuration = DefaultLeaseDuration
+	}
+	if cfg.RenewInterval == 0 {
+		cfg.RenewInterval = DefaultRenewInterval
+	}
+	return &Manager{
+		log:    log,
+		client: client,
+		cfg:    cfg,
+		now:    time.Now,
+		owned:  map[int]time.Time{},
+	}
+}
+
+// Of returns the shard of a namespaced key, the same on every replica
+func Of(namespace, name string, shards int) int {
+	h := fnv.New32a()
+	h.Write([]byte(namespace + "/" + name))
+	return int(h.Sum32() % uint32(shards))
+}
+
+// ShardOf returns the shard of a namespaced key
+func (m *Manager) ShardOf(namespace, name string) int {
+	return Of(namespace, name, m.cfg.Shards)
+}
+
+// Owns returns true when this replica holds the shard of a namespaced key
+func (m *Manager) Owns(namespace, name string) bool {
+	shard := m.ShardOf(namespace, name)
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	until, ok := m.owned[shard]
+	return ok && m.now().Before(until)
+}
+
+// OwnedShards returns the shards this replica holds, in order
+func (m *Manager) OwnedShards() []int {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	var shards []int
+	now := m.now()
+	for shard, until := range m.owned {
+		if now.Before(until) {
+			shards = append(shards, shard)
+		}
+	}
+	slices.Sort(shards)
+	return shards
+}
+
+// OnAcquire registers a function called when this replica acquires a shard, typically
+// to requeue the keys of the shard. Hooks must be registered before the manager starts.
+func (m *Manager) OnAcquire(hook func(ctx context.Context, shard int)) {
+	m.hooks = append(m.hooks, hook)
+}
+
+// Start renews the leases until the context is done, then releases them so that other
+// replicas take the shards over without waiting for the leases to expire
+func (m *Manager) Start(ctx context.Context) error {
+	m.log.Infof(ctx, "starting shard manager, identity %s, %d shards", m.cfg.Identity, m.cfg.Shards)
+	ticker := time.NewTicker(m.cfg.RenewInterval)
+	defer ticker.Stop()
+	for {
+		if err := m.sync(ctx); err != nil {
+			m.log.Warnf(ctx, "shard lease sync failed: %s", err)
+		}
+		select {
+		case <-ctx.Done():
+			m.releaseAll(context.Background())
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection is false, every replica takes part in sharding
+func (m *Manager) NeedLeaderElection() bool {
+	return false
+}
+
+func (m *Manager) sync(ctx context.Context) error {
+	now := m.now()
+	if err := m.renewMember(ctx, now); err != nil {
+		return fmt.Errorf("renewing member lease: %w", err)
+	}
+
+	leases := &coordinationv1.LeaseList{}
+	if err := m.client.List(ctx, leases, client.InNamespace(m.cfg.Namespace),
+		client.MatchingLabels{GroupLabel: m.cfg.Group}); err != nil {
+		return fmt.Errorf("listing leases: %w", err)
+	}
+	members := 0
+	shardLeases := map[int]*coordinationv1.Lease{}
+	for i := range leases.Items {
+		lease := &leases.Items[i]
+		if _, ok := lease.Labels[MemberLabel]; ok {
+			if isHeld(lease, now) {
+				members++
+			} else if expiredFor(lease, now) > memberGcLeaseDurations*m.cfg.LeaseDuration {
+				m.deleteLease(ctx, lease)
+			}
+			continue
+		}
+		shard, err := strconv.Atoi(lease.Labels[ShardLabel])
+		if err == nil && shard >= 0 && shard < m.cfg.Shards {
+			shardLeases[shard] = lease
+		}
+	}
+	target := (m.cfg.Shards + max(members, 1) - 1) / max(members, 1)
+
+	// shards whose lease was taken over by another replica are lost
+	var owned []int
+	for shard := 0; shard < m.cfg.Shards; shard++ {
+		if lease := shardLeases[shard]; lease != nil && holder(lease) == m.cfg.Identity {
+			owned = append(owned, shard)
+		} else {
+			m.drop(ctx, shard)
+		}
+	}
+
+	// give up the shards above the target, for new replicas to pick them up
+	for len(owned) > target {
+		shard := owned[len(owned)-1]
+		owned = owned[:len(owned)-1]
+		m.release(ctx, shardLeases[shard])
+	}
+	for i := 0; i < len(owned); {
+		if err := m.renew(ctx, owned[i], shardLeases[owned[i]], now); err != nil {
+			m.log.Infof(ctx, "lost shard %d, renewal failed: %s", owned[i], err)
+			m.drop(ctx, owned[i])
+			owned = slices.Delete(owned, i, i+1)
+			continue
+		}
+		i++
+	}
+
+	// acquire free shards, starting at a shard depending on the identity so that
+	// replicas do not all compete for the same leases
+	var acquired []int
+	start := Of(m.cfg.Namespace, m.cfg.Identity, m.cfg.Shards)
+	for i := 0; i < m.cfg.Shards && len(owned) < target; i++ {
+		shard := (start + i) % m.cfg.Shards
+		lease := shardLeases[shard]
+		if slices.Contains(owned, shard) || (lease != nil && isHeld(lease, now)) {
+			continue
+		}
+		if err := m.acquire(ctx, shard, lease, now); err != nil {
+			// most likely another replica acquired it first
+			m.log.Debugf(ctx, "could not acquire shard %d: %s", shard, err)
+			continue
+		}
+		owned = append(owned, shard)
+		acquired = append(acquired, shard)
+	}
+
+	for _, shard := range acquired {
+		m.log.Infof(ctx, "acquired shard %d", shard)
+		for _, hook := range m.hooks {
+			hook(ctx, shard)
+		}
+	}
+	return nil
+}
+
+func (m *Manager) renewMember(ctx context.Context, now time.Time) error {
+	lease := &coordinationv1.Lease{}
+	key := types.NamespacedName{Namespace: m.cfg.Namespace, Name: m.memberLeaseName()}
+	err := m.client.Get(ctx, key, lease)
+	if apierrors.IsNotFound(err) {
+		lease = m.newLease(key.Name, now)
+		lease.Labels[MemberLabel] = m.cfg.Identity
+		return m.client.Create(ctx, lease)
+	}
+	if err != nil {
+		return err
+	}
+	lease.Spec.RenewTime = &metav1.MicroTime{Time: now}
+	return m.client.Update(ctx, lease)
+}
+
+func (m *Manager) acquire(ctx context.Context, shard int, lease *coordinationv1.Lease, now time.Time) error {
+	if lease == nil {
+		lease = m.newLease(m.shardLeaseName(shard), now)
+		lease.Labels[ShardLabel] = strconv.Itoa(shard)
+		if err := m.client.Create(ctx, lease); err != nil {
+			return err
+		}
+	} else {
+		lease = lease.DeepCopy()
+		lease.Spec.HolderIdentity = &m.cfg.Identity
+		lease.Spec.LeaseDurationSeconds = m.leaseDurationSeconds()
+		lease.Spec.AcquireTime = &metav1.MicroTime{Time: now}
+		lease.Spec.RenewTime = &metav1.MicroTime{Time: now}
+		transitions := int32(1)
+		if lease.Spec.LeaseTransitions != nil {
+			transitions += *lease.Spec.LeaseTransitions
+		}
+		lease.Spec.LeaseTransitions = &transitions
+		// the update fails on conflicts, when another replica updated the lease since it was listed
+		if err := m.client.Update(ctx, lease); err != nil {
+			return err
+		}
+	}
+	m.own(shard, now)
+	return nil
+}
+
+func (m *Manager) renew(ctx context.Context, shard int, lease *coordinationv1.Lease, now time.Time) error {
+	lease = lease.DeepCopy()
+	lease.Spec.RenewTime = &metav1.MicroTime{Time: now}
+	if err := m.client.Update(ctx, lease); err != nil {
+		return err
+	}
+	m.own(shard, now)
+	return nil
+}
+
+// release stops reconciling the shard before giving its lease up
+func (m *Manager) release(ctx context.Context, lease *coordinationv1.Lease) {
+	shard, _ := strconv.Atoi(lease.Labels[ShardLabel])
+	m.drop(ctx, shard)
+	lease = lease.DeepCopy()
+	lease.Spec.HolderIdentity = nil
+	lease.Spec.RenewTime = nil
+	if err := m.client.Update(ctx, lease); err != nil {
+		m.log.Infof(ctx, "failed to release shard %d, it will be available when its lease expires: %s", shard, err)
+		return
+	}
+	m.log.Infof(ctx, "released shard %d", shard)
+}
+
+func (m *Manager) releaseAll(ctx context.Context) {
+	leases := &coordinationv1.LeaseList{}
+	if err := m.client.List(ctx, leases, client.InNamespace(m.cfg.Namespace),
+		client.MatchingLabels{GroupLabel: m.cfg.Group}); err != nil {
+		m.log.Infof(ctx, "failed to release shards: %s", err)
+		return
+	}
+	for i := range leases.Items {
+		lease := &leases.Items[i]
+		switch {
+		case lease.Labels[MemberLabel] == m.cfg.Identity:
+			m.deleteLease(ctx, lease)
+		case holder(lease) == m.cfg.Identity:
+			m.release(ctx, lease)
+		}
+	}
+}
+
+func (m *Manager) deleteLease(ctx context.Context, lease *coordinationv1.Lease) {
+	if err := m.client.Delete(ctx, lease); client.IgnoreNotFound(err) != nil {
+		m.log.Debugf(ctx, "failed to delete lease %s: %s", lease.Name, err)
+	}
+}
+
+// own records the shard as owned until its lease could expire for the other replicas,
+// minus a renewal interval to absorb delays and clock skews
+func (m *Manager) own(shard int, renewTime time.Time) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.owned[shard] = renewTime.Add(m.cfg.LeaseDuration - m.cfg.RenewInterval)
+}
+
+func (m *Manager) drop(ctx context.Context, shard int) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if _, ok := m.owned[shard]; ok {
+		m.log.Infof(ctx, "no longer owns shard %d", shard)
+		delete(m.owned, shard)
+	}
+}
+
+func (m *Manager) newLease(name string, now time.Time) *coordinationv1.Lease {
+	return &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: m.cfg.Namespace,
+			Labels:    map[string]string{GroupLabel: m.cfg.Group},
+		},
+		Spec: coordinationv1.LeaseSpec{
+			HolderIdentity:       &m.cfg.Identity,
+			LeaseDurationSeconds: m.leaseDurationSeconds(),
+			AcquireTime:          &metav1.MicroTime{Time: now},
+			RenewTime:            &metav1.MicroTime{Time: now},
+		},
+	}
+}
+
+func (m *Manager) leaseDurationSeconds() *int32 {
+	seconds := int32(m.cfg.LeaseDuration.Seconds())
+	return &seconds
+}
+
+func (m *Manager) shardLeaseName(shard int) string {
+	return fmt.Sprintf("%s-shard-%d", m.cfg.Group, shard)
+}
+
+func (m *Manager) memberLeaseName() string {
+	return fmt.Sprintf("%s-member-%s", m.cfg.Group, m.cfg.Identity)
+}
+
+func holder(lease *coordinationv1.Lease) string {
+	if lease.Spec.HolderIdentity == nil {
+		return ""
+	}
+	return *lease.Spec.HolderIdentity
+}
+
+// isHeld returns true when the lease has a holder which renewed it within its duration
+func isHeld(lease *coordinationv1.Lease, now time.Time) bool {
+	return holder(lease) != "" && expiredFor(lease, now) <= 0
+}
+
+// expiredFor returns for how long the lease has been expired, negative when it is not
+func expiredFor(lease *coordinationv1.Lease, now time.Time) time.Duration {
+	if lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+		return time.Duration(1<<63 - 1)
+	}
+	expiry := lease.Spec.RenewTime.Add(time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second)
+	return now.Sub(expiry)
+}
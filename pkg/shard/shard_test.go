@@ -0,0 +1,143 @@
+package shard
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/aws/aws-application-networking-k8s/pkg/utils/gwlog"
+)
+
+func TestOf(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		name := fmt.Sprintf("route-%d", i)
+		shard := Of("ns", name, 7)
+		assert.True(t, shard >= 0 && shard < 7)
+		assert.Equal(t, shard, Of("ns", name, 7))
+	}
+	assert.Equal(t, 0, Of("ns", "route", 1))
+}
+
+type testReplica struct {
+	*Manager
+	acquired []int
+}
+
+func newTestReplicas(t *testing.T, shards int, identities ...string) (client.Client, *time.Time, []*testReplica) {
+	scheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(scheme)
+	k8sClient := testclient.NewClientBuilder().WithScheme(scheme).Build()
+
+	now := time.Unix(1700000000, 0)
+	var replicas []*testReplica
+	for _, identity := range identities {
+		r := &testReplica{Manager: NewManager(gwlog.FallbackLogger, k8sClient, Config{
+			Shards:    shards,
+			Namespace: "system",
+			Identity:  identity,
+		})}
+		r.now = func() time.Time { return now }
+		r.OnAcquire(func(ctx context.Context, shard int) {
+			r.acquired = append(r.acquired, shard)
+		})
+		replicas = append(replicas, r)
+	}
+	return k8sClient, &now, replicas
+}
+
+func assertPartition(t *testing.T, shards int, replicas ...*testReplica) {
+	owners := map[int]int{}
+	for _, r := range replicas {
+		for _, shard := range r.OwnedShards() {
+			owners[shard]++
+		}
+	}
+	for shard := 0; shard < shards; shard++ {
+		assert.Equal(t, 1, owners[shard], "shard %d", shard)
+	}
+}
+
+func TestManager_Rebalance(t *testing.T) {
+	ctx := context.Background()
+	_, _, replicas := newTestReplicas(t, 4, "a", "b")
+	a, b := replicas[0], replicas[1]
+
+	assert.NoError(t, a.sync(ctx))
+	assert.Equal(t, []int{0, 1, 2, 3}, a.OwnedShards())
+	assert.Len(t, a.acquired, 4)
+	assertPartition(t, 4, a)
+
+	// b joins, all shards are held
+	assert.NoError(t, b.sync(ctx))
+	assert.Empty(t, b.OwnedShards())
+
+	// a gives up the shards above its share, b picks them up
+	assert.NoError(t, a.sync(ctx))
+	assert.Len(t, a.OwnedShards(), 2)
+	assert.NoError(t, b.sync(ctx))
+	assert.Len(t, b.OwnedShards(), 2)
+	assert.ElementsMatch(t, b.OwnedShards(), b.acquired)
+	assertPartition(t, 4, a, b)
+
+	// stable once balanced
+	assert.NoError(t, a.sync(ctx))
+	assert.NoError(t, b.sync(ctx))
+	assert.Len(t, a.acquired, 4)
+	assert.Len(t, b.acquired, 2)
+	assertPartition(t, 4, a, b)
+
+	for i := 0; i < 50; i++ {
+		key := fmt.Sprintf("route-%d", i)
+		assert.NotEqual(t, a.Owns("default", key), b.Owns("default", key), key)
+	}
+}
+
+func TestManager_Failover(t *testing.T) {
+	ctx := context.Background()
+	_, now, replicas := newTestReplicas(t, 3, "a", "b")
+	a, b := replicas[0], replicas[1]
+
+	assert.NoError(t, a.sync(ctx))
+	assert.NoError(t, b.sync(ctx))
+	assert.NoError(t, a.sync(ctx))
+	assert.NoError(t, b.sync(ctx))
+	assertPartition(t, 3, a, b)
+
+	// a stops renewing, its ownership ends before its leases expire
+	*now = now.Add(DefaultLeaseDuration - DefaultRenewInterval)
+	assert.Empty(t, a.OwnedShards())
+
+	*now = now.Add(DefaultRenewInterval + time.Second)
+	assert.NoError(t, b.sync(ctx))
+	assert.Equal(t, []int{0, 1, 2}, b.OwnedShards())
+}
+
+func TestManager_ReleaseAll(t *testing.T) {
+	ctx := context.Background()
+	k8sClient, _, replicas := newTestReplicas(t, 2, "a", "b")
+	a, b := replicas[0], replicas[1]
+
+	assert.NoError(t, a.sync(ctx))
+	assert.NoError(t, b.sync(ctx))
+	a.releaseAll(ctx)
+	assert.Empty(t, a.OwnedShards())
+
+	leases := &coordinationv1.LeaseList{}
+	assert.NoError(t, k8sClient.List(ctx, leases))
+	for _, lease := range leases.Items {
+		assert.NotEqual(t, "a", holder(&lease), lease.Name)
+		assert.NotEqual(t, "a", lease.Labels[MemberLabel], lease.Name)
+	}
+
+	// b takes the shards over without waiting for the leases to expire
+	assert.NoError(t, b.sync(ctx))
+	assert.Equal(t, []int{0, 1}, b.OwnedShards())
+}
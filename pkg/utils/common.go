@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"math/rand"
 	"strings"
@@ -64,6 +66,24 @@ func LatticeServiceName(k8sSourceRouteName string, k8sSourceRouteNamespace strin
 	return fmt.Sprintf("%s-%s", Truncate(k8sSourceRouteName, 20), Truncate(k8sSourceRouteNamespace, 18))
 }
 
+// LatticeHeadlessServiceName is the name of the Lattice service exposing one cluster of a Headless
+// ServiceImport, within the 40 characters of Lattice service names. The names of a route share the
+// LatticeHeadlessServiceNamePrefix. Names that do not fit are truncated and get a hash of the route,
+// namespace and cluster so that they do not collide.
+func LatticeHeadlessServiceName(k8sSourceRouteName string, k8sSourceRouteNamespace string, cluster string) string {
+	prefix := LatticeHeadlessServiceNamePrefix(k8sSourceRouteName, k8sSourceRouteNamespace)
+	normalized := strings.ToLower(strings.ReplaceAll(cluster, "_", "-"))
+	if len(k8sSourceRouteName) <= 10 && len(k8sSourceRouteNamespace) <= 8 && len(normalized) <= 20 {
+		return prefix + Truncate(normalized, 20)
+	}
+	hash := sha256.Sum256([]byte(k8sSourceRouteName + "/" + k8sSourceRouteNamespace + "/" + cluster))
+	return fmt.Sprintf("%s%s-%s", prefix, Truncate(normalized, 11), hex.EncodeToString(hash[:])[:8])
+}
+
+func LatticeHeadlessServiceNamePrefix(k8sSourceRouteName string, k8sSourceRouteNamespace string) string {
+	return fmt.Sprintf("%s-%s-", Truncate(k8sSourceRouteName, 10), Truncate(k8sSourceRouteNamespace, 8))
+}
+
 func TargetRefToLatticeResourceName(
 	targetRef *gwv1alpha2.NamespacedPolicyTargetReference,
 	parentNamespace string,
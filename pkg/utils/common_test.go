@@ -61,3 +61,18 @@ func TestSet(t *testing.T) {
 	})
 
 }
+
+func TestLatticeHeadlessServiceName(t *testing.T) {
+	assert.Equal(t, "kafka-ns-cluster-a", LatticeHeadlessServiceName("kafka", "ns", "cluster-a"))
+	assert.Equal(t, "long-route-long-nam-eu-west-1-p-4b6b06a0",
+		LatticeHeadlessServiceName("long-route-name", "long-namespace", "EU_West_1-Prod-Cluster"))
+	assert.True(t, len(LatticeHeadlessServiceName("a-very-long-route-name", "a-very-long-namespace",
+		"a-very-long-cluster-name")) <= 40)
+
+	// truncated names do not collide
+	assert.NotEqual(t, LatticeHeadlessServiceName("long-route-name-1", "ns", "cluster-a"),
+		LatticeHeadlessServiceName("long-route-name-2", "ns", "cluster-a"))
+	assert.NotEqual(t, LatticeHeadlessServiceName("kafka", "ns", "prod-cluster-eu-west-1"),
+		LatticeHeadlessServiceName("kafka", "ns", "prod-cluster-eu-west-2"))
+	assert.Equal(t, "kafka-ns-", LatticeHeadlessServiceNamePrefix("kafka", "ns"))
+}
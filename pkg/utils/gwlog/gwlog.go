@@ -12,6 +12,8 @@ import (
 
 type TracedLogger struct {
 	InnerLogger *zap.SugaredLogger
+	// level is shared by the named loggers, set on loggers built with NewLogger
+	level *zap.AtomicLevel
 }
 
 func (t *TracedLogger) Infoln(args ...interface{}) {
@@ -111,7 +113,14 @@ func (t *TracedLogger) Warn(ctx context.Context, msg string) {
 }
 
 func (t *TracedLogger) Named(name string) *TracedLogger {
-	return &TracedLogger{InnerLogger: t.InnerLogger.Named(name)}
+	return &TracedLogger{InnerLogger: t.InnerLogger.Named(name), level: t.level}
+}
+
+// SetLevel changes the level of the logger and of all the loggers named from the same root logger
+func (t *TracedLogger) SetLevel(level zapcore.Level) {
+	if t.level != nil {
+		t.level.SetLevel(level)
+	}
 }
 
 type Logger = *TracedLogger
@@ -128,13 +137,14 @@ func NewLogger(level zapcore.Level) Logger {
 		zc.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
 	}
 
-	zc.Level = zap.NewAtomicLevelAt(level)
+	atomicLevel := zap.NewAtomicLevelAt(level)
+	zc.Level = atomicLevel
 
 	z, err := zc.Build()
 	if err != nil {
 		log.Fatal("cannot initialize zapr logger", err)
 	}
-	return &TracedLogger{InnerLogger: z.Sugar().WithOptions(zap.AddCallerSkip(1))}
+	return &TracedLogger{InnerLogger: z.Sugar().WithOptions(zap.AddCallerSkip(1)), level: &atomicLevel}
 }
 
 var FallbackLogger = NewLogger(zap.DebugLevel)
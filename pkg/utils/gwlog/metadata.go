@@ -4,6 +4,10 @@ import (
 	"context"
 
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/aws/aws-application-networking-k8s/pkg/utils/tracing"
 )
 
 type key string
@@ -25,11 +29,16 @@ func newMetadata() *metadata {
 	}
 }
 
+// NewTrace attaches log metadata to the context. The trace id is taken from the
+// OpenTelemetry span in ctx when there is one, so log lines can be matched to traces.
 func NewTrace(ctx context.Context) context.Context {
-	currID := uuid.New()
+	currID := uuid.New().String()
+	if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+		currID = sc.TraceID().String()
+	}
 
 	newCtx := context.WithValue(ctx, metadataKey, newMetadata())
-	AddMetadata(newCtx, traceID, currID.String())
+	AddMetadata(newCtx, traceID, currID)
 
 	return newCtx
 }
@@ -68,6 +77,11 @@ func GetTraceID(ctx context.Context) string {
 }
 
 func StartReconcileTrace(ctx context.Context, log Logger, k8sresourcetype, name, namespace string) context.Context {
+	ctx, _ = tracing.Start(ctx, "reconcile "+k8sresourcetype,
+		attribute.String("k8s.resource.type", k8sresourcetype),
+		attribute.String("k8s.resource.name", name),
+		attribute.String("k8s.namespace.name", namespace),
+	)
 	ctx = NewTrace(ctx)
 	AddMetadata(ctx, "type", k8sresourcetype)
 	AddMetadata(ctx, "name", name)
@@ -78,6 +92,8 @@ func StartReconcileTrace(ctx context.Context, log Logger, k8sresourcetype, name,
 	return ctx
 }
 
-func EndReconcileTrace(ctx context.Context, log Logger) {
+// EndReconcileTrace ends the reconcile span, which is marked as failed when the reconcile returned an error.
+func EndReconcileTrace(ctx context.Context, log Logger, err error) {
 	log.Infow(ctx, ReconcileEnd, getMetadata(ctx)...)
+	tracing.End(trace.SpanFromContext(ctx), err)
 }
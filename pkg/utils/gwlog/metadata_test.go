@@ -2,8 +2,16 @@ package gwlog
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
 )
 
 func TestGetTrace(t *testing.T) {
@@ -30,3 +38,41 @@ func TestMetadata(t *testing.T) {
 		t.Errorf("expected context to have metadata with key foo and val bar, got %s", md)
 	}
 }
+
+func TestTraceIDFromSpan(t *testing.T) {
+	otel.SetTracerProvider(sdktrace.NewTracerProvider())
+	defer otel.SetTracerProvider(noop.NewTracerProvider())
+
+	ctx := StartReconcileTrace(context.TODO(), FallbackLogger, "route", "name", "ns")
+	defer EndReconcileTrace(ctx, FallbackLogger, nil)
+
+	spanTraceID := trace.SpanContextFromContext(ctx).TraceID().String()
+	if GetTraceID(ctx) != spanTraceID {
+		t.Errorf("expected trace_id %s to match span trace id %s", GetTraceID(ctx), spanTraceID)
+	}
+}
+
+func TestEndReconcileTraceRecordsError(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(noop.NewTracerProvider())
+
+	ctx := StartReconcileTrace(context.TODO(), FallbackLogger, "route", "name", "ns")
+	EndReconcileTrace(ctx, FallbackLogger, errors.New("failed"))
+	ctx = StartReconcileTrace(context.TODO(), FallbackLogger, "route", "name", "ns")
+	EndReconcileTrace(ctx, FallbackLogger, nil)
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 ended spans, got %d", len(spans))
+	}
+	if spans[0].Status().Code != codes.Error || spans[0].Status().Description != "failed" {
+		t.Errorf("expected failed reconcile span to have error status, got %v", spans[0].Status())
+	}
+	if len(spans[0].Events()) != 1 || spans[0].Events()[0].Name != "exception" {
+		t.Errorf("expected failed reconcile span to record the error, got events %v", spans[0].Events())
+	}
+	if spans[1].Status().Code != codes.Unset {
+		t.Errorf("expected successful reconcile span to have unset status, got %v", spans[1].Status())
+	}
+}
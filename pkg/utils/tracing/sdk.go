@@ -0,0 +1,69 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	sdkHandlerStartSpan = "startTracingSpan"
+	sdkHandlerEndSpan   = "endTracingSpan"
+)
+
+type sdkSpanKey struct{}
+
+// InjectHandlers wraps every SDK API call in a client span. Retries of the same call
+// are part of one span, the number of retries is recorded as an attribute.
+func InjectHandlers(handlers *request.Handlers) {
+	handlers.Validate.PushFrontNamed(request.NamedHandler{
+		Name: sdkHandlerStartSpan,
+		Fn:   startSpan,
+	})
+	handlers.Complete.PushBackNamed(request.NamedHandler{
+		Name: sdkHandlerEndSpan,
+		Fn:   endSpan,
+	})
+}
+
+func startSpan(r *request.Request) {
+	service := r.ClientInfo.ServiceID
+	operation := operationForRequest(r)
+	ctx, span := Tracer().Start(r.Context(), service+"."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("rpc.system", "aws-api"),
+			attribute.String("rpc.service", service),
+			attribute.String("rpc.method", operation),
+		))
+	r.SetContext(context.WithValue(ctx, sdkSpanKey{}, span))
+}
+
+func endSpan(r *request.Request) {
+	// only end spans started by startSpan, never the caller's span
+	span, ok := r.Context().Value(sdkSpanKey{}).(trace.Span)
+	if !ok {
+		return
+	}
+	span.SetAttributes(attribute.Int("aws.retry_count", r.RetryCount))
+	if r.RequestID != "" {
+		span.SetAttributes(attribute.String("aws.request_id", r.RequestID))
+	}
+	if r.HTTPResponse != nil {
+		span.SetAttributes(attribute.Int("http.response.status_code", r.HTTPResponse.StatusCode))
+	}
+	if aerr, ok := r.Error.(awserr.Error); ok {
+		span.SetAttributes(attribute.String("aws.error_code", aerr.Code()))
+	}
+	End(span, r.Error)
+}
+
+func operationForRequest(r *request.Request) string {
+	if r.Operation != nil {
+		return r.Operation.Name
+	}
+	return "?"
+}
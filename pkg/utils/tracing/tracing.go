@@ -0,0 +1,86 @@
+// Package tracing wires OpenTelemetry spans for reconciles, model deployment and AWS API calls
+package tracing
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	TracerName = "github.com/aws/aws-application-networking-k8s"
+
+	defaultServiceName = "aws-gateway-controller"
+
+	// standard OpenTelemetry SDK environment variables
+	OTEL_EXPORTER_OTLP_ENDPOINT        = "OTEL_EXPORTER_OTLP_ENDPOINT"
+	OTEL_EXPORTER_OTLP_TRACES_ENDPOINT = "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"
+	OTEL_SDK_DISABLED                  = "OTEL_SDK_DISABLED"
+)
+
+type ShutdownFunc func(ctx context.Context) error
+
+// Enabled reports whether an OTLP endpoint is configured and the SDK is not explicitly disabled.
+func Enabled() bool {
+	if strings.ToLower(os.Getenv(OTEL_SDK_DISABLED)) == "true" {
+		return false
+	}
+	return os.Getenv(OTEL_EXPORTER_OTLP_ENDPOINT) != "" || os.Getenv(OTEL_EXPORTER_OTLP_TRACES_ENDPOINT) != ""
+}
+
+// Setup installs a global tracer provider which exports spans over OTLP/HTTP. The exporter, sampler and
+// resource are configured through the standard OTEL_* environment variables. When no endpoint is configured
+// the global no-op provider is left in place and the returned shutdown function does nothing.
+func Setup(ctx context.Context) (ShutdownFunc, error) {
+	if !Enabled() {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithAttributes(semconv.ServiceName(defaultServiceName)),
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(tp)
+	return tp.Shutdown, nil
+}
+
+func Tracer() trace.Tracer {
+	return otel.Tracer(TracerName)
+}
+
+// Start creates a span as a child of the span in ctx, if any.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End marks the span as failed when err is not nil and ends it.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
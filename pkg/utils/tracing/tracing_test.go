@@ -0,0 +1,120 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/vpclattice"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func setupRecorder(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+	return recorder
+}
+
+func newTestLattice(statusCode int, body string) *vpclattice.VPCLattice {
+	sess := session.Must(session.NewSession(&aws.Config{
+		Region:      aws.String("us-west-2"),
+		Credentials: credentials.NewStaticCredentials("id", "secret", ""),
+		MaxRetries:  aws.Int(0),
+	}))
+	client := vpclattice.New(sess)
+	client.Handlers.Send.Clear()
+	client.Handlers.Send.PushBack(func(r *request.Request) {
+		r.HTTPResponse = &http.Response{
+			StatusCode: statusCode,
+			Header:     http.Header{"X-Amzn-Requestid": []string{"req-id"}},
+			Body:       io.NopCloser(strings.NewReader(body)),
+		}
+	})
+	InjectHandlers(&client.Handlers)
+	return client
+}
+
+func TestEnabled(t *testing.T) {
+	t.Setenv(OTEL_EXPORTER_OTLP_ENDPOINT, "")
+	t.Setenv(OTEL_EXPORTER_OTLP_TRACES_ENDPOINT, "")
+	assert.False(t, Enabled())
+
+	t.Setenv(OTEL_EXPORTER_OTLP_ENDPOINT, "http://localhost:4318")
+	assert.True(t, Enabled())
+
+	t.Setenv(OTEL_SDK_DISABLED, "true")
+	assert.False(t, Enabled())
+}
+
+func TestSetup_NoopWhenDisabled(t *testing.T) {
+	t.Setenv(OTEL_EXPORTER_OTLP_ENDPOINT, "")
+	t.Setenv(OTEL_EXPORTER_OTLP_TRACES_ENDPOINT, "")
+	prev := otel.GetTracerProvider()
+
+	shutdown, err := Setup(context.TODO())
+	assert.Nil(t, err)
+	assert.Equal(t, prev, otel.GetTracerProvider())
+	assert.Nil(t, shutdown(context.TODO()))
+}
+
+func TestSdkSpanIsChildOfCallerSpan(t *testing.T) {
+	recorder := setupRecorder(t)
+	client := newTestLattice(200, "{}")
+
+	ctx, parent := Start(context.TODO(), "reconcile route")
+	_, err := client.GetServiceWithContext(ctx, &vpclattice.GetServiceInput{ServiceIdentifier: aws.String("svc-0123456789abcdef0")})
+	assert.Nil(t, err)
+	End(parent, nil)
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 2)
+	sdkSpan := spans[0]
+	assert.Equal(t, "VPC Lattice.GetService", sdkSpan.Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), sdkSpan.Parent().SpanID())
+	assert.Equal(t, codes.Unset, sdkSpan.Status().Code)
+	assert.Contains(t, sdkSpan.Attributes(), attribute.String("aws.request_id", "req-id"))
+}
+
+func TestSdkSpanRecordsError(t *testing.T) {
+	recorder := setupRecorder(t)
+	client := newTestLattice(404, `{"message": "not found"}`)
+	client.Handlers.UnmarshalError.Clear()
+	client.Handlers.UnmarshalError.PushBack(func(r *request.Request) {
+		r.Error = errors.New("not found")
+	})
+
+	_, err := client.GetServiceWithContext(context.TODO(), &vpclattice.GetServiceInput{ServiceIdentifier: aws.String("svc-0123456789abcdef0")})
+	assert.NotNil(t, err)
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.False(t, spans[0].Parent().IsValid())
+}
+
+func TestEndDoesNotEndCallerSpan(t *testing.T) {
+	recorder := setupRecorder(t)
+
+	ctx, parent := Start(context.TODO(), "parent")
+	r := &request.Request{HTTPRequest: &http.Request{}}
+	r.SetContext(ctx)
+	endSpan(r)
+
+	assert.Len(t, recorder.Ended(), 0)
+	parent.End()
+	assert.Len(t, recorder.Ended(), 1)
+}
@@ -0,0 +1,71 @@
+package core
+
+import (
+	"context"
+	"github.com/aws/aws-application-networking-k8s/pkg/utils/gwlog"
+	admissionv1 "k8s.io/api/admission/v1"
+	"net/http"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+type validatingHandler struct {
+	log       gwlog.Logger
+	validator Validator
+	decoder   admission.Decoder
+}
+
+func (h *validatingHandler) SetDecoder(d admission.Decoder) {
+	h.decoder = d
+}
+
+// Handle handles admission requests.
+func (h *validatingHandler) Handle(ctx context.Context, req admission.Request) admission.Response {
+	h.log.Debugw(ctx, "validating webhook request", "operation", req.Operation, "kind", req.Kind.Kind, "name", req.Name, "namespace", req.Namespace)
+	var resp admission.Response
+	switch req.Operation {
+	case admissionv1.Create:
+		resp = h.handleCreate(ctx, req)
+	case admissionv1.Update:
+		resp = h.handleUpdate(ctx, req)
+	default:
+		resp = admission.Allowed("")
+	}
+	h.log.Debugw(ctx, "validating webhook response", "allowed", resp.Allowed, "result", resp.Result)
+	return resp
+}
+
+func (h *validatingHandler) handleCreate(ctx context.Context, req admission.Request) admission.Response {
+	prototype, err := h.validator.Prototype(req)
+	if err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	obj := prototype.DeepCopyObject()
+	if err := h.decoder.DecodeRaw(req.Object, obj); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	if err := h.validator.ValidateCreate(ContextWithAdmissionRequest(ctx, req), obj); err != nil {
+		return admission.Denied(err.Error())
+	}
+	return admission.Allowed("")
+}
+
+func (h *validatingHandler) handleUpdate(ctx context.Context, req admission.Request) admission.Response {
+	prototype, err := h.validator.Prototype(req)
+	if err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	obj := prototype.DeepCopyObject()
+	oldObj := prototype.DeepCopyObject()
+	if err := h.decoder.DecodeRaw(req.Object, obj); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if err := h.decoder.DecodeRaw(req.OldObject, oldObj); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	if err := h.validator.ValidateUpdate(ContextWithAdmissionRequest(ctx, req), obj, oldObj); err != nil {
+		return admission.Denied(err.Error())
+	}
+	return admission.Allowed("")
+}
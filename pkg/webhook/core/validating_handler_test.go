@@ -0,0 +1,182 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/aws/aws-application-networking-k8s/pkg/utils/gwlog"
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"net/http"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	"testing"
+)
+
+func Test_validatingHandler_Handle(t *testing.T) {
+	scheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(scheme)
+	decoder := admission.NewDecoder(scheme)
+
+	initialPod := &corev1.Pod{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Pod",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "foo",
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name:  "bar",
+					Image: "bar:v1",
+				},
+			},
+		},
+	}
+	initialPodRaw, err := json.Marshal(initialPod)
+	assert.NoError(t, err)
+	updatedPod := initialPod.DeepCopy()
+	updatedPod.Spec.Containers[0].Image = "bar:v2"
+	updatedPodRaw, err := json.Marshal(updatedPod)
+	assert.NoError(t, err)
+
+	podPrototype := func(req admission.Request) (runtime.Object, error) {
+		return &corev1.Pod{}, nil
+	}
+	denied := admission.Response{
+		AdmissionResponse: admissionv1.AdmissionResponse{
+			Allowed: false,
+			Result: &metav1.Status{
+				Code:    http.StatusForbidden,
+				Message: "oops, invalid object",
+				Reason:  "Forbidden",
+			},
+		},
+	}
+
+	tests := []struct {
+		name                    string
+		validatorPrototype      func(req admission.Request) (runtime.Object, error)
+		validatorValidateCreate func(ctx context.Context, obj runtime.Object) error
+		validatorValidateUpdate func(ctx context.Context, obj runtime.Object, oldObj runtime.Object) error
+		req                     admission.Request
+		want                    admission.Response
+	}{
+		{
+			name:               "[create] approve request",
+			validatorPrototype: podPrototype,
+			validatorValidateCreate: func(ctx context.Context, obj runtime.Object) error {
+				assert.Equal(t, "bar:v1", obj.(*corev1.Pod).Spec.Containers[0].Image)
+				return nil
+			},
+			req: admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: admissionv1.Create,
+					Object:    runtime.RawExtension{Raw: initialPodRaw},
+				},
+			},
+			want: admission.Allowed(""),
+		},
+		{
+			name:               "[create] reject request",
+			validatorPrototype: podPrototype,
+			validatorValidateCreate: func(ctx context.Context, obj runtime.Object) error {
+				return errors.New("oops, invalid object")
+			},
+			req: admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: admissionv1.Create,
+					Object:    runtime.RawExtension{Raw: initialPodRaw},
+				},
+			},
+			want: denied,
+		},
+		{
+			name: "[create] unexpected object type - prototype returns error",
+			validatorPrototype: func(req admission.Request) (runtime.Object, error) {
+				return nil, errors.New("oops, unexpected object type")
+			},
+			req: admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: admissionv1.Create,
+					Object:    runtime.RawExtension{Raw: initialPodRaw},
+				},
+			},
+			want: admission.Errored(http.StatusBadRequest, errors.New("oops, unexpected object type")),
+		},
+		{
+			name:               "[update] approve request",
+			validatorPrototype: podPrototype,
+			validatorValidateUpdate: func(ctx context.Context, obj runtime.Object, oldObj runtime.Object) error {
+				assert.Equal(t, "bar:v2", obj.(*corev1.Pod).Spec.Containers[0].Image)
+				assert.Equal(t, "bar:v1", oldObj.(*corev1.Pod).Spec.Containers[0].Image)
+				return nil
+			},
+			req: admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: admissionv1.Update,
+					Object:    runtime.RawExtension{Raw: updatedPodRaw},
+					OldObject: runtime.RawExtension{Raw: initialPodRaw},
+				},
+			},
+			want: admission.Allowed(""),
+		},
+		{
+			name:               "[update] reject request",
+			validatorPrototype: podPrototype,
+			validatorValidateUpdate: func(ctx context.Context, obj runtime.Object, oldObj runtime.Object) error {
+				return errors.New("oops, invalid object")
+			},
+			req: admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: admissionv1.Update,
+					Object:    runtime.RawExtension{Raw: updatedPodRaw},
+					OldObject: runtime.RawExtension{Raw: initialPodRaw},
+				},
+			},
+			want: denied,
+		},
+		{
+			name: "[delete] approve request",
+			req: admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: admissionv1.Delete,
+					OldObject: runtime.RawExtension{Raw: initialPodRaw},
+				},
+			},
+			want: admission.Allowed(""),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			validator := NewMockValidator(ctrl)
+			if tt.validatorPrototype != nil {
+				validator.EXPECT().Prototype(gomock.Any()).DoAndReturn(tt.validatorPrototype)
+			}
+			if tt.validatorValidateCreate != nil {
+				validator.EXPECT().ValidateCreate(gomock.Any(), gomock.Any()).DoAndReturn(tt.validatorValidateCreate)
+			}
+			if tt.validatorValidateUpdate != nil {
+				validator.EXPECT().ValidateUpdate(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(tt.validatorValidateUpdate)
+			}
+
+			h := &validatingHandler{
+				log:       gwlog.FallbackLogger,
+				validator: validator,
+				decoder:   decoder,
+			}
+			got := h.Handle(ctx, tt.req)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package core
+
+import (
+	"context"
+	"github.com/aws/aws-application-networking-k8s/pkg/utils/gwlog"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+//go:generate mockgen -destination validator_mocks.go -package core github.com/aws/aws-application-networking-k8s/pkg/webhook/core Validator
+type Validator interface {
+	// Prototype returns a prototype of Object for this admission request.
+	Prototype(req admission.Request) (runtime.Object, error)
+
+	// ValidateCreate handles Object creation and returns error if the object is invalid.
+	ValidateCreate(ctx context.Context, obj runtime.Object) error
+	// ValidateUpdate handles Object update and returns error if the object is invalid.
+	ValidateUpdate(ctx context.Context, obj runtime.Object, oldObj runtime.Object) error
+}
+
+// ValidatingWebhookForValidator creates a new validating Webhook.
+func ValidatingWebhookForValidator(log gwlog.Logger, scheme *runtime.Scheme, validator Validator) *admission.Webhook {
+	return &admission.Webhook{
+		Handler: &validatingHandler{
+			log:       log,
+			validator: validator,
+			decoder:   admission.NewDecoder(scheme),
+		},
+	}
+}
@@ -34,6 +34,10 @@ type PodReadinessGateInjector struct {
 func (m *PodReadinessGateInjector) MutateCreate(ctx context.Context, pod *corev1.Pod) error {
 	pct := corev1.PodConditionType(PodReadinessGateConditionType)
 	m.log.Debugf(ctx, "Webhook invoked for pod %s/%s", pod.Namespace, getPodName(pod))
+	if !config.IsNamespaceWatched(pod.Namespace) {
+		m.log.Debugf(ctx, "Namespace %s is not watched, skipping pod %s", pod.Namespace, getPodName(pod))
+		return nil
+	}
 
 	found := false
 	for _, rg := range pod.Spec.ReadinessGates {
@@ -75,7 +79,7 @@ func (m *PodReadinessGateInjector) requiresReadinessGate(ctx context.Context, po
 	routes := m.listAllRoutes(ctx)
 	for _, route := range routes {
 		if svc := m.isPodUsedByRoute(route, svcMatches); svc != nil {
-			if m.routeHasLatticeGateway(ctx, route) {
+			if routeHasLatticeGateway(ctx, m.log, m.k8sClient, route) {
 				m.log.Debugf(ctx, "Pod %s/%s is used by service %s/%s and route %s/%s", pod.Namespace, getPodName(pod),
 					svc.Namespace, svc.Name, route.Namespace(), route.Name())
 				return true, nil
@@ -173,9 +177,9 @@ func (m *PodReadinessGateInjector) isPodUsedByRoute(route core.Route, svcMap map
 	return nil
 }
 
-func (m *PodReadinessGateInjector) routeHasLatticeGateway(ctx context.Context, route core.Route) bool {
+func routeHasLatticeGateway(ctx context.Context, log gwlog.Logger, k8sClient client.Client, route core.Route) bool {
 	if len(route.Spec().ParentRefs()) == 0 {
-		m.log.Debugf(ctx, "Route %s/%s has no parentRefs", route.Namespace(), route.Name())
+		log.Debugf(ctx, "Route %s/%s has no parentRefs", route.Namespace(), route.Name())
 		return false
 	}
 
@@ -189,8 +193,8 @@ func (m *PodReadinessGateInjector) routeHasLatticeGateway(ctx context.Context, r
 		Name:      string(route.Spec().ParentRefs()[0].Name),
 	}
 
-	if err := m.k8sClient.Get(ctx, gwName, gw); err != nil {
-		m.log.Debugf(ctx, "Unable to retrieve gateway %s/%s for route %s/%s, %s",
+	if err := k8sClient.Get(ctx, gwName, gw); err != nil {
+		log.Debugf(ctx, "Unable to retrieve gateway %s/%s for route %s/%s, %s",
 			gwName.Namespace, gwName.Name, route.Namespace(), route.Name(), err)
 		return false
 	}
@@ -202,17 +206,17 @@ func (m *PodReadinessGateInjector) routeHasLatticeGateway(ctx context.Context, r
 		Name:      string(gw.Spec.GatewayClassName),
 	}
 
-	if err := m.k8sClient.Get(ctx, gwClassName, gwClass); err != nil {
-		m.log.Debugf(ctx, "Unable to retrieve gateway class %s/%s for gateway %s/%s, %s",
+	if err := k8sClient.Get(ctx, gwClassName, gwClass); err != nil {
+		log.Debugf(ctx, "Unable to retrieve gateway class %s/%s for gateway %s/%s, %s",
 			gwClassName.Namespace, gwClass.Name, gwName.Namespace, gwName.Name, err)
 		return false
 	}
 
 	if gwClass.Spec.ControllerName == config.LatticeGatewayControllerName {
-		m.log.Debugf(ctx, "Gateway %s/%s is a lattice gateway", gwName.Namespace, gwName.Name)
+		log.Debugf(ctx, "Gateway %s/%s is a lattice gateway", gwName.Namespace, gwName.Name)
 		return true
 	}
 
-	m.log.Debugf(ctx, "Gateway %s/%s is not a lattice gateway", gwName.Namespace, gwName.Name)
+	log.Debugf(ctx, "Gateway %s/%s is not a lattice gateway", gwName.Namespace, gwName.Name)
 	return false
 }
@@ -0,0 +1,98 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	anv1alpha1 "github.com/aws/aws-application-networking-k8s/pkg/apis/applicationnetworking/v1alpha1"
+	"github.com/aws/aws-application-networking-k8s/pkg/gateway"
+	"github.com/aws/aws-application-networking-k8s/pkg/k8s/policyhelper"
+	"github.com/aws/aws-application-networking-k8s/pkg/utils/gwlog"
+	webhookcore "github.com/aws/aws-application-networking-k8s/pkg/webhook/core"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+const (
+	apiPathValidatePolicy = "/validate-policy"
+)
+
+// NewPolicyValidator rejects Lattice policies with an unsupported targetRef kind or invalid values.
+// Whether the target exists or conflicts with another policy is still reported in the policy status.
+func NewPolicyValidator(log gwlog.Logger, scheme *runtime.Scheme, k8sClient client.Client) *policyValidator {
+	return &policyValidator{
+		log:        log,
+		scheme:     scheme,
+		tgpHandler: policyhelper.NewTargetGroupPolicyHandler(log, k8sClient),
+		iapHandler: policyhelper.NewIAMAuthPolicyHandler(log, k8sClient),
+		vapHandler: policyhelper.NewVpcAssociationPolicyHandler(log, k8sClient),
+		sipHandler: policyhelper.NewServiceImportPolicyHandler(log, k8sClient),
+	}
+}
+
+var _ webhookcore.Validator = &policyValidator{}
+
+type policyValidator struct {
+	log        gwlog.Logger
+	scheme     *runtime.Scheme
+	tgpHandler *policyhelper.PolicyHandler[*policyhelper.TGP]
+	iapHandler *policyhelper.PolicyHandler[*policyhelper.IAP]
+	vapHandler *policyhelper.PolicyHandler[*policyhelper.VAP]
+	sipHandler *policyhelper.PolicyHandler[*policyhelper.SIP]
+}
+
+func (v *policyValidator) Prototype(req admission.Request) (runtime.Object, error) {
+	switch req.Kind.Kind {
+	case "TargetGroupPolicy":
+		return &anv1alpha1.TargetGroupPolicy{}, nil
+	case "IAMAuthPolicy":
+		return &anv1alpha1.IAMAuthPolicy{}, nil
+	case "AccessLogPolicy":
+		return &anv1alpha1.AccessLogPolicy{}, nil
+	case "VpcAssociationPolicy":
+		return &anv1alpha1.VpcAssociationPolicy{}, nil
+	case "ServiceImportPolicy":
+		return &anv1alpha1.ServiceImportPolicy{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported policy kind %s", req.Kind.Kind)
+	}
+}
+
+func (v *policyValidator) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	return v.validate(obj)
+}
+
+func (v *policyValidator) ValidateUpdate(ctx context.Context, obj runtime.Object, oldObj runtime.Object) error {
+	return v.validate(obj)
+}
+
+func (v *policyValidator) validate(obj runtime.Object) error {
+	switch policy := obj.(type) {
+	case *anv1alpha1.TargetGroupPolicy:
+		if err := v.tgpHandler.ValidateTargetRefKind(policy); err != nil {
+			return err
+		}
+		return gateway.ValidateTargetGroupPolicy(policy)
+	case *anv1alpha1.IAMAuthPolicy:
+		if err := v.iapHandler.ValidateTargetRefKind(policy); err != nil {
+			return err
+		}
+		return v.iapHandler.ValidateSpec(policy)
+	case *anv1alpha1.AccessLogPolicy:
+		return policyhelper.ValidateAccessLogPolicyTargetRef(policy)
+	case *anv1alpha1.VpcAssociationPolicy:
+		return v.vapHandler.ValidateTargetRefKind(policy)
+	case *anv1alpha1.ServiceImportPolicy:
+		if err := v.sipHandler.ValidateTargetRefKind(policy); err != nil {
+			return err
+		}
+		return v.sipHandler.ValidateSpec(policy)
+	default:
+		return fmt.Errorf("unsupported policy type %T", obj)
+	}
+}
+
+func (v *policyValidator) SetupWithManager(log gwlog.Logger, mgr ctrl.Manager) {
+	mgr.GetWebhookServer().Register(apiPathValidatePolicy, webhookcore.ValidatingWebhookForValidator(log, v.scheme, v))
+}
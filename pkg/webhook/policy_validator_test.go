@@ -0,0 +1,119 @@
+package webhook
+
+import (
+	"context"
+	anv1alpha1 "github.com/aws/aws-application-networking-k8s/pkg/apis/applicationnetworking/v1alpha1"
+	"github.com/aws/aws-application-networking-k8s/pkg/utils/gwlog"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gwv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gwv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	"testing"
+)
+
+func Test_policyValidator(t *testing.T) {
+	k8sScheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(k8sScheme)
+	anv1alpha1.Install(k8sScheme)
+	v := NewPolicyValidator(gwlog.FallbackLogger, k8sScheme, testclient.NewClientBuilder().WithScheme(k8sScheme).Build())
+
+	targetRef := func(group, kind string) *gwv1alpha2.NamespacedPolicyTargetReference {
+		return &gwv1alpha2.NamespacedPolicyTargetReference{Group: gwv1.Group(group), Kind: gwv1.Kind(kind), Name: "target"}
+	}
+	objectMeta := metav1.ObjectMeta{Name: "policy", Namespace: "default"}
+	otherNamespace := gwv1.Namespace("other")
+
+	tests := []struct {
+		name    string
+		obj     runtime.Object
+		wantErr bool
+	}{
+		{
+			name: "valid TargetGroupPolicy",
+			obj: &anv1alpha1.TargetGroupPolicy{ObjectMeta: objectMeta, Spec: anv1alpha1.TargetGroupPolicySpec{
+				TargetRef: targetRef("", "Service"),
+				Protocol:  aws.String("HTTP"),
+			}},
+		},
+		{
+			name: "TargetGroupPolicy with unsupported kind",
+			obj: &anv1alpha1.TargetGroupPolicy{ObjectMeta: objectMeta, Spec: anv1alpha1.TargetGroupPolicySpec{
+				TargetRef: targetRef(gwv1.GroupName, "HTTPRoute"),
+			}},
+			wantErr: true,
+		},
+		{
+			name: "TargetGroupPolicy with invalid health check",
+			obj: &anv1alpha1.TargetGroupPolicy{ObjectMeta: objectMeta, Spec: anv1alpha1.TargetGroupPolicySpec{
+				TargetRef:   targetRef("", "Service"),
+				HealthCheck: &anv1alpha1.HealthCheckConfig{StatusMatch: aws.String("2xx")},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "valid IAMAuthPolicy",
+			obj: &anv1alpha1.IAMAuthPolicy{ObjectMeta: objectMeta, Spec: anv1alpha1.IAMAuthPolicySpec{
+				TargetRef: targetRef(gwv1.GroupName, "Gateway"),
+				Policy:    `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Principal": "*", "Action": "vpc-lattice-svcs:Invoke"}]}`,
+			}},
+		},
+		{
+			name: "IAMAuthPolicy with a typo",
+			obj: &anv1alpha1.IAMAuthPolicy{ObjectMeta: objectMeta, Spec: anv1alpha1.IAMAuthPolicySpec{
+				TargetRef: targetRef(gwv1.GroupName, "Gateway"),
+				Policy:    `{"Version": "2012-10-17", "Statement": [{"Effect": "Alow", "Principal": "*", "Action": "vpc-lattice-svcs:Invoke"}]}`,
+			}},
+			wantErr: true,
+		},
+		{
+			name: "IAMAuthPolicy with invalid JSON",
+			obj: &anv1alpha1.IAMAuthPolicy{ObjectMeta: objectMeta, Spec: anv1alpha1.IAMAuthPolicySpec{
+				TargetRef: targetRef(gwv1.GroupName, "Gateway"),
+				Policy:    `{"Version": "2012-10-17",`,
+			}},
+			wantErr: true,
+		},
+		{
+			name: "valid AccessLogPolicy",
+			obj: &anv1alpha1.AccessLogPolicy{ObjectMeta: objectMeta, Spec: anv1alpha1.AccessLogPolicySpec{
+				TargetRef: targetRef(gwv1.GroupName, "HTTPRoute"),
+			}},
+		},
+		{
+			name: "AccessLogPolicy targeting another namespace",
+			obj: &anv1alpha1.AccessLogPolicy{ObjectMeta: objectMeta, Spec: anv1alpha1.AccessLogPolicySpec{
+				TargetRef: &gwv1alpha2.NamespacedPolicyTargetReference{
+					Group: gwv1.GroupName, Kind: "Gateway", Name: "target", Namespace: &otherNamespace,
+				},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "valid VpcAssociationPolicy",
+			obj: &anv1alpha1.VpcAssociationPolicy{ObjectMeta: objectMeta, Spec: anv1alpha1.VpcAssociationPolicySpec{
+				TargetRef: targetRef(gwv1.GroupName, "Gateway"),
+			}},
+		},
+		{
+			name: "VpcAssociationPolicy with unsupported kind",
+			obj: &anv1alpha1.VpcAssociationPolicy{ObjectMeta: objectMeta, Spec: anv1alpha1.VpcAssociationPolicySpec{
+				TargetRef: targetRef(gwv1.GroupName, "HTTPRoute"),
+			}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.ValidateCreate(context.TODO(), tt.obj)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
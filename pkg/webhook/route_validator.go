@@ -0,0 +1,83 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"github.com/aws/aws-application-networking-k8s/pkg/config"
+	"github.com/aws/aws-application-networking-k8s/pkg/gateway"
+	"github.com/aws/aws-application-networking-k8s/pkg/model/core"
+	"github.com/aws/aws-application-networking-k8s/pkg/utils/gwlog"
+	webhookcore "github.com/aws/aws-application-networking-k8s/pkg/webhook/core"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	gwv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gwv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+)
+
+const (
+	apiPathValidateRoute = "/validate-route"
+)
+
+// NewRouteValidator rejects the routes of Lattice gateways that the model builder would fail to build.
+// Routes of other gateways are always allowed.
+func NewRouteValidator(log gwlog.Logger, scheme *runtime.Scheme, k8sClient client.Client) *routeValidator {
+	return &routeValidator{
+		log:       log,
+		scheme:    scheme,
+		k8sClient: k8sClient,
+	}
+}
+
+var _ webhookcore.Validator = &routeValidator{}
+
+type routeValidator struct {
+	log       gwlog.Logger
+	scheme    *runtime.Scheme
+	k8sClient client.Client
+}
+
+func (v *routeValidator) Prototype(req admission.Request) (runtime.Object, error) {
+	switch req.Kind.Kind {
+	case "HTTPRoute":
+		return &gwv1.HTTPRoute{}, nil
+	case "GRPCRoute":
+		return &gwv1.GRPCRoute{}, nil
+	case "TLSRoute":
+		return &gwv1alpha2.TLSRoute{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported route kind %s", req.Kind.Kind)
+	}
+}
+
+func (v *routeValidator) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	return v.validate(ctx, obj)
+}
+
+func (v *routeValidator) ValidateUpdate(ctx context.Context, obj runtime.Object, oldObj runtime.Object) error {
+	return v.validate(ctx, obj)
+}
+
+func (v *routeValidator) validate(ctx context.Context, obj runtime.Object) error {
+	route, err := core.NewRoute(obj.(client.Object))
+	if err != nil {
+		return err
+	}
+	if !config.IsNamespaceWatched(route.Namespace()) || !routeHasLatticeGateway(ctx, v.log, v.k8sClient, route) {
+		return nil
+	}
+	if err := gateway.ValidateRoute(ctx, v.log, route); err != nil {
+		return fmt.Errorf("%s %s/%s is not supported by VPC Lattice: %w",
+			route.GroupKind().Kind, route.Namespace(), route.Name(), err)
+	}
+	if err := gateway.ValidateBackendRefsIpFamilies(ctx, v.k8sClient, route); err != nil {
+		return fmt.Errorf("%s %s/%s is not supported by VPC Lattice: %w",
+			route.GroupKind().Kind, route.Namespace(), route.Name(), err)
+	}
+	return nil
+}
+
+func (v *routeValidator) SetupWithManager(log gwlog.Logger, mgr ctrl.Manager) {
+	mgr.GetWebhookServer().Register(apiPathValidateRoute, webhookcore.ValidatingWebhookForValidator(log, v.scheme, v))
+}
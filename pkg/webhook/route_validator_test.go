@@ -0,0 +1,86 @@
+package webhook
+
+import (
+	"context"
+	"github.com/aws/aws-application-networking-k8s/pkg/utils/gwlog"
+	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	gwv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gwv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	"testing"
+)
+
+func Test_routeValidator(t *testing.T) {
+	ctx := context.TODO()
+	k8sScheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(k8sScheme)
+	gwv1.Install(k8sScheme)
+	k8sClient := testclient.NewClientBuilder().WithScheme(k8sScheme).WithObjects(
+		&gwv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "amazon-vpc-lattice", Namespace: "default"},
+			Spec:       gwv1.GatewayClassSpec{ControllerName: "application-networking.k8s.aws/gateway-api-controller"},
+		},
+		&gwv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "other-gateway-type", Namespace: "default"},
+			Spec:       gwv1.GatewayClassSpec{ControllerName: "example.com/other-controller"},
+		},
+		&gwv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Name: "lattice-gw", Namespace: "test"},
+			Spec:       gwv1.GatewaySpec{GatewayClassName: "amazon-vpc-lattice"},
+		},
+		&gwv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Name: "other-gw", Namespace: "test"},
+			Spec:       gwv1.GatewaySpec{GatewayClassName: "other-gateway-type"},
+		},
+	).Build()
+	v := NewRouteValidator(gwlog.FallbackLogger, k8sScheme, k8sClient)
+
+	httpRoute := func(gw string, matches ...gwv1.HTTPRouteMatch) *gwv1.HTTPRoute {
+		return &gwv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{Name: "route", Namespace: "test"},
+			Spec: gwv1.HTTPRouteSpec{
+				CommonRouteSpec: gwv1.CommonRouteSpec{ParentRefs: []gwv1.ParentReference{{Name: gwv1.ObjectName(gw)}}},
+				Rules:           []gwv1.HTTPRouteRule{{Matches: matches}},
+			},
+		}
+	}
+
+	assert.NoError(t, v.ValidateCreate(ctx, httpRoute("lattice-gw", gwv1.HTTPRouteMatch{})))
+	assert.Error(t, v.ValidateCreate(ctx, httpRoute("lattice-gw", gwv1.HTTPRouteMatch{}, gwv1.HTTPRouteMatch{})))
+	assert.Error(t, v.ValidateUpdate(ctx, httpRoute("lattice-gw", gwv1.HTTPRouteMatch{}, gwv1.HTTPRouteMatch{}), httpRoute("lattice-gw")))
+	// routes of other gateways are not validated
+	assert.NoError(t, v.ValidateCreate(ctx, httpRoute("other-gw", gwv1.HTTPRouteMatch{}, gwv1.HTTPRouteMatch{})))
+
+	tlsRoute := &gwv1alpha2.TLSRoute{
+		ObjectMeta: metav1.ObjectMeta{Name: "route", Namespace: "test"},
+		Spec: gwv1alpha2.TLSRouteSpec{
+			CommonRouteSpec: gwv1.CommonRouteSpec{ParentRefs: []gwv1.ParentReference{{Name: "lattice-gw"}}},
+			Rules:           []gwv1alpha2.TLSRouteRule{{}, {}},
+		},
+	}
+	assert.Error(t, v.ValidateCreate(ctx, tlsRoute))
+}
+
+func Test_routeValidator_Prototype(t *testing.T) {
+	v := NewRouteValidator(gwlog.FallbackLogger, nil, nil)
+	request := func(kind string) admission.Request {
+		return admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{Kind: metav1.GroupVersionKind{Kind: kind}}}
+	}
+
+	obj, err := v.Prototype(request("HTTPRoute"))
+	assert.NoError(t, err)
+	assert.IsType(t, &gwv1.HTTPRoute{}, obj)
+	obj, err = v.Prototype(request("GRPCRoute"))
+	assert.NoError(t, err)
+	assert.IsType(t, &gwv1.GRPCRoute{}, obj)
+	obj, err = v.Prototype(request("TLSRoute"))
+	assert.NoError(t, err)
+	assert.IsType(t, &gwv1alpha2.TLSRoute{}, obj)
+	_, err = v.Prototype(request("UDPRoute"))
+	assert.Error(t, err)
+}
@@ -11,6 +11,7 @@ TEMP_CERT=`mktemp`
 
 WEBHOOK_SVC_NAME=webhook-service
 WEBHOOK_NAME=aws-appnet-gwc-mutating-webhook
+VALIDATING_WEBHOOK_NAME=aws-appnet-gwc-validating-webhook
 WEBHOOK_NAMESPACE=aws-application-networking-system
 WEBHOOK_SECRET_NAME=webhook-cert
 
@@ -29,6 +30,9 @@ echo "Patching webhook with new cert"
 kubectl patch mutatingwebhookconfigurations.admissionregistration.k8s.io $WEBHOOK_NAME \
     --namespace $WEBHOOK_NAMESPACE --type='json' \
     -p="[{'op': 'replace', 'path': '/webhooks/0/clientConfig/caBundle', 'value': '${CERT_B64}'}]"
+kubectl patch validatingwebhookconfigurations.admissionregistration.k8s.io $VALIDATING_WEBHOOK_NAME \
+    --namespace $WEBHOOK_NAMESPACE --type='json' \
+    -p="[{'op': 'replace', 'path': '/webhooks/0/clientConfig/caBundle', 'value': '${CERT_B64}'}, {'op': 'replace', 'path': '/webhooks/1/clientConfig/caBundle', 'value': '${CERT_B64}'}]"
 
 rm $TEMP_KEY $TEMP_CERT
 echo "Done"
\ No newline at end of file
@@ -78,6 +78,7 @@ yq -i e '(.[] as $item | select(.metadata.name == "webhook-cert" and .kind == "S
 
 echo "Patching webhook"
 yq -i e '(.[] as $item | select(.metadata.name == "aws-appnet-gwc-mutating-webhook" and .kind == "MutatingWebhookConfiguration") | .webhooks[0].clientConfig.caBundle) = env(CA_B64)' $DEPLOY_YAML 2>&1
+yq -i e '(.[] as $item | select(.metadata.name == "aws-appnet-gwc-validating-webhook" and .kind == "ValidatingWebhookConfiguration") | .webhooks[].clientConfig.caBundle) = env(CA_B64)' $DEPLOY_YAML 2>&1
 
 echo "Enabling webhook"
 yq -i -e '(.[] as $item | select(.metadata.name == "gateway-api-controller" and .kind == "Deployment") | .spec.template.spec.containers[] | select(.name == "manager") | .env[] | select(.name == "WEBHOOK_ENABLED") | .value) = "true"' $DEPLOY_YAML 2>&1